@@ -0,0 +1,88 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements read-only accessors for individual keys of the merged
+// configuration, for keys that have no field in the typed configuration.
+package vcfg
+
+import (
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// withKoanf calls fn with the merged values under the read lock, or returns the
+// zero value of R if no configuration has been loaded
+func withKoanf[T, R any](cm *ConfigManager[T], fn func(k *koanf.Koanf) R) R {
+	var zero R
+	if cm == nil {
+		return zero
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if cm.koanf == nil {
+		return zero
+	}
+	return fn(cm.koanf)
+}
+
+// Exists reports whether path, e.g. "plugins.cache", is set in the merged
+// configuration of all sources.
+//
+// The key accessors read the merged values directly, so dynamic keys such as
+// the entries of a map[string]any section can be queried without unmarshaling.
+// They see the values as the sources provide them: struct tag defaults are not
+// applied, and ${...} references and encrypted values are not resolved. Missing
+// keys and values of the wrong type yield the zero value.
+func (cm *ConfigManager[T]) Exists(path string) bool {
+	return withKoanf(cm, func(k *koanf.Koanf) bool { return k.Exists(path) })
+}
+
+// Value returns a copy of the value at path: a scalar, a []any or a
+// map[string]any for a section. It returns nil if path is not set.
+func (cm *ConfigManager[T]) Value(path string) any {
+	return withKoanf(cm, func(k *koanf.Koanf) any { return k.Get(path) })
+}
+
+// String returns the value at path as a string. See Exists for the semantics
+// shared by all key accessors.
+func (cm *ConfigManager[T]) String(path string) string {
+	return withKoanf(cm, func(k *koanf.Koanf) string { return k.String(path) })
+}
+
+// Strings returns the list at path as a []string.
+func (cm *ConfigManager[T]) Strings(path string) []string {
+	return withKoanf(cm, func(k *koanf.Koanf) []string { return k.Strings(path) })
+}
+
+// Int returns the value at path as an int.
+func (cm *ConfigManager[T]) Int(path string) int {
+	return withKoanf(cm, func(k *koanf.Koanf) int { return k.Int(path) })
+}
+
+// Int64 returns the value at path as an int64.
+func (cm *ConfigManager[T]) Int64(path string) int64 {
+	return withKoanf(cm, func(k *koanf.Koanf) int64 { return k.Int64(path) })
+}
+
+// Float64 returns the value at path as a float64.
+func (cm *ConfigManager[T]) Float64(path string) float64 {
+	return withKoanf(cm, func(k *koanf.Koanf) float64 { return k.Float64(path) })
+}
+
+// Bool returns the value at path as a bool.
+func (cm *ConfigManager[T]) Bool(path string) bool {
+	return withKoanf(cm, func(k *koanf.Koanf) bool { return k.Bool(path) })
+}
+
+// Duration returns the value at path as a time.Duration. Strings such as "30s"
+// are parsed with time.ParseDuration and numbers are taken as nanoseconds.
+func (cm *ConfigManager[T]) Duration(path string) time.Duration {
+	return withKoanf(cm, func(k *koanf.Koanf) time.Duration { return k.Duration(path) })
+}
+
+// MapKeys returns the sorted keys of the section at path, e.g. the instance
+// names below "plugins". It returns an empty list if path is not a section.
+func (cm *ConfigManager[T]) MapKeys(path string) []string {
+	return withKoanf(cm, func(k *koanf.Koanf) []string { return k.MapKeys(path) })
+}
@@ -0,0 +1,57 @@
+package vcfg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigManager_KeyAccessors(t *testing.T) {
+	cm := newManager[TestConfig](rawbytes.Provider([]byte(`{
+		"name": "test",
+		"port": 8080,
+		"enabled": true,
+		"ratio": 0.5,
+		"timeout": "1m30s",
+		"hosts": ["a", "b"],
+		"plugins": {"cache": {"type": "redis"}, "audit": {"type": "file"}}
+	}`)))
+
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+	cm.cfg.Store(cfg)
+
+	assert.True(t, cm.Exists("plugins.cache"))
+	assert.True(t, cm.Exists("plugins.cache.type"))
+	assert.False(t, cm.Exists("plugins.queue"))
+
+	assert.Equal(t, "test", cm.String("name"))
+	assert.Equal(t, "8080", cm.String("port"))
+	assert.Equal(t, 8080, cm.Int("port"))
+	assert.Equal(t, int64(8080), cm.Int64("port"))
+	assert.Equal(t, 0.5, cm.Float64("ratio"))
+	assert.True(t, cm.Bool("enabled"))
+	assert.Equal(t, 90*time.Second, cm.Duration("timeout"))
+	assert.Equal(t, []string{"a", "b"}, cm.Strings("hosts"))
+	assert.Equal(t, []string{"audit", "cache"}, cm.MapKeys("plugins"))
+	assert.Equal(t, "redis", cm.String("plugins.cache.type"))
+
+	// Value returns a copy
+	section := cm.Value("plugins.cache").(map[string]any)
+	section["type"] = "changed"
+	assert.Equal(t, "redis", cm.String("plugins.cache.type"))
+
+	// Missing keys and unloaded managers yield zero values
+	assert.Empty(t, cm.String("missing"))
+	assert.Zero(t, cm.Int("missing"))
+	assert.Nil(t, cm.Value("missing"))
+
+	var nilManager *ConfigManager[TestConfig]
+	assert.False(t, nilManager.Exists("name"))
+	assert.Empty(t, nilManager.String("name"))
+	assert.Empty(t, nilManager.MapKeys("plugins"))
+}
@@ -6,38 +6,38 @@ package vcfg
 import (
 	"context"
 	"fmt"
-	"strings"
+	"io/fs"
+	"log/slog"
+	"time"
 
-	"github.com/knadh/koanf/providers/cliflagv3"
-	"github.com/knadh/koanf/providers/env"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
 	"github.com/urfave/cli/v3"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/nextpkg/vcfg/plugins"
 	"github.com/nextpkg/vcfg/providers"
-	"github.com/nextpkg/vcfg/slogs"
 )
 
 // Builder provides a fluent interface for constructing ConfigManager instances.
 // It allows step-by-step configuration of various sources, plugins, and options
 // before building the final ConfigManager.
 type Builder[T any] struct {
-	// sources holds the configuration sources (file paths, providers, etc.)
-	sources []any
-	// plugins holds manually added plugin entries
-	plugins []plugins.PluginEntry
-	// enableWatch determines if configuration file watching should be enabled
-	enableWatch bool
-	// enablePlugin determines if plugin discovery and initialization should be enabled
-	enablePlugin bool
+	// options holds the sources and feature switches shared with New
+	options
+	// reloadHooks run after every successful reload
+	reloadHooks []ReloadHook[T]
 }
 
 // NewBuilder creates a new Builder instance for configuration type T.
 // The builder is initialized with empty sources and plugins, ready for configuration.
 func NewBuilder[T any]() *Builder[T] {
 	return &Builder[T]{
-		sources: make([]any, 0),
-		plugins: make([]plugins.PluginEntry, 0),
+		options: options{
+			sources: make([]any, 0),
+			plugins: make([]plugins.PluginEntry, 0),
+		},
 	}
 }
 
@@ -45,7 +45,86 @@ func NewBuilder[T any]() *Builder[T] {
 // The file format will be automatically detected based on the file extension.
 // Supported formats include JSON, YAML, TOML, and others supported by koanf.
 func (b *Builder[T]) AddFile(path string) *Builder[T] {
-	b.sources = append(b.sources, path)
+	WithFile(path)(&b.options)
+	return b
+}
+
+// AddFileSet adds a fixed, ordered list of files as one logical configuration source.
+// The files are merged in order, later files overriding earlier ones, and files that
+// do not exist are skipped. Unlike separate AddFile calls, the set is a single source:
+// with watching enabled a change to any of its files reloads the merged whole once.
+func (b *Builder[T]) AddFileSet(paths []string) *Builder[T] {
+	WithFileSet(paths)(&b.options)
+	return b
+}
+
+// AddDir adds the files of a drop-in directory such as "conf.d" as one logical
+// configuration source. Files whose names match pattern, e.g. "*.yaml", are
+// merged in lexical order, so fragments are typically named "10-base.yaml",
+// "20-db.yaml" and so on; an empty pattern matches all files. Each file is
+// parsed according to its extension, and hidden files are ignored.
+//
+// The directory is listed on every load, so with watching enabled, adding,
+// editing or removing a fragment reloads the merged whole once.
+func (b *Builder[T]) AddDir(dir, pattern string) *Builder[T] {
+	WithDir(dir, pattern)(&b.options)
+	return b
+}
+
+// AddDefaultsFile adds a file of default values, e.g. a committed defaults.yaml,
+// as the lowest-priority source: it is merged below all other sources regardless
+// of the order of the builder calls. A missing file is skipped silently. Values
+// from the file take precedence over `default` struct tags. Multiple defaults
+// files are merged in the order they were added.
+func (b *Builder[T]) AddDefaultsFile(path string) *Builder[T] {
+	WithDefaultsFile(path)(&b.options)
+	return b
+}
+
+// AddFS adds the file at path within fsys as a defaults layer, typically
+// defaults compiled into the binary with go:embed:
+//
+//	//go:embed defaults.yaml
+//	var defaultsFS embed.FS
+//
+//	builder.AddFS(defaultsFS, "defaults.yaml").AddFile("/etc/app/config.yaml")
+//
+// Like AddDefaultsFile, the file is merged below all other sources regardless
+// of the order of the builder calls, and its format is detected from the
+// extension. Unlike a defaults file on disk, a missing embedded file makes
+// loading fail, since it points to a mistake in the build.
+func (b *Builder[T]) AddFS(fsys fs.FS, path string) *Builder[T] {
+	WithFS(fsys, path)(&b.options)
+	return b
+}
+
+// WithProfile activates a profile such as "prod": every file added with AddFile
+// is overlaid by its profile variant, the file name with the profile inserted
+// before the extension, e.g. config.prod.yaml over config.yaml. Each overlay is
+// merged right after its base file, so later sources still take precedence,
+// and missing overlays are skipped. An empty profile, e.g. from an unset
+// environment variable, activates none.
+//
+//	builder.AddFile("config.yaml").WithProfile(os.Getenv("APP_PROFILE"))
+func (b *Builder[T]) WithProfile(profile string) *Builder[T] {
+	WithProfile(profile)(&b.options)
+	return b
+}
+
+// WithPriority declares the precedence of sources by kind, from lowest to
+// highest, instead of the order of the Add calls. For example
+//
+//	builder.AddEnv("APP_").AddCliFlags(cmd, ".").AddFile("config.yaml").
+//		WithPriority(vcfg.SourceFile, vcfg.SourceFlags, vcfg.SourceEnv)
+//
+// merges the file first, then the flags, and lets environment variables
+// override both. Sources of unlisted kinds, such as defaults files unless
+// listed, are merged before all listed kinds, and sources of the same kind keep
+// the order they were added in. Providers and Origin reflect the resulting
+// order. Custom providers can declare their kind by implementing
+// providers.KindProvider.
+func (b *Builder[T]) WithPriority(kinds ...string) *Builder[T] {
+	WithPriority(kinds...)(&b.options)
 	return b
 }
 
@@ -53,42 +132,137 @@ func (b *Builder[T]) AddFile(path string) *Builder[T] {
 // Environment variables with the specified prefix will be included,
 // with the prefix stripped and keys converted using dot notation.
 func (b *Builder[T]) AddEnv(prefix string) *Builder[T] {
-	envProvider := env.ProviderWithValue(prefix, ".", func(s string, v string) (string, any) {
-		// Remove the prefix and convert environment variable names to configuration keys
-		// e.g., APP_SERVER_PORT -> server.port
-		key := strings.TrimPrefix(s, prefix)
-		key = strings.ToLower(strings.ReplaceAll(key, "_", "."))
-		return key, v
-	})
-	b.sources = append(b.sources, envProvider)
+	WithEnv(prefix)(&b.options)
+	return b
+}
+
+// AddEnvBlob adds a whole configuration document stored base64-encoded in the
+// environment variable varName, as injected by some PaaS platforms. format names
+// the document's format ("yaml", "yml" or "json"). Unlike AddEnv no key mapping is
+// performed; the decoded document is parsed like a file. An unknown format or a
+// missing or malformed variable makes Build fail.
+func (b *Builder[T]) AddEnvBlob(varName, format string) *Builder[T] {
+	WithEnvBlob(varName, format)(&b.options)
+	return b
+}
+
+// AddBytes adds an in-memory configuration document as a source, parsed in the
+// given format, e.g. "yaml" or "json". It suits embedded defaults and tests:
+//
+//	//go:embed defaults.yaml
+//	var defaults []byte
+//
+//	builder.AddBytes(defaults, "yaml").AddFile("config.yaml")
+//
+// The data is copied, so later changes to the slice have no effect. An unknown
+// format makes Build fail, as does a malformed document.
+func (b *Builder[T]) AddBytes(data []byte, format string) *Builder[T] {
+	WithBytes(data, format)(&b.options)
+	return b
+}
+
+// AddString adds an in-memory configuration document given as a string. It is
+// the string counterpart of AddBytes.
+func (b *Builder[T]) AddString(s, format string) *Builder[T] {
+	WithString(s, format)(&b.options)
+	return b
+}
+
+// AddConsul adds the keys below prefix in the Consul KV store of the agent at addr
+// as a configuration source. Keys are nested by their path segments, e.g.
+// "app/server/port" becomes server.port for the prefix "app/". With watching
+// enabled, blocking queries reload the configuration as soon as a key changes.
+// Use providers.WithConsulToken and providers.WithConsulDatacenter for ACL
+// tokens and datacenter selection.
+func (b *Builder[T]) AddConsul(addr, prefix string, opts ...providers.ConsulOption) *Builder[T] {
+	WithConsul(addr, prefix, opts...)(&b.options)
+	return b
+}
+
+// AddVault adds the secret at path of a Vault KV v2 secrets engine as a
+// configuration source, so credentials stay out of config files. The secret's
+// fields are merged at the root or, with providers.WithVaultKeyPath, below a
+// configuration path. The token defaults to VAULT_TOKEN. With watching enabled
+// the token is renewed before it expires and a new secret version reloads the
+// configuration like a file change.
+func (b *Builder[T]) AddVault(addr, path string, opts ...providers.VaultOption) *Builder[T] {
+	WithVault(addr, path, opts...)(&b.options)
+	return b
+}
+
+// AddRemote adds the JSON or YAML document served at rawURL as a configuration
+// source. The format follows the URL's extension unless set with
+// providers.WithRemoteFormat. Failed fetches are retried with backoff and
+// repeated loads send the previous ETag and Last-Modified values, so unchanged
+// documents are not transferred again. With watching enabled the URL is polled
+// and a changed document reloads the configuration like a file change.
+func (b *Builder[T]) AddRemote(rawURL string, opts ...providers.RemoteOption) *Builder[T] {
+	WithRemote(rawURL, opts...)(&b.options)
+	return b
+}
+
+// AddSopsFile adds a file encrypted with SOPS as a configuration source. The
+// file is decrypted with the sops executable, which finds age, PGP or KMS keys
+// the usual way, e.g. through SOPS_AGE_KEY_FILE. The format follows the file
+// extension unless set with providers.WithSopsFormat. With watching enabled a
+// change of the encrypted file reloads the configuration.
+func (b *Builder[T]) AddSopsFile(path string, opts ...providers.SopsOption) *Builder[T] {
+	WithSopsFile(path, opts...)(&b.options)
 	return b
 }
 
 // AddProvider adds a custom koanf.Provider as a configuration source.
 // This allows integration with any provider that implements the koanf.Provider interface.
 func (b *Builder[T]) AddProvider(provider koanf.Provider) *Builder[T] {
-	b.sources = append(b.sources, provider)
+	WithProvider(provider)(&b.options)
+	return b
+}
+
+// AddNamedProvider adds a custom koanf.Provider under a friendly name.
+// The name identifies the source in Providers(), reload traces and errors.
+// If parser is nil the provider must return parsed data from Read.
+func (b *Builder[T]) AddNamedProvider(name string, provider koanf.Provider, parser koanf.Parser) *Builder[T] {
+	WithNamedProvider(name, provider, parser)(&b.options)
 	return b
 }
 
 // AddCliFlags adds CLI flags as a configuration source using the urfave/cli library.
 // CLI flags are typically added last to ensure they override other configuration sources.
-// The flags are processed through a wrapper that handles key name mapping and flattening.
+// Only flags that were set, on the command line or through their environment sources,
+// are used, so flags left at their defaults don't override file values. The flags of
+// parent commands are included, and flag names map to keys split by delim, e.g.
+// --server.port sets server.port with delim ".".
 func (b *Builder[T]) AddCliFlags(cmd *cli.Command, delim string) *Builder[T] {
-	// Create a wrapped Provider to handle key name mapping
-	cliProvider := providers.NewCliProviderWrapper(cliflagv3.Provider(cmd, delim), cmd.Name, delim)
-
-	slogs.Debug("AddCliFlags: created wrapper", "cmd", cmd.Name, "delim", delim)
+	WithCliFlags(cmd, delim)(&b.options)
+	return b
+}
 
-	b.sources = append(b.sources, cliProvider)
+// AddPFlags adds the flags of a spf13/pflag flag set, such as cmd.Flags() of a
+// cobra command, as a configuration source. Only flags set on the command line
+// are used, so flags left at their defaults don't override file or environment
+// values. Flag names map to dotted keys, e.g. --server.port sets server.port.
+// Add the flags last so that they override the other sources.
+func (b *Builder[T]) AddPFlags(flagSet *pflag.FlagSet) *Builder[T] {
+	WithPFlags(flagSet)(&b.options)
 	return b
 }
 
 // WithWatch enables configuration file watching for automatic reloading.
 // When enabled, the ConfigManager will monitor configuration files for changes
 // and automatically reload the configuration when modifications are detected.
-func (b *Builder[T]) WithWatch() *Builder[T] {
-	b.enableWatch = true
+// Options such as WithDebounce tune how change events are coalesced.
+func (b *Builder[T]) WithWatch(opts ...WatchOption) *Builder[T] {
+	WithWatch(opts...)(&b.options)
+	return b
+}
+
+// WithWatchDebounce enables watching and sets the debounce interval: change
+// events arriving within d of each other, such as the several writes of an
+// editor's save or a deployment replacing files one by one, collapse into a
+// single reload once no event arrived for d. It is shorthand for
+// WithWatch(WithDebounce(d)).
+func (b *Builder[T]) WithWatchDebounce(d time.Duration) *Builder[T] {
+	WithWatchDebounce(d)(&b.options)
 	return b
 }
 
@@ -96,7 +270,306 @@ func (b *Builder[T]) WithWatch() *Builder[T] {
 // When enabled, the ConfigManager will automatically discover plugin configurations
 // in the loaded config and initialize the corresponding plugin instances.
 func (b *Builder[T]) WithPlugin() *Builder[T] {
-	b.enablePlugin = true
+	WithPlugins()(&b.options)
+	return b
+}
+
+// RequireKeys requires the given key paths, e.g. "server.port", to be set by at
+// least one source. Presence is checked after all sources are merged, so a key
+// explicitly set to a zero value such as 0 or "" satisfies the check, while the
+// struct tag `validate:"required"` would reject it. Loads and reloads with missing
+// keys fail with a validation ConfigError listing them.
+func (b *Builder[T]) RequireKeys(paths ...string) *Builder[T] {
+	WithRequiredKeys(paths...)(&b.options)
+	return b
+}
+
+// WithFormatSniffing detects whether sources of unknown format, such as custom
+// providers returning raw bytes or files without a known extension, contain JSON
+// or YAML by looking at their content. Without it such sources are parsed as JSON
+// (providers) or YAML (files). Explicitly named formats are not affected.
+func (b *Builder[T]) WithFormatSniffing() *Builder[T] {
+	WithFormatSniffing()(&b.options)
+	return b
+}
+
+// WithIncludes lets the files added with AddFile include other files, so a
+// large configuration can be split across files:
+//
+//	include: [database.yaml, secrets.yaml, "features/*.yaml"]
+//
+// Paths are relative to the including file and may be glob patterns. Included
+// files are merged in the order listed and may include further files; include
+// cycles fail the load. The values of the including file take precedence over
+// the files it includes, and the include key itself is not part of the merged
+// configuration. With watching enabled, editing an included file reloads the
+// configuration as well.
+func (b *Builder[T]) WithIncludes() *Builder[T] {
+	WithIncludes()(&b.options)
+	return b
+}
+
+// WithValidateTag reads validation rules from the struct tag name instead of
+// `validate`, e.g. for projects that already use `validate` for another library.
+// The setting applies to this manager only; use validator.SetTagName to change
+// the tag for all managers.
+func (b *Builder[T]) WithValidateTag(name string) *Builder[T] {
+	WithValidateTag(name)(&b.options)
+	return b
+}
+
+// WithKeyNormalizer rewrites the keys of every source with fn before the sources
+// are merged, so fragments written in different styles or formats align, e.g.
+// strings.ToLower merges "Server.Port" from YAML with "server.port" from JSON.
+// fn is applied to each key segment separately and should not introduce dots.
+func (b *Builder[T]) WithKeyNormalizer(fn func(string) string) *Builder[T] {
+	WithKeyNormalizer(fn)(&b.options)
+	return b
+}
+
+// WithMergeStrategy sets how later sources are combined with earlier ones at
+// path and below it. By default maps are merged key by key and every other
+// value, including lists, is replaced by the later source. MergeAppend appends
+// lists instead, and MergeReplace replaces maps wholesale:
+//
+//	builder.
+//		WithMergeStrategy("allowed_origins", vcfg.MergeAppend).
+//		WithMergeStrategy("routes", vcfg.MergeReplace)
+//
+// The closest configured path at or above a key decides, so the empty path sets
+// the strategy for the whole configuration and deeper paths refine it.
+func (b *Builder[T]) WithMergeStrategy(path string, strategy MergeStrategy) *Builder[T] {
+	WithMergeStrategy(path, strategy)(&b.options)
+	return b
+}
+
+// WithMigration adds a migration that rewrites the merged values of all sources
+// before they are unmarshaled into T, so config files written for an older schema
+// still load, e.g. by moving a renamed key:
+//
+//	b.WithMigration(func(k *koanf.Koanf) error {
+//		if k.Exists("db.addr") {
+//			_ = k.Set("database.host", k.Get("db.addr"))
+//			k.Delete("db.addr")
+//		}
+//		return nil
+//	})
+//
+// Migrations run on every load and reload in the order they were added, after
+// the migrations added with WithSchemaMigration.
+func (b *Builder[T]) WithMigration(fn Migration) *Builder[T] {
+	WithMigration(fn)(&b.options)
+	return b
+}
+
+// WithSchemaMigration adds a migration that upgrades configs whose schema_version
+// key equals from to version from+1. Migrations for consecutive versions chain,
+// so a config of any older version is upgraded step by step; afterwards
+// schema_version holds the latest version. Configs without schema_version are
+// treated as version 0.
+func (b *Builder[T]) WithSchemaMigration(from int, fn Migration) *Builder[T] {
+	WithSchemaMigration(from, fn)(&b.options)
+	return b
+}
+
+// WithEnvExpansion expands references to environment variables in the string
+// values of all sources before they are unmarshaled, e.g.
+//
+//	database:
+//	  password: ${DB_PASSWORD}
+//	  host: ${DB_HOST:-localhost}
+//
+// ${NAME} expands to the variable's value or "" if it is unset, ${NAME:-default}
+// falls back to default if the variable is unset or empty and ${NAME-default}
+// only if it is unset. Write $${ for a literal ${. The expansion happens on every
+// load and reload; the merged values returned by MarshalMerged keep the
+// references, so expanded secrets are not exposed there.
+func (b *Builder[T]) WithEnvExpansion() *Builder[T] {
+	WithEnvExpansion()(&b.options)
+	return b
+}
+
+// WithInterpolation expands references to other keys of the merged
+// configuration in string values, e.g.
+//
+//	database:
+//	  host: db.internal
+//	  port: 5432
+//	  dsn: postgres://${database.host}:${database.port}/app
+//
+// Referenced values are expanded first, so references can be chained; reference
+// cycles and references to unknown dotted keys fail the load. A value consisting
+// of a single reference keeps the referenced value's type. ${key:-default} falls
+// back to default if the key is missing or empty. Combined with WithEnvExpansion,
+// a reference naming an existing key takes precedence over an environment
+// variable of the same name. Like environment expansion, interpolation happens
+// on every load and MarshalMerged shows the unexpanded values.
+func (b *Builder[T]) WithInterpolation() *Builder[T] {
+	WithInterpolation()(&b.options)
+	return b
+}
+
+// WithDecryptor decrypts individually encrypted string values before they are
+// unmarshaled, e.g.
+//
+//	database:
+//	  password: enc:q2Nw8rYH1cd0...
+//
+// Every string value, including the string elements of lists, that starts with
+// the prefix "enc:" (see WithDecryptPrefix) is passed to decrypt without the
+// prefix and replaced by the returned plaintext. NewAESDecryptor provides a
+// decryptor for AES-GCM encrypted values. Decryption runs on every load and
+// reload after environment expansion and interpolation, so a reference may
+// resolve to an encrypted value; the merged values returned by MarshalMerged keep
+// the ciphertext. A failed decryption fails the load and names the key.
+func (b *Builder[T]) WithDecryptor(decrypt Decryptor) *Builder[T] {
+	WithDecryptor(decrypt)(&b.options)
+	return b
+}
+
+// WithDecryptPrefix sets the prefix that marks the values passed to the
+// decryptor configured with WithDecryptor. The default is "enc:".
+func (b *Builder[T]) WithDecryptPrefix(prefix string) *Builder[T] {
+	WithDecryptPrefix(prefix)(&b.options)
+	return b
+}
+
+// WithPluginShutdownTimeout bounds how long each plugin may take to shut down
+// when the manager is closed or plugins are stopped; the default is 30 seconds.
+// A plugin that exceeds it is logged and skipped so the remaining plugins still
+// shut down, and Close reports a plugins.ShutdownTimeoutError for it. A timeout
+// of zero or less only applies the deadline of the context passed to
+// CloseWithContext.
+func (b *Builder[T]) WithPluginShutdownTimeout(timeout time.Duration) *Builder[T] {
+	WithPluginShutdownTimeout(timeout)(&b.options)
+	return b
+}
+
+// WithDecodeHook adds a mapstructure decode hook that converts merged values to
+// custom field types while decoding, e.g. enums from their names:
+//
+//	b.WithDecodeHook(func(from, to reflect.Type, data any) (any, error) {
+//		if from.Kind() != reflect.String || to != reflect.TypeOf(Level(0)) {
+//			return data, nil
+//		}
+//		return ParseLevel(data.(string))
+//	})
+//
+// Hooks run in the order they were added, before the built-in hooks for
+// durations, byte sizes such as "512MB" and encoding.TextUnmarshaler types
+// such as net.IP, so they may also replace those conversions. Helpers such as
+// mapstructure.StringToSliceHookFunc can be passed as they are. Hooks apply to
+// Get, Sub and UnmarshalInto alike.
+func (b *Builder[T]) WithDecodeHook(hook mapstructure.DecodeHookFunc) *Builder[T] {
+	WithDecodeHook(hook)(&b.options)
+	return b
+}
+
+// WithStrictKeys reports keys of the merged configuration that match no field
+// of T, such as the typo "databse" for "database". By default, or with
+// StrictKeysFail, loads and reloads with unknown keys fail; with StrictKeysWarn
+// the keys are logged and delivered as an EventValidationWarning instead:
+//
+//	b.WithStrictKeys()                    // unknown keys: databse (did you mean database?)
+//	b.WithStrictKeys(vcfg.StrictKeysWarn) // warn only
+//
+// Keys below maps and interface fields are not checked, and a field with the
+// ",remain" option accepts all keys of its struct.
+func (b *Builder[T]) WithStrictKeys(mode ...StrictKeysMode) *Builder[T] {
+	WithStrictKeys(mode...)(&b.options)
+	return b
+}
+
+// WithDeprecatedKeyMapping copies the value of a deprecated key to the key that
+// replaces it, so configs using the old name keep working after a rename:
+//
+//	type ServerConfig struct {
+//		Addr       string `koanf:"addr" deprecated:"use server.listen_addr"`
+//		ListenAddr string `koanf:"listen_addr"`
+//	}
+//
+// The replacement is the key following "use " in the `deprecated` tag. A value
+// set for the replacement itself is never overwritten. Deprecated keys are
+// reported in the log and as EventDeprecatedKey whether or not they are mapped.
+func (b *Builder[T]) WithDeprecatedKeyMapping() *Builder[T] {
+	WithDeprecatedKeyMapping()(&b.options)
+	return b
+}
+
+// WithStrictReload gates reloads on validation: a new configuration that fails to
+// load or validate is rejected as a whole. The previous configuration and its
+// merged values stay active, plugins and change subscribers are not touched, and
+// a ReloadRejected event is delivered to the callbacks registered with
+// ConfigManager.OnReloadRejected.
+func (b *Builder[T]) WithStrictReload() *Builder[T] {
+	WithStrictReload()(&b.options)
+	return b
+}
+
+// WithObserver registers observer to be notified after every reload attempt,
+// every error while watching a source and every plugin Startup, Reload and
+// Shutdown, e.g. to export metrics with the metrics package. Observers are
+// notified in registration order.
+func (b *Builder[T]) WithObserver(observer Observer) *Builder[T] {
+	WithObserver(observer)(&b.options)
+	return b
+}
+
+// WithTracer records OpenTelemetry spans with tracer, so slow sources and
+// plugins are visible in traces:
+//
+//	vcfg.reload          a reload, with the triggering sources
+//	vcfg.load            loading and decoding the configuration
+//	vcfg.load_source     reading and merging one source
+//	vcfg.validate        validating the decoded configuration
+//	vcfg.plugin.startup  a plugin Startup, likewise reload and shutdown
+//
+// Failed operations are marked with an error status. Create the tracer from
+// a provider, e.g. otel.Tracer("github.com/nextpkg/vcfg").
+func (b *Builder[T]) WithTracer(tracer trace.Tracer) *Builder[T] {
+	WithTracer(tracer)(&b.options)
+	return b
+}
+
+// WithLogger routes the log output of the manager and its plugin management
+// to logger, e.g. to use the application's handler and level:
+//
+//	logger := slog.New(handler).With("component", "config")
+//	cm, err := vcfg.NewBuilder[AppConfig]().WithLogger(logger).Build(ctx)
+//
+// The process-wide logger of the slogs package, used by default, is left
+// untouched. Plugin type registration, which happens before any manager
+// exists, and plugins themselves keep logging to their own loggers.
+func (b *Builder[T]) WithLogger(logger *slog.Logger) *Builder[T] {
+	WithLogger(logger)(&b.options)
+	return b
+}
+
+// WithSnapshotRetention sets how many published configurations the manager keeps
+// in its snapshot history, including the current one; the default is 10. With
+// n snapshots Rollback can go back up to n-1 steps.
+func (b *Builder[T]) WithSnapshotRetention(n int) *Builder[T] {
+	WithSnapshotRetention(n)(&b.options)
+	return b
+}
+
+// WithLazyLoad defers loading the configuration until the first call to
+// ConfigManager.GetOrLoad, so commands that exit early never read the sources.
+// Build still creates the source providers, but Get returns nil until the first load.
+// It cannot be combined with WithPlugin.
+func (b *Builder[T]) WithLazyLoad() *Builder[T] {
+	WithLazyLoad()(&b.options)
+	return b
+}
+
+// WithReloadHook registers a hook that runs at the end of every successful reload,
+// whether or not anything changed, e.g. to bump a generation counter or notify a
+// sidecar. Hooks run in registration order; an error returned by a hook is logged
+// but does not revert the new configuration.
+func (b *Builder[T]) WithReloadHook(hook ReloadHook[T]) *Builder[T] {
+	if hook != nil {
+		b.reloadHooks = append(b.reloadHooks, hook)
+	}
 	return b
 }
 
@@ -109,23 +582,82 @@ func (b *Builder[T]) WithPlugin() *Builder[T] {
 //
 // Returns a fully configured ConfigManager or an error if building fails.
 func (b *Builder[T]) Build(ctx context.Context) (*ConfigManager[T], error) {
-	if len(b.sources) == 0 {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if len(b.sources)+len(b.defaultSources) == 0 {
 		return nil, fmt.Errorf("at least one configuration source is required")
 	}
 
+	if b.lazyLoad && b.enablePlugin {
+		return nil, fmt.Errorf("lazy loading cannot be combined with plugins")
+	}
+
 	// Create configuration manager
-	cm := newManager[T](b.sources...)
+	factory := providers.NewProviderFactory()
+	factory.SetFormatSniffing(b.formatSniffing)
+	factory.SetIncludeKey(b.includeKey)
+	profileSources, err := withProfileOverlays(b.sources, b.profile)
+	if err != nil {
+		return nil, err
+	}
+	sources := append(append([]any{}, b.defaultSources...), profileSources...)
+	cm, err := createManagerWithFactory[T](factory, sources...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create configuration manager: %w", err)
+	}
+	sortByPriority(cm.providers, b.priority)
+	cm.reloadHooks = b.reloadHooks
+	cm.requiredKeys = b.requiredKeys
+	cm.validateTag = b.validateTag
+	cm.keyNormalizer = b.keyNormalizer
+	cm.mergeStrategies = b.mergeStrategies
+	cm.migrations = b.migrations
+	cm.decodeHooks = b.decodeHooks
+	cm.mapDeprecated = b.mapDeprecated
+	cm.strictKeys = b.strictKeys
+	cm.strictKeysMode = b.strictKeysMode
+	cm.snapshotRetention = b.snapshotRetention
+	cm.strictReload = b.strictReload
+	cm.expandEnv = b.expandEnv
+	cm.interpolate = b.interpolate
+	cm.decryptor = b.decryptor
+	cm.decryptPrefix = b.decryptPrefix
+	if b.pluginShutdownTimeout != nil {
+		cm.pluginManager.SetShutdownTimeout(*b.pluginShutdownTimeout)
+	}
+	if b.logger != nil {
+		cm.logger = b.logger
+		cm.pluginManager.SetLogger(b.logger)
+	}
+	if b.tracer != nil {
+		cm.tracer = b.tracer
+		cm.pluginManager.SetTracer(b.tracer)
+	}
+	if len(b.observers) > 0 {
+		cm.observers = append(cm.observers, b.observers...)
+		cm.pluginManager.SetObserver(cm.observers)
+	}
+
+	if b.lazyLoad {
+		if b.enableWatch {
+			cm.EnableWatch(b.watchOptions...)
+		}
+		return cm, nil
+	}
 
 	// Load initial configuration
-	cfg, err := cm.load()
+	cfg, err := cm.load(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load initial configuration: %w", err)
 	}
-	cm.cfg.Store(cfg)
+	cm.storeConfig(cfg)
+	cm.emitLoaded()
 
 	// Enable plugins
 	if b.enablePlugin {
-		err = cm.pluginManager.DiscoverAndRegister(cfg)
+		err = cm.EnablePlugins()
 		if err != nil {
 			return nil, fmt.Errorf("failed to register plugins: %w", err)
 		}
@@ -138,7 +670,7 @@ func (b *Builder[T]) Build(ctx context.Context) (*ConfigManager[T], error) {
 
 	// Enable watching
 	if b.enableWatch {
-		cm.EnableWatch()
+		cm.EnableWatch(b.watchOptions...)
 	}
 
 	return cm, nil
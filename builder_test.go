@@ -1,16 +1,27 @@
 package vcfg
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"github.com/knadh/koanf/parsers/json"
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v3"
+
+	"github.com/nextpkg/vcfg/providers"
 )
 
 type BuilderTestConfig struct {
@@ -38,6 +49,74 @@ func TestBuilder_AddFile(t *testing.T) {
 	assert.Equal(t, testFile, builder.sources[0])
 }
 
+func TestBuilder_AddFile_HCL(t *testing.T) {
+	type HCLConfig struct {
+		Name   string `koanf:"name"`
+		Server struct {
+			Port    int           `koanf:"port"`
+			Timeout time.Duration `koanf:"timeout"`
+		} `koanf:"server"`
+		Services map[string]struct {
+			Replicas int `koanf:"replicas"`
+		} `koanf:"service"`
+	}
+
+	configFile := filepath.Join(t.TempDir(), "config.hcl")
+	writeFile(t, configFile, `name = "app"
+
+server {
+  port    = 8080
+  timeout = "5s"
+}
+
+service "web" {
+  replicas = 3
+}
+`)
+
+	cm, err := NewBuilder[HCLConfig]().AddFile(configFile).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, "app", cfg.Name)
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, 5*time.Second, cfg.Server.Timeout)
+	assert.Equal(t, 3, cfg.Services["web"].Replicas)
+}
+
+func TestBuilder_AddFile_LegacyFormats(t *testing.T) {
+	type LegacyConfig struct {
+		Name   string `koanf:"name"`
+		Server struct {
+			Host    string        `koanf:"host"`
+			Port    int           `koanf:"port"`
+			TLS     bool          `koanf:"tls"`
+			Timeout time.Duration `koanf:"timeout"`
+		} `koanf:"server"`
+	}
+
+	dir := t.TempDir()
+	iniFile := filepath.Join(dir, "legacy.ini")
+	propertiesFile := filepath.Join(dir, "application.properties")
+	writeFile(t, iniFile, "name = legacy\n\n[server]\nhost = example.com\nport = 8080\ntls = true\n")
+	writeFile(t, propertiesFile, "server.port=9090\nserver.timeout=5s\n")
+
+	cm, err := NewBuilder[LegacyConfig]().
+		AddFile(iniFile).
+		AddFile(propertiesFile).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, "legacy", cfg.Name)
+	assert.Equal(t, "example.com", cfg.Server.Host)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.True(t, cfg.Server.TLS)
+	assert.Equal(t, 5*time.Second, cfg.Server.Timeout)
+}
+
 func TestBuilder_AddEnv(t *testing.T) {
 	builder := NewBuilder[BuilderTestConfig]()
 	prefix := "TEST_"
@@ -46,8 +125,11 @@ func TestBuilder_AddEnv(t *testing.T) {
 	assert.Equal(t, builder, result) // Should return self for chaining
 	assert.Len(t, builder.sources, 1)
 
-	// Verify the provider is of correct type
-	_, ok := builder.sources[0].(*env.Env)
+	// Verify the provider is of correct type and named by its prefix
+	source, ok := builder.sources[0].(providers.ProviderConfig)
+	require.True(t, ok)
+	assert.Equal(t, "env:TEST_", source.Name)
+	_, ok = source.Provider.(*env.Env)
 	assert.True(t, ok)
 }
 
@@ -93,6 +175,88 @@ func TestBuilder_AddEnv_KeyMapping(t *testing.T) {
 	assert.Equal(t, "testdb", cfg.Database.Name)
 }
 
+func TestBuilder_AddEnv_MultiplePrefixes(t *testing.T) {
+	type MultiEnvConfig struct {
+		Server struct {
+			Host string `json:"host"`
+			Port int    `json:"port"`
+		} `json:"server"`
+		Region string `json:"region"`
+	}
+
+	t.Setenv("COMMON_SERVER_HOST", "common-host")
+	t.Setenv("COMMON_SERVER_PORT", "8000")
+	t.Setenv("COMMON_REGION", "eu")
+	t.Setenv("APP_SERVER_PORT", "9090")
+
+	// Later prefixes override earlier ones for the same key
+	cm, err := NewBuilder[MultiEnvConfig]().
+		AddEnv("COMMON_").
+		AddEnv("APP_").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, "common-host", cfg.Server.Host)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, "eu", cfg.Region)
+
+	names := []string{cm.Providers()[0].Name, cm.Providers()[1].Name}
+	assert.Equal(t, []string{"env:COMMON_", "env:APP_"}, names)
+
+	// Reversing the order reverses the precedence
+	cm2, err := NewBuilder[MultiEnvConfig]().
+		AddEnv("APP_").
+		AddEnv("COMMON_").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm2.Close()
+	assert.Equal(t, 8000, cm2.Get().Server.Port)
+}
+
+func TestBuilder_AddConsul(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/kv/app/", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("X-Consul-Token"))
+		w.Header().Set("X-Consul-Index", "7")
+		_, _ = w.Write([]byte(`[{"Key":"app/name","Value":"Y29uc3Vs"},{"Key":"app/port","Value":"ODA4MA=="}]`))
+	}))
+	defer server.Close()
+
+	cm, err := NewBuilder[BuilderTestConfig]().
+		AddConsul(server.URL, "app/", providers.WithConsulToken("token")).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, "consul", cm.Get().Name)
+	assert.Equal(t, 8080, cm.Get().Port)
+	assert.Equal(t, "consul:app/", cm.Providers()[0].Name)
+	assert.True(t, cm.Providers()[0].Watchable)
+}
+
+func TestBuilder_AddRemote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("name: remote\nport: 9000\n"))
+	}))
+	defer server.Close()
+
+	cm, err := NewBuilder[BuilderTestConfig]().
+		AddRemote(server.URL + "/app.yaml").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, "remote", cm.Get().Name)
+	assert.Equal(t, 9000, cm.Get().Port)
+	assert.Equal(t, "remote:"+server.URL+"/app.yaml", cm.Providers()[0].Name)
+	assert.True(t, cm.Providers()[0].Watchable)
+
+	_, err = NewBuilder[BuilderTestConfig]().AddRemote("ftp://host/app.yaml").Build(context.Background())
+	assert.ErrorContains(t, err, "must use http or https")
+}
+
 func TestBuilder_AddProvider(t *testing.T) {
 	builder := NewBuilder[BuilderTestConfig]()
 	provider := rawbytes.Provider([]byte(`{"name":"test"}`))
@@ -120,6 +284,87 @@ func TestBuilder_AddCliFlags(t *testing.T) {
 	assert.Len(t, builder.sources, 1)
 }
 
+func TestBuilder_AddCliFlags_OnlySet(t *testing.T) {
+	type CliConfig struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+		Host string `json:"host"`
+	}
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("name: file\nport: 8080\nhost: file-host\n"), 0644))
+
+	var cfg *CliConfig
+	cmd := &cli.Command{
+		Name: "app",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "name", Value: "flag-default"},
+			&cli.IntFlag{Name: "port", Value: 80},
+		},
+		Commands: []*cli.Command{{
+			Name:  "serve",
+			Flags: []cli.Flag{&cli.StringFlag{Name: "host", Value: "flag-host"}},
+			Action: func(ctx context.Context, cmd *cli.Command) error {
+				cm, err := NewBuilder[CliConfig]().
+					AddFile(configFile).
+					AddCliFlags(cmd, ".").
+					Build(ctx)
+				if err != nil {
+					return err
+				}
+				defer cm.Close()
+				cfg = cm.Get()
+				return nil
+			},
+		}},
+	}
+
+	require.NoError(t, cmd.Run(context.Background(), []string{"app", "--port", "9090", "serve"}))
+	// Flags left at their defaults keep the file values
+	assert.Equal(t, "file", cfg.Name)
+	assert.Equal(t, 9090, cfg.Port)
+	assert.Equal(t, "file-host", cfg.Host)
+
+	require.NoError(t, cmd.Run(context.Background(), []string{"app", "serve", "--host", "cli-host"}))
+	assert.Equal(t, "cli-host", cfg.Host)
+}
+
+func TestBuilder_AddPFlags(t *testing.T) {
+	type PFlagsConfig struct {
+		Name   string `json:"name"`
+		Server struct {
+			Port int `json:"port"`
+		} `json:"server"`
+		Debug bool `json:"debug"`
+	}
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("name: file\nserver:\n  port: 8080\n"), 0644))
+
+	fs := pflag.NewFlagSet("serve", pflag.ContinueOnError)
+	fs.String("name", "flag-default", "")
+	fs.Int("server.port", 80, "")
+	fs.Bool("debug", false, "")
+	require.NoError(t, fs.Parse([]string{"--server.port=9090", "--debug"}))
+
+	cm, err := NewBuilder[PFlagsConfig]().
+		AddFile(configFile).
+		AddPFlags(fs).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	// The unset flag keeps the file value instead of its default
+	assert.Equal(t, "file", cfg.Name)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, SourceFlags, cm.Providers()[1].Kind)
+
+	_, err = New[PFlagsConfig](WithPFlags(nil))
+	assert.Error(t, err)
+}
+
 func TestBuilder_WithWatch(t *testing.T) {
 	builder := NewBuilder[BuilderTestConfig]()
 	assert.False(t, builder.enableWatch)
@@ -250,3 +495,451 @@ func TestBuilder_ChainedCalls(t *testing.T) {
 	cm.DisableWatch()
 	cm.Close()
 }
+
+func TestBuilder_WithWatchOptions(t *testing.T) {
+	builder := NewBuilder[BuilderTestConfig]()
+
+	result := builder.WithWatch(WithDebounce(0))
+	assert.Equal(t, builder, result)
+	assert.True(t, builder.enableWatch)
+	assert.Len(t, builder.watchOptions, 1)
+}
+
+func TestBuilder_AddNamedProvider(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"name":"file","port":80}`), 0644))
+
+	cm, err := NewBuilder[BuilderTestConfig]().
+		AddFile(configFile).
+		AddNamedProvider("settings-service", rawbytes.Provider([]byte(`{"port":9090}`)), json.Parser()).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, "file", cm.Get().Name)
+	assert.Equal(t, 9090, cm.Get().Port)
+
+	sources := cm.Providers()
+	require.Len(t, sources, 2)
+
+	absPath, err := filepath.Abs(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, SourceInfo{Name: absPath, Type: "*providers.FileWatcher", Watchable: true, Kind: SourceFile}, sources[0])
+	assert.Equal(t, SourceInfo{Name: "settings-service", Type: "*rawbytes.RawBytes", Watchable: false, Kind: SourceCustom}, sources[1])
+}
+
+func TestBuilder_RequireKeys(t *testing.T) {
+	// Present but zero satisfies the requirement
+	cm, err := NewBuilder[BuilderTestConfig]().
+		AddNamedProvider("settings", rawbytes.Provider([]byte(`{"name":"","port":0}`)), json.Parser()).
+		RequireKeys("name", "port").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+	assert.Equal(t, 0, cm.Get().Port)
+
+	// Keys may be provided by different sources
+	cm2, err := New[BuilderTestConfig](
+		WithProvider(rawbytes.Provider([]byte(`{"name":"app"}`))),
+		WithProvider(rawbytes.Provider([]byte(`{"port":80}`))),
+		WithRequiredKeys("name", "port"),
+	)
+	require.NoError(t, err)
+	defer cm2.Close()
+
+	// Absent keys are reported together
+	_, err = NewBuilder[BuilderTestConfig]().
+		AddNamedProvider("settings", rawbytes.Provider([]byte(`{"name":"app"}`)), json.Parser()).
+		RequireKeys("name", "port", "server.host").
+		Build(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeValidationFailure})
+	assert.ErrorContains(t, err, "missing required keys: port, server.host")
+}
+
+func TestBuilder_AddDefaultsFile(t *testing.T) {
+	type DefaultsFileConfig struct {
+		Name    string `json:"name" default:"tag-name"`
+		Port    int    `json:"port" default:"80"`
+		Host    string `json:"host" default:"tag-host"`
+		Timeout string `json:"timeout" default:"1s"`
+	}
+
+	dir := t.TempDir()
+	defaultsFile := filepath.Join(dir, "defaults.yaml")
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(defaultsFile, []byte("name: default-name\nport: 8000\nhost: default-host\n"), 0644))
+	require.NoError(t, os.WriteFile(configFile, []byte("name: app\n"), 0644))
+
+	// The defaults file is merged below the config file even when added last
+	cm, err := NewBuilder[DefaultsFileConfig]().
+		AddFile(configFile).
+		AddEnv("VCFG_DEFAULTS_TEST_").
+		AddDefaultsFile(defaultsFile).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, "app", cfg.Name)
+	assert.Equal(t, 8000, cfg.Port)
+	assert.Equal(t, "default-host", cfg.Host)
+	assert.Equal(t, "1s", cfg.Timeout)
+	assert.Equal(t, "defaults:"+defaultsFile, cm.Providers()[0].Name)
+
+	// A missing defaults file is skipped
+	cm2, err := New[DefaultsFileConfig](
+		WithDefaultsFile(filepath.Join(dir, "missing.yaml")),
+		WithFile(configFile),
+	)
+	require.NoError(t, err)
+	defer cm2.Close()
+	assert.Equal(t, "app", cm2.Get().Name)
+	assert.Equal(t, 80, cm2.Get().Port)
+}
+
+func TestBuilder_AddFS(t *testing.T) {
+	type FSConfig struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+
+	fsys := fstest.MapFS{
+		"config/defaults.json": {Data: []byte(`{"name": "embedded", "port": 8000}`)},
+	}
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("name: app\n"), 0644))
+
+	// The embedded file is merged below the config file even when added last
+	cm, err := NewBuilder[FSConfig]().
+		AddFile(configFile).
+		AddFS(fsys, "config/defaults.json").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, "app", cm.Get().Name)
+	assert.Equal(t, 8000, cm.Get().Port)
+	assert.Equal(t, SourceInfo{Name: "fs:config/defaults.json", Type: "*providers.FSFile", Kind: SourceDefaults}, cm.Providers()[0])
+
+	// A missing embedded file fails the build
+	_, err = New[FSConfig](WithFS(fsys, "config/missing.json"))
+	assert.Error(t, err)
+
+	// Paths must use the io/fs form
+	_, err = New[FSConfig](WithFS(fsys, "/config/defaults.json"))
+	assert.Error(t, err)
+}
+
+func TestBuilder_WithFormatSniffing(t *testing.T) {
+	yamlSource := rawbytes.Provider([]byte("name: sniffed\nport: 8080\n"))
+
+	// Without sniffing the bytes are parsed as JSON and fail
+	_, err := NewBuilder[BuilderTestConfig]().AddProvider(yamlSource).Build(context.Background())
+	assert.Error(t, err)
+
+	cm, err := NewBuilder[BuilderTestConfig]().
+		AddProvider(rawbytes.Provider([]byte(`{"enabled": true}`))).
+		AddProvider(yamlSource).
+		WithFormatSniffing().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, BuilderTestConfig{Name: "sniffed", Port: 8080, Enabled: true}, *cm.Get())
+
+	cm2, err := New[BuilderTestConfig](WithProvider(yamlSource), WithFormatSniffing())
+	require.NoError(t, err)
+	defer cm2.Close()
+	assert.Equal(t, "sniffed", cm2.Get().Name)
+}
+
+func TestBuilder_WithValidateTag(t *testing.T) {
+	type TaggedConfig struct {
+		Name string `json:"name" check:"required" validate:"min=100"`
+	}
+
+	cm, err := NewBuilder[TaggedConfig]().
+		AddProvider(rawbytes.Provider([]byte(`{"name":"app"}`))).
+		WithValidateTag("check").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+	assert.Equal(t, "app", cm.Get().Name)
+
+	_, err = New[TaggedConfig](WithProvider(rawbytes.Provider([]byte(`{}`))), WithValidateTag("check"))
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeValidationFailure})
+
+	// Managers without the option keep using the validate tag
+	_, err = NewBuilder[TaggedConfig]().AddProvider(rawbytes.Provider([]byte(`{"name":"app"}`))).Build(context.Background())
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeValidationFailure})
+}
+
+func TestBuilder_BuildUnsupportedSource(t *testing.T) {
+	builder := NewBuilder[BuilderTestConfig]()
+	builder.sources = append(builder.sources, 42)
+
+	assert.NotPanics(t, func() {
+		cm, err := builder.Build(context.Background())
+		assert.Error(t, err)
+		assert.Nil(t, cm)
+	})
+}
+
+func TestBuilder_BuildNonStructType(t *testing.T) {
+	// The type is checked before any source is read
+	configFile := "/path/to/config.yaml"
+
+	_, err := NewBuilder[int]().AddFile(configFile).Build(context.Background())
+	assert.ErrorContains(t, err, "configuration type int must be a struct")
+
+	_, err = New[*BuilderTestConfig](WithFile(configFile))
+	assert.ErrorContains(t, err, "must be a struct, got ptr")
+
+	_, err = Load[map[string]any](configFile)
+	assert.Error(t, err)
+
+	var cfgErr *ConfigError
+	if assert.ErrorAs(t, err, &cfgErr) {
+		assert.Equal(t, ErrorTypeValidationFailure, cfgErr.Type)
+	}
+
+	assert.Panics(t, func() { MustLoad[[]string](configFile) })
+}
+
+func TestBuilder_AddEnvBlob(t *testing.T) {
+	blob := "name: from-blob\nport: 7070\nenabled: true\n"
+	t.Setenv("TEST_CONFIG_BLOB", base64.StdEncoding.EncodeToString([]byte(blob)))
+
+	cm, err := NewBuilder[BuilderTestConfig]().
+		AddEnvBlob("TEST_CONFIG_BLOB", "yaml").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	config := cm.Get()
+	assert.Equal(t, "from-blob", config.Name)
+	assert.Equal(t, 7070, config.Port)
+	assert.True(t, config.Enabled)
+
+	sources := cm.Providers()
+	require.Len(t, sources, 1)
+	assert.Equal(t, "env:TEST_CONFIG_BLOB", sources[0].Name)
+}
+
+func TestBuilder_AddEnvBlob_Errors(t *testing.T) {
+	t.Setenv("TEST_CONFIG_BLOB", base64.StdEncoding.EncodeToString([]byte("name: x\n")))
+
+	_, err := NewBuilder[BuilderTestConfig]().
+		AddEnvBlob("TEST_CONFIG_BLOB", "xml").
+		Build(context.Background())
+	assert.ErrorContains(t, err, "unsupported configuration format")
+
+	_, err = NewBuilder[BuilderTestConfig]().
+		AddEnvBlob("TEST_CONFIG_BLOB_MISSING", "yaml").
+		Build(context.Background())
+	assert.ErrorContains(t, err, "not set")
+}
+
+func TestBuilder_AddFileSet_ReloadsOncePerEdit(t *testing.T) {
+	type FileSetConfig struct {
+		Name    string `koanf:"name"`
+		Port    int    `koanf:"port"`
+		Enabled bool   `koanf:"enabled"`
+		Marker  string `koanf:"marker"`
+	}
+
+	tempDir := t.TempDir()
+	files := []string{
+		filepath.Join(tempDir, "base.yaml"),
+		filepath.Join(tempDir, "env.yaml"),
+		filepath.Join(tempDir, "missing.yaml"),
+		filepath.Join(tempDir, "local.yaml"),
+	}
+	writeFile(t, files[0], "name: base\nport: 80\n")
+	writeFile(t, files[1], "port: 8080\n")
+	writeFile(t, files[3], "enabled: true\n")
+
+	// Every load reads this provider once, so its reads count the reloads
+	counter := &countingWatchProvider{data: []byte(`{}`)}
+
+	cm, err := NewBuilder[FileSetConfig]().
+		AddFileSet(files).
+		AddProvider(counter).
+		WithWatch(WithDebounce(50 * time.Millisecond)).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, FileSetConfig{Name: "base", Port: 8080, Enabled: true}, *cm.Get())
+	require.Len(t, cm.Providers(), 2)
+	assert.Equal(t, "*providers.FileSet", cm.Providers()[0].Type)
+	assert.True(t, cm.Providers()[0].Watchable)
+
+	// Give the watchers time to start
+	time.Sleep(100 * time.Millisecond)
+
+	edits := []struct {
+		file    string
+		content string
+		check   func(c *FileSetConfig) bool
+	}{
+		{files[0], "name: base\nport: 80\nmarker: one\n", func(c *FileSetConfig) bool { return c.Marker == "one" }},
+		{files[1], "port: 9090\n", func(c *FileSetConfig) bool { return c.Port == 9090 }},
+		{files[3], "enabled: true\nmarker: three\n", func(c *FileSetConfig) bool { return c.Marker == "three" }},
+	}
+
+	for _, edit := range edits {
+		reads := counter.reads.Load()
+		writeFile(t, edit.file, edit.content)
+
+		require.Eventually(t, func() bool { return edit.check(cm.Get()) }, 2*time.Second, 10*time.Millisecond)
+		time.Sleep(200 * time.Millisecond)
+		assert.Equal(t, reads+1, counter.reads.Load(), "editing %s should reload once", filepath.Base(edit.file))
+	}
+
+	assert.Equal(t, FileSetConfig{Name: "base", Port: 9090, Enabled: true, Marker: "three"}, *cm.Get())
+}
+
+func TestBuilder_AddDir(t *testing.T) {
+	type DirConfig struct {
+		Name     string `koanf:"name"`
+		Port     int    `koanf:"port"`
+		Debug    bool   `koanf:"debug"`
+		Replicas int    `koanf:"replicas"`
+	}
+
+	confDir := filepath.Join(t.TempDir(), "conf.d")
+	require.NoError(t, os.Mkdir(confDir, 0755))
+	writeFile(t, filepath.Join(confDir, "10-base.yaml"), "name: base\nport: 80\n---\nport: 8080\n")
+	writeFile(t, filepath.Join(confDir, "20-debug.yaml"), "debug: true\n")
+	writeFile(t, filepath.Join(confDir, "notes.txt"), "port: 1\n")
+
+	cm, err := NewBuilder[DirConfig]().
+		AddDir(confDir, "*.yaml").
+		WithWatchDebounce(50 * time.Millisecond).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, DirConfig{Name: "base", Port: 8080, Debug: true}, *cm.Get())
+	assert.Equal(t, "dir:"+filepath.Join(confDir, "*.yaml"), cm.Providers()[0].Name)
+
+	// Give the watcher time to start
+	time.Sleep(100 * time.Millisecond)
+
+	// A new fragment is picked up, and removing one drops its values
+	writeFile(t, filepath.Join(confDir, "30-scale.yaml"), "replicas: 3\n")
+	require.Eventually(t, func() bool { return cm.Get().Replicas == 3 }, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.Remove(filepath.Join(confDir, "20-debug.yaml")))
+	require.Eventually(t, func() bool { return !cm.Get().Debug }, 2*time.Second, 10*time.Millisecond)
+
+	_, err = NewBuilder[DirConfig]().AddDir(confDir, "[").Build(context.Background())
+	assert.ErrorContains(t, err, "invalid pattern")
+}
+
+func TestBuilder_WithIncludes(t *testing.T) {
+	type IncludeConfig struct {
+		Name     string   `koanf:"name"`
+		Include  []string `koanf:"include"`
+		Database struct {
+			Host string `koanf:"host"`
+			Port int    `koanf:"port"`
+		} `koanf:"database"`
+	}
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	databaseFile := filepath.Join(dir, "database.yaml")
+	writeFile(t, configFile, "include: [database.yaml]\nname: app\n")
+	writeFile(t, databaseFile, "database:\n  host: db\n  port: 5432\n")
+
+	cm, err := NewBuilder[IncludeConfig]().
+		AddFile(configFile).
+		WithIncludes().
+		WithWatchDebounce(50 * time.Millisecond).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, "app", cfg.Name)
+	assert.Empty(t, cfg.Include)
+	assert.Equal(t, "db", cfg.Database.Host)
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Equal(t, configFile, cm.Providers()[0].Name)
+
+	// Give the watchers time to start
+	time.Sleep(100 * time.Millisecond)
+
+	writeFile(t, databaseFile, "database:\n  host: db\n  port: 6543\n")
+	require.Eventually(t, func() bool { return cm.Get().Database.Port == 6543 }, 2*time.Second, 10*time.Millisecond)
+
+	// Without WithIncludes the key is an ordinary value
+	plain, err := NewBuilder[IncludeConfig]().AddFile(databaseFile).AddFile(configFile).Build(context.Background())
+	require.NoError(t, err)
+	defer plain.Close()
+	assert.Equal(t, []string{"database.yaml"}, plain.Get().Include)
+}
+
+func TestBuilder_AddBytes(t *testing.T) {
+	defaults := []byte("name: embedded\nport: 80\n")
+
+	cm, err := NewBuilder[BuilderTestConfig]().
+		AddBytes(defaults, "yaml").
+		AddString(`{"port": 8080}`, ".JSON").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	// The data was copied
+	copy(defaults, "name: modified")
+	require.NoError(t, cm.reload(context.Background()))
+
+	assert.Equal(t, "embedded", cm.Get().Name)
+	assert.Equal(t, 8080, cm.Get().Port)
+	assert.Equal(t, "bytes:yaml", cm.Providers()[0].Name)
+	assert.Equal(t, "bytes:json", cm.Providers()[1].Name)
+
+	cm, err = New[BuilderTestConfig](WithString("name = legacy\n", "ini"))
+	require.NoError(t, err)
+	defer cm.Close()
+	assert.Equal(t, "legacy", cm.Get().Name)
+
+	_, err = NewBuilder[BuilderTestConfig]().AddString("name: x", "xml").Build(context.Background())
+	assert.ErrorContains(t, err, "unsupported configuration format")
+
+	_, err = NewBuilder[BuilderTestConfig]().AddString("{invalid", "json").Build(context.Background())
+	assert.Error(t, err)
+}
+
+func TestBuilder_WithLogger(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\ncache:\n  host: one\n")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	cm, err := NewBuilder[UpdateAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		WithStrictReload().
+		WithLogger(logger).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	// Plugin management logs to the logger
+	assert.Contains(t, buf.String(), `msg="Plugin started"`)
+	assert.Contains(t, buf.String(), "key=recorder:cache")
+
+	// So does the manager
+	writeFile(t, configFile, "name: ''\n")
+	require.Error(t, cm.Reload(context.Background()))
+	assert.Contains(t, buf.String(), `msg="Configuration reload rejected, keeping previous configuration"`)
+
+	_, err = NewBuilder[UpdateAppConfig]().WithLogger(nil).Build(context.Background())
+	assert.Error(t, err)
+}
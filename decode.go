@@ -0,0 +1,145 @@
+// Package vcfg provides configuration management capabilities.
+// This file configures how the merged configuration is decoded into the
+// target struct, including the decode hooks applied to every field.
+package vcfg
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/knadh/koanf/v2"
+
+	"github.com/nextpkg/vcfg/defaults"
+	"github.com/nextpkg/vcfg/types"
+)
+
+// setMissingDefaults applies the struct tag defaults of out, decoded from the
+// values of k below path, to the fields whose keys are not set. Keys are matched
+// case-insensitively, as the decoder matches them, and a key set to null counts
+// as not set, as decoding leaves its field untouched.
+func setMissingDefaults(out any, k *koanf.Koanf, path string) error {
+	raw := k.Raw()
+	return defaults.SetMissingDefaults(out, "koanf", func(key string) bool {
+		if path != "" {
+			key = path + "." + key
+		}
+		return lookupFold(raw, strings.Split(key, k.Delim())) != nil
+	})
+}
+
+// lookupFold returns the value of the nested key path in m, nil if it is not
+// set. Each segment matches its key exactly or else case-insensitively.
+func lookupFold(m map[string]any, path []string) any {
+	value, ok := m[path[0]]
+	if !ok {
+		for key, v := range m {
+			if strings.EqualFold(key, path[0]) {
+				value = v
+				break
+			}
+		}
+	}
+	if len(path) == 1 || value == nil {
+		return value
+	}
+	sub, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	return lookupFold(sub, path[1:])
+}
+
+// unmarshalConf returns the koanf unmarshal configuration used to decode the
+// merged configuration into out. It keeps koanf's default behavior (weak typing,
+// encoding.TextUnmarshaler support) and adds vcfg's own hooks for durations and
+// byte sizes.
+// The hooks registered with WithDecodeHook run first, in registration order.
+func unmarshalConf(out any, hooks ...mapstructure.DecodeHookFunc) koanf.UnmarshalConf {
+	all := append(append([]mapstructure.DecodeHookFunc{}, hooks...),
+		durationHookFunc(),
+		stringToBytesHookFunc(),
+		mapstructure.TextUnmarshallerHookFunc(),
+	)
+	return koanf.UnmarshalConf{
+		DecoderConfig: &mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.ComposeDecodeHookFunc(all...),
+			Result:           out,
+			WeaklyTypedInput: true,
+		},
+	}
+}
+
+// durationHookFunc decodes time.Duration fields the same way in every format:
+// strings are duration strings such as "30s" or integer nanoseconds such as
+// "30000000000", as environment variables and INI files deliver them, and
+// numbers are nanoseconds. Numbers with a fractional part are rejected rather
+// than truncated, since "1.5" more likely meant seconds than nanoseconds.
+func durationHookFunc() mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if t != durationType {
+			return data, nil
+		}
+
+		switch f.Kind() {
+		case reflect.String:
+			s := strings.TrimSpace(reflect.ValueOf(data).String())
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return time.Duration(n), nil
+			}
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid duration %q: want a duration such as \"30s\" or integer nanoseconds", s)
+			}
+			return d, nil
+		case reflect.Float32, reflect.Float64:
+			n := reflect.ValueOf(data).Float()
+			if n != math.Trunc(n) || math.Abs(n) >= math.MaxInt64 {
+				return nil, fmt.Errorf("invalid duration %v: want a duration such as \"30s\" or integer nanoseconds", n)
+			}
+			return time.Duration(n), nil
+		default:
+			return data, nil
+		}
+	}
+}
+
+// stringToBytesHookFunc converts human-readable byte sizes such as "500MB"
+// into byte counts for signed and unsigned integer fields, failing if the count
+// does not fit the field. Plain numeric strings and time.Duration fields are
+// left untouched so the regular decoding rules apply to them.
+func stringToBytesHookFunc() mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t == durationType {
+			return data, nil
+		}
+
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return data, nil
+		}
+
+		s := data.(string)
+		if !types.HasByteUnit(s) {
+			return data, nil
+		}
+
+		n, err := types.ParseBytes(s)
+		if err != nil {
+			return nil, err
+		}
+
+		// ParseBytes never returns negative sizes, so they fit any uint64
+		target := reflect.New(t).Elem()
+		if target.CanInt() && target.OverflowInt(n) || target.CanUint() && target.OverflowUint(uint64(n)) {
+			return nil, fmt.Errorf("byte size %q overflows %v", s, t)
+		}
+		return reflect.ValueOf(n).Convert(t).Interface(), nil
+	}
+}
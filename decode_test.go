@@ -0,0 +1,330 @@
+package vcfg
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nextpkg/vcfg/types"
+)
+
+type SizeTestConfig struct {
+	MaxFileSize int64         `koanf:"max_file_size" validate:"omitempty,min_bytes=1KB"`
+	Timeout     time.Duration `koanf:"timeout"`
+}
+
+func TestLoadConfig_ByteSizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected int64
+	}{
+		{"megabytes", `{"max_file_size":"500MB"}`, 500 * 1024 * 1024},
+		{"gigabytes", `{"max_file_size":"1GB"}`, 1 << 30},
+		{"plain number string", `{"max_file_size":"2048"}`, 2048},
+		{"plain number", `{"max_file_size":4096}`, 4096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := newManager[SizeTestConfig](rawbytes.Provider([]byte(tt.config)))
+			cfg, err := cm.load(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.MaxFileSize)
+		})
+	}
+}
+
+func TestLoadConfig_ByteSizesKeepDurations(t *testing.T) {
+	cm := newManager[SizeTestConfig](rawbytes.Provider([]byte(`{"timeout":"30s"}`)))
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.Timeout)
+}
+
+func TestLoadConfig_ByteSizesInvalid(t *testing.T) {
+	// Unknown unit fails decoding
+	cm := newManager[SizeTestConfig](rawbytes.Provider([]byte(`{"max_file_size":"10XB"}`)))
+	_, err := cm.load(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeParseFailure})
+
+	// Below the min_bytes rule fails validation
+	cm = newManager[SizeTestConfig](rawbytes.Provider([]byte(`{"max_file_size":100}`)))
+	_, err = cm.load(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeValidationFailure})
+}
+
+func TestLoadConfig_ByteSizesIntegerKinds(t *testing.T) {
+	type Config struct {
+		Int    int    `koanf:"int" validate:"max_bytes=1GB"`
+		Uint64 uint64 `koanf:"uint64" validate:"min_bytes=1MB"`
+		Uint32 uint32 `koanf:"uint32"`
+		Int16  int16  `koanf:"int16"`
+	}
+
+	cm := newManager[Config](rawbytes.Provider([]byte(
+		`{"int":"10MB","uint64":"2GB","uint32":"1KB","int16":"16KB"}`)))
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Config{Int: 10 << 20, Uint64: 2 << 30, Uint32: 1 << 10, Int16: 16 << 10}, *cfg)
+
+	// Sizes that do not fit the field fail decoding
+	cm = newManager[Config](rawbytes.Provider([]byte(`{"int16":"32KB"}`)))
+	_, err = cm.load(context.Background())
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeParseFailure})
+	assert.ErrorContains(t, err, "overflows int16")
+
+	cm = newManager[Config](rawbytes.Provider([]byte(`{"uint32":"4GB"}`)))
+	_, err = cm.load(context.Background())
+	assert.ErrorContains(t, err, "overflows uint32")
+}
+
+type CollectionDefaultsConfig struct {
+	Ports  []int             `koanf:"ports" default:"80,443"`
+	Labels map[string]string `koanf:"labels" default:"team=core,tier=web"`
+}
+
+func TestLoadConfig_CollectionDefaultsPrecedence(t *testing.T) {
+	cm := newManager[CollectionDefaultsConfig](rawbytes.Provider([]byte(`{"ports":[8080],"labels":{"tier":"api"}}`)))
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+
+	// A configured slice or map replaces the default as a whole
+	assert.Equal(t, []int{8080}, cfg.Ports)
+	assert.Equal(t, map[string]string{"tier": "api"}, cfg.Labels)
+}
+
+type ZeroValueConfig struct {
+	Port    int      `koanf:"port" default:"8080"`
+	Debug   bool     `koanf:"debug" default:"true"`
+	Name    string   `koanf:"name" default:"app"`
+	Tags    []string `koanf:"tags" default:"a,b"`
+	Workers *int     `koanf:"workers" default:"4"`
+	Cache   *struct {
+		Size int    `koanf:"size" default:"100"`
+		Mode string `koanf:"mode" default:"lru"`
+	} `koanf:"cache"`
+}
+
+func TestLoadConfig_DefaultsKeepExplicitZeros(t *testing.T) {
+	cm := newManager[ZeroValueConfig](rawbytes.Provider([]byte(
+		`{"port":0,"debug":false,"name":"","tags":[],"workers":0,"cache":{"size":0}}`)))
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, cfg.Port)
+	assert.False(t, cfg.Debug)
+	assert.Equal(t, "", cfg.Name)
+	assert.Empty(t, cfg.Tags)
+	require.NotNil(t, cfg.Workers)
+	assert.Equal(t, 0, *cfg.Workers)
+	require.NotNil(t, cfg.Cache)
+	assert.Equal(t, 0, cfg.Cache.Size)
+	assert.Equal(t, "lru", cfg.Cache.Mode, "missing keys of a configured section get defaults")
+
+	// Absent and null keys get their defaults
+	cm = newManager[ZeroValueConfig](rawbytes.Provider([]byte(`{"port":null}`)))
+	cfg, err = cm.load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 8080, cfg.Port)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, "app", cfg.Name)
+	assert.Equal(t, []string{"a", "b"}, cfg.Tags)
+	require.NotNil(t, cfg.Workers)
+	assert.Equal(t, 4, *cfg.Workers)
+	assert.Nil(t, cfg.Cache)
+}
+
+func TestLoadConfig_DefaultsKeepExplicitZerosOfCapitalizedKeys(t *testing.T) {
+	// Keys are decoded case-insensitively, so they count as set regardless of case
+	cm := newManager[ZeroValueConfig](rawbytes.Provider([]byte(
+		`{"Port":0,"DEBUG":false,"Cache":{"Size":0}}`)))
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, cfg.Port)
+	assert.False(t, cfg.Debug)
+	assert.Equal(t, "app", cfg.Name)
+	require.NotNil(t, cfg.Cache)
+	assert.Equal(t, 0, cfg.Cache.Size)
+	assert.Equal(t, "lru", cfg.Cache.Mode)
+}
+
+// hookLevel is an enum decoded from its name by a decode hook
+type hookLevel int
+
+const (
+	hookLevelInfo hookLevel = iota
+	hookLevelDebug
+)
+
+// levelHook decodes hookLevel values from their names
+func levelHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeOf(hookLevel(0)) {
+		return data, nil
+	}
+	switch strings.ToLower(data.(string)) {
+	case "info":
+		return hookLevelInfo, nil
+	case "debug":
+		return hookLevelDebug, nil
+	default:
+		return nil, fmt.Errorf("unknown level %q", data)
+	}
+}
+
+type HookConfig struct {
+	Level  hookLevel `koanf:"level"`
+	Listen net.IP    `koanf:"listen"`
+	Log    struct {
+		Level hookLevel `koanf:"level"`
+	} `koanf:"log"`
+}
+
+func TestBuilder_WithDecodeHook(t *testing.T) {
+	data := `{"level":"debug","listen":"10.0.0.1","log":{"level":"DEBUG"}}`
+
+	cm, err := NewBuilder[HookConfig]().
+		AddBytes([]byte(data), "json").
+		WithDecodeHook(levelHook).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, hookLevelDebug, cm.Get().Level)
+	assert.Equal(t, "10.0.0.1", cm.Get().Listen.String())
+
+	// Sub and UnmarshalInto use the hooks too
+	type logSection struct {
+		Level hookLevel `koanf:"level"`
+	}
+	section, err := Sub[logSection](cm, "log")
+	require.NoError(t, err)
+	assert.Equal(t, hookLevelDebug, section.Level)
+
+	var out HookConfig
+	require.NoError(t, cm.UnmarshalInto(&out))
+	assert.Equal(t, hookLevelDebug, out.Log.Level)
+
+	// Hook errors fail the load
+	_, err = NewBuilder[HookConfig]().
+		AddBytes([]byte(`{"level":"verbose"}`), "json").
+		WithDecodeHook(levelHook).
+		Build(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown level "verbose"`)
+
+	// Without the hook the names cannot be decoded
+	_, err = NewBuilder[HookConfig]().AddBytes([]byte(data), "json").Build(context.Background())
+	require.Error(t, err)
+
+	_, err = NewBuilder[HookConfig]().AddBytes([]byte(data), "json").WithDecodeHook(nil).Build(context.Background())
+	require.Error(t, err)
+}
+
+type TypedValuesConfig struct {
+	MaxBodySize types.Size `koanf:"max_body_size" default:"1MB" validate:"max_bytes=1GB"`
+	Requests    types.Rate `koanf:"requests" default:"100/s" validate:"gt=0"`
+	Upstream    types.URL  `koanf:"upstream" validate:"required"`
+}
+
+func TestLoadConfig_TypedValues(t *testing.T) {
+	t.Setenv("TYPED_REQUESTS", "3000/min")
+
+	cm, err := NewBuilder[TypedValuesConfig]().
+		AddBytes([]byte("max_body_size: 512MB\nupstream: https://api.internal:8443\n"), "yaml").
+		AddEnv("TYPED_").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, types.Size(512<<20), cfg.MaxBodySize)
+	assert.Equal(t, types.Rate(50), cfg.Requests)
+	assert.Equal(t, "api.internal:8443", cfg.Upstream.Host)
+
+	// Plain numbers decode too, and defaults fill the missing keys
+	cm2 := newManager[TypedValuesConfig](rawbytes.Provider([]byte(`{"max_body_size":2048,"upstream":"http://a"}`)))
+	cfg, err = cm2.load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, types.Size(2048), cfg.MaxBodySize)
+	assert.Equal(t, types.Rate(100), cfg.Requests)
+
+	// A changed URL is reported as one change
+	next := *cfg
+	next.Upstream, err = types.ParseURL("http://b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"upstream"}, changedPaths(cfg, &next))
+
+	for name, data := range map[string]string{
+		"size above max":   `{"max_body_size":"2GB","upstream":"http://a"}`,
+		"zero rate":        `{"requests":"0/s","upstream":"http://a"}`,
+		"missing upstream": `{}`,
+		"invalid url":      `{"upstream":"not a url"}`,
+		"invalid rate":     `{"requests":"fast","upstream":"http://a"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := newManager[TypedValuesConfig](rawbytes.Provider([]byte(data))).load(context.Background())
+			assert.Error(t, err)
+		})
+	}
+}
+
+type DurationFormatsConfig struct {
+	Timeout  time.Duration            `koanf:"timeout"`
+	Interval *time.Duration           `koanf:"interval"`
+	Backoff  []time.Duration          `koanf:"backoff"`
+	Per      map[string]time.Duration `koanf:"per"`
+}
+
+func TestLoadConfig_DurationFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format string
+	}{
+		{"json strings", `{"timeout":"30s","interval":"1m","backoff":["1s","2s"],"per":{"a":"5s"}}`, "json"},
+		{"json nanoseconds", `{"timeout":30000000000,"interval":60000000000,"backoff":[1000000000,2000000000],"per":{"a":5000000000}}`, "json"},
+		{"json nanosecond strings", `{"timeout":"30000000000","interval":"1m","backoff":["1s","2000000000"],"per":{"a":"5s"}}`, "json"},
+		{"yaml", "timeout: 30s\ninterval: 60000000000\nbackoff: [1s, 2s]\nper:\n  a: 5s\n", "yaml"},
+		{"properties", "timeout=30s\ninterval=60000000000\nbackoff=1000000000\nper.a=5s\n", "properties"},
+		{"ini", "timeout = 30000000000\ninterval = 1m\nbackoff = 1s\n[per]\na = 5000000000\n", "ini"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm, err := NewBuilder[DurationFormatsConfig]().
+				AddBytes([]byte(tt.data), tt.format).
+				Build(context.Background())
+			require.NoError(t, err)
+			defer cm.Close()
+
+			cfg := cm.Get()
+			assert.Equal(t, 30*time.Second, cfg.Timeout)
+			require.NotNil(t, cfg.Interval)
+			assert.Equal(t, time.Minute, *cfg.Interval)
+			assert.Equal(t, time.Second, cfg.Backoff[0])
+			assert.Equal(t, 5*time.Second, cfg.Per["a"])
+		})
+	}
+}
+
+func TestLoadConfig_DurationInvalid(t *testing.T) {
+	for _, data := range []string{`{"timeout":1.5}`, `{"timeout":"1.5"}`, `{"timeout":"soon"}`} {
+		t.Run(data, func(t *testing.T) {
+			_, err := newManager[DurationFormatsConfig](rawbytes.Provider([]byte(data))).load(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid duration")
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the decryption of individually encrypted string values,
+// such as "enc:<ciphertext>", before they are unmarshaled.
+package vcfg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+// defaultDecryptPrefix marks the values passed to the decryptor unless configured
+const defaultDecryptPrefix = "enc:"
+
+// Decryptor returns the plaintext of an encrypted configuration value. It
+// receives the value without the encryption prefix.
+type Decryptor func(ciphertext string) (string, error)
+
+// NewAESDecryptor returns a Decryptor for values encrypted with AES-GCM: the
+// standard base64 encoding of the nonce followed by the sealed ciphertext. The
+// key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func NewAESDecryptor(key []byte) (Decryptor, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AES key: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ciphertext string) (string, error) {
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(ciphertext))
+		if err != nil {
+			return "", fmt.Errorf("invalid base64 ciphertext: %w", err)
+		}
+		if len(data) < gcm.NonceSize() {
+			return "", errors.New("ciphertext too short")
+		}
+		nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+		plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+		if err != nil {
+			return "", errors.New("message authentication failed")
+		}
+		return string(plaintext), nil
+	}, nil
+}
+
+// decryptValues returns a koanf instance holding the values of k with every
+// string value that starts with the decryption prefix replaced by its
+// plaintext, or k itself if no decryptor is configured. Like expandValues it
+// leaves k untouched, so the merged values keep the ciphertext.
+func (cm *ConfigManager[T]) decryptValues(k *koanf.Koanf) (*koanf.Koanf, error) {
+	if cm.decryptor == nil {
+		return k, nil
+	}
+
+	prefix := cm.decryptPrefix
+	if prefix == "" {
+		prefix = defaultDecryptPrefix
+	}
+
+	flat := k.All()
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, err := decryptValue(flat[key], key, prefix, cm.decryptor)
+		if err != nil {
+			return nil, err
+		}
+		flat[key] = value
+	}
+
+	decrypted := koanf.New(".")
+	if err := decrypted.Load(mapProvider(maps.Unflatten(flat, ".")), nil); err != nil {
+		return nil, err
+	}
+	return decrypted, nil
+}
+
+// decryptValue decrypts a prefixed string value or the prefixed string elements
+// of a slice. Errors name the key path but never the value.
+func decryptValue(value any, path, prefix string, decrypt Decryptor) (any, error) {
+	switch v := value.(type) {
+	case string:
+		ciphertext, ok := strings.CutPrefix(v, prefix)
+		if !ok {
+			return v, nil
+		}
+		plaintext, err := decrypt(ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return plaintext, nil
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			decrypted, err := decryptValue(item, fmt.Sprintf("%s[%d]", path, i), prefix, decrypt)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = decrypted
+		}
+		return items, nil
+	default:
+		return value, nil
+	}
+}
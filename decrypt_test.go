@@ -0,0 +1,82 @@
+package vcfg
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptAES encrypts plaintext in the format read by NewAESDecryptor
+func encryptAES(t *testing.T, key []byte, plaintext string) string {
+	t.Helper()
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plaintext), nil))
+}
+
+func TestBuilder_WithDecryptor(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	decrypt, err := NewAESDecryptor(key)
+	require.NoError(t, err)
+
+	t.Setenv("VCFG_DB_PASSWORD", "enc:"+encryptAES(t, key, "s3cret"))
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, `database:
+  host: db.internal
+  password: ${VCFG_DB_PASSWORD}
+  replicas: [db1, "enc:`+encryptAES(t, key, "db2")+`"]
+`)
+
+	cm, err := NewBuilder[ExpandTestConfig]().
+		AddFile(configFile).
+		WithEnvExpansion().
+		WithDecryptor(decrypt).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, "s3cret", cfg.Database.Password)
+	assert.Equal(t, []string{"db1", "db2"}, cfg.Database.Replicas)
+
+	var view ExpandTestConfig
+	require.NoError(t, cm.UnmarshalInto(&view))
+	assert.Equal(t, "s3cret", view.Database.Password)
+
+	// A value that cannot be decrypted fails the reload and names the key
+	writeFile(t, configFile, "database:\n  password: enc:"+encryptAES(t, make([]byte, 32), "other")+"\n")
+	err = cm.reload(context.Background())
+	assert.ErrorContains(t, err, "database.password: message authentication failed")
+	assert.Equal(t, "s3cret", cm.Get().Database.Password)
+
+	// A custom prefix
+	writeFile(t, configFile, "database:\n  host: enc:plain\n  password: aes:"+encryptAES(t, key, "rotated")+"\n")
+	custom, err := NewBuilder[ExpandTestConfig]().
+		AddFile(configFile).
+		WithDecryptor(decrypt).
+		WithDecryptPrefix("aes:").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer custom.Close()
+	assert.Equal(t, "enc:plain", custom.Get().Database.Host)
+	assert.Equal(t, "rotated", custom.Get().Database.Password)
+
+	_, err = NewAESDecryptor([]byte("short"))
+	assert.Error(t, err)
+}
@@ -4,15 +4,22 @@
 package defaults
 
 import (
+	"encoding"
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// textUnmarshalerType is the type of encoding.TextUnmarshaler
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
 // SetDefaults sets default values for struct fields using the "default" struct tag.
 // It recursively processes nested structs and handles various data types including
-// strings, integers, floats, booleans, slices, and pointers.
+// strings, integers, floats, booleans, durations, slices and maps of these, and
+// pointers. Types implementing encoding.TextUnmarshaler, such as types.Size,
+// types.Rate, types.URL and net.IP, parse the default themselves.
 //
 // The function only sets defaults for fields that have zero values, preserving
 // any existing non-zero values.
@@ -22,19 +29,91 @@ import (
 // Examples:
 //
 //	type Config struct {
-//	    Port     int           `default:"8080"`
-//	    Host     string        `default:"localhost"`
-//	    Timeout  time.Duration `default:"30s"`
-//	    Debug    bool          `default:"false"`
-//	    Tags     []string      `default:"tag1,tag2,tag3"`
+//	    Port     int               `default:"8080"`
+//	    Host     string            `default:"localhost"`
+//	    Timeout  time.Duration     `default:"30s"`
+//	    Debug    bool              `default:"false"`
+//	    Tags     []string          `default:"tag1,tag2,tag3"`
+//	    Ports    []int             `default:"80,443"`
+//	    Backoff  []time.Duration   `default:"1s,5s,10s"`
+//	    Labels   map[string]string `default:"team=core,tier=web"`
 //	}
 //
+// Defaults of the form func:<name> are computed by a function registered with
+// RegisterFunc, e.g. `default:"func:hostname"`, and env:<NAME> defaults are read
+// from an environment variable, e.g. `default:"env:HOME"`. Empty results leave
+// the field unset.
+//
+// Slice defaults are comma-separated values and map defaults comma-separated
+// key=value pairs; neither may contain literal commas.
+//
+// SetDefaults only looks at zero values, so it cannot tell an explicit zero from
+// an unset field; vcfg therefore decodes the configuration first and then sets
+// the defaults of the keys that are absent with SetMissingDefaults. A configured
+// value, slice or map thus replaces the default as a whole.
+//
 // Parameters:
 //   - ptr: A pointer to a struct that should have default values applied
 //
 // Returns:
 //   - error: An error if the operation fails, nil otherwise
 func SetDefaults(ptr any) error {
+	return setDefaults(ptr, "", nil)
+}
+
+// SetMissingDefaults is like SetDefaults for a struct that was already decoded
+// from configuration values: fields whose key isSet reports as set are left
+// alone even if they hold zero values, so an explicit `port: 0` is kept rather
+// than replaced by the default. Key paths are built from the tagName struct
+// tags as for decoding, e.g. server.port, falling back to the lower-cased field
+// name; squashed and embedded structs add no segment.
+//
+// Structs behind non-nil pointers are descended into, so the missing fields of
+// decoded sections get their defaults as well.
+func SetMissingDefaults(ptr any, tagName string, isSet func(key string) bool) error {
+	return setDefaults(ptr, "", &presence{tagName: tagName, isSet: isSet})
+}
+
+// presence tells setDefaults which keys are set, nil if none are
+type presence struct {
+	// tagName is the struct tag naming the keys of fields
+	tagName string
+	// isSet reports whether the key is set
+	isSet func(key string) bool
+}
+
+// key returns the key path of field below path, "" without presence
+func (p *presence) key(path string, field reflect.StructField) string {
+	if p == nil {
+		return ""
+	}
+
+	name := strings.ToLower(field.Name)
+	if tag, ok := field.Tag.Lookup(p.tagName); ok {
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			name = parts[0]
+		} else if len(parts) > 1 && parts[1] == "squash" {
+			return path
+		}
+	} else if field.Anonymous {
+		return path
+	}
+
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// set reports whether the key is set
+func (p *presence) set(key string) bool {
+	return p != nil && p.isSet(key)
+}
+
+// setDefaults sets the defaults of the struct ptr points to, whose key path is
+// path, skipping the keys that are set according to p
+func setDefaults(ptr any, path string, p *presence) error {
 	if ptr == nil {
 		return nil
 	}
@@ -55,10 +134,19 @@ func SetDefaults(ptr any) error {
 		if !field.CanSet() {
 			continue
 		}
+		key := p.key(path, fieldType)
 
 		// Handle nested structs recursively
-		if field.Kind() == reflect.Struct {
-			if err := SetDefaults(field.Addr().Interface()); err != nil {
+		if field.Kind() == reflect.Struct && !isText(field.Type()) {
+			if err := setDefaults(field.Addr().Interface(), key, p); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Handle structs that were already allocated, e.g. by decoding
+		if field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.Struct && !isText(field.Elem().Type()) {
+			if err := setDefaults(field.Interface(), key, p); err != nil {
 				return err
 			}
 			continue
@@ -70,8 +158,17 @@ func SetDefaults(ptr any) error {
 			continue
 		}
 
-		// Only set default if field is zero value
-		if !field.IsZero() {
+		// Only set default if field is zero value and its key is not set
+		if !field.IsZero() || p.set(key) {
+			continue
+		}
+
+		// Compute dynamic defaults such as func:hostname or env:HOME
+		defaultValue, ok, err := resolveDefault(defaultValue)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fieldType.Name, err)
+		}
+		if !ok {
 			continue
 		}
 
@@ -85,7 +182,7 @@ func SetDefaults(ptr any) error {
 
 // setFieldValue sets a struct field's value based on its type and the provided string value.
 // It handles type conversion for various Go types including primitives, time.Duration,
-// slices, nested structs, and pointers.
+// types implementing encoding.TextUnmarshaler, slices, maps, nested structs, and pointers.
 //
 // Parameters:
 //   - field: The reflect.Value of the field to set
@@ -94,6 +191,11 @@ func SetDefaults(ptr any) error {
 // Returns:
 //   - error: An error if type conversion or assignment fails, nil otherwise
 func setFieldValue(field reflect.Value, value string) error {
+	// Types such as types.Size, types.URL and net.IP parse their own text form
+	if field.CanAddr() && isText(field.Type()) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(value)
@@ -136,15 +238,39 @@ func setFieldValue(field reflect.Value, value string) error {
 		field.SetBool(boolVal)
 
 	case reflect.Slice:
-		// Handle string slices (comma-separated values)
-		if field.Type().Elem().Kind() == reflect.String {
-			if value != "" {
-				sliceVal := reflect.MakeSlice(field.Type(), 0, 0)
-				for _, item := range splitAndTrim(value, ",") {
-					sliceVal = reflect.Append(sliceVal, reflect.ValueOf(item))
+		// Handle slices of scalars (comma-separated values), e.g. "1s,5s,10s"
+		if isScalar(field.Type().Elem()) && value != "" {
+			sliceVal := reflect.MakeSlice(field.Type(), 0, 0)
+			for _, item := range splitAndTrim(value, ",") {
+				elem := reflect.New(field.Type().Elem()).Elem()
+				if err := setFieldValue(elem, item); err != nil {
+					return err
 				}
-				field.Set(sliceVal)
+				sliceVal = reflect.Append(sliceVal, elem)
 			}
+			field.Set(sliceVal)
+		}
+
+	case reflect.Map:
+		// Handle maps of scalars (comma-separated key=value pairs), e.g. "k1=v1,k2=v2"
+		if isScalar(field.Type().Key()) && isScalar(field.Type().Elem()) && value != "" {
+			mapVal := reflect.MakeMap(field.Type())
+			for _, item := range splitAndTrim(value, ",") {
+				k, v, ok := strings.Cut(item, "=")
+				if !ok {
+					return fmt.Errorf("invalid map default %q: expected key=value", item)
+				}
+				key := reflect.New(field.Type().Key()).Elem()
+				if err := setFieldValue(key, strings.TrimSpace(k)); err != nil {
+					return err
+				}
+				elem := reflect.New(field.Type().Elem()).Elem()
+				if err := setFieldValue(elem, strings.TrimSpace(v)); err != nil {
+					return err
+				}
+				mapVal.SetMapIndex(key, elem)
+			}
+			field.Set(mapVal)
 		}
 
 	case reflect.Struct:
@@ -161,7 +287,7 @@ func setFieldValue(field reflect.Value, value string) error {
 			field.Set(newVal)
 		}
 		// Set the value for the pointed-to element
-		if field.Elem().Kind() == reflect.Struct {
+		if field.Elem().Kind() == reflect.Struct && !isText(field.Elem().Type()) {
 			return SetDefaults(field.Interface())
 		} else {
 			// For non-struct pointers, set the value directly
@@ -172,6 +298,29 @@ func setFieldValue(field reflect.Value, value string) error {
 	return nil
 }
 
+// isScalar reports whether values of type t are parsed from a single default
+// value, so slices and maps of t can hold defaults.
+func isScalar(t reflect.Type) bool {
+	if isText(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isText reports whether values of type t parse their own text form through
+// encoding.TextUnmarshaler, so they are set like scalars
+func isText(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
 // splitAndTrim splits a string by the specified delimiter and trims whitespace
 // from each resulting part. Empty parts after trimming are excluded from the result.
 //
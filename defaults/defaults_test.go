@@ -1,8 +1,12 @@
 package defaults
 
 import (
+	"net"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/nextpkg/vcfg/types"
 )
 
 type TestConfig struct {
@@ -130,3 +134,160 @@ func TestSetDefaultsNonStruct(t *testing.T) {
 		t.Errorf("Expected no error for non-struct, got %v", err)
 	}
 }
+
+type CollectionConfig struct {
+	Ports   []int             `default:"80, 443"`
+	Weights []float64         `default:"0.5,1.5"`
+	Backoff []time.Duration   `default:"1s,5s,10s"`
+	Labels  map[string]string `default:"team=core, tier = web"`
+	Limits  map[string]int    `default:"conns=10,reqs=100"`
+	Nested  [][]string        `default:"ignored"`
+}
+
+func TestSetDefaultsCollections(t *testing.T) {
+	config := &CollectionConfig{}
+	if err := SetDefaults(config); err != nil {
+		t.Fatalf("SetDefaults failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(config.Ports, []int{80, 443}) {
+		t.Errorf("Expected Ports to be [80 443], got %v", config.Ports)
+	}
+	if !reflect.DeepEqual(config.Weights, []float64{0.5, 1.5}) {
+		t.Errorf("Expected Weights to be [0.5 1.5], got %v", config.Weights)
+	}
+	expectedBackoff := []time.Duration{time.Second, 5 * time.Second, 10 * time.Second}
+	if !reflect.DeepEqual(config.Backoff, expectedBackoff) {
+		t.Errorf("Expected Backoff to be %v, got %v", expectedBackoff, config.Backoff)
+	}
+	expectedLabels := map[string]string{"team": "core", "tier": "web"}
+	if !reflect.DeepEqual(config.Labels, expectedLabels) {
+		t.Errorf("Expected Labels to be %v, got %v", expectedLabels, config.Labels)
+	}
+	expectedLimits := map[string]int{"conns": 10, "reqs": 100}
+	if !reflect.DeepEqual(config.Limits, expectedLimits) {
+		t.Errorf("Expected Limits to be %v, got %v", expectedLimits, config.Limits)
+	}
+	if config.Nested != nil {
+		t.Errorf("Expected Nested to stay nil, got %v", config.Nested)
+	}
+
+	// Existing collections are kept
+	config = &CollectionConfig{Ports: []int{8080}, Labels: map[string]string{"team": "infra"}}
+	if err := SetDefaults(config); err != nil {
+		t.Fatalf("SetDefaults failed: %v", err)
+	}
+	if !reflect.DeepEqual(config.Ports, []int{8080}) || config.Labels["team"] != "infra" || len(config.Labels) != 1 {
+		t.Errorf("Expected existing values to be kept, got %v and %v", config.Ports, config.Labels)
+	}
+}
+
+func TestSetDefaultsCollectionsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config any
+	}{
+		{"IntElement", &struct {
+			Ports []int `default:"80,http"`
+		}{}},
+		{"DurationElement", &struct {
+			Backoff []time.Duration `default:"1s,soon"`
+		}{}},
+		{"MissingEquals", &struct {
+			Labels map[string]string `default:"team"`
+		}{}},
+		{"MapValue", &struct {
+			Limits map[string]int `default:"conns=many"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetDefaults(tt.config); err == nil {
+				t.Errorf("Expected an error for %s", tt.name)
+			}
+		})
+	}
+}
+
+type PresenceCommon struct {
+	Region string `default:"eu"`
+}
+
+type PresenceConfig struct {
+	PresenceCommon `koanf:",squash"`
+	Port           int `koanf:"port" default:"8080"`
+	Server         struct {
+		Timeout time.Duration `koanf:"timeout" default:"30s"`
+		Retries int           `default:"3"`
+	} `koanf:"server"`
+}
+
+func TestSetMissingDefaults(t *testing.T) {
+	set := map[string]bool{"port": true, "server.retries": true, "region": true}
+	var checked []string
+	isSet := func(key string) bool {
+		checked = append(checked, key)
+		return set[key]
+	}
+
+	config := &PresenceConfig{}
+	if err := SetMissingDefaults(config, "koanf", isSet); err != nil {
+		t.Fatalf("SetMissingDefaults failed: %v", err)
+	}
+
+	// Set keys keep their zero values
+	if config.Port != 0 || config.Server.Retries != 0 || config.Region != "" {
+		t.Errorf("Expected set keys to stay zero, got %+v", config)
+	}
+	if config.Server.Timeout != 30*time.Second {
+		t.Errorf("Expected Server.Timeout to be 30s, got %v", config.Server.Timeout)
+	}
+
+	expected := []string{"region", "port", "server.timeout", "server.retries"}
+	if !reflect.DeepEqual(checked, expected) {
+		t.Errorf("Expected keys %v, got %v", expected, checked)
+	}
+}
+
+type TextConfig struct {
+	MaxBody  types.Size   `default:"10MB"`
+	Limit    types.Rate   `default:"100/s"`
+	Endpoint types.URL    `default:"http://localhost:8080"`
+	Backup   *types.URL   `default:"s3://backups/app"`
+	Listen   net.IP       `default:"127.0.0.1"`
+	Chunks   []types.Size `default:"4KB,1MB"`
+}
+
+func TestSetDefaultsTextTypes(t *testing.T) {
+	config := &TextConfig{}
+	if err := SetDefaults(config); err != nil {
+		t.Fatalf("SetDefaults failed: %v", err)
+	}
+
+	if config.MaxBody != 10<<20 {
+		t.Errorf("Expected MaxBody to be 10MB, got %v", config.MaxBody)
+	}
+	if config.Limit != 100 {
+		t.Errorf("Expected Limit to be 100/s, got %v", config.Limit)
+	}
+	if config.Endpoint.Host != "localhost:8080" {
+		t.Errorf("Expected Endpoint host to be 'localhost:8080', got '%s'", config.Endpoint.Host)
+	}
+	if config.Backup == nil || config.Backup.String() != "s3://backups/app" {
+		t.Errorf("Expected Backup to be 's3://backups/app', got %v", config.Backup)
+	}
+	if !config.Listen.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("Expected Listen to be 127.0.0.1, got %v", config.Listen)
+	}
+	if !reflect.DeepEqual(config.Chunks, []types.Size{4 << 10, 1 << 20}) {
+		t.Errorf("Expected Chunks to be [4KB 1MB], got %v", config.Chunks)
+	}
+
+	invalid := &struct {
+		Limit types.Rate `default:"fast"`
+	}{}
+	if err := SetDefaults(invalid); err == nil {
+		t.Errorf("Expected an error for an invalid rate default")
+	}
+}
@@ -0,0 +1,87 @@
+// Package defaults provides functionality for setting default values on struct fields
+// using struct tags.
+// This file implements dynamic defaults computed when the defaults are set.
+package defaults
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// funcPrefix marks a default computed by a registered function, e.g. func:hostname
+	funcPrefix = "func:"
+	// envPrefix marks a default read from an environment variable, e.g. env:HOME
+	envPrefix = "env:"
+)
+
+var (
+	// funcsMu protects funcs
+	funcsMu sync.RWMutex
+	// funcs are the default functions by name, including the built-in ones
+	funcs = map[string]func() string{
+		"hostname": hostname,
+		"num_cpu":  func() string { return strconv.Itoa(runtime.NumCPU()) },
+	}
+)
+
+// RegisterFunc registers fn to compute the default of fields tagged
+// `default:"func:<name>"`. It is called every time defaults are set, so the
+// value may change between loads:
+//
+//	defaults.RegisterFunc("region", func() string {
+//		return metadata.Region()
+//	})
+//
+// The returned string is parsed like a literal default, so a function may
+// return "4" for an int field or "a,b" for a slice. An empty result leaves the
+// field unset. The built-in functions hostname and num_cpu may be replaced.
+// It is safe to call concurrently with SetDefaults.
+func RegisterFunc(name string, fn func() string) error {
+	if name == "" {
+		return fmt.Errorf("default function name cannot be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("default function %q cannot be nil", name)
+	}
+
+	funcsMu.Lock()
+	defer funcsMu.Unlock()
+	funcs[name] = fn
+	return nil
+}
+
+// resolveDefault computes the default value of a tag: func:<name> calls the
+// registered function and env:<NAME> reads the environment variable; other
+// values are literal. ok is false if the computed default is empty.
+func resolveDefault(tag string) (value string, ok bool, err error) {
+	switch {
+	case strings.HasPrefix(tag, funcPrefix):
+		name := strings.TrimPrefix(tag, funcPrefix)
+		funcsMu.RLock()
+		fn, found := funcs[name]
+		funcsMu.RUnlock()
+		if !found {
+			return "", false, fmt.Errorf("unknown default function %q", name)
+		}
+		value = fn()
+	case strings.HasPrefix(tag, envPrefix):
+		value = os.Getenv(strings.TrimPrefix(tag, envPrefix))
+	default:
+		return tag, true, nil
+	}
+	return value, value != "", nil
+}
+
+// hostname returns the host name, "" if it cannot be determined
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return name
+}
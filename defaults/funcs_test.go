@@ -0,0 +1,60 @@
+package defaults
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type DynamicConfig struct {
+	Host    string   `default:"func:hostname"`
+	Workers int      `default:"func:num_cpu"`
+	Home    string   `default:"env:VCFG_TEST_HOME"`
+	Zones   []string `default:"func:test_zones"`
+	Unset   int      `default:"env:VCFG_TEST_UNSET"`
+}
+
+func TestSetDefaultsDynamic(t *testing.T) {
+	t.Setenv("VCFG_TEST_HOME", "/home/app")
+	if err := RegisterFunc("test_zones", func() string { return "a,b" }); err != nil {
+		t.Fatalf("RegisterFunc failed: %v", err)
+	}
+
+	config := &DynamicConfig{}
+	if err := SetDefaults(config); err != nil {
+		t.Fatalf("SetDefaults failed: %v", err)
+	}
+
+	if want, _ := os.Hostname(); config.Host != want {
+		t.Errorf("Expected Host to be '%s', got '%s'", want, config.Host)
+	}
+	if config.Workers != runtime.NumCPU() {
+		t.Errorf("Expected Workers to be %d, got %d", runtime.NumCPU(), config.Workers)
+	}
+	if config.Home != "/home/app" {
+		t.Errorf("Expected Home to be '/home/app', got '%s'", config.Home)
+	}
+	if len(config.Zones) != 2 || config.Zones[0] != "a" || config.Zones[1] != "b" {
+		t.Errorf("Expected Zones to be [a b], got %v", config.Zones)
+	}
+	if config.Unset != 0 {
+		t.Errorf("Expected Unset to stay 0, got %d", config.Unset)
+	}
+}
+
+func TestSetDefaultsDynamicErrors(t *testing.T) {
+	config := &struct {
+		Value string `default:"func:no_such_func"`
+	}{}
+	if err := SetDefaults(config); err == nil || !strings.Contains(err.Error(), "no_such_func") {
+		t.Errorf("Expected an unknown function error, got %v", err)
+	}
+
+	if err := RegisterFunc("", func() string { return "" }); err == nil {
+		t.Errorf("Expected an error for an empty name")
+	}
+	if err := RegisterFunc("nil_func", nil); err == nil {
+		t.Errorf("Expected an error for a nil function")
+	}
+}
@@ -0,0 +1,130 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the detection of deprecated keys marked with the
+// `deprecated` struct tag, and their optional mapping to replacement keys.
+package vcfg
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// Deprecation describes a deprecated key that is set in the merged configuration.
+type Deprecation struct {
+	// Key is the deprecated configuration key, e.g. server.addr
+	Key string
+	// Hint is the `deprecated` tag of the field, e.g. "use server.listen_addr"
+	Hint string
+	// Replacement is the key named by a hint of the form "use <key>", "" if the
+	// hint names none
+	Replacement string
+	// Mapped reports whether the value was copied to Replacement, see
+	// WithDeprecatedKeyMapping
+	Mapped bool
+}
+
+// deprecatedKey is a key whose field carries the deprecated struct tag
+type deprecatedKey struct {
+	// key is the configuration key of the field
+	key string
+	// hint is the tag value
+	hint string
+	// replacement is the key named by the hint, "" if none
+	replacement string
+}
+
+// deprecatedKeys returns the keys of the fields of t tagged `deprecated`, in
+// field order. Fields of nested structs are included; lists and maps are not
+// descended into.
+func deprecatedKeys(t reflect.Type) []deprecatedKey {
+	var keys []deprecatedKey
+	collectDeprecatedKeys(t, "", make(map[reflect.Type]bool), &keys)
+	return keys
+}
+
+// collectDeprecatedKeys appends the deprecated keys of t below path to keys;
+// visiting holds the struct types being expanded so recursive types terminate
+func collectDeprecatedKeys(t reflect.Type, path string, visiting map[reflect.Type]bool, keys *[]deprecatedKey) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("koanf") == "-" {
+			continue
+		}
+
+		key := joinKeyPath(path, field)
+		if hint, ok := field.Tag.Lookup("deprecated"); ok && key != path {
+			*keys = append(*keys, deprecatedKey{key: key, hint: hint, replacement: replacementKey(hint)})
+		}
+		collectDeprecatedKeys(field.Type, key, visiting, keys)
+	}
+}
+
+// replacementKey returns the key named by a hint of the form "use <key>",
+// ignoring any text after the key, e.g. "use server.listen_addr instead"
+func replacementKey(hint string) string {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(hint), "use ")
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimRight(fields[0], ".,;")
+}
+
+// checkDeprecated returns the deprecated keys that are set in k. With mapping
+// enabled, the value of a deprecated key is copied to its replacement unless
+// the replacement is set itself, and the origin of the value is carried over.
+func checkDeprecated(k *koanf.Koanf, origins keyOrigins, keys []deprecatedKey, mapping bool) ([]Deprecation, error) {
+	var found []Deprecation
+	for _, dk := range keys {
+		if !k.Exists(dk.key) {
+			continue
+		}
+
+		d := Deprecation{Key: dk.key, Hint: dk.hint, Replacement: dk.replacement}
+		if mapping && dk.replacement != "" && !k.Exists(dk.replacement) {
+			if err := k.Set(dk.replacement, k.Get(dk.key)); err != nil {
+				return nil, NewParseError("deprecated", "failed to map deprecated key "+dk.key, err)
+			}
+			mapped := make(keyOrigins)
+			for key, source := range origins {
+				if key == dk.key || strings.HasPrefix(key, dk.key+".") {
+					mapped[dk.replacement+strings.TrimPrefix(key, dk.key)] = source
+				}
+			}
+			for key, source := range mapped {
+				origins[key] = source
+			}
+			d.Mapped = true
+		}
+		found = append(found, d)
+	}
+	return found, nil
+}
+
+// reportDeprecations logs the deprecated keys found in the merged configuration
+// and delivers each as an EventDeprecatedKey
+func (cm *ConfigManager[T]) reportDeprecations(found []Deprecation) {
+	for _, d := range found {
+		cm.log().Warn("Deprecated configuration key",
+			"key", d.Key,
+			"hint", d.Hint,
+			"replacement", d.Replacement,
+			"mapped", d.Mapped,
+		)
+		cm.events.emit(Event{Type: EventDeprecatedKey, Time: time.Now(), Deprecation: d})
+	}
+}
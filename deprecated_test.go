@@ -0,0 +1,91 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// DeprecatedConfig renamed server.addr to server.listen_addr and dropped legacy
+type DeprecatedConfig struct {
+	Server struct {
+		Addr       string `koanf:"addr" deprecated:"use server.listen_addr instead"`
+		ListenAddr string `koanf:"listen_addr"`
+	} `koanf:"server"`
+	Legacy struct {
+		Mode string `koanf:"mode"`
+	} `koanf:"legacy" deprecated:"no longer has any effect"`
+}
+
+func TestDeprecatedKeys(t *testing.T) {
+	keys := deprecatedKeys(reflect.TypeOf(DeprecatedConfig{}))
+	assert.Equal(t, []deprecatedKey{
+		{key: "server.addr", hint: "use server.listen_addr instead", replacement: "server.listen_addr"},
+		{key: "legacy", hint: "no longer has any effect"},
+	}, keys)
+
+	assert.Equal(t, "a.b", replacementKey("use a.b."))
+	assert.Empty(t, replacementKey("use"))
+	assert.Empty(t, replacementKey("removed in v2"))
+}
+
+func TestConfigManager_DeprecatedKeys(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "server:\n  addr: :8080\nlegacy:\n  mode: on\n")
+
+	cm, err := NewBuilder[DeprecatedConfig]().
+		AddFile(configFile).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	// Without mapping the deprecated keys are only reported
+	assert.Equal(t, ":8080", cm.Get().Server.Addr)
+	assert.Empty(t, cm.Get().Server.ListenAddr)
+
+	event := nextEvent(t, cm.Events())
+	assert.Equal(t, EventDeprecatedKey, event.Type)
+	assert.Equal(t, Deprecation{
+		Key:         "server.addr",
+		Hint:        "use server.listen_addr instead",
+		Replacement: "server.listen_addr",
+	}, event.Deprecation)
+	event = nextEvent(t, cm.Events())
+	assert.Equal(t, EventDeprecatedKey, event.Type)
+	assert.Equal(t, "legacy", event.Deprecation.Key)
+	assert.Equal(t, EventLoaded, nextEvent(t, cm.Events()).Type)
+}
+
+func TestBuilder_WithDeprecatedKeyMapping(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "server:\n  addr: :8080\n")
+
+	cm, err := NewBuilder[DeprecatedConfig]().
+		AddFile(configFile).
+		WithDeprecatedKeyMapping().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, ":8080", cm.Get().Server.ListenAddr)
+	origin, ok := cm.Origin("server.listen_addr")
+	assert.True(t, ok)
+	assert.Equal(t, configFile, origin)
+
+	event := nextEvent(t, cm.Events())
+	assert.Equal(t, EventDeprecatedKey, event.Type)
+	assert.True(t, event.Deprecation.Mapped)
+	assert.Equal(t, EventLoaded, nextEvent(t, cm.Events()).Type)
+
+	// A value set for the replacement wins over the deprecated key
+	writeFile(t, configFile, "server:\n  addr: :8080\n  listen_addr: :9090\n")
+	require.NoError(t, cm.Reload(context.Background()))
+	assert.Equal(t, ":9090", cm.Get().Server.ListenAddr)
+	event = nextEvent(t, cm.Events())
+	assert.Equal(t, EventDeprecatedKey, event.Type)
+	assert.False(t, event.Deprecation.Mapped)
+}
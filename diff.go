@@ -0,0 +1,173 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements reflection based comparison of configuration values,
+// reporting the configuration key paths and values that differ between two
+// versions.
+package vcfg
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change describes a configuration value that differs between two versions.
+type Change struct {
+	// Path is the configuration key path of the value, e.g. "server.port"
+	Path string
+	// OldValue is the previous value, nil if the key was added
+	OldValue any
+	// NewValue is the new value, nil if the key was removed
+	NewValue any
+	// Secret reports that the value belongs to a field marked as secret; OldValue
+	// and NewValue are then masked
+	Secret bool
+}
+
+// String formats the change as "path: old -> new".
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Path, c.OldValue, c.NewValue)
+}
+
+// Diff compares two configurations and returns the values that differ, sorted
+// by path. Paths are built from koanf tags, falling back to the lowercased field
+// name; squashed or embedded structs do not add a path segment. Structs are
+// compared field by field and maps per key, while slices and other values are
+// compared as a whole. If only one of the configurations is nil, the result is a
+// single change with an empty path. The values of fields marked as secret (see
+// Redact) are masked.
+//
+// The values in the result share memory with the configurations and must not be
+// modified.
+func Diff[T any](oldCfg, newCfg *T) []Change {
+	var changes []Change
+	collectChanges(reflect.ValueOf(oldCfg), reflect.ValueOf(newCfg), "", false, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// changedPaths returns the sorted configuration key paths (e.g. "server.port")
+// whose values differ between oldCfg and newCfg.
+func changedPaths[T any](oldCfg, newCfg *T) []string {
+	return changePaths(Diff(oldCfg, newCfg))
+}
+
+// changePaths returns the paths of changes
+func changePaths(changes []Change) []string {
+	var paths []string
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	return paths
+}
+
+// collectChanges recursively walks oldValue and newValue in parallel and
+// appends a change for every differing leaf. secret marks values below a secret
+// field, whose changes are masked.
+func collectChanges(oldValue, newValue reflect.Value, path string, secret bool, changes *[]Change) {
+	addChange := func() {
+		oldLeaf, newLeaf := valueInterface(oldValue), valueInterface(newValue)
+		if reflect.DeepEqual(oldLeaf, newLeaf) {
+			return
+		}
+		change := Change{Path: path, OldValue: oldLeaf, NewValue: newLeaf}
+		if secret {
+			change = maskChange(change)
+		}
+		*changes = append(*changes, change)
+	}
+
+	// A map entry present on one side only
+	if !oldValue.IsValid() || !newValue.IsValid() {
+		addChange()
+		return
+	}
+
+	// Handle pointers
+	for oldValue.Kind() == reflect.Ptr || newValue.Kind() == reflect.Ptr {
+		if oldValue.Kind() != reflect.Ptr || newValue.Kind() != reflect.Ptr || oldValue.IsNil() || newValue.IsNil() {
+			addChange()
+			return
+		}
+		oldValue = oldValue.Elem()
+		newValue = newValue.Elem()
+	}
+
+	switch oldValue.Kind() {
+	case reflect.Struct:
+		t := oldValue.Type()
+		// Types decoded from text, e.g. time.Time or types.URL, are single values
+		if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+			addChange()
+			return
+		}
+		for i := range oldValue.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			collectChanges(oldValue.Field(i), newValue.Field(i), joinKeyPath(path, field), secret || isSecretField(field), changes)
+		}
+
+	case reflect.Map:
+		if oldValue.Type().Key().Kind() != reflect.String {
+			addChange()
+			return
+		}
+
+		keys := make(map[string]struct{})
+		for _, k := range oldValue.MapKeys() {
+			keys[k.String()] = struct{}{}
+		}
+		for _, k := range newValue.MapKeys() {
+			keys[k.String()] = struct{}{}
+		}
+
+		for key := range keys {
+			k := reflect.ValueOf(key).Convert(oldValue.Type().Key())
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			collectChanges(oldValue.MapIndex(k), newValue.MapIndex(k), keyPath, secret, changes)
+		}
+
+	default:
+		addChange()
+	}
+}
+
+// joinKeyPath appends the configuration key of field to path. Squashed and
+// embedded fields share their parent's path.
+func joinKeyPath(path string, field reflect.StructField) string {
+	name := strings.ToLower(field.Name)
+	if tag, ok := field.Tag.Lookup("koanf"); ok {
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			name = parts[0]
+		} else if len(parts) > 1 && parts[1] == "squash" {
+			return path
+		}
+	} else if field.Anonymous {
+		return path
+	}
+
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// valueInterface returns the value held by v, or nil for invalid values.
+func valueInterface(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Interface && v.IsNil() {
+		return nil
+	}
+	if !v.CanInterface() {
+		return fmt.Sprintf("%v", v)
+	}
+	return v.Interface()
+}
@@ -0,0 +1,70 @@
+package vcfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type DiffTestConfig struct {
+	Name   string `koanf:"name"`
+	Server struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"server"`
+	Cache    recorderConfig    `koanf:"cache"`
+	Labels   map[string]string `koanf:"labels"`
+	Tags     []string          `koanf:"tags"`
+	Timeout  *int              `koanf:"timeout"`
+	Untagged string
+}
+
+func TestChangedPaths(t *testing.T) {
+	timeout := 5
+	oldCfg := &DiffTestConfig{Name: "app", Labels: map[string]string{"a": "1", "b": "2"}, Tags: []string{"x"}}
+	oldCfg.Server.Host = "localhost"
+	oldCfg.Server.Port = 8080
+	oldCfg.Cache.Host = "cache"
+
+	t.Run("identical", func(t *testing.T) {
+		newCfg := *oldCfg
+		assert.Empty(t, changedPaths(oldCfg, &newCfg))
+	})
+
+	t.Run("nested and squashed fields", func(t *testing.T) {
+		newCfg := *oldCfg
+		newCfg.Server.Port = 9090
+		newCfg.Cache.Host = "other"
+		newCfg.Cache.Type = "recorder"
+		newCfg.Untagged = "value"
+		assert.Equal(t, []string{"cache.host", "cache.type", "server.port", "untagged"}, changedPaths(oldCfg, &newCfg))
+	})
+
+	t.Run("maps slices and pointers", func(t *testing.T) {
+		newCfg := *oldCfg
+		newCfg.Labels = map[string]string{"a": "1", "b": "3", "c": "4"}
+		newCfg.Tags = []string{"x", "y"}
+		newCfg.Timeout = &timeout
+		assert.Equal(t, []string{"labels.b", "labels.c", "tags", "timeout"}, changedPaths(oldCfg, &newCfg))
+	})
+}
+
+func TestDiff(t *testing.T) {
+	oldCfg := &DiffTestConfig{Name: "app", Labels: map[string]string{"a": "1", "b": "2"}}
+	newCfg := &DiffTestConfig{Name: "app", Labels: map[string]string{"b": "3", "c": "4"}}
+	newCfg.Server.Port = 9090
+
+	assert.Equal(t, []Change{
+		{Path: "labels.a", OldValue: "1", NewValue: nil},
+		{Path: "labels.b", OldValue: "2", NewValue: "3"},
+		{Path: "labels.c", OldValue: nil, NewValue: "4"},
+		{Path: "server.port", OldValue: 0, NewValue: 9090},
+	}, Diff(oldCfg, newCfg))
+	assert.Equal(t, "server.port: 0 -> 9090", Diff(oldCfg, newCfg)[3].String())
+
+	assert.Empty(t, Diff(oldCfg, oldCfg))
+
+	changes := Diff(nil, newCfg)
+	assert.Len(t, changes, 1)
+	assert.Empty(t, changes[0].Path)
+}
@@ -0,0 +1,185 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements Dump, which serializes the effective typed configuration
+// for debugging and "config show" style commands.
+package vcfg
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/nextpkg/vcfg/providers"
+)
+
+// DumpOption configures Dump.
+type DumpOption func(*dumpOptions)
+
+// dumpOptions holds the settings of a Dump call
+type dumpOptions struct {
+	// redact masks the fields marked as secret
+	redact bool
+	// origins annotates every value with the source that set it
+	origins bool
+}
+
+// WithDumpRedaction masks the values of fields marked as secret, see Redact.
+func WithDumpRedaction() DumpOption {
+	return func(o *dumpOptions) {
+		o.redact = true
+	}
+}
+
+// WithDumpOrigins annotates every value with a comment naming the source that
+// set it, or "default" if no source did, as reported by Origin. Annotations
+// require the "yaml" format.
+func WithDumpOrigins() DumpOption {
+	return func(o *dumpOptions) {
+		o.origins = true
+	}
+}
+
+// Dump serializes the effective configuration returned by Get in the given
+// format, "yaml", "json", "ini" or "properties". Unlike MarshalMerged it shows
+// the values the application runs with: struct tag defaults are applied,
+// references are expanded and Update changes are included, while keys without a
+// field in T are not. Keys follow the koanf tags, and durations and other
+// text-marshaling types are written as strings, so the output can be loaded
+// again as a config file.
+//
+// Secrets are included unless WithDumpRedaction is given.
+func (cm *ConfigManager[T]) Dump(format string, opts ...DumpOption) ([]byte, error) {
+	var o dumpOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	parser, err := providers.ParserForFormat(format)
+	if err != nil {
+		return nil, NewParseError("manager", "unsupported output format", err)
+	}
+
+	cfg := cm.Get()
+	if cfg == nil {
+		return nil, NewParseError("manager", "configuration not loaded", nil)
+	}
+	if o.redact {
+		cfg = Redact(cfg)
+	}
+
+	values, _ := dumpValue(reflect.ValueOf(cfg)).(map[string]any)
+	if values == nil {
+		values = map[string]any{}
+	}
+
+	isJSON := strings.EqualFold(strings.TrimPrefix(format, "."), "json")
+
+	var data []byte
+	switch {
+	case o.origins && isJSON:
+		return nil, NewParseError("manager", "origin annotations require the yaml format", nil)
+	case o.origins:
+		data, err = marshalAnnotatedYAML(values, cm.Origins())
+	case isJSON:
+		data, err = json.MarshalIndent(values, "", "  ")
+	default:
+		data, err = parser.Marshal(values)
+	}
+	if err != nil {
+		return nil, NewParseError("manager", "failed to marshal configuration", err)
+	}
+	return data, nil
+}
+
+// dumpValue converts v into the plain maps, slices and scalars of a config file
+func dumpValue(v reflect.Value) any {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+				return marshalText(marshaler)
+			}
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == durationType {
+		return fmt.Sprint(v.Interface())
+	}
+	if v.CanInterface() {
+		if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+			return marshalText(marshaler)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		values := make(map[string]any)
+		dumpFields(v, values)
+		return values
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		values := make(map[string]any, v.Len())
+		for _, key := range v.MapKeys() {
+			values[fmt.Sprint(key.Interface())] = dumpValue(v.MapIndex(key))
+		}
+		return values
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes())
+		}
+		items := make([]any, v.Len())
+		for i := range v.Len() {
+			items[i] = dumpValue(v.Index(i))
+		}
+		return items
+	default:
+		return v.Interface()
+	}
+}
+
+// dumpFields adds the exported fields of the struct v to values, inlining the
+// fields of squashed and embedded structs
+func dumpFields(v reflect.Value, values map[string]any) {
+	t := v.Type()
+	for i := range v.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("koanf") == "-" {
+			continue
+		}
+
+		name := joinKeyPath("", field)
+		if name == "" {
+			embedded := v.Field(i)
+			for embedded.Kind() == reflect.Ptr && !embedded.IsNil() {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				dumpFields(embedded, values)
+				continue
+			}
+			name = strings.ToLower(field.Name)
+		}
+
+		values[name] = dumpValue(v.Field(i))
+	}
+}
+
+// marshalText returns the text form of a value, or its error message
+func marshalText(marshaler encoding.TextMarshaler) string {
+	text, err := marshaler.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return string(text)
+}
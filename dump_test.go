@@ -0,0 +1,90 @@
+package vcfg
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type DumpTestConfig struct {
+	Name   string `koanf:"name"`
+	Server struct {
+		Host    string        `koanf:"host" default:"localhost"`
+		Port    int           `koanf:"port"`
+		Timeout time.Duration `koanf:"timeout" default:"30s"`
+	} `koanf:"server"`
+	Database struct {
+		Password string `koanf:"password" vcfg:"secret"`
+	} `koanf:"database"`
+	Cache  recorderConfig    `koanf:"cache"`
+	Labels map[string]string `koanf:"labels"`
+	Tags   []string          `koanf:"tags"`
+	Since  time.Time         `koanf:"since"`
+	Skip   string            `koanf:"-"`
+}
+
+func TestConfigManager_Dump(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, `name: app
+server:
+  port: 8080
+database:
+  password: s3cret
+cache:
+  type: recorder
+  host: redis
+labels:
+  team: core
+tags: [a, b]
+since: 2024-01-02T03:04:05Z
+unknown: ignored
+`)
+
+	cm, err := NewBuilder[DumpTestConfig]().AddFile(configFile).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	data, err := cm.Dump("json")
+	require.NoError(t, err)
+
+	var dumped map[string]any
+	require.NoError(t, json.Unmarshal(data, &dumped))
+	assert.Equal(t, map[string]any{
+		"name":     "app",
+		"server":   map[string]any{"host": "localhost", "port": 8080.0, "timeout": "30s"},
+		"database": map[string]any{"password": "s3cret"},
+		"cache":    map[string]any{"type": "recorder", "depends_on": nil, "enabled": nil, "host": "redis", "port": 6379.0},
+		"labels":   map[string]any{"team": "core"},
+		"tags":     []any{"a", "b"},
+		"since":    "2024-01-02T03:04:05Z",
+	}, dumped)
+
+	// Secrets are masked on request
+	data, err = cm.Dump("yaml", WithDumpRedaction())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "password: '[REDACTED]'")
+	assert.Contains(t, string(data), "timeout: 30s")
+	assert.NotContains(t, string(data), "s3cret")
+
+	// The YAML output loads back into the same configuration
+	reloadedFile := filepath.Join(t.TempDir(), "dump.yaml")
+	data, err = cm.Dump("yaml")
+	require.NoError(t, err)
+	writeFile(t, reloadedFile, string(data))
+	reloaded, err := NewBuilder[DumpTestConfig]().AddFile(reloadedFile).Build(context.Background())
+	require.NoError(t, err)
+	defer reloaded.Close()
+	assert.Equal(t, cm.Get(), reloaded.Get())
+
+	_, err = cm.Dump("toml")
+	assert.Error(t, err)
+
+	var nilManager *ConfigManager[DumpTestConfig]
+	_, err = nilManager.Dump("json")
+	assert.Error(t, err)
+}
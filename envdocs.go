@@ -0,0 +1,79 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the documentation of the environment variables that
+// set the keys of a configuration type.
+package vcfg
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// EnvVar documents the environment variable of a configuration key.
+type EnvVar struct {
+	// Key is the configuration key, e.g. server.port
+	Key string
+	// Name is the environment variable read by AddEnv, e.g. APP_SERVER_PORT.
+	// It is empty for keys containing underscores, since AddEnv maps every
+	// underscore of a variable name to a dot.
+	Name string
+	// Type is the Go type of the field, e.g. int or duration
+	Type string
+	// Default is the `default` tag of the field, if any
+	Default string
+	// Usage is the `usage` tag of the field, if any
+	Usage string
+}
+
+// GenerateEnvDocs lists the keys of T with the environment variables that set
+// them when the configuration is loaded with AddEnv(prefix): the prefix
+// followed by the upper-cased key with dots replaced by underscores, so
+// APP_SERVER_PORT sets server.port for the prefix "APP_". Keys are listed in
+// field order; maps and lists of structs are skipped, like for GenerateFlags.
+func GenerateEnvDocs[T any](prefix string) ([]EnvVar, error) {
+	fields, err := configFields[T]()
+	if err != nil {
+		return nil, fmt.Errorf("cannot document environment variables: %w", err)
+	}
+
+	vars := make([]EnvVar, 0, len(fields))
+	for _, field := range fields {
+		v := EnvVar{
+			Key:     field.key,
+			Type:    envTypeName(field.typ),
+			Default: field.field.Tag.Get("default"),
+			Usage:   field.field.Tag.Get("usage"),
+		}
+		if !strings.Contains(field.key, "_") {
+			v.Name = prefix + strings.ToUpper(strings.ReplaceAll(field.key, ".", "_"))
+		}
+		vars = append(vars, v)
+	}
+	return vars, nil
+}
+
+// envTypeName returns the type name shown for values of type t
+func envTypeName(t reflect.Type) string {
+	if t == durationType {
+		return "duration"
+	}
+	return t.String()
+}
+
+// WriteEnvTable writes vars as an aligned plain text table with the columns
+// VARIABLE, KEY, TYPE, DEFAULT and DESCRIPTION. Keys that cannot be set
+// through the environment show "-" as variable.
+func WriteEnvTable(w io.Writer, vars []EnvVar) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VARIABLE\tKEY\tTYPE\tDEFAULT\tDESCRIPTION")
+	for _, v := range vars {
+		name := v.Name
+		if name == "" {
+			name = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", name, v.Key, v.Type, v.Default, v.Usage)
+	}
+	return tw.Flush()
+}
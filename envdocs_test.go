@@ -0,0 +1,69 @@
+package vcfg
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type EnvDocsTestConfig struct {
+	Server struct {
+		Host    string        `koanf:"host" default:"localhost" usage:"Listen address"`
+		Port    int           `koanf:"port" default:"8080"`
+		Timeout time.Duration `koanf:"timeout" default:"30s"`
+	} `koanf:"server"`
+	Tags        []string          `koanf:"tags"`
+	MaxBody     int64             `koanf:"max_body"`
+	Labels      map[string]string `koanf:"labels"`
+	Internal    string            `koanf:"-"`
+	SkipFlagged string            `koanf:"flagless" flag:"-"`
+}
+
+func TestGenerateEnvDocs(t *testing.T) {
+	vars, err := GenerateEnvDocs[EnvDocsTestConfig]("APP_")
+	require.NoError(t, err)
+
+	assert.Equal(t, []EnvVar{
+		{Key: "server.host", Name: "APP_SERVER_HOST", Type: "string", Default: "localhost", Usage: "Listen address"},
+		{Key: "server.port", Name: "APP_SERVER_PORT", Type: "int", Default: "8080"},
+		{Key: "server.timeout", Name: "APP_SERVER_TIMEOUT", Type: "duration", Default: "30s"},
+		{Key: "tags", Name: "APP_TAGS", Type: "[]string"},
+		// AddEnv would map APP_MAX_BODY to max.body
+		{Key: "max_body", Type: "int64"},
+		{Key: "flagless", Name: "APP_FLAGLESS", Type: "string"},
+	}, vars)
+
+	_, err = GenerateEnvDocs[int]("APP_")
+	assert.Error(t, err)
+}
+
+func TestGenerateEnvDocs_MatchesAddEnv(t *testing.T) {
+	vars, err := GenerateEnvDocs[EnvDocsTestConfig]("VCFG_ENVDOCS_")
+	require.NoError(t, err)
+	for _, v := range vars {
+		if v.Key == "server.port" {
+			t.Setenv(v.Name, "9090")
+		}
+	}
+
+	cm, err := New[EnvDocsTestConfig](WithEnv("VCFG_ENVDOCS_"))
+	require.NoError(t, err)
+	defer cm.Close()
+	assert.Equal(t, 9090, cm.Get().Server.Port)
+}
+
+func TestWriteEnvTable(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteEnvTable(&buf, []EnvVar{
+		{Key: "server.port", Name: "APP_SERVER_PORT", Type: "int", Default: "8080", Usage: "Listen port"},
+		{Key: "max_body", Type: "int64"},
+	}))
+
+	assert.Equal(t, ""+
+		"VARIABLE         KEY          TYPE   DEFAULT  DESCRIPTION\n"+
+		"APP_SERVER_PORT  server.port  int    8080     Listen port\n"+
+		"-                max_body     int64           \n", buf.String())
+}
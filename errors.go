@@ -4,8 +4,16 @@
 package vcfg
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/knadh/koanf/v2"
+
+	"github.com/nextpkg/vcfg/plugins"
 )
 
 // ErrorType represents the category of configuration errors.
@@ -64,6 +72,10 @@ type ConfigError struct {
 	Message string
 	// Cause holds the underlying error that triggered this configuration error
 	Cause error
+	// Line is the 1-based line of a parse failure within the source, 0 if unknown
+	Line int
+	// Column is the 1-based column of a parse failure within the source, 0 if unknown
+	Column int
 }
 
 // Error implements the error interface by returning a formatted error message.
@@ -127,3 +139,83 @@ func NewParseError(source, message string, cause error) *ConfigError {
 func NewValidationError(source, message string, cause error) *ConfigError {
 	return NewConfigError(ErrorTypeValidationFailure, source, message, cause)
 }
+
+// NewPluginError 创建插件错误
+func NewPluginError(source, message string, cause error) *ConfigError {
+	return NewConfigError(ErrorTypePluginFailure, source, message, cause)
+}
+
+// wrapDiscoveryError converts a plugin discovery copy failure into a ConfigError of
+// type ErrorTypePluginFailure whose source is the config field path. Other errors are
+// returned unchanged.
+func wrapDiscoveryError(err error) error {
+	var discoveryErr *plugins.DiscoveryError
+	if errors.As(err, &discoveryErr) {
+		return NewPluginError(discoveryErr.FieldPath, "failed to copy plugin config", discoveryErr.Err)
+	}
+	return err
+}
+
+// yamlLinePattern matches the line reported by yaml.v3 syntax and type errors
+var yamlLinePattern = regexp.MustCompile(`\bline (\d+):`)
+
+// newSourceParseError creates the parse error for a provider that failed to load.
+// If the underlying parser reports a position, it is stored in Line and Column and
+// prefixed to the message as "source:line:column", so users can jump to the problem.
+func newSourceParseError(source string, provider koanf.Provider, cause error) *ConfigError {
+	parseErr := NewParseError(source, "failed to load from provider", cause)
+
+	line, column := parseErrorLocation(cause, provider)
+	if line == 0 {
+		return parseErr
+	}
+
+	parseErr.Line, parseErr.Column = line, column
+	location := fmt.Sprintf("%s:%d", source, line)
+	if column > 0 {
+		location = fmt.Sprintf("%s:%d", location, column)
+	}
+	parseErr.Message = location + ": " + parseErr.Message
+	return parseErr
+}
+
+// parseErrorLocation extracts the 1-based line and column of a parser error.
+// JSON errors carry a byte offset that is resolved against the provider's data;
+// YAML errors carry the line in their message. Zero values mean unknown.
+func parseErrorLocation(err error, provider koanf.Provider) (line, column int) {
+	offset := int64(-1)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	}
+
+	if offset >= 0 {
+		data, readErr := provider.ReadBytes()
+		if readErr != nil || offset > int64(len(data)) {
+			return 0, 0
+		}
+		return offsetToLineColumn(data[:offset])
+	}
+
+	if strings.Contains(err.Error(), "yaml:") {
+		if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+			return line, 0
+		}
+	}
+
+	return 0, 0
+}
+
+// offsetToLineColumn returns the 1-based line and column of the last byte of
+// prefix, which is where encoding/json reports an error.
+func offsetToLineColumn(prefix []byte) (line, column int) {
+	text := strings.TrimSuffix(string(prefix), "\n")
+	line = 1 + strings.Count(text, "\n")
+	column = len(text) - strings.LastIndex(text, "\n") - 1
+	return line, max(column, 1)
+}
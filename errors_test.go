@@ -2,9 +2,12 @@ package vcfg
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestErrorType_String tests the String method of ErrorType
@@ -173,6 +176,16 @@ func TestNewValidationError(t *testing.T) {
 	assert.Equal(t, originalErr, validationErr.Cause)
 }
 
+// TestNewPluginError tests the NewPluginError convenience function
+func TestNewPluginError(t *testing.T) {
+	originalErr := fmt.Errorf("copy failed")
+	pluginErr := NewPluginError("Services.Cache", "failed to copy plugin config", originalErr)
+
+	assert.Equal(t, ErrorTypePluginFailure, pluginErr.Type)
+	assert.Equal(t, "Services.Cache", pluginErr.Source)
+	assert.Equal(t, originalErr, pluginErr.Cause)
+}
+
 // TestErrorType_Coverage tests all error types for coverage
 func TestErrorType_Coverage(t *testing.T) {
 	// Test all error types for coverage
@@ -204,3 +217,39 @@ func TestErrorType_Coverage(t *testing.T) {
 	unknownType := ErrorType(999)
 	assert.Equal(t, "Unknown", unknownType.String())
 }
+
+// TestParseError_ReportsLocation tests that parse errors name the line of the problem
+func TestParseError_ReportsLocation(t *testing.T) {
+	tempDir := t.TempDir()
+
+	yamlFile := filepath.Join(tempDir, "config.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte("name: app\nserver:\n  port: 8080\n  host: a: b\n"), 0644))
+
+	jsonFile := filepath.Join(tempDir, "config.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte("{\n  \"name\": \"app\",\n  \"port\": 80x\n}\n"), 0644))
+
+	tests := []struct {
+		name     string
+		file     string
+		line     int
+		column   int
+		location string
+	}{
+		{"YAML", yamlFile, 4, 0, yamlFile + ":4: "},
+		{"JSON", jsonFile, 3, 13, jsonFile + ":3:13: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load[TestConfig](tt.file)
+			require.Error(t, err)
+
+			var configErr *ConfigError
+			require.ErrorAs(t, err, &configErr)
+			assert.Equal(t, ErrorTypeParseFailure, configErr.Type)
+			assert.Equal(t, tt.line, configErr.Line)
+			assert.Equal(t, tt.column, configErr.Column)
+			assert.Contains(t, err.Error(), tt.location)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the stream of typed lifecycle events returned by
+// ConfigManager.Events.
+package vcfg
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nextpkg/vcfg/plugins"
+)
+
+// eventBufferSize is the number of events buffered for a slow reader
+const eventBufferSize = 64
+
+// EventType identifies the kind of an Event.
+type EventType string
+
+const (
+	// EventLoaded reports the initial load of the configuration
+	EventLoaded EventType = "loaded"
+	// EventReloaded reports a successful reload; Reload holds its outcome
+	EventReloaded EventType = "reloaded"
+	// EventReloadFailed reports a failed reload; Reload holds its outcome and
+	// Err the error
+	EventReloadFailed EventType = "reload_failed"
+	// EventPluginReloaded reports a plugin Reload call, successful or not;
+	// Plugin holds the call and Err its error
+	EventPluginReloaded EventType = "plugin_reloaded"
+	// EventWatchError reports an error while watching Source; Err holds it
+	EventWatchError EventType = "watch_error"
+	// EventValidationWarning reports warnings of a loaded configuration, see
+	// validator.Warnings; Warnings holds them
+	EventValidationWarning EventType = "validation_warning"
+	// EventDeprecatedKey reports a key tagged `deprecated` that is set in the
+	// merged configuration; Deprecation describes it
+	EventDeprecatedKey EventType = "deprecated_key"
+)
+
+// Event is a configuration lifecycle event delivered by ConfigManager.Events.
+// Which payload fields are set depends on Type.
+type Event struct {
+	// Type identifies the kind of event
+	Type EventType
+	// Time is when the event occurred
+	Time time.Time
+	// Reload is the outcome of the reload for EventReloaded and EventReloadFailed
+	Reload ReloadInfo
+	// Plugin is the lifecycle call for EventPluginReloaded
+	Plugin plugins.LifecycleEvent
+	// Source is the watched source for EventWatchError
+	Source string
+	// Err is the error of failed reloads, failed plugin reloads and watch errors
+	Err error
+	// Warnings lists the warnings for EventValidationWarning
+	Warnings []string
+	// Deprecation is the deprecated key for EventDeprecatedKey
+	Deprecation Deprecation
+}
+
+// eventStream is the Observer feeding the Events channel. Events are dropped
+// rather than blocking the manager when the buffer is full.
+type eventStream struct {
+	// mu serializes sends with close
+	mu sync.Mutex
+	// ch buffers the events for the reader
+	ch chan Event
+	// closed reports whether ch has been closed
+	closed bool
+}
+
+// newEventStream creates an open event stream
+func newEventStream() *eventStream {
+	return &eventStream{ch: make(chan Event, eventBufferSize)}
+}
+
+// emit delivers event unless the stream is closed or its buffer is full
+func (s *eventStream) emit(event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- event:
+	default:
+	}
+}
+
+// close closes the channel; later events are discarded
+func (s *eventStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// ReloadCompleted implements Observer.
+func (s *eventStream) ReloadCompleted(info ReloadInfo) {
+	event := Event{Type: EventReloaded, Time: time.Now(), Reload: info}
+	if info.Err != nil {
+		event.Type = EventReloadFailed
+		event.Err = info.Err
+	}
+	s.emit(event)
+}
+
+// WatchFailed implements Observer.
+func (s *eventStream) WatchFailed(source string, err error) {
+	s.emit(Event{Type: EventWatchError, Time: time.Now(), Source: source, Err: err})
+}
+
+// PluginLifecycle implements plugins.Observer.
+func (s *eventStream) PluginLifecycle(event plugins.LifecycleEvent) {
+	if event.Phase != plugins.PhaseReload {
+		return
+	}
+	s.emit(Event{Type: EventPluginReloaded, Time: time.Now(), Plugin: event, Err: event.Err})
+}
+
+// Events returns the channel of lifecycle events of the manager: the initial
+// load, every reload attempt (including Update, Set, Commit and Rollback),
+// every plugin reload, every watch error and the validation warnings and
+// deprecated keys of each loaded configuration. All callers share the same
+// channel, so each event is received once.
+//
+// Up to 64 undelivered events are buffered; newer events are dropped while the
+// buffer is full rather than delaying reloads, so read the channel
+// continuously, e.g.:
+//
+//	go func() {
+//		for ev := range cm.Events() {
+//			if ev.Type == vcfg.EventReloadFailed {
+//				alert(ev.Err)
+//			}
+//		}
+//	}()
+//
+// The channel is closed by Close.
+func (cm *ConfigManager[T]) Events() <-chan Event {
+	return cm.events.ch
+}
+
+// emitLoaded reports the initial load of the configuration
+func (cm *ConfigManager[T]) emitLoaded() {
+	cm.events.emit(Event{Type: EventLoaded, Time: time.Now()})
+}
@@ -0,0 +1,113 @@
+package vcfg
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextEvent returns the next buffered event, failing if there is none
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	default:
+		require.FailNow(t, "no event buffered")
+		return Event{}
+	}
+}
+
+func TestConfigManager_Events(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\ncache:\n  host: one\n")
+
+	cm, err := NewBuilder[UpdateAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	events := cm.Events()
+
+	assert.Equal(t, EventLoaded, nextEvent(t, events).Type)
+
+	// A reload reports the plugin reload before the reload itself
+	writeFile(t, configFile, "name: app\ncache:\n  host: two\n")
+	require.NoError(t, cm.Reload(context.Background()))
+
+	event := nextEvent(t, events)
+	assert.Equal(t, EventPluginReloaded, event.Type)
+	assert.Equal(t, "recorder:cache", event.Plugin.Key)
+	assert.NoError(t, event.Err)
+
+	event = nextEvent(t, events)
+	assert.Equal(t, EventReloaded, event.Type)
+	assert.Equal(t, []string{"cache.host"}, event.Reload.ChangedPaths)
+	assert.False(t, event.Time.IsZero())
+
+	writeFile(t, configFile, "name: [broken\n")
+	require.Error(t, cm.Reload(context.Background()))
+	event = nextEvent(t, events)
+	assert.Equal(t, EventReloadFailed, event.Type)
+	assert.Error(t, event.Err)
+	assert.Equal(t, event.Err, event.Reload.Err)
+
+	cm.observers.WatchFailed("file:config.yaml", errors.New("watch failed"))
+	event = nextEvent(t, events)
+	assert.Equal(t, EventWatchError, event.Type)
+	assert.Equal(t, "file:config.yaml", event.Source)
+	assert.EqualError(t, event.Err, "watch failed")
+
+	// Close closes the channel
+	require.NoError(t, cm.Close())
+	_, ok := <-events
+	assert.False(t, ok)
+	require.NoError(t, cm.Close())
+}
+
+func TestConfigManager_Events_DropsWhenFull(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\n")
+
+	cm, err := NewBuilder[UpdateAppConfig]().
+		AddFile(configFile).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	// Reloads never block on an unread channel
+	for range eventBufferSize + 10 {
+		require.NoError(t, cm.Reload(context.Background()))
+	}
+	assert.Len(t, cm.Events(), eventBufferSize)
+	assert.Equal(t, EventLoaded, nextEvent(t, cm.Events()).Type)
+}
+
+type WarnedConfig struct {
+	Workers int `koanf:"workers" validate_warn:"max=64"`
+}
+
+func TestConfigManager_Events_ValidationWarning(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "workers: 128\n")
+
+	cm, err := NewBuilder[WarnedConfig]().
+		AddFile(configFile).
+		Build(context.Background())
+	require.NoError(t, err, "warnings must not fail the load")
+	defer cm.Close()
+
+	event := nextEvent(t, cm.Events())
+	assert.Equal(t, EventValidationWarning, event.Type)
+	assert.Equal(t, []string{`workers: failed rule "max=64"`}, event.Warnings)
+	assert.Equal(t, EventLoaded, nextEvent(t, cm.Events()).Type)
+
+	// A configuration without warnings reports none
+	writeFile(t, configFile, "workers: 8\n")
+	require.NoError(t, cm.Reload(context.Background()))
+	assert.Equal(t, EventReloaded, nextEvent(t, cm.Events()).Type)
+}
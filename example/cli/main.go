@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -12,79 +11,63 @@ import (
 	"github.com/urfave/cli/v3"
 )
 
-// ServerConfig defines the server configuration structure
+// ServerConfig defines the server configuration structure. The usage tags
+// document the command line flags generated for every field.
 type ServerConfig struct {
 	// Server settings
 	Server struct {
-		Host         string        `json:"host" yaml:"host" default:"localhost"`
-		Port         int           `json:"port" yaml:"port" default:"8080" validate:"min=1,max=65535"`
-		ReadTimeout  time.Duration `json:"read_timeout" yaml:"read_timeout" default:"30s"`
-		WriteTimeout time.Duration `json:"write_timeout" yaml:"write_timeout" default:"30s"`
-	} `json:"server" yaml:"server"`
+		Host         string        `koanf:"host" json:"host" yaml:"host" default:"localhost" usage:"Server host address"`
+		Port         int           `koanf:"port" json:"port" yaml:"port" default:"8080" validate:"min=1,max=65535" usage:"Server port number"`
+		ReadTimeout  time.Duration `koanf:"read_timeout" json:"read_timeout" yaml:"read_timeout" default:"30s" usage:"Server read timeout"`
+		WriteTimeout time.Duration `koanf:"write_timeout" json:"write_timeout" yaml:"write_timeout" default:"30s" usage:"Server write timeout"`
+	} `koanf:"server" json:"server" yaml:"server"`
 
 	// Database settings
 	Database struct {
-		URL         string `json:"url" yaml:"url" validate:"required"`
-		MaxConns    int    `json:"max_conns" yaml:"max_conns" default:"10" validate:"min=1"`
-		MaxIdle     int    `json:"max_idle" yaml:"max_idle" default:"5" validate:"min=1"`
-		MaxLifetime string `json:"max_lifetime" yaml:"max_lifetime" default:"1h"`
-	} `json:"database" yaml:"database"`
+		URL         string `koanf:"url" json:"url" yaml:"url" validate:"required" usage:"Database connection URL"`
+		MaxConns    int    `koanf:"max_conns" json:"max_conns" yaml:"max_conns" default:"10" validate:"min=1" usage:"Maximum open connections"`
+		MaxIdle     int    `koanf:"max_idle" json:"max_idle" yaml:"max_idle" default:"5" validate:"min=1" usage:"Maximum idle connections"`
+		MaxLifetime string `koanf:"max_lifetime" json:"max_lifetime" yaml:"max_lifetime" default:"1h" usage:"Maximum connection lifetime"`
+	} `koanf:"database" json:"database" yaml:"database"`
 
 	// Application settings
 	App struct {
-		Name        string `json:"name" yaml:"name" default:"CLI-App"`
-		Version     string `json:"version" yaml:"version" default:"1.0.0"`
-		Environment string `json:"environment" yaml:"environment" default:"development" validate:"oneof=development staging production"`
-		Debug       bool   `json:"debug" yaml:"debug" default:"false"`
-	} `json:"app" yaml:"app"`
+		Name        string `koanf:"name" json:"name" yaml:"name" default:"CLI-App" usage:"Application name"`
+		Version     string `koanf:"version" json:"version" yaml:"version" default:"1.0.0" usage:"Application version"`
+		Environment string `koanf:"environment" json:"environment" yaml:"environment" default:"development" validate:"oneof=development staging production" usage:"Application environment (development, staging, production)"`
+		Debug       bool   `koanf:"debug" json:"debug" yaml:"debug" default:"false" usage:"Enable debug mode"`
+	} `koanf:"app" json:"app" yaml:"app"`
 
 	// Logging configuration
 	Logging struct {
-		Level  string `json:"level" yaml:"level" default:"info" validate:"oneof=debug info warn error"`
-		Format string `json:"format" yaml:"format" default:"json" validate:"oneof=json text"`
-		Output string `json:"output" yaml:"output" default:"stdout" validate:"oneof=stdout stderr file both"`
-		File   string `json:"file" yaml:"file" default:"./app.log"`
-	} `json:"logging" yaml:"logging"`
+		Level  string `koanf:"level" json:"level" yaml:"level" default:"info" validate:"oneof=debug info warn error" usage:"Logging level (debug, info, warn, error)"`
+		Format string `koanf:"format" json:"format" yaml:"format" default:"json" validate:"oneof=json text" usage:"Log format (json, text)"`
+		Output string `koanf:"output" json:"output" yaml:"output" default:"stdout" validate:"oneof=stdout stderr file both" usage:"Log output (stdout, stderr, file, both)"`
+		File   string `koanf:"file" json:"file" yaml:"file" default:"./app.log" usage:"Log file path"`
+	} `koanf:"logging" json:"logging" yaml:"logging"`
 }
 
 func main() {
+	configFlags, err := vcfg.GenerateFlags[ServerConfig]()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	app := &cli.Command{
 		Name:        "vcfg-cli-example",
 		Usage:       "VCFG CLI integration example",
 		Description: "Demonstrates how to integrate VCFG with urfave/cli for configuration management",
 		Version:     "1.0.0",
-		Flags: []cli.Flag{
+		// The config file flag followed by one flag per configuration field,
+		// e.g. --server.port
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "config",
 				Aliases: []string{"c"},
 				Value:   "config.yaml",
 				Usage:   "Configuration file path",
 			},
-			&cli.StringFlag{
-				Name:  "server.host",
-				Usage: "Server host address",
-			},
-			&cli.IntFlag{
-				Name:  "server.port",
-				Usage: "Server port number",
-			},
-			&cli.StringFlag{
-				Name:  "app.environment",
-				Usage: "Application environment (development, staging, production)",
-			},
-			&cli.BoolFlag{
-				Name:  "app.debug",
-				Usage: "Enable debug mode",
-			},
-			&cli.StringFlag{
-				Name:  "logging.level",
-				Usage: "Logging level (debug, info, warn, error)",
-			},
-			&cli.StringFlag{
-				Name:  "database.url",
-				Usage: "Database connection URL",
-			},
-		},
+		}, configFlags...),
 		Commands: []*cli.Command{
 			{
 				Name:  "serve",
@@ -141,9 +124,9 @@ func runServer(ctx context.Context, cmd *cli.Command) error {
 	cm, err := vcfg.NewBuilder[ServerConfig]().
 		AddFile(cmd.String("config")). // Configuration file
 		AddEnv("VCFG_").               // Environment variables with VCFG_ prefix
-		// Note: CLI flags integration requires special handling in urfave/cli context
-		WithWatch().  // Enable file watching
-		WithPlugin(). // Enable plugins
+		AddCliFlags(cmd, ".").         // Flags set on the command line, e.g. --server.port
+		WithWatch().                   // Enable file watching
+		WithPlugin().                  // Enable plugins
 		Build(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to build configuration: %w", err)
@@ -178,7 +161,7 @@ func showConfig(ctx context.Context, cmd *cli.Command) error {
 	cm, err := vcfg.NewBuilder[ServerConfig]().
 		AddFile(cmd.String("config")).
 		AddEnv("VCFG_").
-		// Note: CLI flags integration requires special handling
+		AddCliFlags(cmd, ".").
 		Build(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
@@ -189,14 +172,34 @@ func showConfig(ctx context.Context, cmd *cli.Command) error {
 
 	// Output format based on logging.format configuration
 	if config.Logging.Format == "json" {
-		return showConfigJSON(config)
+		return showConfigJSON(cm)
+	}
+	if err := showConfigText(config); err != nil {
+		return err
+	}
+	showPlugins(cm)
+	return nil
+}
+
+// showPlugins lists the plugin instances configured below "plugins". The section
+// has no field in ServerConfig, so it is read with the key accessors.
+func showPlugins(cm *vcfg.ConfigManager[ServerConfig]) {
+	names := cm.MapKeys("plugins")
+	if len(names) == 0 {
+		return
+	}
+
+	fmt.Printf("\n=== Plugins ===\n")
+	for _, name := range names {
+		fmt.Printf("%s: type=%s level=%s\n", name,
+			cm.String("plugins."+name+".type"), cm.String("plugins."+name+".level"))
 	}
-	return showConfigText(config)
 }
 
-// showConfigJSON outputs configuration in JSON format
-func showConfigJSON(config *ServerConfig) error {
-	jsonData, err := json.MarshalIndent(config, "", "  ")
+// showConfigJSON outputs the effective configuration in JSON format with
+// secrets masked
+func showConfigJSON(cm *vcfg.ConfigManager[ServerConfig]) error {
+	jsonData, err := cm.Dump("json", vcfg.WithDumpRedaction())
 	if err != nil {
 		return fmt.Errorf("failed to marshal config to JSON: %w", err)
 	}
@@ -242,7 +245,7 @@ func validateConfig(ctx context.Context, cmd *cli.Command) error {
 	cm, err := vcfg.NewBuilder[ServerConfig]().
 		AddFile(cmd.String("config")).
 		AddEnv("VCFG_").
-		// Note: CLI flags integration requires special handling
+		AddCliFlags(cmd, ".").
 		Build(ctx)
 	if err != nil {
 		fmt.Printf("❌ Configuration validation failed: %v\n", err)
@@ -163,7 +163,7 @@ type HealthConfig struct {
 	plugins.BaseConfig `koanf:",squash"`
 	Port               int      `koanf:"port" default:"8081" validate:"min=1,max=65535"`
 	Path               string   `koanf:"path" default:"/health"`
-	Checks             []string `koanf:"checks" default:"[database,redis,external_api]"`
+	Checks             []string `koanf:"checks" default:"[database,redis,external_api]" validate:"dive,required"`
 	Timeout            string   `koanf:"timeout" default:"5s"`
 	Interval           string   `koanf:"interval" default:"10s"`
 }
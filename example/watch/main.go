@@ -29,53 +29,6 @@ type DatabaseConfig struct {
 	Name string `yaml:"name" json:"name"`
 }
 
-// ConfigChangeWatcher watches for configuration changes and displays them
-type ConfigChangeWatcher struct {
-	cm         *vcfg.ConfigManager[AppConfig]
-	lastConfig *AppConfig
-}
-
-// NewConfigChangeWatcher creates a new configuration change watcher
-func NewConfigChangeWatcher(cm *vcfg.ConfigManager[AppConfig]) *ConfigChangeWatcher {
-	return &ConfigChangeWatcher{
-		cm:         cm,
-		lastConfig: cm.Get(),
-	}
-}
-
-// StartWatching starts watching for configuration changes
-func (w *ConfigChangeWatcher) StartWatching(ctx context.Context) {
-	ticker := time.NewTicker(500 * time.Millisecond) // Check every 500ms
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			currentConfig := w.cm.Get()
-			if w.hasConfigChanged(currentConfig) {
-				fmt.Println("\n🔄 Configuration change detected!")
-				printConfigComparison(w.lastConfig, currentConfig)
-				w.lastConfig = currentConfig
-				fmt.Println("\n⏳ Waiting for more changes... (Ctrl+C to exit)")
-			}
-		}
-	}
-}
-
-// hasConfigChanged checks if the configuration has changed
-func (w *ConfigChangeWatcher) hasConfigChanged(newConfig *AppConfig) bool {
-	if w.lastConfig == nil {
-		return true
-	}
-	return w.lastConfig.Server.Host != newConfig.Server.Host ||
-		w.lastConfig.Server.Port != newConfig.Server.Port ||
-		w.lastConfig.Database.Host != newConfig.Database.Host ||
-		w.lastConfig.Database.Port != newConfig.Database.Port ||
-		w.lastConfig.Database.Name != newConfig.Database.Name
-}
-
 func main() {
 	fmt.Println("🔄 VCFG Hot Reload Demo")
 	fmt.Println("=======================")
@@ -103,13 +56,13 @@ func main() {
 	fmt.Println("\n⏳ Watching for configuration changes...")
 	fmt.Println("   (Changes will be detected automatically)")
 
-	// Create context for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Start configuration change watcher
-	watcher := NewConfigChangeWatcher(cm)
-	go watcher.StartWatching(ctx)
+	// React to every validated configuration change
+	unsubscribe := cm.OnChangeDiff(func(_, _ *AppConfig, changes []vcfg.Change) {
+		fmt.Println("\n🔄 Configuration change detected!")
+		printChanges(changes)
+		fmt.Println("\n⏳ Waiting for more changes... (Ctrl+C to exit)")
+	})
+	defer unsubscribe()
 
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
@@ -117,7 +70,6 @@ func main() {
 	<-sigChan
 
 	fmt.Println("\n🛑 Shutting down...")
-	cancel() // Stop the watcher
 	cm.DisableWatch()
 }
 
@@ -130,39 +82,15 @@ func printConfig(prefix string, config *AppConfig) {
 		config.Database.Name)
 }
 
-// printConfigComparison prints a comparison between old and new configurations
-func printConfigComparison(oldConfig, newConfig *AppConfig) {
+// printChanges prints the values that changed in a reload
+func printChanges(changes []vcfg.Change) {
 	fmt.Printf("\n📊 Configuration Changes [%s]\n", time.Now().Format("15:04:05"))
 
-	// Server changes
-	if oldConfig.Server.Host != newConfig.Server.Host {
-		fmt.Printf("├─ Server Host: %s → %s ✨\n", oldConfig.Server.Host, newConfig.Server.Host)
-	} else {
-		fmt.Printf("├─ Server Host: %s (unchanged)\n", newConfig.Server.Host)
-	}
-
-	if oldConfig.Server.Port != newConfig.Server.Port {
-		fmt.Printf("├─ Server Port: %d → %d ✨\n", oldConfig.Server.Port, newConfig.Server.Port)
-	} else {
-		fmt.Printf("├─ Server Port: %d (unchanged)\n", newConfig.Server.Port)
-	}
-
-	// Database changes
-	if oldConfig.Database.Host != newConfig.Database.Host {
-		fmt.Printf("├─ Database Host: %s → %s ✨\n", oldConfig.Database.Host, newConfig.Database.Host)
-	} else {
-		fmt.Printf("├─ Database Host: %s (unchanged)\n", newConfig.Database.Host)
-	}
-
-	if oldConfig.Database.Port != newConfig.Database.Port {
-		fmt.Printf("├─ Database Port: %d → %d ✨\n", oldConfig.Database.Port, newConfig.Database.Port)
-	} else {
-		fmt.Printf("├─ Database Port: %d (unchanged)\n", newConfig.Database.Port)
-	}
-
-	if oldConfig.Database.Name != newConfig.Database.Name {
-		fmt.Printf("└─ Database Name: %s → %s ✨\n", oldConfig.Database.Name, newConfig.Database.Name)
-	} else {
-		fmt.Printf("└─ Database Name: %s (unchanged)\n", newConfig.Database.Name)
+	for i, change := range changes {
+		branch := "├─"
+		if i == len(changes)-1 {
+			branch = "└─"
+		}
+		fmt.Printf("%s %s: %v → %v ✨\n", branch, change.Path, change.OldValue, change.NewValue)
 	}
 }
@@ -0,0 +1,277 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the expansion of ${...} references in string values
+// before they are unmarshaled: references to environment variables such as
+// ${DB_PASSWORD} and, with interpolation, to other keys such as ${database.host}.
+package vcfg
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+// refResolver resolves the expression inside a ${...} reference. ok reports
+// whether the reference was resolved; unresolved references are kept verbatim.
+type refResolver func(expr string) (value any, ok bool, err error)
+
+// expandValues returns a koanf instance holding the values of k with the
+// references in all string values expanded, or k itself if neither environment
+// expansion nor interpolation is enabled. k is not modified, so the merged values
+// keep the references and expanded secrets do not leak into MarshalMerged or
+// snapshots.
+func (cm *ConfigManager[T]) expandValues(k *koanf.Koanf) (*koanf.Koanf, error) {
+	if !cm.expandEnv && !cm.interpolate {
+		return k, nil
+	}
+
+	r := &valueExpander{
+		flat:        k.All(),
+		env:         cm.expandEnv,
+		interpolate: cm.interpolate,
+		done:        make(map[string]bool),
+	}
+
+	keys := make([]string, 0, len(r.flat))
+	for key := range r.flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := r.expandKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	expanded := koanf.New(".")
+	if err := expanded.Load(mapProvider(maps.Unflatten(r.flat, ".")), nil); err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+// valueExpander expands the references in a flattened configuration, resolving
+// referenced keys first so references can be chained
+type valueExpander struct {
+	// flat maps key paths to leaf values; expanded values replace the originals
+	flat map[string]any
+	// env enables references to environment variables
+	env bool
+	// interpolate enables references to other keys
+	interpolate bool
+	// done marks the keys whose values are fully expanded
+	done map[string]bool
+	// stack lists the keys being expanded, to detect reference cycles
+	stack []string
+}
+
+// expandKey expands the value of key unless that already happened
+func (r *valueExpander) expandKey(key string) error {
+	if r.done[key] {
+		return nil
+	}
+	for i, pending := range r.stack {
+		if pending == key {
+			cycle := append(append([]string{}, r.stack[i:]...), key)
+			return fmt.Errorf("reference cycle %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	r.stack = append(r.stack, key)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	value, err := r.expandValue(r.flat[key], key)
+	if err != nil {
+		return err
+	}
+	r.flat[key] = value
+	r.done[key] = true
+	return nil
+}
+
+// expandValue expands the references in a string value or the string elements of a slice
+func (r *valueExpander) expandValue(value any, path string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		expanded, err := expandRefs(v, r.resolve)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return expanded, nil
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			expanded, err := r.expandValue(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = expanded
+		}
+		return items, nil
+	default:
+		return value, nil
+	}
+}
+
+// resolve resolves a reference expression. A reference naming an existing key
+// takes precedence over an environment variable of the same name.
+func (r *valueExpander) resolve(expr string) (any, bool, error) {
+	if r.interpolate {
+		if _, ok := r.flat[expr]; ok {
+			return r.resolveKey(expr)
+		}
+	}
+
+	name, fallback, mode := splitEnvDefault(expr)
+	if r.interpolate {
+		if _, ok := r.flat[name]; ok && mode != "" {
+			value, _, err := r.resolveKey(name)
+			if err == nil && mode == ":-" && value == "" {
+				value = fallback
+			}
+			return value, true, err
+		}
+	}
+
+	if r.env && isEnvName(name) {
+		return lookupEnv(name, fallback, mode), true, nil
+	}
+
+	switch {
+	case r.interpolate && mode != "":
+		return fallback, true, nil
+	case r.interpolate && strings.Contains(name, "."):
+		return nil, false, fmt.Errorf("reference to unknown key %s", name)
+	}
+	return nil, false, nil
+}
+
+// resolveKey returns the expanded value of key
+func (r *valueExpander) resolveKey(key string) (any, bool, error) {
+	if err := r.expandKey(key); err != nil {
+		return nil, false, err
+	}
+	return r.flat[key], true, nil
+}
+
+// expandEnv replaces the environment variable references in s:
+//
+//   - ${NAME} expands to the value of NAME, or "" if it is unset
+//   - ${NAME:-default} expands to default if NAME is unset or empty
+//   - ${NAME-default} expands to default if NAME is unset
+//   - $${ is kept as a literal ${
+//
+// Text that is not a well-formed reference, such as a lone $ or ${}, is kept as is.
+func expandEnv(s string) (string, error) {
+	expanded, err := expandRefs(s, func(expr string) (any, bool, error) {
+		name, fallback, mode := splitEnvDefault(expr)
+		if !isEnvName(name) {
+			return nil, false, nil
+		}
+		return lookupEnv(name, fallback, mode), true, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return expanded.(string), nil
+}
+
+// lookupEnv returns the value of the environment variable name, applying the
+// default of a ${NAME:-default} or ${NAME-default} reference
+func lookupEnv(name, fallback, mode string) string {
+	value, ok := os.LookupEnv(name)
+	switch {
+	case mode == ":-" && value == "":
+		return fallback
+	case mode == "-" && !ok:
+		return fallback
+	}
+	return value
+}
+
+// expandRefs replaces the ${...} references in s with the values returned by
+// resolve and turns $${ into a literal ${. If s consists of a single resolved
+// reference, the referenced value is returned with its type, e.g. an int;
+// otherwise values are formatted into the string.
+func expandRefs(s string, resolve refResolver) (any, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+
+		// $${ escapes a literal ${
+		if start > 0 && s[start-1] == '$' {
+			b.WriteString(s[:start-1])
+			b.WriteString("${")
+			s = s[start+2:]
+			continue
+		}
+
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated reference in %q", s)
+		}
+		end += start
+
+		value, ok, err := resolve(s[start+2 : end])
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			// Not a reference this expansion handles; keep it verbatim
+			b.WriteString(s[:end+1])
+			s = s[end+1:]
+			continue
+		}
+
+		// A value that is a single reference keeps the referenced type
+		if b.Len() == 0 && start == 0 && end == len(s)-1 {
+			return value, nil
+		}
+		b.WriteString(s[:start])
+		b.WriteString(fmt.Sprint(value))
+		s = s[end+1:]
+	}
+}
+
+// splitEnvDefault splits a reference expression into the variable name, the
+// default value and the default operator (":-", "-" or "" for none)
+func splitEnvDefault(expr string) (name, fallback, mode string) {
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case strings.HasPrefix(expr[i:], ":-"):
+			return expr[:i], expr[i+2:], ":-"
+		case expr[i] == '-':
+			return expr[:i], expr[i+1:], "-"
+		}
+	}
+	return expr, "", ""
+}
+
+// isEnvName reports whether name is a valid environment variable name: letters,
+// digits and underscores, not starting with a digit
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("VCFG_USER", "admin")
+	t.Setenv("VCFG_EMPTY", "")
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{"${VCFG_USER}", "admin"},
+		{"user=${VCFG_USER}@${VCFG_USER}", "user=admin@admin"},
+		{"${VCFG_UNSET}", ""},
+		{"${VCFG_UNSET:-fallback}", "fallback"},
+		{"${VCFG_EMPTY:-fallback}", "fallback"},
+		{"${VCFG_EMPTY-fallback}", ""},
+		{"${VCFG_UNSET-fallback}", "fallback"},
+		{"${VCFG_UNSET:-a-b}", "a-b"},
+		{"$${VCFG_USER}", "${VCFG_USER}"},
+		{"cost: $5 ${}", "cost: $5 ${}"},
+		{"${database.host}", "${database.host}"},
+	}
+	for _, tt := range tests {
+		got, err := expandEnv(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := expandEnv("${VCFG_USER")
+	assert.ErrorContains(t, err, "unterminated reference")
+}
+
+type ExpandTestConfig struct {
+	Database struct {
+		Host     string   `koanf:"host"`
+		Port     int      `koanf:"port"`
+		Password string   `koanf:"password"`
+		Replicas []string `koanf:"replicas"`
+	} `koanf:"database"`
+}
+
+func TestBuilder_WithEnvExpansion(t *testing.T) {
+	t.Setenv("VCFG_DB_PASSWORD", "s3cret")
+	t.Setenv("VCFG_DB_PORT", "6543")
+	t.Setenv("VCFG_REPLICA", "db2")
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, `database:
+  host: ${VCFG_DB_HOST:-localhost}
+  port: ${VCFG_DB_PORT}
+  password: ${VCFG_DB_PASSWORD}
+  replicas: [db1, "${VCFG_REPLICA}"]
+`)
+
+	cm, err := NewBuilder[ExpandTestConfig]().
+		AddFile(configFile).
+		WithEnvExpansion().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, "localhost", cfg.Database.Host)
+	assert.Equal(t, 6543, cfg.Database.Port)
+	assert.Equal(t, "s3cret", cfg.Database.Password)
+	assert.Equal(t, []string{"db1", "db2"}, cfg.Database.Replicas)
+
+	// The merged values keep the references
+	merged, err := cm.MarshalMerged("yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(merged), "s3cret")
+
+	var view ExpandTestConfig
+	require.NoError(t, cm.UnmarshalInto(&view))
+	assert.Equal(t, "s3cret", view.Database.Password)
+
+	// Reloads see changed variables
+	t.Setenv("VCFG_DB_PASSWORD", "rotated")
+	require.NoError(t, cm.reload(context.Background()))
+	assert.Equal(t, "rotated", cm.Get().Database.Password)
+
+	// Without the option values are kept verbatim
+	plain, err := NewBuilder[ExpandTestConfig]().AddFile(configFile).WithLazyLoad().Build(context.Background())
+	require.NoError(t, err)
+	defer plain.Close()
+	writeFile(t, configFile, "database:\n  password: ${VCFG_DB_PASSWORD}\n")
+	cfg, err = plain.GetOrLoad()
+	require.NoError(t, err)
+	assert.Equal(t, "${VCFG_DB_PASSWORD}", cfg.Database.Password)
+}
+
+type InterpolateTestConfig struct {
+	Database struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+		DSN  string `koanf:"dsn"`
+	} `koanf:"database"`
+	Replica struct {
+		Port int    `koanf:"port"`
+		URL  string `koanf:"url"`
+	} `koanf:"replica"`
+	Cache   string   `koanf:"cache"`
+	Mirrors []string `koanf:"mirrors"`
+	Literal string   `koanf:"literal"`
+}
+
+func TestBuilder_WithInterpolation(t *testing.T) {
+	t.Setenv("VCFG_CACHE_HOST", "redis")
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, `database:
+  host: db.internal
+  port: 5432
+  dsn: postgres://${database.host}:${database.port}/app
+replica:
+  port: ${database.port}
+  url: ${database.dsn}?replica=true
+cache: ${VCFG_CACHE_HOST}:${cache.port:-6379}
+mirrors: ["${database.host}", other]
+literal: $${database.host}
+`)
+
+	cm, err := NewBuilder[InterpolateTestConfig]().
+		AddFile(configFile).
+		WithInterpolation().
+		WithEnvExpansion().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, "postgres://db.internal:5432/app", cfg.Database.DSN)
+	assert.Equal(t, 5432, cfg.Replica.Port)
+	assert.Equal(t, "postgres://db.internal:5432/app?replica=true", cfg.Replica.URL)
+	assert.Equal(t, "redis:6379", cfg.Cache)
+	assert.Equal(t, []string{"db.internal", "other"}, cfg.Mirrors)
+	assert.Equal(t, "${database.host}", cfg.Literal)
+
+	merged, err := cm.MarshalMerged("yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(merged), "${database.host}:${database.port}")
+
+	// Cycles and unknown keys fail the load
+	writeFile(t, configFile, "database:\n  host: ${replica.url}\nreplica:\n  url: ${database.host}\n")
+	err = cm.reload(context.Background())
+	assert.ErrorContains(t, err, "reference cycle database.host -> replica.url -> database.host")
+
+	writeFile(t, configFile, "database:\n  dsn: ${database.hots}\n")
+	err = cm.reload(context.Background())
+	assert.ErrorContains(t, err, "database.dsn: reference to unknown key database.hots")
+}
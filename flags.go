@@ -0,0 +1,299 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the generation of command line flags from the struct
+// tags of a configuration type, for urfave/cli and spf13/pflag.
+package vcfg
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/urfave/cli/v3"
+)
+
+// flagSpec describes a flag generated for a configuration field
+type flagSpec struct {
+	// name is the flag name, the dotted key of the field
+	name string
+	// short is an optional one-letter alias
+	short string
+	// usage is the help text
+	usage string
+	// def is the default value, the tag itself for string flags and converted
+	// by schemaDefault for the others; nil if none
+	def any
+	// typ is the field type, with pointers removed
+	typ reflect.Type
+}
+
+// GenerateFlags returns urfave/cli flags for the fields of T, so the flags need
+// not be declared a second time next to the configuration struct. Every field
+// holding a scalar, a duration or a list of scalars gets a flag:
+//
+//   - the name is the dotted key of the field, e.g. server.port, so the flags
+//     map back to the configuration with AddCliFlags(cmd, ".")
+//   - `flag:"-"` skips the field
+//   - the `short` tag adds a one-letter alias, e.g. short:"p"
+//   - the `usage` tag becomes the help text
+//   - the `default` tag becomes the default shown in the help text
+//
+// Nested structs are expanded, maps and lists of structs are skipped. Since
+// AddCliFlags only applies flags that were set, the defaults shown are those
+// vcfg applies anyway.
+func GenerateFlags[T any]() ([]cli.Flag, error) {
+	specs, err := flagSpecs[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]cli.Flag, 0, len(specs))
+	for _, spec := range specs {
+		flags = append(flags, spec.cliFlag())
+	}
+	return flags, nil
+}
+
+// GeneratePFlags returns a pflag.FlagSet with flags for the fields of T,
+// derived from the struct tags as described for GenerateFlags. Add them to a
+// cobra command with cmd.Flags().AddFlagSet and load them with AddPFlags.
+func GeneratePFlags[T any]() (*pflag.FlagSet, error) {
+	specs, err := flagSpecs[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	fs := pflag.NewFlagSet(reflect.TypeOf((*T)(nil)).Elem().Name(), pflag.ContinueOnError)
+	for _, spec := range specs {
+		spec.addPFlag(fs)
+	}
+	return fs, nil
+}
+
+// flagSpecs returns the flags of the struct type T
+func flagSpecs[T any]() ([]flagSpec, error) {
+	fields, err := configFields[T]()
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate flags: %w", err)
+	}
+
+	specs := make([]flagSpec, 0, len(fields))
+	for _, field := range fields {
+		if field.field.Tag.Get("flag") == "-" {
+			continue
+		}
+
+		spec := flagSpec{
+			name:  field.key,
+			short: field.field.Tag.Get("short"),
+			usage: field.field.Tag.Get("usage"),
+			typ:   field.typ,
+		}
+		if len(spec.short) > 1 {
+			return nil, fmt.Errorf("invalid short flag for %s: %q is not a single letter", field.key, spec.short)
+		}
+		if value, ok := field.field.Tag.Lookup("default"); ok {
+			spec.def = value
+			if !isStringFlag(field.typ) {
+				def, err := schemaDefault(field.typ, value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid default for %s: %w", field.key, err)
+				}
+				spec.def = def
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// configField is a field of a configuration struct holding a scalar, a
+// duration or a list of scalars, i.e. a value that can be given as text
+type configField struct {
+	// key is the dotted key of the field, e.g. server.port
+	key string
+	// field is the struct field, for its tags
+	field reflect.StructField
+	// typ is the field type, with pointers removed
+	typ reflect.Type
+}
+
+// configFields returns the text-valued fields of the struct type T in
+// declaration order, expanding nested structs. Maps and lists of structs are
+// skipped.
+func configFields[T any]() ([]configField, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", t)
+	}
+
+	var fields []configField
+	collectConfigFields(t, "", make(map[reflect.Type]bool), &fields)
+	return fields, nil
+}
+
+// collectConfigFields appends the text-valued fields of t below path to fields;
+// visiting holds the struct types being expanded so recursive types terminate
+func collectConfigFields(t reflect.Type, path string, visiting map[reflect.Type]bool, fields *[]configField) {
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("koanf") == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		key := joinKeyPath(path, field)
+		if fieldType.Kind() == reflect.Struct && !isFlagScalar(fieldType) {
+			collectConfigFields(fieldType, key, visiting, fields)
+			continue
+		}
+		if key == path {
+			// A squashed field that is no struct has no key of its own
+			key = joinKeyPath(path, reflect.StructField{Name: field.Name})
+		}
+
+		if !isFlagScalar(fieldType) {
+			if fieldType.Kind() != reflect.Slice || !isFlagScalar(fieldType.Elem()) {
+				continue
+			}
+		}
+		*fields = append(*fields, configField{key: key, field: field, typ: fieldType})
+	}
+}
+
+// isFlagScalar reports whether values of type t fit into a single flag value
+func isFlagScalar(t reflect.Type) bool {
+	if t == durationType || t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isStringFlag reports whether fields of type t take their flag value as a
+// string, decoded like a string in a file: text types, times, byte slices and
+// int64 fields, which accept byte sizes such as "500MB"
+func isStringFlag(t reflect.Type) bool {
+	if t == durationType {
+		return false
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Slice:
+		return false
+	default:
+		return true
+	}
+}
+
+// aliases returns the short alias of the flag as a list
+func (s flagSpec) aliases() []string {
+	if s.short == "" {
+		return nil
+	}
+	return []string{s.short}
+}
+
+// cliFlag returns the urfave/cli flag for the spec
+func (s flagSpec) cliFlag() cli.Flag {
+	if isStringFlag(s.typ) {
+		return &cli.StringFlag{Name: s.name, Aliases: s.aliases(), Usage: s.usage, Value: s.stringDefault()}
+	}
+	if s.typ == durationType {
+		flag := &cli.DurationFlag{Name: s.name, Aliases: s.aliases(), Usage: s.usage}
+		if def, ok := s.def.(string); ok {
+			flag.Value, _ = time.ParseDuration(def)
+		}
+		return flag
+	}
+
+	switch s.typ.Kind() {
+	case reflect.Bool:
+		flag := &cli.BoolFlag{Name: s.name, Aliases: s.aliases(), Usage: s.usage}
+		flag.Value, _ = s.def.(bool)
+		return flag
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		flag := &cli.Int64Flag{Name: s.name, Aliases: s.aliases(), Usage: s.usage}
+		flag.Value, _ = s.def.(int64)
+		return flag
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		flag := &cli.Uint64Flag{Name: s.name, Aliases: s.aliases(), Usage: s.usage}
+		flag.Value, _ = s.def.(uint64)
+		return flag
+	case reflect.Float32, reflect.Float64:
+		flag := &cli.Float64Flag{Name: s.name, Aliases: s.aliases(), Usage: s.usage}
+		flag.Value, _ = s.def.(float64)
+		return flag
+	default:
+		flag := &cli.StringSliceFlag{Name: s.name, Aliases: s.aliases(), Usage: s.usage}
+		flag.Value, _ = s.def.([]string)
+		return flag
+	}
+}
+
+// addPFlag adds the pflag flag for the spec to fs
+func (s flagSpec) addPFlag(fs *pflag.FlagSet) {
+	if isStringFlag(s.typ) {
+		fs.StringP(s.name, s.short, s.stringDefault(), s.usage)
+		return
+	}
+	if s.typ == durationType {
+		var def time.Duration
+		if value, ok := s.def.(string); ok {
+			def, _ = time.ParseDuration(value)
+		}
+		fs.DurationP(s.name, s.short, def, s.usage)
+		return
+	}
+
+	switch s.typ.Kind() {
+	case reflect.Bool:
+		def, _ := s.def.(bool)
+		fs.BoolP(s.name, s.short, def, s.usage)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		def, _ := s.def.(int64)
+		fs.Int64P(s.name, s.short, def, s.usage)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		def, _ := s.def.(uint64)
+		fs.Uint64P(s.name, s.short, def, s.usage)
+	case reflect.Float32, reflect.Float64:
+		def, _ := s.def.(float64)
+		fs.Float64P(s.name, s.short, def, s.usage)
+	default:
+		def, _ := s.def.([]string)
+		fs.StringSliceP(s.name, s.short, def, s.usage)
+	}
+}
+
+// stringDefault returns the default of a string flag, "" if none
+func (s flagSpec) stringDefault() string {
+	def, _ := s.def.(string)
+	return def
+}
@@ -0,0 +1,135 @@
+package vcfg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+type FlagsTestBase struct {
+	Name string `koanf:"name" short:"n" usage:"Application name" default:"app"`
+}
+
+type FlagsTestConfig struct {
+	FlagsTestBase `koanf:",squash"`
+
+	Server struct {
+		Host    string        `koanf:"host" usage:"Listen address" default:"localhost"`
+		Port    int           `koanf:"port" short:"p" usage:"Listen port" default:"8080"`
+		Timeout time.Duration `koanf:"timeout" default:"30s"`
+	} `koanf:"server"`
+	Debug    bool              `koanf:"debug"`
+	Ratio    float64           `koanf:"ratio" default:"0.5"`
+	Workers  uint              `koanf:"workers"`
+	MaxBody  int64             `koanf:"max_body" default:"1048576"`
+	Tags     []string          `koanf:"tags" default:"a,b"`
+	Labels   map[string]string `koanf:"labels"`
+	Secret   string            `koanf:"secret" flag:"-"`
+	Internal string            `koanf:"-"`
+}
+
+func TestGenerateFlags(t *testing.T) {
+	flags, err := GenerateFlags[FlagsTestConfig]()
+	require.NoError(t, err)
+
+	byName := make(map[string]cli.Flag)
+	var names []string
+	for _, flag := range flags {
+		names = append(names, flag.Names()[0])
+		byName[flag.Names()[0]] = flag
+	}
+	assert.Equal(t, []string{
+		"name", "server.host", "server.port", "server.timeout",
+		"debug", "ratio", "workers", "max_body", "tags",
+	}, names)
+
+	name := byName["name"].(*cli.StringFlag)
+	assert.Equal(t, "app", name.Value)
+	assert.Equal(t, []string{"n"}, name.Aliases)
+	assert.Equal(t, "Application name", name.Usage)
+
+	assert.Equal(t, int64(8080), byName["server.port"].(*cli.Int64Flag).Value)
+	assert.Equal(t, 30*time.Second, byName["server.timeout"].(*cli.DurationFlag).Value)
+	assert.IsType(t, &cli.BoolFlag{}, byName["debug"])
+	assert.Equal(t, 0.5, byName["ratio"].(*cli.Float64Flag).Value)
+	assert.IsType(t, &cli.Uint64Flag{}, byName["workers"])
+	// int64 fields take strings to accept byte sizes
+	assert.Equal(t, "1048576", byName["max_body"].(*cli.StringFlag).Value)
+	assert.Equal(t, []string{"a", "b"}, byName["tags"].(*cli.StringSliceFlag).Value)
+}
+
+func TestGenerateFlags_RoundTrip(t *testing.T) {
+	flags, err := GenerateFlags[FlagsTestConfig]()
+	require.NoError(t, err)
+
+	var cfg *FlagsTestConfig
+	cmd := &cli.Command{
+		Name:  "app",
+		Flags: flags,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			cm, err := NewBuilder[FlagsTestConfig]().AddCliFlags(cmd, ".").Build(ctx)
+			if err != nil {
+				return err
+			}
+			defer cm.Close()
+			cfg = cm.Get()
+			return nil
+		},
+	}
+
+	args := []string{"app", "-p", "9090", "--server.timeout", "5s", "--max_body", "2MB", "--tags", "x"}
+	require.NoError(t, cmd.Run(context.Background(), args))
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, 5*time.Second, cfg.Server.Timeout)
+	assert.Equal(t, int64(2*1024*1024), cfg.MaxBody)
+	assert.Equal(t, []string{"x"}, cfg.Tags)
+	// Unset flags leave the default tags in effect
+	assert.Equal(t, "app", cfg.Name)
+	assert.Equal(t, "localhost", cfg.Server.Host)
+}
+
+func TestGeneratePFlags(t *testing.T) {
+	fs, err := GeneratePFlags[FlagsTestConfig]()
+	require.NoError(t, err)
+
+	port := fs.Lookup("server.port")
+	require.NotNil(t, port)
+	assert.Equal(t, "p", port.Shorthand)
+	assert.Equal(t, "8080", port.DefValue)
+	assert.Equal(t, "Listen port", port.Usage)
+	assert.Nil(t, fs.Lookup("secret"))
+	assert.Nil(t, fs.Lookup("labels"))
+
+	require.NoError(t, fs.Parse([]string{"-p", "9090", "--debug", "--ratio=0.75"}))
+	cm, err := NewBuilder[FlagsTestConfig]().AddPFlags(fs).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 0.75, cfg.Ratio)
+	assert.Equal(t, "localhost", cfg.Server.Host)
+}
+
+func TestGenerateFlags_Errors(t *testing.T) {
+	_, err := GenerateFlags[string]()
+	assert.Error(t, err)
+
+	type badDefault struct {
+		Port int `koanf:"port" default:"http"`
+	}
+	_, err = GenerateFlags[badDefault]()
+	assert.ErrorContains(t, err, "port")
+
+	type badShort struct {
+		Port int `koanf:"port" short:"po"`
+	}
+	_, err = GeneratePFlags[badShort]()
+	assert.ErrorContains(t, err, "short")
+
+}
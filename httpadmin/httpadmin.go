@@ -0,0 +1,222 @@
+// Package httpadmin provides HTTP handlers exposing a standard introspection
+// and control surface for a vcfg.ConfigManager: the effective configuration
+// with secrets masked, the changes of the last reload, an on-demand reload and
+// the health of the plugins. The handler can be mounted on any mux.
+package httpadmin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nextpkg/vcfg"
+)
+
+// Handler serves the admin endpoints of a configuration manager, relative to
+// where it is mounted:
+//
+//	GET  /config          the effective configuration with secrets masked;
+//	                      ?format=yaml|json|ini|properties, JSON by default
+//	GET  /config/diff     the changes of the most recent reload
+//	POST /config/reload   reloads from all sources, responding like /config/diff
+//	GET  /plugins/health  the status of every plugin instance; 503 if unhealthy
+//
+// Mount it below a prefix with http.StripPrefix:
+//
+//	mux.Handle("/admin/", http.StripPrefix("/admin", httpadmin.New(cm)))
+//
+// The endpoints expose configuration details and trigger reloads, so serve
+// them on an internal listener or behind authentication.
+type Handler[T any] struct {
+	cm  *vcfg.ConfigManager[T]
+	mux *http.ServeMux
+}
+
+// New returns the admin handler for cm.
+func New[T any](cm *vcfg.ConfigManager[T]) *Handler[T] {
+	h := &Handler[T]{cm: cm, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /config", h.serveConfig)
+	h.mux.HandleFunc("GET /config/diff", h.serveDiff)
+	h.mux.HandleFunc("POST /config/reload", h.serveReload)
+	h.mux.HandleFunc("GET /plugins/health", h.servePluginHealth)
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// contentTypes maps dump formats to response content types
+var contentTypes = map[string]string{
+	"json":       "application/json",
+	"yaml":       "application/yaml",
+	"yml":        "application/yaml",
+	"ini":        "text/plain; charset=utf-8",
+	"properties": "text/plain; charset=utf-8",
+}
+
+// serveConfig writes the redacted effective configuration
+func (h *Handler[T]) serveConfig(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "json"
+	}
+	contentType, ok := contentTypes[format]
+	if !ok {
+		writeError(w, http.StatusBadRequest, errors.New("unsupported format "+format))
+		return
+	}
+
+	data, err := h.cm.Dump(format, vcfg.WithDumpRedaction())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(data)
+}
+
+// ReloadResponse is the JSON body of /config/diff and /config/reload.
+type ReloadResponse struct {
+	// Time is when the reload started, zero if none happened yet
+	Time time.Time `json:"time"`
+	// Sources lists what triggered the reload
+	Sources []string `json:"sources"`
+	// Changes lists the changed values, secrets masked
+	Changes []ChangeResponse `json:"changes"`
+	// ReloadedPlugins lists the keys of the plugin instances reloaded
+	ReloadedPlugins []string `json:"reloaded_plugins"`
+	// Error is the error of a failed reload, empty on success
+	Error string `json:"error,omitempty"`
+}
+
+// ChangeResponse is a changed value in a ReloadResponse.
+type ChangeResponse struct {
+	Path     string `json:"path"`
+	OldValue any    `json:"old"`
+	NewValue any    `json:"new"`
+	Secret   bool   `json:"secret,omitempty"`
+}
+
+// reloadResponse converts info to its JSON form
+func reloadResponse(info vcfg.ReloadInfo) ReloadResponse {
+	resp := ReloadResponse{
+		Time:            info.Time,
+		Sources:         emptyIfNil(info.Sources),
+		Changes:         make([]ChangeResponse, 0, len(info.Changes)),
+		ReloadedPlugins: emptyIfNil(info.ReloadedPlugins),
+	}
+	for _, change := range info.Changes {
+		resp.Changes = append(resp.Changes, ChangeResponse{
+			Path:     change.Path,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+			Secret:   change.Secret,
+		})
+	}
+	if info.Err != nil {
+		resp.Error = info.Err.Error()
+	}
+	return resp
+}
+
+// serveDiff writes the changes of the most recent reload
+func (h *Handler[T]) serveDiff(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, reloadResponse(h.cm.LastReloadInfo()))
+}
+
+// serveReload reloads the configuration from all sources and writes the changes.
+// An invalid configuration is rejected with 422 and leaves the current one in place.
+func (h *Handler[T]) serveReload(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	if err := h.cm.Reload(r.Context()); err != nil {
+		status = http.StatusInternalServerError
+		if errors.Is(err, &vcfg.ConfigError{Type: vcfg.ErrorTypeValidationFailure}) {
+			status = http.StatusUnprocessableEntity
+		}
+	}
+	writeJSON(w, status, reloadResponse(h.cm.LastReloadInfo()))
+}
+
+// PluginHealth is the JSON body of /plugins/health.
+type PluginHealth struct {
+	// Healthy reports whether every plugin is healthy
+	Healthy bool `json:"healthy"`
+	// Plugins lists the plugin instances sorted by key
+	Plugins []PluginStatus `json:"plugins"`
+}
+
+// PluginStatus is the health of a plugin instance in PluginHealth. An instance
+// is healthy if it is running, registered for a lazy start or disabled, and its
+// last reload succeeded.
+type PluginStatus struct {
+	Key             string    `json:"key"`
+	Type            string    `json:"type"`
+	Instance        string    `json:"instance"`
+	Healthy         bool      `json:"healthy"`
+	Started         bool      `json:"started"`
+	Lazy            bool      `json:"lazy,omitempty"`
+	Disabled        bool      `json:"disabled,omitempty"`
+	ReloadFailures  int       `json:"reload_failures,omitempty"`
+	LastReloadError string    `json:"last_reload_error,omitempty"`
+	BackoffUntil    time.Time `json:"backoff_until,omitzero"`
+}
+
+// servePluginHealth writes the plugin statuses, with 503 if any is unhealthy
+func (h *Handler[T]) servePluginHealth(w http.ResponseWriter, _ *http.Request) {
+	health := PluginHealth{Healthy: true, Plugins: []PluginStatus{}}
+	for _, status := range h.cm.PluginStatus() {
+		plugin := PluginStatus{
+			Key:            status.Key,
+			Type:           status.PluginType,
+			Instance:       status.InstanceName,
+			Started:        status.Started,
+			Lazy:           status.Lazy,
+			Disabled:       !status.Enabled,
+			ReloadFailures: status.ReloadFailures,
+			BackoffUntil:   status.BackoffUntil,
+		}
+		if status.LastReloadError != nil {
+			plugin.LastReloadError = status.LastReloadError.Error()
+		}
+		plugin.Healthy = (status.Started || status.Lazy || !status.Enabled) && status.LastReloadError == nil
+		health.Healthy = health.Healthy && plugin.Healthy
+		health.Plugins = append(health.Plugins, plugin)
+	}
+
+	status := http.StatusOK
+	if !health.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, health)
+}
+
+// errorResponse is the JSON body of failed requests
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError writes err as a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeJSON writes v as an indented JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// emptyIfNil returns s, or an empty slice if s is nil, so lists encode as []
+func emptyIfNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,174 @@
+package httpadmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nextpkg/vcfg"
+	"github.com/nextpkg/vcfg/plugins"
+)
+
+// probeConfig configures probePlugin
+type probeConfig struct {
+	plugins.BaseConfig `koanf:",squash"`
+	FailReload         bool `koanf:"fail_reload"`
+}
+
+// probePlugin is a plugin whose reloads fail on request
+type probePlugin struct{}
+
+func (p *probePlugin) Startup(ctx context.Context, config any) error { return nil }
+
+func (p *probePlugin) Reload(ctx context.Context, config any) error {
+	if config.(*probeConfig).FailReload {
+		return errors.New("reload refused")
+	}
+	return nil
+}
+
+func (p *probePlugin) Shutdown(ctx context.Context) error { return nil }
+
+func init() {
+	plugins.RegisterPluginType("probe", &probePlugin{}, &probeConfig{})
+}
+
+type adminTestConfig struct {
+	Name     string      `koanf:"name" validate:"required"`
+	Password string      `koanf:"password" vcfg:"secret"`
+	Port     int         `koanf:"port"`
+	Probe    probeConfig `koanf:"probe"`
+}
+
+func newTestManager(t *testing.T, content string) (*vcfg.ConfigManager[adminTestConfig], string) {
+	t.Helper()
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(content), 0644))
+
+	cm, err := vcfg.NewBuilder[adminTestConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cm.Close() })
+	return cm, configFile
+}
+
+func serve(h http.Handler, method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestHandler_Config(t *testing.T) {
+	cm, _ := newTestManager(t, "name: app\npassword: hunter2\nport: 8080\n")
+	h := New(cm)
+
+	rec := serve(h, http.MethodGet, "/config")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "app", body["name"])
+	assert.NotContains(t, rec.Body.String(), "hunter2")
+
+	rec = serve(h, http.MethodGet, "/config?format=yaml")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "name: app")
+
+	rec = serve(h, http.MethodGet, "/config?format=hcl")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(h, http.MethodPost, "/config")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHandler_ReloadAndDiff(t *testing.T) {
+	cm, configFile := newTestManager(t, "name: app\npassword: hunter2\nport: 8080\n")
+	h := New(cm)
+
+	rec := serve(h, http.MethodGet, "/config/diff")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var diff ReloadResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &diff))
+	assert.Empty(t, diff.Changes)
+
+	require.NoError(t, os.WriteFile(configFile, []byte("name: app\npassword: s3cret\nport: 9090\n"), 0644))
+	rec = serve(h, http.MethodPost, "/config/reload")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 9090, cm.Get().Port)
+	assert.NotContains(t, rec.Body.String(), "s3cret")
+
+	var reload ReloadResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reload))
+	require.Len(t, reload.Changes, 2)
+	assert.Equal(t, "password", reload.Changes[0].Path)
+	assert.True(t, reload.Changes[0].Secret)
+	assert.Equal(t, "port", reload.Changes[1].Path)
+	assert.Equal(t, 8080.0, reload.Changes[1].OldValue)
+	assert.Equal(t, 9090.0, reload.Changes[1].NewValue)
+
+	// The diff endpoint reports the same reload
+	rec = serve(h, http.MethodGet, "/config/diff")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &diff))
+	assert.Equal(t, reload.Changes, diff.Changes)
+
+	// An invalid configuration is rejected and the current one kept
+	require.NoError(t, os.WriteFile(configFile, []byte("port: 1\n"), 0644))
+	rec = serve(h, http.MethodPost, "/config/reload")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "error")
+	assert.Equal(t, 9090, cm.Get().Port)
+
+	rec = serve(h, http.MethodGet, "/config/reload")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHandler_PluginHealth(t *testing.T) {
+	cm, configFile := newTestManager(t, "name: app\nprobe:\n  fail_reload: false\n")
+	require.NoError(t, cm.StartPlugins(context.Background()))
+	h := New(cm)
+
+	rec := serve(h, http.MethodGet, "/plugins/health")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var health PluginHealth
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	assert.True(t, health.Healthy)
+	require.Len(t, health.Plugins, 1)
+	assert.Equal(t, "probe:probe", health.Plugins[0].Key)
+	assert.True(t, health.Plugins[0].Started)
+
+	// A failed plugin reload makes the plugin unhealthy
+	require.NoError(t, os.WriteFile(configFile, []byte("name: app\nprobe:\n  fail_reload: true\n"), 0644))
+	serve(h, http.MethodPost, "/config/reload")
+
+	rec = serve(h, http.MethodGet, "/plugins/health")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	assert.False(t, health.Healthy)
+	assert.Equal(t, 1, health.Plugins[0].ReloadFailures)
+	assert.Equal(t, "reload refused", health.Plugins[0].LastReloadError)
+}
+
+func TestHandler_PluginHealthDisabled(t *testing.T) {
+	cm, _ := newTestManager(t, "name: app\nprobe:\n  enabled: false\n")
+	require.NoError(t, cm.StartPlugins(context.Background()))
+
+	rec := serve(New(cm), http.MethodGet, "/plugins/health")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var health PluginHealth
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	require.Len(t, health.Plugins, 1)
+	assert.True(t, health.Plugins[0].Healthy)
+	assert.True(t, health.Plugins[0].Disabled)
+	assert.False(t, health.Plugins[0].Started)
+}
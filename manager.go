@@ -5,13 +5,19 @@ package vcfg
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/knadh/koanf/v2"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 
-	"github.com/nextpkg/vcfg/defaults"
 	"github.com/nextpkg/vcfg/plugins"
 	"github.com/nextpkg/vcfg/providers"
 	"github.com/nextpkg/vcfg/slogs"
@@ -30,6 +36,8 @@ type (
 		providers []providers.ProviderConfig
 		// koanf is the underlying configuration library instance
 		koanf *koanf.Koanf
+		// origins records the source that set each key of koanf; replaced together with koanf
+		origins keyOrigins
 		// once ensures one-time initialization operations
 		once sync.Once
 		// cfg stores the current configuration using atomic operations for thread safety
@@ -40,6 +48,103 @@ type (
 		watchers []func()
 		// pluginManager manages plugin discovery, initialization, and lifecycle
 		pluginManager *plugins.PluginManager[T]
+		// lastReload stores the ReloadInfo of the most recent reload attempt
+		lastReload atomic.Value
+		// reloadHooks run after every successful reload
+		reloadHooks []ReloadHook[T]
+		// initMu serializes the first load performed by GetOrLoad
+		initMu sync.Mutex
+		// closers are cleanup callbacks registered with OnClose
+		closers []func() error
+		// signalMu protects signalHandlers and closed
+		signalMu sync.Mutex
+		// signalHandlers stop the handlers registered with ReloadOnSignal
+		signalHandlers []func()
+		// closed reports that CloseWithContext was called; no signal handlers are added then
+		closed bool
+		// requiredKeys lists key paths that must be present after merging all sources
+		requiredKeys []string
+		// keyNormalizer rewrites every key of every source before merging, nil to keep keys as is
+		keyNormalizer func(string) string
+		// mergeStrategies control how sources are combined per key path, nil for deep merging
+		mergeStrategies mergeStrategies
+		// migrations rewrite the merged values of older schemas before unmarshaling
+		migrations []migration
+		// decodeHooks convert merged values to field types before the built-in hooks
+		decodeHooks []mapstructure.DecodeHookFunc
+		// deprecatedKeys are the keys of T tagged `deprecated`
+		deprecatedKeys []deprecatedKey
+		// mapDeprecated copies the values of deprecated keys to their replacements
+		mapDeprecated bool
+		// strictKeys reports keys that match no field of T as selected by strictKeysMode
+		strictKeys bool
+		// strictKeysMode selects whether unknown keys fail the load or are warned about
+		strictKeysMode StrictKeysMode
+		// validateTag is the struct tag holding validation rules, "" for the shared default
+		validateTag string
+		// reloadMu serializes reloads and updates so neither overwrites the other
+		reloadMu sync.Mutex
+		// subMu protects subscribers, rejectionSubscribers and nextSubID
+		subMu sync.Mutex
+		// subscribers are the change subscriptions registered with Subscribe
+		subscribers []subscriber[T]
+		// rejectionSubscribers are the callbacks registered with OnReloadRejected
+		rejectionSubscribers []rejectionSubscriber
+		// strictReload rejects invalid reloads without touching the merged values
+		strictReload bool
+		// expandEnv expands ${NAME} references in string values before unmarshaling
+		expandEnv bool
+		// interpolate expands ${key.path} references to other keys before unmarshaling
+		interpolate bool
+		// decryptor decrypts string values starting with decryptPrefix, nil to disable
+		decryptor Decryptor
+		// decryptPrefix marks encrypted values, "" for the default "enc:"
+		decryptPrefix string
+		// nextSubID is the id of the most recent subscription
+		nextSubID uint64
+		// pluginsMu serializes EnablePlugins calls
+		pluginsMu sync.Mutex
+		// pluginsEnabled reports whether plugin discovery has completed successfully
+		pluginsEnabled bool
+		// stageMu protects staged
+		stageMu sync.Mutex
+		// staged is the candidate configuration loaded by Prepare, nil if none
+		staged *stagedConfig[T]
+		// snapMu protects snapshots and snapshotVersion
+		snapMu sync.Mutex
+		// snapshots holds the retained history of published configurations, oldest first
+		snapshots []snapshotEntry[T]
+		// snapshotVersion is the version of the most recent snapshot
+		snapshotVersion uint64
+		// snapshotRetention is the number of snapshots kept, 0 for the default
+		snapshotRetention int
+		// overridesMu protects overrides
+		overridesMu sync.Mutex
+		// overrides holds the values changed with Set, merged over all sources; nil if none
+		overrides *koanf.Koanf
+		// observers are notified of reloads and watch errors, events first
+		observers observers
+		// events feeds the channel returned by Events
+		events *eventStream
+		// tracer records spans for loads, reloads and validation, nil to disable
+		tracer trace.Tracer
+		// logger receives the log output, nil for the slogs logger
+		logger *slog.Logger
+	}
+
+	// ReloadHook is called with the new configuration after a successful reload.
+	ReloadHook[T any] func(ctx context.Context, cfg *T) error
+
+	// SourceInfo describes a configuration source registered with a ConfigManager.
+	SourceInfo struct {
+		// Name is the friendly name of the source (file path, custom name or provider type)
+		Name string
+		// Type is the Go type of the underlying koanf.Provider
+		Type string
+		// Watchable reports whether the source supports change notifications
+		Watchable bool
+		// Kind classifies the source, e.g. "file" or "env", see WithPriority
+		Kind string
 	}
 
 	// Watcher interface defines the contract for providers that support
@@ -69,20 +174,56 @@ type (
 //   - sources: Variable number of configuration sources (file paths or koanf.Provider instances)
 //
 // Returns a new ConfigManager instance ready for configuration loading.
-// Panics if provider creation fails.
+// Panics if provider creation fails; use createManager to handle the error.
 func newManager[T any](sources ...any) *ConfigManager[T] {
-	factory := providers.NewProviderFactory()
-	providerConfigs, err := factory.CreateProviders(sources...)
+	cm, err := createManager[T](sources...)
 	if err != nil {
 		panic(err)
 	}
+	return cm
+}
+
+// createManager is like newManager but returns an error if provider creation fails.
+func createManager[T any](sources ...any) (*ConfigManager[T], error) {
+	return createManagerWithFactory[T](providers.NewProviderFactory(), sources...)
+}
+
+// createManagerWithFactory is like createManager but detects parsers with the
+// given factory, e.g. one with format sniffing enabled.
+func createManagerWithFactory[T any](factory *providers.ProviderFactory, sources ...any) (*ConfigManager[T], error) {
+	if err := checkConfigType[T](); err != nil {
+		return nil, err
+	}
+
+	providerConfigs, err := factory.CreateProviders(sources...)
+	if err != nil {
+		return nil, err
+	}
 
-	return &ConfigManager[T]{
-		providers:     providerConfigs,
-		koanf:         koanf.New("."),
-		watchers:      make([]func(), 0),
-		pluginManager: plugins.NewPluginManager[T](),
+	events := newEventStream()
+	cm := &ConfigManager[T]{
+		providers:      providerConfigs,
+		koanf:          koanf.New("."),
+		watchers:       make([]func(), 0),
+		pluginManager:  plugins.NewPluginManager[T](),
+		deprecatedKeys: deprecatedKeys(reflect.TypeOf((*T)(nil)).Elem()),
+		events:         events,
+		observers:      observers{events},
 	}
+	cm.pluginManager.SetObserver(cm.observers)
+	return cm, nil
+}
+
+// checkConfigType reports an error unless T is a struct type. Defaults, decoding,
+// validation and plugin discovery all walk struct fields, so any other type,
+// including a pointer to a struct, would silently load nothing.
+func checkConfigType[T any]() error {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return NewValidationError("manager",
+			fmt.Sprintf("configuration type %s must be a struct, got %s", typ, typ.Kind()), nil)
+	}
+	return nil
 }
 
 // load loads configuration from all sources, applies defaults, validates the result,
@@ -95,32 +236,100 @@ func newManager[T any](sources ...any) *ConfigManager[T] {
 // 4. Validates the configuration
 //
 // Returns a pointer to the loaded and validated configuration, or an error if any step fails.
-func (cm *ConfigManager[T]) load() (*T, error) {
+func (cm *ConfigManager[T]) load(ctx context.Context) (cfg *T, err error) {
+	ctx, span := cm.startSpan(ctx, spanLoad)
+	defer func() { endSpan(span, err) }()
+
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
 	// load all sources
-	err := cm.loadSource()
+	err = cm.loadSource(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return cm.loadConfig()
+	return cm.loadConfig(ctx)
 }
 
 // loadSource loads all configuration providers and merges them into the koanf instance.
 // Providers are loaded in order, with later providers overriding earlier ones.
 // Each provider is loaded with its associated parser for proper data interpretation.
 //
+// Sources are merged into a fresh koanf instance that replaces the current one only
+// when every provider loaded successfully. This ensures keys removed from a source
+// disappear on reload (reverting their fields to defaults) and that a failed load
+// leaves the previous state untouched.
+//
 // Returns an error if reading from any provider or merging configurations fails.
-func (cm *ConfigManager[T]) loadSource() error {
+func (cm *ConfigManager[T]) loadSource(ctx context.Context) error {
+	k, origins, err := cm.mergeSources(ctx)
+	if err != nil {
+		return err
+	}
+
+	cm.koanf = k
+	cm.origins = origins
+	return nil
+}
+
+// mergeSources reads all providers in order into a fresh koanf instance, applies
+// the migrations and checks the required keys. It also returns the source that
+// set each key. It does not modify the manager.
+func (cm *ConfigManager[T]) mergeSources(ctx context.Context) (*koanf.Koanf, keyOrigins, error) {
+	k := koanf.New(".")
+	origins := make(keyOrigins)
 	for _, providerConfig := range cm.providers {
-		if err := cm.koanf.Load(providerConfig.Provider, providerConfig.Parser); err != nil {
-			return NewParseError(fmt.Sprintf("%T", providerConfig.Provider), "failed to load from provider", err)
+		_, span := cm.startSpan(ctx, spanLoadSource, attrSource.String(providerConfig.Name))
+		keys, err := cm.loadProvider(k, providerConfig)
+		if err != nil {
+			err = newSourceParseError(providerConfig.Name, providerConfig.Provider, err)
+			endSpan(span, err)
+			return nil, nil, err
 		}
+		span.End()
+		origins.record(keys, providerConfig.Name)
 	}
 
-	return nil
+	if err := applyMigrations(k, cm.migrations); err != nil {
+		return nil, nil, err
+	}
+	deprecations, err := checkDeprecated(k, origins, cm.deprecatedKeys, cm.mapDeprecated)
+	if err != nil {
+		return nil, nil, err
+	}
+	if overrides := cm.overrideValues(); overrides != nil {
+		if err := k.Merge(overrides); err != nil {
+			return nil, nil, NewParseError(setSource, "failed to apply changed values", err)
+		}
+		origins.record(overrides.Keys(), setSource)
+	}
+	origins.prune(k)
+
+	if missing := missingKeys(k, cm.requiredKeys); len(missing) > 0 {
+		return nil, nil, NewValidationError("koanf", "missing required keys: "+strings.Join(missing, ", "), nil)
+	}
+
+	unknown, err := cm.checkUnknownKeys(k)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cm.reportDeprecations(deprecations)
+	cm.reportWarnings(unknown)
+	return k, origins, nil
+}
+
+// missingKeys returns the paths that do not exist in k, in the given order.
+// A path exists if any source set it, even to a zero value.
+func missingKeys(k *koanf.Koanf, paths []string) []string {
+	var missing []string
+	for _, path := range paths {
+		if !k.Exists(path) {
+			missing = append(missing, path)
+		}
+	}
+	return missing
 }
 
 // loadConfig unmarshals the merged configuration from koanf into the target struct type,
@@ -132,75 +341,111 @@ func (cm *ConfigManager[T]) loadSource() error {
 // 3. Running validation on the final configuration
 //
 // Returns a pointer to the processed configuration, or an error if any step fails.
-func (cm *ConfigManager[T]) loadConfig() (*T, error) {
+func (cm *ConfigManager[T]) loadConfig(ctx context.Context) (*T, error) {
 	if cm == nil || cm.koanf == nil {
 		return nil, NewParseError("manager", "configuration manager not properly initialized", nil)
 	}
 
+	return cm.decode(ctx, cm.koanf)
+}
+
+// resolveValues returns the values of k as they are unmarshaled: references are
+// expanded and encrypted values decrypted, on a copy if either is enabled.
+func (cm *ConfigManager[T]) resolveValues(k *koanf.Koanf) (*koanf.Koanf, error) {
+	k, err := cm.expandValues(k)
+	if err != nil {
+		return nil, NewParseError("expand", "failed to expand references in configuration values", err)
+	}
+
+	k, err = cm.decryptValues(k)
+	if err != nil {
+		return nil, NewParseError("decrypt", "failed to decrypt configuration values", err)
+	}
+	return k, nil
+}
+
+// decode builds a configuration from the merged values in k: the merged values
+// are unmarshaled first, then struct tag defaults are applied to the keys that
+// are not set, so explicit zero values are kept, and the result is validated.
+// With environment expansion or interpolation enabled, references in string
+// values are expanded on a copy of k before unmarshaling, and with a decryptor,
+// encrypted values are decrypted after that.
+func (cm *ConfigManager[T]) decode(ctx context.Context, k *koanf.Koanf) (*T, error) {
 	var cfg T
 
-	// Set default values using struct tags
-	err := defaults.SetDefaults(&cfg)
+	k, err := cm.resolveValues(k)
 	if err != nil {
-		return nil, NewParseError("defaults", "failed to set default values", err)
+		return nil, err
 	}
 
-	err = cm.koanf.Unmarshal("", &cfg)
+	err = k.UnmarshalWithConf("", &cfg, unmarshalConf(&cfg, cm.decodeHooks...))
 	if err != nil {
 		return nil, NewParseError("koanf", "failed to unmarshal configuration", err)
 	}
 
-	err = validator.Validate(&cfg)
+	// Set default values using struct tags for the keys that are not set
+	err = setMissingDefaults(&cfg, k, "")
 	if err != nil {
-		return nil, NewValidationError("validator", "configuration validation failed", err)
+		return nil, NewParseError("defaults", "failed to set default values", err)
+	}
+
+	_, span := cm.startSpan(ctx, spanValidate)
+	err = validator.ValidateWithTag(&cfg, cm.validateTag)
+	if err != nil {
+		err = NewValidationError("validator", "configuration validation failed", err)
+		endSpan(span, err)
+		return nil, err
 	}
+	span.End()
+
+	cm.reportWarnings(validator.WarningsWithTag(&cfg, cm.validateTag))
 
 	return &cfg, nil
 }
 
+// reportWarnings logs the warning-level validation results of a decoded
+// configuration and delivers them as an EventValidationWarning
+func (cm *ConfigManager[T]) reportWarnings(warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	for _, warning := range warnings {
+		cm.log().Warn("Configuration warning", "warning", warning)
+	}
+	cm.events.emit(Event{Type: EventValidationWarning, Time: time.Now(), Warnings: warnings})
+}
+
 // EnableWatch enables watching for configuration changes.
 // It sets up file watchers for providers that implement the Watcher interface.
 // When a configuration change is detected, it reloads the configuration and
 // triggers plugin reloads for affected plugins.
+//
+// Change events from all providers are debounced: events arriving within the
+// debounce interval are coalesced into a single reload. The interval defaults to
+// a platform specific value (see WithDebounce) and can be tuned via opts.
 // This method is thread-safe and can be called multiple times safely.
-func (cm *ConfigManager[T]) EnableWatch() *ConfigManager[T] {
+func (cm *ConfigManager[T]) EnableWatch(opts ...WatchOption) *ConfigManager[T] {
 	cm.once.Do(func() {
+		watchOpts := newWatchOptions(opts...)
+		debounce := newDebouncer(watchOpts.debounce, cm.reloadFromWatch)
+
 		for _, providerConfig := range cm.providers {
 			if watcher, ok := providerConfig.Provider.(Watcher); ok {
+				source := providerConfig.Name
 				err := watcher.Watch(func(event any, err error) {
 					if err != nil {
-						slogs.Error("Watch error", "error", err)
+						cm.log().Error("Watch error", "error", err)
+						cm.observers.WatchFailed(source, err)
 						return
 					}
 
-					slogs.Debug("Configuration change detected", "event", event)
-
-					// Get old configuration before reload
-					oldConfig := cm.Get()
-
-					// Reload configuration
-					newConfig, loadErr := cm.load()
-					if loadErr != nil {
-						slogs.Error("Failed to reload configuration", "error", loadErr)
-						return
-					}
-
-					// Store new configuration
-					cm.cfg.Store(newConfig)
-
-					// Handle plugin configuration changes intelligently
-					if oldConfig != nil {
-						if err := cm.pluginManager.Reload(context.Background(), oldConfig, newConfig); err != nil {
-							slogs.Error("Failed to handle smart plugin reload", "error", err)
-							return
-						}
-					}
-
-					slogs.Debug("Configuration reloaded successfully")
+					cm.log().Debug("Configuration change detected", "event", event, "source", source)
+					debounce.trigger(source)
 				})
 
 				if err != nil {
-					slogs.Error("Failed to enable watch", "error", err)
+					cm.log().Error("Failed to enable watch", "error", err)
+					cm.observers.WatchFailed(source, err)
 					continue
 				}
 
@@ -212,13 +457,15 @@ func (cm *ConfigManager[T]) EnableWatch() *ConfigManager[T] {
 					if fileProvider, ok := providerConfig.Provider.(interface{ Unwatch() error }); ok {
 						cm.watchers = append(cm.watchers, func() {
 							if err := fileProvider.Unwatch(); err != nil {
-								slogs.Error("Failed to unwatch", "error", err)
+								cm.log().Error("Failed to unwatch", "error", err)
 							}
 						})
 					}
 				}
 			}
 		}
+
+		cm.watchers = append(cm.watchers, debounce.stop)
 	})
 
 	return cm
@@ -254,17 +501,179 @@ func (cm *ConfigManager[T]) Get() *T {
 	return ret
 }
 
-// EnablePlugins automatically discovers and registers plugin instances based on current configuration
+// GetOrLoad returns the current configuration, loading it from the sources first
+// if the manager has not loaded it yet (see WithLazyLoad). The loaded configuration
+// is cached, so later calls return the same value without reading the sources.
+// Unlike Get it never returns nil without an error; a failed load is not cached
+// and is retried by the next call.
+func (cm *ConfigManager[T]) GetOrLoad() (*T, error) {
+	if cm == nil {
+		return nil, NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+	if cfg := cm.Get(); cfg != nil {
+		return cfg, nil
+	}
+
+	cm.initMu.Lock()
+	defer cm.initMu.Unlock()
+
+	// Another caller may have loaded the configuration while we waited
+	if cfg := cm.Get(); cfg != nil {
+		return cfg, nil
+	}
+
+	cfg, err := cm.load(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	cm.storeConfig(cfg)
+	cm.emitLoaded()
+
+	return cfg, nil
+}
+
+// UnmarshalInto decodes the current merged configuration into out, which may be
+// any struct type rather than T. This allows tools to read the same configuration
+// through a broader or narrower view without creating a second manager.
+// Defaults and validation are not applied to out.
+//
+// Parameters:
+//   - out: A pointer to the struct that receives the decoded configuration
+//
+// Returns an error if the manager is not initialized or decoding fails.
+func (cm *ConfigManager[T]) UnmarshalInto(out any) error {
+	if cm == nil {
+		return NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if cm.koanf == nil {
+		return NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+
+	k, err := cm.resolveValues(cm.koanf)
+	if err != nil {
+		return err
+	}
+
+	if err := k.UnmarshalWithConf("", out, unmarshalConf(out, cm.decodeHooks...)); err != nil {
+		return NewParseError("koanf", "failed to unmarshal configuration", err)
+	}
+
+	return nil
+}
+
+// Sub decodes the configuration below path, e.g. "database", into a new S, so a
+// library can consume its own section without depending on the application's
+// root type T. Struct tag defaults are applied to the keys of S that are not
+// set and the result is validated like T. A missing path yields S with its defaults only.
+// It is a function rather than a method because Go methods cannot declare their
+// own type parameters:
+//
+//	db, err := vcfg.Sub[dbpool.Config](cm, "database")
+//
+// Each call decodes the current merged values again; subscribe with Subscribe or
+// OnChange to learn when the section changes.
+func Sub[S, T any](cm *ConfigManager[T], path string) (*S, error) {
+	if cm == nil {
+		return nil, NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if cm.koanf == nil {
+		return nil, NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+
+	k, err := cm.resolveValues(cm.koanf)
+	if err != nil {
+		return nil, err
+	}
+
+	var out S
+	if err := k.UnmarshalWithConf(path, &out, unmarshalConf(&out, cm.decodeHooks...)); err != nil {
+		return nil, NewParseError("koanf", fmt.Sprintf("failed to unmarshal configuration at %q", path), err)
+	}
+
+	if err := setMissingDefaults(&out, k, path); err != nil {
+		return nil, NewParseError("defaults", "failed to set default values", err)
+	}
+
+	if err := validator.ValidateWithTag(&out, cm.validateTag); err != nil {
+		return nil, NewValidationError("validator", fmt.Sprintf("configuration at %q failed validation", path), err)
+	}
+
+	return &out, nil
+}
+
+// MarshalMerged serializes the merged configuration of all sources in the given
+// format, "json" or "yaml". Unlike the typed configuration returned by Get it
+// includes keys that have no corresponding field in T, which makes it useful for
+// tooling and debugging. Defaults from struct tags are not included.
+func (cm *ConfigManager[T]) MarshalMerged(format string) ([]byte, error) {
+	parser, err := providers.ParserForFormat(format)
+	if err != nil {
+		return nil, NewParseError("manager", "unsupported output format", err)
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if cm.koanf == nil {
+		return nil, NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+
+	data, err := cm.koanf.Marshal(parser)
+	if err != nil {
+		return nil, NewParseError("koanf", "failed to marshal merged configuration", err)
+	}
+	return data, nil
+}
+
+// Providers returns information about the configuration sources of this manager
+// in precedence order (later sources override earlier ones).
+func (cm *ConfigManager[T]) Providers() []SourceInfo {
+	infos := make([]SourceInfo, 0, len(cm.providers))
+	for _, providerConfig := range cm.providers {
+		_, watchable := providerConfig.Provider.(Watcher)
+		infos = append(infos, SourceInfo{
+			Name:      providerConfig.Name,
+			Type:      fmt.Sprintf("%T", providerConfig.Provider),
+			Watchable: watchable,
+			Kind:      providerConfig.Kind,
+		})
+	}
+	return infos
+}
+
+// EnablePlugins automatically discovers and registers plugin instances based on current configuration.
+// It is safe to call concurrently and more than once: only the first successful call registers
+// plugins, later calls return nil. A failed discovery may be retried.
 // This method uses the global plugin type registry to automatically instantiate and register plugins
 // for any configuration field that matches a registered plugin type
 func (cm *ConfigManager[T]) EnablePlugins() error {
+	cm.pluginsMu.Lock()
+	defer cm.pluginsMu.Unlock()
+
+	if cm.pluginsEnabled {
+		return nil
+	}
+
 	config := cm.Get()
 	if config == nil {
 		return fmt.Errorf("no configuration available for auto-registration")
 	}
 
 	// Use auto-registration
-	return cm.pluginManager.DiscoverAndRegister(config)
+	if err := cm.pluginManager.DiscoverAndRegister(config); err != nil {
+		return wrapDiscoveryError(err)
+	}
+
+	cm.pluginsEnabled = true
+	return nil
 }
 
 // StartPlugins starts all registered plugins
@@ -273,6 +682,19 @@ func (cm *ConfigManager[T]) StartPlugins(ctx context.Context) error {
 	return cm.pluginManager.Startup(ctx)
 }
 
+// EnsurePluginStarted starts the plugin registered under key if it is not running yet.
+// Plugins registered with LazyStart are not started by StartPlugins or Build; call this
+// method before first use. The key has the form "pluginType:instanceName".
+func (cm *ConfigManager[T]) EnsurePluginStarted(ctx context.Context, key string) error {
+	return cm.pluginManager.EnsureStarted(ctx, key)
+}
+
+// PluginStatus returns a snapshot of all registered plugin instances sorted by key,
+// including reload failures and whether a plugin's reloads are currently throttled.
+func (cm *ConfigManager[T]) PluginStatus() []plugins.PluginStatus {
+	return cm.pluginManager.Status()
+}
+
 // StopPlugins stops all running plugins
 // This method gracefully stops all plugin instances
 func (cm *ConfigManager[T]) StopPlugins(ctx context.Context) error {
@@ -296,15 +718,54 @@ func (cm *ConfigManager[T]) Close() error {
 	return cm.CloseWithContext(context.Background())
 }
 
-// CloseWithContext closes the configuration manager with context, including all plugins and watchers
+// CloseWithContext closes the configuration manager with context, including all plugins and watchers,
+// then runs the cleanup callbacks registered with OnClose
 func (cm *ConfigManager[T]) CloseWithContext(ctx context.Context) error {
 	if cm == nil {
 		return nil
 	}
 
-	// Stop all watchers
+	// Stop all watchers and signal handlers, so no reload reaches the plugins
+	// while they shut down
 	cm.DisableWatch()
+	cm.stopSignalHandlers()
 
 	// Shutdown all plugins
-	return cm.pluginManager.Shutdown(ctx)
+	err := cm.pluginManager.Shutdown(ctx)
+
+	// Run cleanup callbacks in reverse registration order
+	cm.mu.Lock()
+	closers := cm.closers
+	cm.closers = nil
+	cm.mu.Unlock()
+
+	errs := []error{err}
+	for i := len(closers) - 1; i >= 0; i-- {
+		errs = append(errs, closers[i]())
+	}
+	cm.events.close()
+
+	return errors.Join(errs...)
+}
+
+// log returns the logger of the manager, set with WithLogger
+func (cm *ConfigManager[T]) log() *slog.Logger {
+	if cm.logger != nil {
+		return cm.logger
+	}
+	return slogs.Logger()
+}
+
+// OnClose registers fn to run when the manager is closed, e.g. to close a
+// database pool built from the configuration. Callbacks run after plugins are
+// shut down, in reverse registration order, and each runs at most once; their
+// errors are joined into the error returned by Close.
+func (cm *ConfigManager[T]) OnClose(fn func() error) {
+	if fn == nil {
+		return
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.closers = append(cm.closers, fn)
 }
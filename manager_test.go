@@ -2,6 +2,8 @@ package vcfg
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"sync"
@@ -50,7 +52,7 @@ func TestConfigManager_Get(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			cm := newManager[TestConfig](rawbytes.Provider([]byte(tt.config)))
 
-			cfg, err := cm.load()
+			cfg, err := cm.load(context.Background())
 			require.NoError(t, err)
 			cm.cfg.Store(cfg)
 
@@ -73,6 +75,58 @@ func TestConfigManager_GetNil(t *testing.T) {
 	assert.Nil(t, result2)
 }
 
+func TestConfigManager_GetOrLoad(t *testing.T) {
+	provider := &countingWatchProvider{data: []byte(`{"name":"lazy","port":8080}`)}
+
+	cm, err := NewBuilder[TestConfig]().
+		AddProvider(provider).
+		WithLazyLoad().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	// Nothing is read until the first access
+	assert.Nil(t, cm.Get())
+	assert.Equal(t, int32(0), provider.reads.Load())
+
+	cfg, err := cm.GetOrLoad()
+	require.NoError(t, err)
+	assert.Equal(t, "lazy", cfg.Name)
+	assert.Equal(t, 8080, cfg.Port)
+	assert.Equal(t, int32(1), provider.reads.Load())
+
+	// Later calls return the cached value
+	provider.set(`{"name":"changed"}`)
+	again, err := cm.GetOrLoad()
+	require.NoError(t, err)
+	assert.Same(t, cfg, again)
+	assert.Same(t, cfg, cm.Get())
+	assert.Equal(t, int32(1), provider.reads.Load())
+}
+
+func TestConfigManager_GetOrLoadError(t *testing.T) {
+	provider := &countingWatchProvider{data: []byte(`{invalid`)}
+	cm, err := New[TestConfig](WithProvider(provider), WithLazyLoad())
+	require.NoError(t, err)
+
+	cfg, err := cm.GetOrLoad()
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+
+	// A failed load is retried by the next call
+	provider.set(`{"name":"fixed"}`)
+	cfg, err = cm.GetOrLoad()
+	require.NoError(t, err)
+	assert.Equal(t, "fixed", cfg.Name)
+
+	var nilManager *ConfigManager[TestConfig]
+	_, err = nilManager.GetOrLoad()
+	assert.Error(t, err)
+
+	_, err = New[TestConfig](WithProvider(provider), WithLazyLoad(), WithPlugins())
+	assert.Error(t, err)
+}
+
 func TestConfigManager_EnableWatch(t *testing.T) {
 	// Create a temporary config file
 	tmpDir := t.TempDir()
@@ -87,7 +141,7 @@ func TestConfigManager_EnableWatch(t *testing.T) {
 	cm := newManager[TestConfig](fileProvider)
 
 	// Load initial config
-	cfg, err := cm.load()
+	cfg, err := cm.load(context.Background())
 	require.NoError(t, err)
 	cm.cfg.Store(cfg)
 
@@ -120,7 +174,7 @@ func TestConfigManager_DisableWatch(t *testing.T) {
 	cm := newManager[TestConfig](fileProvider)
 
 	// Load initial config
-	cfg, err := cm.load()
+	cfg, err := cm.load(context.Background())
 	require.NoError(t, err)
 	cm.cfg.Store(cfg)
 
@@ -151,7 +205,7 @@ func TestConfigManager_DisableWatch_ThreadSafety(t *testing.T) {
 	cm := newManager[TestConfig](fileProvider)
 
 	// Load initial config
-	cfg, err := cm.load()
+	cfg, err := cm.load(context.Background())
 	require.NoError(t, err)
 	cm.cfg.Store(cfg)
 
@@ -176,7 +230,7 @@ func TestConfigManager_Close(t *testing.T) {
 	cm := newManager[TestConfig](rawbytes.Provider([]byte(`{"name":"test"}`)))
 
 	// Load config
-	cfg, err := cm.load()
+	cfg, err := cm.load(context.Background())
 	require.NoError(t, err)
 	cm.cfg.Store(cfg)
 
@@ -189,7 +243,7 @@ func TestConfigManager_CloseWithContext(t *testing.T) {
 	cm := newManager[TestConfig](rawbytes.Provider([]byte(`{"name":"test"}`)))
 
 	// Load config
-	cfg, err := cm.load()
+	cfg, err := cm.load(context.Background())
 	require.NoError(t, err)
 	cm.cfg.Store(cfg)
 
@@ -201,11 +255,32 @@ func TestConfigManager_CloseWithContext(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestConfigManager_OnClose(t *testing.T) {
+	cm := newManager[TestConfig](rawbytes.Provider([]byte(`{"name":"test"}`)))
+
+	var order []int
+	errFirst := errors.New("first failed")
+	errThird := errors.New("third failed")
+	cm.OnClose(func() error { order = append(order, 1); return errFirst })
+	cm.OnClose(func() error { order = append(order, 2); return nil })
+	cm.OnClose(nil)
+	cm.OnClose(func() error { order = append(order, 3); return errThird })
+
+	err := cm.Close()
+	assert.Equal(t, []int{3, 2, 1}, order)
+	assert.ErrorIs(t, err, errFirst)
+	assert.ErrorIs(t, err, errThird)
+
+	// Callbacks run only once
+	require.NoError(t, cm.Close())
+	assert.Equal(t, []int{3, 2, 1}, order)
+}
+
 func TestConfigManager_EnableAndStartPlugins(t *testing.T) {
 	cm := newManager[TestConfig](rawbytes.Provider([]byte(`{"name":"test","value":42}`)))
 
 	// Load config
-	cfg, err := cm.load()
+	cfg, err := cm.load(context.Background())
 	require.NoError(t, err)
 	cm.cfg.Store(cfg)
 
@@ -222,7 +297,7 @@ func TestConfigManager_MustEnableAndStartPlugins(t *testing.T) {
 	cm := newManager[TestConfig](rawbytes.Provider([]byte(`{"name":"test"}`)))
 
 	// Load config
-	cfg, err := cm.load()
+	cfg, err := cm.load(context.Background())
 	require.NoError(t, err)
 	cm.cfg.Store(cfg)
 
@@ -231,3 +306,111 @@ func TestConfigManager_MustEnableAndStartPlugins(t *testing.T) {
 		cm.MustEnableAndStartPlugins()
 	})
 }
+
+func TestConfigManager_MarshalMerged(t *testing.T) {
+	cm := newManager[TestConfig](rawbytes.Provider([]byte(`{"name":"test","port":8080,"extra":{"region":"eu"}}`)))
+
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+	cm.cfg.Store(cfg)
+
+	t.Run("json", func(t *testing.T) {
+		data, err := cm.MarshalMerged("json")
+		require.NoError(t, err)
+
+		var out map[string]any
+		require.NoError(t, json.Unmarshal(data, &out))
+		assert.Equal(t, "test", out["name"])
+		assert.Equal(t, map[string]any{"region": "eu"}, out["extra"])
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		data, err := cm.MarshalMerged(".yml")
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "name: test")
+		assert.Contains(t, string(data), "region: eu")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := cm.MarshalMerged("toml")
+		assert.ErrorContains(t, err, "unsupported configuration format")
+	})
+}
+
+func TestConfigManager_UnmarshalInto(t *testing.T) {
+	cm := newManager[TestConfig](rawbytes.Provider([]byte(`{"name":"test","port":8080,"enabled":true,"extra":"value"}`)))
+
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+	cm.cfg.Store(cfg)
+
+	t.Run("superset struct", func(t *testing.T) {
+		var out struct {
+			Name    string `koanf:"name"`
+			Port    int    `koanf:"port"`
+			Enabled bool   `koanf:"enabled"`
+			Extra   string `koanf:"extra"`
+		}
+		require.NoError(t, cm.UnmarshalInto(&out))
+		assert.Equal(t, "test", out.Name)
+		assert.Equal(t, 8080, out.Port)
+		assert.True(t, out.Enabled)
+		assert.Equal(t, "value", out.Extra)
+	})
+
+	t.Run("subset struct", func(t *testing.T) {
+		var out struct {
+			Port int `koanf:"port"`
+		}
+		require.NoError(t, cm.UnmarshalInto(&out))
+		assert.Equal(t, 8080, out.Port)
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		var out struct {
+			Port int `koanf:"port"`
+		}
+		err := cm.UnmarshalInto(out)
+		assert.Error(t, err)
+	})
+
+	t.Run("nil manager", func(t *testing.T) {
+		var nilManager *ConfigManager[TestConfig]
+		assert.Error(t, nilManager.UnmarshalInto(&struct{}{}))
+	})
+}
+
+type SubTestConfig struct {
+	Host    string `koanf:"host" default:"localhost"`
+	Port    int    `koanf:"port" validate:"required"`
+	MaxConn int    `koanf:"max_conn" default:"10"`
+}
+
+func TestSub(t *testing.T) {
+	cm := newManager[TestConfig](rawbytes.Provider([]byte(`{"name":"test","port":8080,"database":{"port":5432,"max_conn":50},"cache":{"host":"redis"}}`)))
+
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+	cm.cfg.Store(cfg)
+
+	db, err := Sub[SubTestConfig](cm, "database")
+	require.NoError(t, err)
+	assert.Equal(t, SubTestConfig{Host: "localhost", Port: 5432, MaxConn: 50}, *db)
+
+	// Validation applies to the section
+	_, err = Sub[SubTestConfig](cm, "cache")
+	var configErr *ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, ErrorTypeValidationFailure, configErr.Type)
+
+	// The root path decodes the whole configuration
+	root, err := Sub[struct {
+		Name string `koanf:"name"`
+	}](cm, "")
+	require.NoError(t, err)
+	assert.Equal(t, "test", root.Name)
+
+	var nilManager *ConfigManager[TestConfig]
+	_, err = Sub[SubTestConfig](nilManager, "database")
+	assert.Error(t, err)
+}
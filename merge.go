@@ -0,0 +1,100 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements merge strategies, which control per key path how the
+// value of a later source is combined with the value of earlier ones.
+package vcfg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeStrategy controls how a later source's value at a key path is combined
+// with the value already merged from earlier sources.
+type MergeStrategy int
+
+const (
+	// MergeDeep merges maps key by key and replaces all other values. It is the
+	// default behavior.
+	MergeDeep MergeStrategy = iota
+	// MergeReplace replaces the earlier value wholesale, including maps, so no
+	// keys of earlier sources survive below the path.
+	MergeReplace
+	// MergeAppend appends the elements of a later list to the earlier list
+	// instead of replacing it. Maps are merged as with MergeDeep.
+	MergeAppend
+)
+
+// String returns the name of the strategy.
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeDeep:
+		return "deep"
+	case MergeReplace:
+		return "replace"
+	case MergeAppend:
+		return "append"
+	default:
+		return fmt.Sprintf("MergeStrategy(%d)", int(s))
+	}
+}
+
+// mergeStrategies maps key paths to the strategy applied at and below them;
+// the empty path sets the strategy for the whole configuration
+type mergeStrategies map[string]MergeStrategy
+
+// strategyFor returns the strategy of the closest configured path at or above path
+func (m mergeStrategies) strategyFor(path string) MergeStrategy {
+	for {
+		if strategy, ok := m[path]; ok {
+			return strategy
+		}
+		if path == "" {
+			return MergeDeep
+		}
+
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			path = ""
+		} else {
+			path = path[:i]
+		}
+	}
+}
+
+// merge merges src into dest following the configured strategies. It has the
+// signature of koanf.WithMergeFunc.
+func (m mergeStrategies) merge(src, dest map[string]any) error {
+	m.mergeAt("", src, dest)
+	return nil
+}
+
+// mergeAt merges the section src at path into dest
+func (m mergeStrategies) mergeAt(path string, src, dest map[string]any) {
+	for key, value := range src {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+
+		strategy := m.strategyFor(keyPath)
+		existing, ok := dest[key]
+		if !ok || strategy == MergeReplace {
+			dest[key] = value
+			continue
+		}
+
+		switch value := value.(type) {
+		case map[string]any:
+			if section, ok := existing.(map[string]any); ok {
+				m.mergeAt(keyPath, value, section)
+				continue
+			}
+		case []any:
+			if list, ok := existing.([]any); ok && strategy == MergeAppend {
+				dest[key] = append(append(make([]any, 0, len(list)+len(value)), list...), value...)
+				continue
+			}
+		}
+		dest[key] = value
+	}
+}
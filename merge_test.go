@@ -0,0 +1,106 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MergeTestConfig struct {
+	Origins []string          `koanf:"origins"`
+	Tags    []string          `koanf:"tags"`
+	Labels  map[string]string `koanf:"labels"`
+	Routes  map[string]string `koanf:"routes"`
+	Server  struct {
+		Host    string   `koanf:"host"`
+		Port    int      `koanf:"port"`
+		Aliases []string `koanf:"aliases"`
+	} `koanf:"server"`
+}
+
+func TestBuilder_WithMergeStrategy(t *testing.T) {
+	dir := t.TempDir()
+	baseFile := filepath.Join(dir, "base.yaml")
+	overrideFile := filepath.Join(dir, "override.yaml")
+	writeFile(t, baseFile, `origins: [a.example.com]
+tags: [base]
+labels: {team: core, tier: backend}
+routes: {"/": home, "/api": api}
+server:
+  host: base
+  port: 80
+  aliases: [www]
+`)
+	writeFile(t, overrideFile, `origins: [b.example.com]
+tags: [override]
+labels: {tier: frontend}
+routes: {"/docs": docs}
+server:
+  port: 8080
+  aliases: [api]
+`)
+
+	// Default: maps merge, lists are replaced
+	cm, err := NewBuilder[MergeTestConfig]().AddFile(baseFile).AddFile(overrideFile).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+	cfg := cm.Get()
+	assert.Equal(t, []string{"b.example.com"}, cfg.Origins)
+	assert.Equal(t, map[string]string{"team": "core", "tier": "frontend"}, cfg.Labels)
+	assert.Len(t, cfg.Routes, 3)
+
+	cm, err = NewBuilder[MergeTestConfig]().
+		AddFile(baseFile).
+		AddFile(overrideFile).
+		WithMergeStrategy("origins", MergeAppend).
+		WithMergeStrategy("routes", MergeReplace).
+		WithMergeStrategy("server", MergeAppend).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg = cm.Get()
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, cfg.Origins)
+	assert.Equal(t, []string{"override"}, cfg.Tags)
+	assert.Equal(t, map[string]string{"team": "core", "tier": "frontend"}, cfg.Labels)
+	assert.Equal(t, map[string]string{"/docs": "docs"}, cfg.Routes)
+	assert.Equal(t, "base", cfg.Server.Host)
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, []string{"www", "api"}, cfg.Server.Aliases)
+
+	// Removed keys have no origin left
+	_, ok := cm.Origin("routes./api")
+	assert.False(t, ok)
+
+	// The root path sets the default, deeper paths refine it
+	cm, err = New[MergeTestConfig](
+		WithFile(baseFile),
+		WithFile(overrideFile),
+		WithMergeStrategy("", MergeAppend),
+		WithMergeStrategy("server.aliases", MergeDeep),
+	)
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg = cm.Get()
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, cfg.Origins)
+	assert.Equal(t, []string{"base", "override"}, cfg.Tags)
+	assert.Equal(t, []string{"api"}, cfg.Server.Aliases)
+
+	_, err = NewBuilder[MergeTestConfig]().AddFile(baseFile).WithMergeStrategy("tags", MergeStrategy(42)).Build(context.Background())
+	assert.ErrorContains(t, err, "unknown merge strategy MergeStrategy(42)")
+}
+
+func TestMergeStrategies_StrategyFor(t *testing.T) {
+	strategies := mergeStrategies{"server": MergeReplace, "server.tls.ciphers": MergeAppend}
+
+	assert.Equal(t, MergeDeep, strategies.strategyFor("name"))
+	assert.Equal(t, MergeReplace, strategies.strategyFor("server"))
+	assert.Equal(t, MergeReplace, strategies.strategyFor("server.tls"))
+	assert.Equal(t, MergeAppend, strategies.strategyFor("server.tls.ciphers"))
+	assert.Equal(t, MergeDeep, strategies.strategyFor("servers"))
+	assert.Equal(t, "append", MergeAppend.String())
+}
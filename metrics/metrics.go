@@ -0,0 +1,136 @@
+// Package metrics exports Prometheus metrics for a vcfg.ConfigManager: reload
+// attempts and failures, validation failures, the time of the last successful
+// reload, watcher errors and the durations and failures of plugin lifecycle
+// calls. Register the collectors and pass them to the manager as an observer:
+//
+//	m, err := metrics.New(prometheus.DefaultRegisterer)
+//	if err != nil {
+//		return err
+//	}
+//	cm, err := vcfg.NewBuilder[AppConfig]().
+//		AddFile("config.yaml").
+//		WithObserver(m).
+//		Build(ctx)
+package metrics
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/nextpkg/vcfg"
+	"github.com/nextpkg/vcfg/plugins"
+)
+
+// namespace prefixes the names of all metrics
+const namespace = "vcfg"
+
+// Metrics is a vcfg.Observer recording the configuration lifecycle as
+// Prometheus metrics:
+//
+//	vcfg_reloads_total                      reload attempts
+//	vcfg_reload_failures_total              failed reload attempts
+//	vcfg_validation_failures_total          reloads rejected by validation
+//	vcfg_last_reload_success_timestamp_seconds
+//	                                        Unix time of the last successful reload
+//	vcfg_watch_errors_total{source}         errors while watching a source
+//	vcfg_plugin_lifecycle_duration_seconds{plugin_type,phase}
+//	                                        duration of Startup, Reload and Shutdown calls
+//	vcfg_plugin_lifecycle_failures_total{plugin_type,phase}
+//	                                        failed Startup, Reload and Shutdown calls
+//
+// Plugin metrics are labeled by plugin type rather than instance to keep the
+// number of series bounded. A Metrics may observe several managers.
+type Metrics struct {
+	reloads            prometheus.Counter
+	reloadFailures     prometheus.Counter
+	validationFailures prometheus.Counter
+	lastReload         prometheus.Gauge
+	watchErrors        *prometheus.CounterVec
+	pluginDuration     *prometheus.HistogramVec
+	pluginFailures     *prometheus.CounterVec
+}
+
+// New creates the metrics and registers them on reg.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	if reg == nil {
+		return nil, errors.New("metrics registerer must not be nil")
+	}
+
+	m := &Metrics{
+		reloads: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reloads_total",
+			Help:      "Total number of configuration reload attempts.",
+		}),
+		reloadFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reload_failures_total",
+			Help:      "Total number of failed configuration reload attempts.",
+		}),
+		validationFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "validation_failures_total",
+			Help:      "Total number of configuration reloads that failed validation.",
+		}),
+		lastReload: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_reload_success_timestamp_seconds",
+			Help:      "Unix time of the last successful configuration reload.",
+		}),
+		watchErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "watch_errors_total",
+			Help:      "Total number of errors while watching configuration sources.",
+		}, []string{"source"}),
+		pluginDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "plugin_lifecycle_duration_seconds",
+			Help:      "Duration of plugin Startup, Reload and Shutdown calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"plugin_type", "phase"}),
+		pluginFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "plugin_lifecycle_failures_total",
+			Help:      "Total number of failed plugin Startup, Reload and Shutdown calls.",
+		}, []string{"plugin_type", "phase"}),
+	}
+
+	for _, c := range []prometheus.Collector{
+		m.reloads, m.reloadFailures, m.validationFailures, m.lastReload,
+		m.watchErrors, m.pluginDuration, m.pluginFailures,
+	} {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("failed to register configuration metrics: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// ReloadCompleted implements vcfg.Observer.
+func (m *Metrics) ReloadCompleted(info vcfg.ReloadInfo) {
+	m.reloads.Inc()
+	if info.Err != nil {
+		m.reloadFailures.Inc()
+		if errors.Is(info.Err, &vcfg.ConfigError{Type: vcfg.ErrorTypeValidationFailure}) {
+			m.validationFailures.Inc()
+		}
+		return
+	}
+	m.lastReload.Set(float64(info.Time.UnixNano()) / 1e9)
+}
+
+// WatchFailed implements vcfg.Observer.
+func (m *Metrics) WatchFailed(source string, _ error) {
+	m.watchErrors.WithLabelValues(source).Inc()
+}
+
+// PluginLifecycle implements plugins.Observer.
+func (m *Metrics) PluginLifecycle(event plugins.LifecycleEvent) {
+	phase := string(event.Phase)
+	m.pluginDuration.WithLabelValues(event.PluginType, phase).Observe(event.Duration.Seconds())
+	if event.Err != nil {
+		m.pluginFailures.WithLabelValues(event.PluginType, phase).Inc()
+	}
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nextpkg/vcfg"
+	"github.com/nextpkg/vcfg/plugins"
+)
+
+// probeConfig configures probePlugin
+type probeConfig struct {
+	plugins.BaseConfig `koanf:",squash"`
+	FailReload         bool `koanf:"fail_reload"`
+}
+
+// probePlugin is a plugin whose reloads fail on request
+type probePlugin struct{}
+
+func (p *probePlugin) Startup(ctx context.Context, config any) error { return nil }
+
+func (p *probePlugin) Reload(ctx context.Context, config any) error {
+	if config.(*probeConfig).FailReload {
+		return errors.New("reload refused")
+	}
+	return nil
+}
+
+func (p *probePlugin) Shutdown(ctx context.Context) error { return nil }
+
+func init() {
+	plugins.RegisterPluginType("probe", &probePlugin{}, &probeConfig{})
+}
+
+type metricsTestConfig struct {
+	Name  string      `koanf:"name" validate:"required"`
+	Port  int         `koanf:"port"`
+	Probe probeConfig `koanf:"probe"`
+}
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := New(reg)
+	require.NoError(t, err)
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("name: app\nport: 1\n"), 0644))
+
+	cm, err := vcfg.NewBuilder[metricsTestConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		WithObserver(m).
+		Build(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(m.pluginDuration))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.reloads))
+
+	// A successful reload
+	require.NoError(t, os.WriteFile(configFile, []byte("name: app\nport: 2\n"), 0644))
+	require.NoError(t, cm.Reload(context.Background()))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.reloads))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.reloadFailures))
+	assert.InDelta(t, float64(cm.LastReloadInfo().Time.Unix()), testutil.ToFloat64(m.lastReload), 1)
+
+	// A reload failing validation
+	require.NoError(t, os.WriteFile(configFile, []byte("name: ''\n"), 0644))
+	require.Error(t, cm.Reload(context.Background()))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.reloads))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.reloadFailures))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.validationFailures))
+
+	// A failing plugin reload
+	require.NoError(t, os.WriteFile(configFile, []byte("name: app\nprobe:\n  fail_reload: true\n"), 0644))
+	require.Error(t, cm.Reload(context.Background()))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.reloadFailures))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.validationFailures))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.pluginFailures.WithLabelValues("probe", "reload")))
+
+	require.NoError(t, cm.Close())
+	assert.Equal(t, 3, testutil.CollectAndCount(m.pluginDuration))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.pluginFailures.WithLabelValues("probe", "shutdown")))
+
+	m.WatchFailed("file:config.yaml", errors.New("watch failed"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.watchErrors.WithLabelValues("file:config.yaml")))
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err)
+
+	// Registering twice on the same registry fails
+	reg := prometheus.NewRegistry()
+	_, err = New(reg)
+	require.NoError(t, err)
+	_, err = New(reg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements migrations that rewrite the merged configuration keys of
+// older schema versions before they are unmarshaled.
+package vcfg
+
+import (
+	"fmt"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// SchemaVersionKey is the configuration key holding the schema version that
+// selects the migrations registered with WithSchemaMigration.
+const SchemaVersionKey = "schema_version"
+
+// Migration rewrites merged configuration values to the current schema, typically
+// by moving renamed keys with k.Set and k.Delete.
+type Migration func(k *koanf.Koanf) error
+
+// migration is a registered Migration
+type migration struct {
+	// fn rewrites the merged values
+	fn Migration
+	// versioned reports whether fn only applies to configs of schema version from
+	versioned bool
+	// from is the schema version fn upgrades to from+1
+	from int64
+}
+
+// applyMigrations runs migrations on the merged values in k. Versioned migrations
+// are chained first: while a migration is registered for the current schema
+// version it runs and the version is bumped by one, so a config of any older
+// version is upgraded step by step. A config without SchemaVersionKey is treated
+// as version 0. Unversioned migrations then run on every load in the order they
+// were registered.
+func applyMigrations(k *koanf.Koanf, migrations []migration) error {
+	byVersion := make(map[int64]Migration)
+	for _, m := range migrations {
+		if m.versioned {
+			byVersion[m.from] = m.fn
+		}
+	}
+
+	for len(byVersion) > 0 {
+		version := k.Int64(SchemaVersionKey)
+		fn, ok := byVersion[version]
+		if !ok {
+			break
+		}
+		// Each step runs at most once, even if it does not bump the version
+		delete(byVersion, version)
+
+		if err := fn(k); err != nil {
+			return NewConfigError(ErrorTypeMergeFailure, "migration",
+				fmt.Sprintf("migration from schema version %d failed", version), err)
+		}
+		if err := k.Set(SchemaVersionKey, version+1); err != nil {
+			return NewConfigError(ErrorTypeMergeFailure, "migration", "failed to update schema version", err)
+		}
+	}
+
+	for i, m := range migrations {
+		if m.versioned {
+			continue
+		}
+		if err := m.fn(k); err != nil {
+			return NewConfigError(ErrorTypeMergeFailure, "migration",
+				fmt.Sprintf("migration %d failed", i), err)
+		}
+	}
+
+	return nil
+}
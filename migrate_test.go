@@ -0,0 +1,124 @@
+package vcfg
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MigratedConfig is the current schema of a config whose keys were renamed over time
+type MigratedConfig struct {
+	SchemaVersion int `koanf:"schema_version"`
+	Database      struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"database"`
+	Timeout string `koanf:"timeout"`
+}
+
+// renameKey moves the value of from to to if from is set
+func renameKey(from, to string) Migration {
+	return func(k *koanf.Koanf) error {
+		if !k.Exists(from) {
+			return nil
+		}
+		if err := k.Set(to, k.Get(from)); err != nil {
+			return err
+		}
+		k.Delete(from)
+		return nil
+	}
+}
+
+func TestBuilder_WithMigration(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "db_host: legacy-host\n")
+
+	cm, err := NewBuilder[MigratedConfig]().
+		AddFile(configFile).
+		WithMigration(renameKey("db_host", "database.host")).
+		RequireKeys("database.host").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, "legacy-host", cm.Get().Database.Host)
+
+	merged, err := cm.MarshalMerged("yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(merged), "db_host")
+
+	// Migrations also run on reload
+	writeFile(t, configFile, "db_host: reloaded-host\n")
+	require.NoError(t, cm.reload(context.Background()))
+	assert.Equal(t, "reloaded-host", cm.Get().Database.Host)
+}
+
+func TestBuilder_WithSchemaMigration(t *testing.T) {
+	var steps []int
+	step := func(from int, m Migration) Migration {
+		return func(k *koanf.Koanf) error {
+			steps = append(steps, from)
+			return m(k)
+		}
+	}
+
+	build := func(content string) (*ConfigManager[MigratedConfig], error) {
+		steps = nil
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		writeFile(t, configFile, content)
+
+		return New[MigratedConfig](
+			WithFile(configFile),
+			// Registration order does not matter for versioned migrations
+			WithSchemaMigration(2, step(2, renameKey("database.address", "database.host"))),
+			WithSchemaMigration(1, step(1, renameKey("db", "database"))),
+			WithMigration(renameKey("request_timeout", "timeout")),
+		)
+	}
+
+	// A version 1 config passes through all steps
+	cm, err := build("schema_version: 1\ndb:\n  address: old-host\n  port: 5432\nrequest_timeout: 5s\n")
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, []int{1, 2}, steps)
+	assert.Equal(t, 3, cfg.SchemaVersion)
+	assert.Equal(t, "old-host", cfg.Database.Host)
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Equal(t, "5s", cfg.Timeout)
+
+	// A version 2 config only needs the last step
+	cm2, err := build("schema_version: 2\ndatabase:\n  address: v2-host\n")
+	require.NoError(t, err)
+	defer cm2.Close()
+	assert.Equal(t, []int{2}, steps)
+	assert.Equal(t, "v2-host", cm2.Get().Database.Host)
+
+	// A current config is left alone
+	cm3, err := build("schema_version: 3\ndatabase:\n  host: new-host\n")
+	require.NoError(t, err)
+	defer cm3.Close()
+	assert.Empty(t, steps)
+	assert.Equal(t, "new-host", cm3.Get().Database.Host)
+}
+
+func TestBuilder_WithMigration_Error(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "schema_version: 1\n")
+
+	errMigrate := errors.New("cannot migrate")
+	_, err := NewBuilder[MigratedConfig]().
+		AddFile(configFile).
+		WithSchemaMigration(1, func(*koanf.Koanf) error { return errMigrate }).
+		Build(context.Background())
+	assert.ErrorIs(t, err, errMigrate)
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeMergeFailure})
+	assert.ErrorContains(t, err, "migration from schema version 1 failed")
+}
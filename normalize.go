@@ -0,0 +1,89 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements key normalization, which rewrites the keys of every
+// source before merging so fragments written in different styles align.
+package vcfg
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/knadh/koanf/v2"
+
+	"github.com/nextpkg/vcfg/providers"
+)
+
+// mapProvider is a koanf.Provider serving an already parsed configuration map
+type mapProvider map[string]any
+
+// ReadBytes is not supported; the map is served by Read
+func (m mapProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("Read method not implemented, use Read instead")
+}
+
+// Read returns the configuration map
+func (m mapProvider) Read() (map[string]any, error) {
+	return m, nil
+}
+
+// loadProvider loads a single source into k, rewriting its keys with the
+// manager's key normalizer if one is configured and merging it according to the
+// configured merge strategies. It returns the flattened keys
+// the source set, so their origin can be recorded.
+func (cm *ConfigManager[T]) loadProvider(k *koanf.Koanf, providerConfig providers.ProviderConfig) ([]string, error) {
+	source := koanf.New(".")
+	if err := source.Load(providerConfig.Provider, providerConfig.Parser); err != nil {
+		return nil, err
+	}
+
+	if cm.keyNormalizer != nil {
+		normalized := koanf.New(".")
+		if err := normalized.Load(mapProvider(normalizeKeys(source.Raw(), cm.keyNormalizer)), nil); err != nil {
+			return nil, err
+		}
+		source = normalized
+	}
+
+	if cm.mergeStrategies != nil {
+		err := k.Load(mapProvider(source.Raw()), nil, koanf.WithMergeFunc(cm.mergeStrategies.merge))
+		if err != nil {
+			return nil, err
+		}
+		return source.Keys(), nil
+	}
+
+	if err := k.Merge(source); err != nil {
+		return nil, err
+	}
+	return source.Keys(), nil
+}
+
+// normalizeKeys returns a copy of m with fn applied to every key at every
+// nesting level. Keys that normalize to the same name are merged: nested maps
+// are combined, otherwise the value of the last key in sorted order wins.
+func normalizeKeys(m map[string]any, fn func(string) string) map[string]any {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	out := make(map[string]any, len(m))
+	for _, key := range keys {
+		value := m[key]
+		if nested, ok := value.(map[string]any); ok {
+			value = normalizeKeys(nested, fn)
+		}
+
+		name := fn(key)
+		if existing, ok := out[name].(map[string]any); ok {
+			if nested, ok := value.(map[string]any); ok {
+				for k, v := range nested {
+					existing[k] = v
+				}
+				continue
+			}
+		}
+		out[name] = value
+	}
+	return out
+}
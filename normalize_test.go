@@ -0,0 +1,75 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MixedFormatConfig struct {
+	Name   string `koanf:"name"`
+	Server struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"server"`
+	Tags []string `koanf:"tags"`
+}
+
+func TestBuilder_MixedFormats(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	override := filepath.Join(dir, "override.json")
+	writeFile(t, base, "name: app\nserver:\n  host: localhost\n  port: 80\ntags: [a]\n")
+	writeFile(t, override, `{"server": {"port": 9090}, "tags": ["b", "c"]}`)
+
+	cm, err := NewBuilder[MixedFormatConfig]().AddFile(base).AddFile(override).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, "app", cfg.Name)
+	assert.Equal(t, "localhost", cfg.Server.Host)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, []string{"b", "c"}, cfg.Tags)
+}
+
+func TestBuilder_WithKeyNormalizer(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	override := filepath.Join(dir, "override.json")
+	writeFile(t, base, "Name: app\nServer:\n  Host: localhost\n  Port: 80\n")
+	writeFile(t, override, `{"server": {"PORT": 9090}, "name": "override"}`)
+
+	cm, err := NewBuilder[MixedFormatConfig]().
+		AddFile(base).
+		AddFile(override).
+		WithKeyNormalizer(strings.ToLower).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	cfg := cm.Get()
+	assert.Equal(t, "override", cfg.Name)
+	assert.Equal(t, "localhost", cfg.Server.Host)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, []string{"name", "server"}, cm.koanf.MapKeys(""))
+	assert.ElementsMatch(t, []string{"host", "port"}, cm.koanf.MapKeys("server"))
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	in := map[string]any{
+		"Server": map[string]any{"Host": "a"},
+		"server": map[string]any{"port": 80},
+		"Name":   "x",
+	}
+
+	out := normalizeKeys(in, strings.ToLower)
+	assert.Equal(t, map[string]any{
+		"server": map[string]any{"host": "a", "port": 80},
+		"name":   "x",
+	}, out)
+}
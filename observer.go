@@ -0,0 +1,49 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the observation of reloads, watch errors and plugin
+// lifecycle calls, e.g. to export them as metrics.
+package vcfg
+
+import "github.com/nextpkg/vcfg/plugins"
+
+// Observer is notified of the configuration lifecycle. The metrics package
+// provides an implementation exporting Prometheus metrics. Methods are called
+// synchronously on the reloading goroutine, so they must return quickly.
+type Observer interface {
+	plugins.Observer
+	// ReloadCompleted is called after every reload attempt with its outcome:
+	// reloads from the sources as well as Update, Set, Commit and Rollback.
+	// info.Err is nil on success.
+	ReloadCompleted(info ReloadInfo)
+	// WatchFailed is called with errors reported while watching source
+	WatchFailed(source string, err error)
+}
+
+// observers fans notifications out to several observers in registration order
+type observers []Observer
+
+// PluginLifecycle implements plugins.Observer.
+func (o observers) PluginLifecycle(event plugins.LifecycleEvent) {
+	for _, observer := range o {
+		observer.PluginLifecycle(event)
+	}
+}
+
+// ReloadCompleted implements Observer.
+func (o observers) ReloadCompleted(info ReloadInfo) {
+	for _, observer := range o {
+		observer.ReloadCompleted(info)
+	}
+}
+
+// WatchFailed implements Observer.
+func (o observers) WatchFailed(source string, err error) {
+	for _, observer := range o {
+		observer.WatchFailed(source, err)
+	}
+}
+
+// recordReload stores info as the last reload and notifies the observers
+func (cm *ConfigManager[T]) recordReload(info ReloadInfo) {
+	cm.lastReload.Store(info)
+	cm.observers.ReloadCompleted(info)
+}
@@ -0,0 +1,72 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nextpkg/vcfg/plugins"
+)
+
+// recordingObserver records the notifications it receives
+type recordingObserver struct {
+	reloads     []ReloadInfo
+	watchErrors []error
+	lifecycle   []plugins.LifecycleEvent
+}
+
+func (o *recordingObserver) ReloadCompleted(info ReloadInfo) {
+	o.reloads = append(o.reloads, info)
+}
+
+func (o *recordingObserver) WatchFailed(_ string, err error) {
+	o.watchErrors = append(o.watchErrors, err)
+}
+
+func (o *recordingObserver) PluginLifecycle(event plugins.LifecycleEvent) {
+	o.lifecycle = append(o.lifecycle, event)
+}
+
+func TestBuilder_WithObserver(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\ncache:\n  host: one\n")
+
+	first, second := &recordingObserver{}, &recordingObserver{}
+	cm, err := NewBuilder[UpdateAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		WithObserver(first).
+		WithObserver(second).
+		Build(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, first.lifecycle, 1)
+	assert.Equal(t, plugins.PhaseStartup, first.lifecycle[0].Phase)
+	assert.Empty(t, first.reloads)
+
+	writeFile(t, configFile, "name: app\ncache:\n  host: two\n")
+	require.NoError(t, cm.Reload(context.Background()))
+	require.NoError(t, cm.Update(func(cfg *UpdateAppConfig) error {
+		cfg.Name = "updated"
+		return nil
+	}))
+	writeFile(t, configFile, "name: [broken\n")
+	require.Error(t, cm.Reload(context.Background()))
+
+	require.Len(t, first.reloads, 3)
+	assert.Equal(t, []string{"manual"}, first.reloads[0].Sources)
+	assert.NoError(t, first.reloads[0].Err)
+	assert.Equal(t, []string{updateSource}, first.reloads[1].Sources)
+	assert.Error(t, first.reloads[2].Err)
+	assert.Equal(t, plugins.PhaseReload, first.lifecycle[1].Phase)
+
+	require.NoError(t, cm.Close())
+	assert.Equal(t, plugins.PhaseShutdown, first.lifecycle[2].Phase)
+	assert.Equal(t, first, second)
+
+	_, err = NewBuilder[UpdateAppConfig]().WithObserver(nil).Build(context.Background())
+	assert.Error(t, err)
+}
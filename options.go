@@ -0,0 +1,656 @@
+// Package vcfg provides a flexible configuration management system.
+// This file implements functional options for constructing ConfigManager instances
+// with New, as an alternative to the Builder.
+package vcfg
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/knadh/koanf/providers/env"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+	"github.com/urfave/cli/v3"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nextpkg/vcfg/plugins"
+	"github.com/nextpkg/vcfg/providers"
+	"github.com/nextpkg/vcfg/slogs"
+)
+
+// options holds the construction settings that do not depend on the
+// configuration type. It is shared by Builder and New so both styles
+// produce identical managers.
+type options struct {
+	// sources holds the configuration sources (file paths, providers, etc.)
+	sources []any
+	// plugins holds manually added plugin entries
+	plugins []plugins.PluginEntry
+	// enableWatch determines if configuration file watching should be enabled
+	enableWatch bool
+	// watchOptions holds options applied when watching is enabled
+	watchOptions []WatchOption
+	// enablePlugin determines if plugin discovery and initialization should be enabled
+	enablePlugin bool
+	// lazyLoad defers loading the configuration until GetOrLoad is called
+	lazyLoad bool
+	// requiredKeys lists key paths that must be present after merging all sources
+	requiredKeys []string
+	// formatSniffing detects JSON or YAML from the content of sources of unknown format
+	formatSniffing bool
+	// includeKey enables include directives in file sources under this key
+	includeKey string
+	// validateTag is the struct tag holding validation rules
+	validateTag string
+	// keyNormalizer rewrites every key of every source before merging
+	keyNormalizer func(string) string
+	// mergeStrategies control how sources are combined per key path
+	mergeStrategies mergeStrategies
+	// defaultSources are optional defaults files merged below all other sources
+	defaultSources []any
+	// profile names the overlay layered over every file source, "" for none
+	profile string
+	// priority lists source kinds from lowest to highest precedence
+	priority []string
+	// migrations rewrite the merged values of older schemas before unmarshaling
+	migrations []migration
+	// decodeHooks convert merged values to field types before the built-in hooks
+	decodeHooks []mapstructure.DecodeHookFunc
+	// mapDeprecated copies the values of deprecated keys to their replacements
+	mapDeprecated bool
+	// strictKeys reports keys that match no field of the configuration type
+	strictKeys bool
+	// strictKeysMode selects whether unknown keys fail the load or are warned about
+	strictKeysMode StrictKeysMode
+	// pluginShutdownTimeout overrides the per-plugin shutdown deadline, nil for the default
+	pluginShutdownTimeout *time.Duration
+	// expandEnv expands environment variable references in string values
+	expandEnv bool
+	// interpolate expands references to other keys in string values
+	interpolate bool
+	// decryptor decrypts string values that start with decryptPrefix
+	decryptor Decryptor
+	// decryptPrefix marks encrypted values, "" for the default "enc:"
+	decryptPrefix string
+	// strictReload rejects reloads whose configuration fails to load or validate
+	strictReload bool
+	// snapshotRetention is the number of published configurations kept for Rollback
+	snapshotRetention int
+	// observers are notified of reloads, watch errors and plugin lifecycle calls
+	observers []Observer
+	// tracer records spans for the load and reload pipeline, nil to disable
+	tracer trace.Tracer
+	// logger receives the log output of the manager and its plugin manager
+	logger *slog.Logger
+	// err records the first invalid option; it is reported by Build
+	err error
+}
+
+// setErr records err unless an earlier option already failed.
+func (o *options) setErr(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+// log returns the logger set with WithLogger, the slogs logger if none
+func (o *options) log() *slog.Logger {
+	if o.logger != nil {
+		return o.logger
+	}
+	return slogs.Logger()
+}
+
+// Option configures a ConfigManager created by New.
+type Option func(*options)
+
+// WithFile adds a file path as a configuration source.
+// The file format is detected from the file extension.
+func WithFile(path string) Option {
+	return func(o *options) {
+		o.sources = append(o.sources, path)
+	}
+}
+
+// WithFileSet adds an ordered list of files as one logical configuration source.
+// See Builder.AddFileSet for details.
+func WithFileSet(paths []string) Option {
+	return func(o *options) {
+		fileSet, err := providers.NewFileSet(paths)
+		if err != nil {
+			o.setErr(err)
+			return
+		}
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     fileSet.Name(),
+			Provider: fileSet,
+		})
+	}
+}
+
+// WithDir adds the files of a drop-in directory matching pattern as one logical
+// configuration source. See Builder.AddDir for details.
+func WithDir(dir, pattern string) Option {
+	return func(o *options) {
+		dirSource, err := providers.NewDir(dir, pattern)
+		if err != nil {
+			o.setErr(err)
+			return
+		}
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     dirSource.Name(),
+			Provider: dirSource,
+		})
+	}
+}
+
+// WithDefaultsFile adds an optional defaults file as the lowest-priority source.
+// See Builder.AddDefaultsFile for details.
+func WithDefaultsFile(path string) Option {
+	return func(o *options) {
+		fileSet, err := providers.NewFileSet([]string{path})
+		if err != nil {
+			o.setErr(err)
+			return
+		}
+		o.defaultSources = append(o.defaultSources, providers.ProviderConfig{
+			Name:     "defaults:" + fileSet.Paths()[0],
+			Provider: fileSet,
+			Kind:     providers.KindDefaults,
+		})
+	}
+}
+
+// WithProfile layers the profile overlay of every file source over it, e.g.
+// config.prod.yaml over config.yaml. See Builder.WithProfile for details.
+func WithProfile(profile string) Option {
+	return func(o *options) {
+		if strings.ContainsAny(profile, `/\`) {
+			o.setErr(NewValidationError("profile", fmt.Sprintf("invalid profile name %q", profile), nil))
+			return
+		}
+		o.profile = profile
+	}
+}
+
+// WithPriority orders the sources by kind instead of registration order.
+// See Builder.WithPriority for details.
+func WithPriority(kinds ...string) Option {
+	return func(o *options) {
+		seen := make(map[string]bool, len(kinds))
+		for _, kind := range kinds {
+			if kind == "" || seen[kind] {
+				o.setErr(NewValidationError("priority", fmt.Sprintf("invalid or repeated source kind %q", kind), nil))
+				return
+			}
+			seen[kind] = true
+		}
+		o.priority = kinds
+	}
+}
+
+// WithFS adds a file of fsys, such as an embed.FS, as a defaults source.
+// See Builder.AddFS for details.
+func WithFS(fsys fs.FS, path string) Option {
+	return func(o *options) {
+		fsFile, err := providers.NewFSFile(fsys, path)
+		if err != nil {
+			o.setErr(err)
+			return
+		}
+		o.defaultSources = append(o.defaultSources, providers.ProviderConfig{
+			Name:     fsFile.Name(),
+			Provider: fsFile,
+			Parser:   fsFile.RequiredParser(),
+			Kind:     providers.KindDefaults,
+		})
+	}
+}
+
+// WithEnv adds environment variables with the given prefix as a configuration source.
+// The prefix is stripped and the remaining name is mapped to a dotted key,
+// e.g. APP_SERVER_PORT -> server.port.
+func WithEnv(prefix string) Option {
+	return func(o *options) {
+		envProvider := env.ProviderWithValue(prefix, ".", func(s string, v string) (string, any) {
+			// Remove the prefix and convert environment variable names to configuration keys
+			key := strings.TrimPrefix(s, prefix)
+			key = strings.ToLower(strings.ReplaceAll(key, "_", "."))
+			return key, v
+		})
+		// Name the source by its prefix so that several env sources stay
+		// distinguishable in Providers(), reload traces and errors
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     "env:" + prefix,
+			Provider: envProvider,
+		})
+	}
+}
+
+// WithCliFlags adds the flags of a urfave/cli command that were set as a
+// configuration source. See Builder.AddCliFlags for details.
+func WithCliFlags(cmd *cli.Command, delim string) Option {
+	return func(o *options) {
+		if cmd == nil {
+			o.setErr(NewValidationError("cli", "command must not be nil", nil))
+			return
+		}
+		cliProvider := providers.NewCliFlags(cmd, delim)
+
+		o.log().Debug("WithCliFlags: created provider", "cmd", cmd.Name, "delim", delim)
+
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     cliProvider.Name(),
+			Provider: cliProvider,
+			Kind:     providers.KindFlags,
+		})
+	}
+}
+
+// WithPFlags adds the flags of a pflag.FlagSet that were set on the command line
+// as a configuration source. See Builder.AddPFlags for details.
+func WithPFlags(flagSet *pflag.FlagSet) Option {
+	return func(o *options) {
+		if flagSet == nil {
+			o.setErr(NewValidationError("pflags", "flag set must not be nil", nil))
+			return
+		}
+		flags := providers.NewPFlags(flagSet, ".")
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     flags.Name(),
+			Provider: flags,
+			Kind:     providers.KindFlags,
+		})
+	}
+}
+
+// WithEnvBlob adds a configuration document stored base64-encoded in the
+// environment variable varName. format names the document's format, e.g.
+// "yaml" or "json". The variable is read when the configuration is loaded.
+func WithEnvBlob(varName, format string) Option {
+	return func(o *options) {
+		parser, err := providers.ParserForFormat(format)
+		if err != nil {
+			o.setErr(fmt.Errorf("env blob %s: %w", varName, err))
+			return
+		}
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     "env:" + varName,
+			Provider: providers.NewEnvBlob(varName),
+			Parser:   parser,
+		})
+	}
+}
+
+// WithBytes adds an in-memory configuration document in the given format as a
+// configuration source. See Builder.AddBytes for details.
+func WithBytes(data []byte, format string) Option {
+	return func(o *options) {
+		parser, err := providers.ParserForFormat(format)
+		if err != nil {
+			o.setErr(fmt.Errorf("bytes source: %w", err))
+			return
+		}
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     "bytes:" + strings.ToLower(strings.TrimPrefix(format, ".")),
+			Provider: rawbytes.Provider(append([]byte(nil), data...)),
+			Parser:   parser,
+		})
+	}
+}
+
+// WithString adds an in-memory configuration document in the given format as a
+// configuration source. See Builder.AddBytes for details.
+func WithString(s, format string) Option {
+	return WithBytes([]byte(s), format)
+}
+
+// WithConsul adds the keys below prefix in the Consul KV store as a configuration
+// source. See Builder.AddConsul for details.
+func WithConsul(addr, prefix string, opts ...providers.ConsulOption) Option {
+	return func(o *options) {
+		consul, err := providers.NewConsulProvider(addr, prefix, opts...)
+		if err != nil {
+			o.setErr(err)
+			return
+		}
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     consul.Name(),
+			Provider: consul,
+		})
+	}
+}
+
+// WithVault adds a secret of a Vault KV v2 secrets engine as a configuration
+// source. See Builder.AddVault for details.
+func WithVault(addr, path string, opts ...providers.VaultOption) Option {
+	return func(o *options) {
+		vault, err := providers.NewVaultProvider(addr, path, opts...)
+		if err != nil {
+			o.setErr(err)
+			return
+		}
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     vault.Name(),
+			Provider: vault,
+		})
+	}
+}
+
+// WithRemote adds a JSON or YAML document served at an HTTP(S) URL as a
+// configuration source. See Builder.AddRemote for details.
+func WithRemote(rawURL string, opts ...providers.RemoteOption) Option {
+	return func(o *options) {
+		remote, err := providers.NewRemoteProvider(rawURL, opts...)
+		if err != nil {
+			o.setErr(err)
+			return
+		}
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     remote.Name(),
+			Provider: remote,
+			Parser:   remote.RequiredParser(),
+		})
+	}
+}
+
+// WithSopsFile adds a SOPS-encrypted file as a configuration source. See
+// Builder.AddSopsFile for details.
+func WithSopsFile(path string, opts ...providers.SopsOption) Option {
+	return func(o *options) {
+		sops, err := providers.NewSopsFile(path, opts...)
+		if err != nil {
+			o.setErr(err)
+			return
+		}
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     sops.Name(),
+			Provider: sops,
+			Parser:   sops.RequiredParser(),
+		})
+	}
+}
+
+// WithProvider adds a custom koanf.Provider as a configuration source.
+func WithProvider(provider koanf.Provider) Option {
+	return func(o *options) {
+		o.sources = append(o.sources, provider)
+	}
+}
+
+// WithNamedProvider adds a custom koanf.Provider under a friendly name. See
+// Builder.AddNamedProvider for details.
+func WithNamedProvider(name string, provider koanf.Provider, parser koanf.Parser) Option {
+	return func(o *options) {
+		o.sources = append(o.sources, providers.ProviderConfig{
+			Name:     name,
+			Provider: provider,
+			Parser:   parser,
+		})
+	}
+}
+
+// WithWatch enables watching of the configuration sources for automatic reloading.
+// Options such as WithDebounce tune how change events are coalesced.
+func WithWatch(opts ...WatchOption) Option {
+	return func(o *options) {
+		o.enableWatch = true
+		o.watchOptions = append(o.watchOptions, opts...)
+	}
+}
+
+// WithWatchDebounce enables watching like WithWatch and coalesces change events
+// arriving within d of each other into a single reload. See Builder.WithWatchDebounce.
+func WithWatchDebounce(d time.Duration) Option {
+	return WithWatch(WithDebounce(d))
+}
+
+// WithPlugins enables plugin discovery and initialization.
+func WithPlugins() Option {
+	return func(o *options) {
+		o.enablePlugin = true
+	}
+}
+
+// WithRequiredKeys requires the given key paths, e.g. "server.port", to be set
+// by at least one source. See Builder.RequireKeys for details.
+func WithRequiredKeys(paths ...string) Option {
+	return func(o *options) {
+		o.requiredKeys = append(o.requiredKeys, paths...)
+	}
+}
+
+// WithFormatSniffing detects whether sources of unknown format contain JSON or
+// YAML by looking at their content. See Builder.WithFormatSniffing for details.
+func WithFormatSniffing() Option {
+	return func(o *options) {
+		o.formatSniffing = true
+	}
+}
+
+// WithIncludes lets file sources include other files listed under the
+// "include" key. See Builder.WithIncludes for details.
+func WithIncludes() Option {
+	return func(o *options) {
+		o.includeKey = "include"
+	}
+}
+
+// WithValidateTag reads validation rules from the struct tag name instead of
+// `validate`. See Builder.WithValidateTag for details.
+func WithValidateTag(name string) Option {
+	return func(o *options) {
+		o.validateTag = name
+	}
+}
+
+// WithKeyNormalizer rewrites the keys of every source with fn before merging.
+// See Builder.WithKeyNormalizer for details.
+func WithKeyNormalizer(fn func(string) string) Option {
+	return func(o *options) {
+		o.keyNormalizer = fn
+	}
+}
+
+// WithMergeStrategy sets how sources are merged at and below path.
+// See Builder.WithMergeStrategy for details.
+func WithMergeStrategy(path string, strategy MergeStrategy) Option {
+	return func(o *options) {
+		if strategy < MergeDeep || strategy > MergeAppend {
+			o.setErr(NewValidationError("merge", fmt.Sprintf("unknown merge strategy %v for %q", strategy, path), nil))
+			return
+		}
+		if o.mergeStrategies == nil {
+			o.mergeStrategies = make(mergeStrategies)
+		}
+		o.mergeStrategies[path] = strategy
+	}
+}
+
+// WithMigration adds a migration that runs on the merged values of every load.
+// See Builder.WithMigration for details.
+func WithMigration(fn Migration) Option {
+	return func(o *options) {
+		if fn == nil {
+			return
+		}
+		o.migrations = append(o.migrations, migration{fn: fn})
+	}
+}
+
+// WithSchemaMigration adds a migration from schema version from to from+1.
+// See Builder.WithSchemaMigration for details.
+func WithSchemaMigration(from int, fn Migration) Option {
+	return func(o *options) {
+		if fn == nil {
+			return
+		}
+		o.migrations = append(o.migrations, migration{fn: fn, versioned: true, from: int64(from)})
+	}
+}
+
+// WithEnvExpansion expands ${NAME} and ${NAME:-default} references to environment
+// variables in string values. See Builder.WithEnvExpansion for details.
+func WithEnvExpansion() Option {
+	return func(o *options) {
+		o.expandEnv = true
+	}
+}
+
+// WithInterpolation expands ${key.path} references to other configuration keys
+// in string values. See Builder.WithInterpolation for details.
+func WithInterpolation() Option {
+	return func(o *options) {
+		o.interpolate = true
+	}
+}
+
+// WithDecryptor decrypts string values that start with the decryption prefix.
+// See Builder.WithDecryptor for details.
+func WithDecryptor(decrypt Decryptor) Option {
+	return func(o *options) {
+		o.decryptor = decrypt
+	}
+}
+
+// WithDecryptPrefix sets the prefix marking encrypted values, "enc:" by default.
+// See Builder.WithDecryptor for details.
+func WithDecryptPrefix(prefix string) Option {
+	return func(o *options) {
+		if prefix == "" {
+			o.setErr(NewValidationError("decryptor", "decryption prefix must not be empty", nil))
+			return
+		}
+		o.decryptPrefix = prefix
+	}
+}
+
+// WithPluginShutdownTimeout bounds how long each plugin may take to shut down.
+// See Builder.WithPluginShutdownTimeout for details.
+func WithPluginShutdownTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.pluginShutdownTimeout = &timeout
+	}
+}
+
+// WithDecodeHook adds a mapstructure decode hook converting merged values to
+// custom field types. See Builder.WithDecodeHook for details.
+func WithDecodeHook(hook mapstructure.DecodeHookFunc) Option {
+	return func(o *options) {
+		if hook == nil {
+			o.setErr(NewValidationError("options", "decode hook must not be nil", nil))
+			return
+		}
+		o.decodeHooks = append(o.decodeHooks, hook)
+	}
+}
+
+// WithStrictKeys reports keys of the merged configuration that match no field
+// of the configuration type. See Builder.WithStrictKeys for details.
+func WithStrictKeys(mode ...StrictKeysMode) Option {
+	return func(o *options) {
+		o.strictKeys = true
+		o.strictKeysMode = StrictKeysFail
+		if len(mode) > 0 {
+			o.strictKeysMode = mode[len(mode)-1]
+		}
+	}
+}
+
+// WithDeprecatedKeyMapping copies the values of deprecated keys to the keys
+// that replace them. See Builder.WithDeprecatedKeyMapping for details.
+func WithDeprecatedKeyMapping() Option {
+	return func(o *options) {
+		o.mapDeprecated = true
+	}
+}
+
+// WithStrictReload rejects reloads whose new configuration fails to load or
+// validate. See Builder.WithStrictReload for details.
+func WithStrictReload() Option {
+	return func(o *options) {
+		o.strictReload = true
+	}
+}
+
+// WithObserver registers an observer of reloads, watch errors and plugin
+// lifecycle calls. See Builder.WithObserver for details.
+func WithObserver(observer Observer) Option {
+	return func(o *options) {
+		if observer == nil {
+			o.setErr(NewValidationError("options", "observer must not be nil", nil))
+			return
+		}
+		o.observers = append(o.observers, observer)
+	}
+}
+
+// WithTracer records OpenTelemetry spans for loads, reloads and plugin
+// lifecycle calls. See Builder.WithTracer for details.
+func WithTracer(tracer trace.Tracer) Option {
+	return func(o *options) {
+		if tracer == nil {
+			o.setErr(NewValidationError("options", "tracer must not be nil", nil))
+			return
+		}
+		o.tracer = tracer
+	}
+}
+
+// WithLogger routes the log output of the manager to logger. See
+// Builder.WithLogger for details.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		if logger == nil {
+			o.setErr(NewValidationError("options", "logger must not be nil", nil))
+			return
+		}
+		o.logger = logger
+	}
+}
+
+// WithSnapshotRetention sets how many published configurations are kept for
+// Rollback. See Builder.WithSnapshotRetention for details.
+func WithSnapshotRetention(n int) Option {
+	return func(o *options) {
+		if n < 1 {
+			o.setErr(NewValidationError("options", fmt.Sprintf("snapshot retention must be at least 1, got %d", n), nil))
+			return
+		}
+		o.snapshotRetention = n
+	}
+}
+
+// WithLazyLoad defers reading the configuration sources until the first call to
+// ConfigManager.GetOrLoad. It cannot be combined with WithPlugins, since plugin
+// discovery needs the loaded configuration.
+func WithLazyLoad() Option {
+	return func(o *options) {
+		o.lazyLoad = true
+	}
+}
+
+// New creates a ConfigManager from functional options. It is equivalent to
+// applying the same settings through NewBuilder and calling Build with a
+// background context, e.g. New[T](WithFile("config.yaml")) matches
+// NewBuilder[T]().AddFile("config.yaml").Build(context.Background()).
+//
+// Type parameter:
+//   - T: The configuration struct type to unmarshal into
+//
+// Returns a fully configured ConfigManager or an error if building fails.
+func New[T any](opts ...Option) (*ConfigManager[T], error) {
+	builder := NewBuilder[T]()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&builder.options)
+		}
+	}
+	return builder.Build(context.Background())
+}
@@ -0,0 +1,101 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+
+	"github.com/nextpkg/vcfg/providers"
+)
+
+func TestNew_MatchesBuilder(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: fromfile\ncache:\n  host: filehost\n  port: 1000\n")
+	t.Setenv("OPTS_CACHE_PORT", "2000")
+
+	fromOptions, err := New[RecorderAppConfig](
+		WithFile(configFile),
+		WithEnv("OPTS_"),
+		WithWatch(WithDebounce(time.Millisecond)),
+		WithPlugins(),
+	)
+	require.NoError(t, err)
+	defer fromOptions.Close()
+
+	fromBuilder, err := NewBuilder[RecorderAppConfig]().
+		AddFile(configFile).
+		AddEnv("OPTS_").
+		WithWatch(WithDebounce(time.Millisecond)).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer fromBuilder.Close()
+
+	assert.Equal(t, fromBuilder.Get(), fromOptions.Get())
+	assert.Equal(t, 2000, fromOptions.Get().Cache.Port)
+	assert.Equal(t, fromBuilder.Providers(), fromOptions.Providers())
+	assert.Len(t, fromOptions.watchers, len(fromBuilder.watchers))
+
+	plugin := getRecorder(t, fromOptions, "recorder:cache")
+	require.Len(t, plugin.startups, 1)
+	assert.Equal(t, "filehost", plugin.startups[0].Host)
+}
+
+func TestNew_Options(t *testing.T) {
+	builder := NewBuilder[BuilderTestConfig]()
+	for _, opt := range []Option{
+		WithFile("config.yaml"),
+		WithEnv("APP_"),
+		WithWatch(WithDebounce(time.Second)),
+		WithPlugins(),
+	} {
+		opt(&builder.options)
+	}
+
+	assert.Len(t, builder.sources, 2)
+	assert.Equal(t, "config.yaml", builder.sources[0])
+	assert.True(t, builder.enableWatch)
+	assert.Len(t, builder.watchOptions, 1)
+	assert.True(t, builder.enablePlugin)
+}
+
+func TestNew_SourceOptions(t *testing.T) {
+	cmd := &cli.Command{Name: "app"}
+	builder := NewBuilder[BuilderTestConfig]()
+	for _, opt := range []Option{
+		WithNamedProvider("settings", rawbytes.Provider([]byte(`{"port":9090}`)), json.Parser()),
+		WithCliFlags(cmd, "."),
+	} {
+		opt(&builder.options)
+	}
+
+	// The same sources as added by AddNamedProvider and AddCliFlags
+	expected := NewBuilder[BuilderTestConfig]().
+		AddNamedProvider("settings", rawbytes.Provider([]byte(`{"port":9090}`)), json.Parser()).
+		AddCliFlags(cmd, ".")
+	require.Len(t, builder.sources, 2)
+	for i, source := range builder.sources {
+		got, want := source.(providers.ProviderConfig), expected.sources[i].(providers.ProviderConfig)
+		assert.Equal(t, want.Name, got.Name)
+		assert.Equal(t, want.Kind, got.Kind)
+		assert.IsType(t, want.Provider, got.Provider)
+	}
+
+	_, err := New[BuilderTestConfig](WithCliFlags(nil, "."))
+	assert.ErrorContains(t, err, "command must not be nil")
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := New[BuilderTestConfig]()
+	assert.Error(t, err)
+
+	_, err = New[BuilderTestConfig](WithFile(filepath.Join(t.TempDir(), "missing.yaml")))
+	assert.Error(t, err)
+}
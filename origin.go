@@ -0,0 +1,129 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements per-key provenance: which source set each value of the
+// merged configuration.
+package vcfg
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/knadh/koanf/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultOrigin annotates dumped values that no source set
+const defaultOrigin = "default"
+
+// keyOrigins maps flattened key paths to the name of the source that set them.
+// It is replaced as a whole, never modified after merging.
+type keyOrigins map[string]string
+
+// record attributes keys to source; later sources override earlier ones
+func (o keyOrigins) record(keys []string, source string) {
+	for _, key := range keys {
+		o[key] = source
+	}
+}
+
+// prune drops the keys that are no longer present in k, e.g. keys renamed by a
+// migration or replaced by a later source setting their parent to a scalar
+func (o keyOrigins) prune(k *koanf.Koanf) {
+	for key := range o {
+		if !k.Exists(key) {
+			delete(o, key)
+		}
+	}
+}
+
+// Origin returns the name of the source that set the value at path, e.g.
+// "env:APP_" or the path of a config file, as listed by Providers. When several
+// sources set a key, the one merged last wins, as it does for the value. The
+// result is false for keys no source set, whose values come from struct tag
+// defaults, and for paths of whole sections such as "server"; use Origins to
+// list the keys below a section.
+func (cm *ConfigManager[T]) Origin(path string) (source string, ok bool) {
+	if cm == nil {
+		return "", false
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	source, ok = cm.origins[path]
+	return source, ok
+}
+
+// Origins returns the source of every key set in the merged configuration,
+// keyed by path. The returned map is a copy.
+func (cm *ConfigManager[T]) Origins() map[string]string {
+	if cm == nil {
+		return nil
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	origins := make(map[string]string, len(cm.origins))
+	for key, source := range cm.origins {
+		origins[key] = source
+	}
+	return origins
+}
+
+// marshalAnnotatedYAML marshals values as YAML with a comment after every value
+// naming the source that set it, or "default" if none did
+func marshalAnnotatedYAML(values map[string]any, origins map[string]string) ([]byte, error) {
+	node, err := annotatedNode(values, "", origins)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(node)
+}
+
+// annotatedNode converts a dumped section to a YAML mapping node whose leaf
+// values carry origin comments
+func annotatedNode(values map[string]any, path string, origins map[string]string) (*yaml.Node, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range keys {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: key}
+
+		var valueNode *yaml.Node
+		if section, ok := values[key].(map[string]any); ok && len(section) > 0 {
+			node, err := annotatedNode(section, keyPath, origins)
+			if err != nil {
+				return nil, err
+			}
+			valueNode = node
+		} else {
+			valueNode = &yaml.Node{}
+			if err := valueNode.Encode(values[key]); err != nil {
+				return nil, fmt.Errorf("%s: %w", keyPath, err)
+			}
+
+			comment := "from " + defaultOrigin
+			if source, ok := origins[keyPath]; ok {
+				comment = "from " + source
+			}
+
+			// Comments of block collections go on the key line
+			if valueNode.Kind == yaml.ScalarNode || valueNode.Style&yaml.FlowStyle != 0 {
+				valueNode.LineComment = comment
+			} else {
+				keyNode.LineComment = comment
+			}
+		}
+
+		mapping.Content = append(mapping.Content, keyNode, valueNode)
+	}
+	return mapping, nil
+}
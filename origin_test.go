@@ -0,0 +1,76 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type OriginTestConfig struct {
+	Server struct {
+		Host string `koanf:"host" default:"localhost"`
+		Port int    `koanf:"port"`
+	} `koanf:"server"`
+	Tags []string `koanf:"tags"`
+}
+
+func TestConfigManager_Origin(t *testing.T) {
+	dir := t.TempDir()
+	baseFile := filepath.Join(dir, "base.yaml")
+	overrideFile := filepath.Join(dir, "override.yaml")
+	writeFile(t, baseFile, "server:\n  port: 8080\ntags: [a, b]\n")
+	writeFile(t, overrideFile, "server:\n  port: 9090\n")
+	t.Setenv("VCFGORIGIN_SERVER_PORT", "7070")
+
+	cm, err := NewBuilder[OriginTestConfig]().
+		AddFile(baseFile).
+		AddFile(overrideFile).
+		WithLazyLoad().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+	_, err = cm.GetOrLoad()
+	require.NoError(t, err)
+
+	source, ok := cm.Origin("server.port")
+	assert.True(t, ok)
+	assert.Equal(t, cm.Providers()[1].Name, source)
+
+	source, ok = cm.Origin("tags")
+	assert.True(t, ok)
+	assert.Equal(t, cm.Providers()[0].Name, source)
+
+	// Defaults and sections have no origin
+	_, ok = cm.Origin("server.host")
+	assert.False(t, ok)
+	_, ok = cm.Origin("server")
+	assert.False(t, ok)
+
+	assert.Equal(t, map[string]string{
+		"server.port": cm.Providers()[1].Name,
+		"tags":        cm.Providers()[0].Name,
+	}, cm.Origins())
+
+	// An environment source merged last wins
+	withEnv, err := NewBuilder[OriginTestConfig]().
+		AddFile(baseFile).
+		AddEnv("VCFGORIGIN_").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer withEnv.Close()
+
+	source, _ = withEnv.Origin("server.port")
+	assert.Equal(t, "env:VCFGORIGIN_", source)
+
+	data, err := withEnv.Dump("yaml", WithDumpOrigins())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "port: 7070 # from env:VCFGORIGIN_")
+	assert.Contains(t, string(data), "host: localhost # from default")
+	assert.Contains(t, string(data), "tags: # from "+withEnv.Providers()[0].Name)
+
+	_, err = withEnv.Dump("json", WithDumpOrigins())
+	assert.Error(t, err)
+}
@@ -39,12 +39,16 @@ type LoggerConfig struct {
 	EnableRotation bool `koanf:"enable_rotation" default:"false"`
 	// RotateInterval sets the rotation interval (daily, hourly)
 	RotateInterval string `koanf:"rotate_interval" default:"daily"`
-	// MaxFileSize sets the maximum file size in bytes before rotation (0 = no size limit)
-	MaxFileSize int64 `koanf:"max_file_size" default:"524288000"` // 500MB
+	// MaxFileSize sets the maximum file size in bytes before rotation (0 = no size limit).
+	// Human-readable sizes such as "500MB" are accepted; non-zero values must be at least 1KB.
+	MaxFileSize int64 `koanf:"max_file_size" default:"524288000" validate:"omitempty,min_bytes=1KB"` // 500MB
 	// MaxAge sets the maximum number of days to retain old log files
 	MaxAge int `koanf:"max_age" default:"7"`
 	// TimeFormat sets the time format for rotated file names
 	TimeFormat string `koanf:"time_format" default:"2006-01-02"`
+	// RotateUTC uses UTC instead of local time for rotation boundaries and file name
+	// date stamps, so hosts in different time zones rotate at the same instant
+	RotateUTC bool `koanf:"rotate_utc" default:"false"`
 }
 
 // LoggerPlugin implements the logger plugin that provides structured logging
@@ -54,6 +58,8 @@ type LoggerPlugin struct {
 	mu sync.RWMutex
 	// logger is the configured slog.Logger instance
 	logger *slog.Logger
+	// out is the writer behind the logger's handler; Reload swaps its destination
+	out *swapWriter
 	// file holds the log file handle when file output is enabled
 	file *os.File
 	// config stores the current plugin configuration
@@ -113,63 +119,167 @@ func setGlobalLogger(logger *slog.Logger) {
 // Returns:
 //   - error: An error if initialization fails, nil otherwise
 func (p *LoggerPlugin) Startup(ctx context.Context, config any) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	loggerConfig, ok := config.(*LoggerConfig)
 	if !ok {
 		return fmt.Errorf("invalid logger config type: %T", config)
 	}
 
+	logger, err := p.configure(loggerConfig)
+	if err != nil {
+		return err
+	}
+
+	// Set as global logger
+	setGlobalLogger(logger)
+
+	// Log outside the plugin lock: the rotating writer acquires it on every write
+	logger.Info("Logger plugin started",
+		"level", loggerConfig.Level,
+		"format", loggerConfig.Format,
+		"output", loggerConfig.Output,
+		"add_source", loggerConfig.AddSource,
+	)
+
+	return nil
+}
+
+// configure builds the logger for the given configuration and stores it
+// together with the configuration on the plugin.
+//
+// Parameters:
+//   - loggerConfig: The logger configuration to apply
+//
+// Returns:
+//   - *slog.Logger: The configured logger
+//   - error: An error if the configuration is invalid, nil otherwise
+func (p *LoggerPlugin) configure(loggerConfig *LoggerConfig) (*slog.Logger, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	p.config = loggerConfig
 
-	// Parse log level
-	level, err := parseLogLevel(p.config.Level)
+	newHandler, err := p.handlerFactory()
 	if err != nil {
-		return fmt.Errorf("invalid log level %s: %w", p.config.Level, err)
+		return nil, err
 	}
 
 	// Create writer based on output configuration
 	writer, err := p.createWriter()
 	if err != nil {
-		return fmt.Errorf("failed to create writer: %w", err)
+		return nil, fmt.Errorf("failed to create writer: %w", err)
+	}
+
+	// The handler writes through a swapWriter so Reload can change the destination
+	p.out = &swapWriter{w: writer}
+	p.logger = slog.New(newHandler(p.out))
+
+	return p.logger, nil
+}
+
+// reconfigure applies a new configuration to a running logger. The new output
+// is opened first and then swapped in behind the existing handler while writes
+// are held back, so lines logged concurrently, including through loggers obtained
+// before the reload, are neither lost nor written to a closed file. On failure
+// the previous configuration stays in effect.
+//
+// Parameters:
+//   - out: The running logger's swapWriter
+//   - loggerConfig: The logger configuration to apply
+//
+// Returns:
+//   - *slog.Logger: The logger for the new configuration
+//   - error: An error if the configuration is invalid, nil otherwise
+func (p *LoggerPlugin) reconfigure(out *swapWriter, loggerConfig *LoggerConfig) (*slog.Logger, error) {
+	// Lock order matches writes: swapWriter first, then the plugin
+	out.mu.Lock()
+	defer out.mu.Unlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	oldConfig, oldFile := p.config, p.file
+	oldLogDate, oldFileSize, oldSequence := p.currentLogDate, p.currentFileSize, p.fileSequence
+	restore := func() {
+		if p.file != nil && p.file != oldFile {
+			_ = p.file.Close()
+		}
+		p.config, p.file = oldConfig, oldFile
+		p.currentLogDate, p.currentFileSize, p.fileSequence = oldLogDate, oldFileSize, oldSequence
+	}
+
+	p.config = loggerConfig
+	p.file = nil
+
+	newHandler, err := p.handlerFactory()
+	if err != nil {
+		restore()
+		return nil, err
+	}
+
+	writer, err := p.createWriter()
+	if err != nil {
+		restore()
+		return nil, fmt.Errorf("failed to create writer: %w", err)
+	}
+
+	out.w = writer
+	if oldFile != nil {
+		// The new output is already live; a failed close only leaks the old handle
+		_ = oldFile.Close()
+	}
+
+	p.logger = slog.New(newHandler(out))
+	return p.logger, nil
+}
+
+// handlerFactory validates the level and format of the current configuration
+// and returns a constructor for the matching slog.Handler.
+//
+// Returns:
+//   - func(io.Writer) slog.Handler: Creates a handler writing to the given writer
+//   - error: An error if the level or format is invalid, nil otherwise
+func (p *LoggerPlugin) handlerFactory() (func(io.Writer) slog.Handler, error) {
+	// Parse log level
+	level, err := parseLogLevel(p.config.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level %s: %w", p.config.Level, err)
 	}
 
-	// Create handler based on format
-	var handler slog.Handler
 	handlerOpts := &slog.HandlerOptions{
 		Level:     level,
 		AddSource: p.config.AddSource,
 	}
 
+	// Create handler based on format
 	switch strings.ToLower(p.config.Format) {
 	case "json":
-		handler = slog.NewJSONHandler(writer, handlerOpts)
+		return func(w io.Writer) slog.Handler { return slog.NewJSONHandler(w, handlerOpts) }, nil
 	case "text":
-		handler = slog.NewTextHandler(writer, handlerOpts)
+		return func(w io.Writer) slog.Handler { return slog.NewTextHandler(w, handlerOpts) }, nil
 	default:
-		return fmt.Errorf("unsupported log format: %s", p.config.Format)
+		return nil, fmt.Errorf("unsupported log format: %s", p.config.Format)
 	}
+}
 
-	// Create logger
-	p.logger = slog.New(handler)
-
-	// Set as global logger
-	setGlobalLogger(p.logger)
-
-	p.logger.Info("Logger plugin started",
-		"level", p.config.Level,
-		"format", p.config.Format,
-		"output", p.config.Output,
-		"add_source", p.config.AddSource,
-	)
+// swapWriter is an io.Writer whose destination can be replaced while in use.
+// Writes hold a read lock and a swap holds the write lock, so a write either
+// completes against the old destination or waits for the new one.
+type swapWriter struct {
+	mu sync.RWMutex
+	w  io.Writer
+}
 
-	return nil
+// Write implements io.Writer by forwarding to the current destination
+func (sw *swapWriter) Write(b []byte) (int, error) {
+	sw.mu.RLock()
+	defer sw.mu.RUnlock()
+	return sw.w.Write(b)
 }
 
 // Reload implements the plugins.Plugin interface by reloading the logger
-// with new configuration. It gracefully shuts down the current logger
-// and reinitializes it with the new settings.
+// with new configuration. A running logger keeps its handler output and only
+// swaps the destination writer, so output changes (e.g. stdout to file) do not
+// drop log lines. If the logger is not running it is started instead.
 //
 // Parameters:
 //   - ctx: Context for the reload operation
@@ -178,15 +288,36 @@ func (p *LoggerPlugin) Startup(ctx context.Context, config any) error {
 // Returns:
 //   - error: An error if reload fails, nil otherwise
 func (p *LoggerPlugin) Reload(ctx context.Context, config any) error {
-	p.logger.Info("Reloading logger plugin")
+	loggerConfig, ok := config.(*LoggerConfig)
+	if !ok {
+		return fmt.Errorf("invalid logger config type: %T", config)
+	}
+
+	p.mu.RLock()
+	logger, out := p.logger, p.out
+	p.mu.RUnlock()
+
+	if out == nil {
+		return p.Startup(ctx, config)
+	}
 
-	// Stop current logger first
-	if err := p.Shutdown(ctx); err != nil {
-		return fmt.Errorf("failed to stop logger during reload: %w", err)
+	logger.Info("Reloading logger plugin")
+
+	logger, err := p.reconfigure(out, loggerConfig)
+	if err != nil {
+		return fmt.Errorf("failed to reload logger: %w", err)
 	}
 
-	// Start with new config
-	return p.Startup(ctx, config)
+	setGlobalLogger(logger)
+
+	logger.Info("Logger plugin reloaded",
+		"level", loggerConfig.Level,
+		"format", loggerConfig.Format,
+		"output", loggerConfig.Output,
+		"add_source", loggerConfig.AddSource,
+	)
+
+	return nil
 }
 
 // Shutdown implements the plugins.Plugin interface by gracefully shutting down
@@ -198,13 +329,18 @@ func (p *LoggerPlugin) Reload(ctx context.Context, config any) error {
 // Returns:
 //   - error: An error if shutdown fails, nil otherwise
 func (p *LoggerPlugin) Shutdown(ctx context.Context) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	// Log before taking the lock: the rotating writer acquires it on every write
+	p.mu.RLock()
+	logger := p.logger
+	p.mu.RUnlock()
 
-	if p.logger != nil {
-		p.logger.Info("Logger plugin stopping")
+	if logger != nil {
+		logger.Info("Logger plugin stopping")
 	}
 
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	// Close file if opened
 	if p.file != nil {
 		if err := p.file.Close(); err != nil {
@@ -214,6 +350,7 @@ func (p *LoggerPlugin) Shutdown(ctx context.Context) error {
 	}
 
 	p.logger = nil
+	p.out = nil
 	p.config = nil
 
 	return nil
@@ -342,14 +479,14 @@ func (p *LoggerPlugin) createRotatingFileWriter() (io.Writer, error) {
 	// Create rotating writer
 	return &rotatingWriter{
 		plugin: p,
-		file:   file,
 	}, nil
 }
 
-// rotatingWriter wraps a file and handles rotation logic
+// rotatingWriter handles rotation logic for the plugin's log file. It keeps no
+// file handle of its own and always writes to the plugin's current file, so the
+// handler built on top of it can never write to a file that was rotated away.
 type rotatingWriter struct {
 	plugin *LoggerPlugin
-	file   *os.File
 }
 
 // Write implements io.Writer interface with rotation logic
@@ -362,21 +499,31 @@ func (rw *rotatingWriter) Write(p []byte) (n int, err error) {
 		if err = rw.plugin.rotateFile(); err != nil {
 			return 0, fmt.Errorf("failed to rotate log file: %w", err)
 		}
-		// Update file reference after rotation
-		rw.file = rw.plugin.file
+	}
+
+	if rw.plugin.file == nil {
+		return 0, fmt.Errorf("log file is closed")
 	}
 
 	// Write to current file
-	n, err = rw.file.Write(p)
+	n, err = rw.plugin.file.Write(p)
 	if err == nil {
 		rw.plugin.currentFileSize += int64(n)
 	}
 	return n, err
 }
 
+// now returns the current time in the zone used for rotation, UTC if RotateUTC is set
+func (p *LoggerPlugin) now() time.Time {
+	if p.config.RotateUTC {
+		return time.Now().UTC()
+	}
+	return time.Now()
+}
+
 // needsRotation checks if log rotation is needed based on time or file size
 func (p *LoggerPlugin) needsRotation() bool {
-	now := time.Now()
+	now := p.now()
 	currentDate := now.Format(p.config.TimeFormat)
 
 	// Check time-based rotation
@@ -392,15 +539,9 @@ func (p *LoggerPlugin) needsRotation() bool {
 	return false
 }
 
-// rotateFile performs the actual file rotation
+// rotateFile performs the actual file rotation. The new file is opened before
+// the current one is closed, so a failed rotation keeps logging to the current file.
 func (p *LoggerPlugin) rotateFile() error {
-	// Close current file
-	if p.file != nil {
-		if err := p.file.Close(); err != nil {
-			return fmt.Errorf("failed to close current log file: %w", err)
-		}
-	}
-
 	// Get new log file path
 	newLogPath, err := p.getCurrentLogPath()
 	if err != nil {
@@ -413,16 +554,24 @@ func (p *LoggerPlugin) rotateFile() error {
 		return fmt.Errorf("failed to open new log file: %w", err)
 	}
 
-	// Update plugin state
+	// Switch to the new file before closing the current one, so writes never
+	// reach a closed handle even if closing fails
+	oldFile := p.file
 	p.file = file
 	p.currentFileSize = 0
 
+	if oldFile != nil {
+		if err := oldFile.Close(); err != nil {
+			return fmt.Errorf("failed to close rotated log file: %w", err)
+		}
+	}
+
 	return nil
 }
 
 // getCurrentLogPath generates the current log file path based on rotation settings
 func (p *LoggerPlugin) getCurrentLogPath() (string, error) {
-	now := time.Now()
+	now := p.now()
 	currentDate := now.Format(p.config.TimeFormat)
 
 	// Update current log date
@@ -507,7 +656,7 @@ func (p *LoggerPlugin) cleanupOldLogs() error {
 	}
 
 	// Parse dates and remove old files
-	cutoffDate := time.Now().AddDate(0, 0, -p.config.MaxAge)
+	cutoffDate := p.now().AddDate(0, 0, -p.config.MaxAge)
 	prefixLen := len(baseWithoutExt) + 1 // +1 for the dash
 
 	for _, file := range files {
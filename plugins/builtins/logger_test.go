@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -751,7 +753,6 @@ func TestLoggerPlugin_RotatingWriter(t *testing.T) {
 	// Create rotating writer
 	rw := &rotatingWriter{
 		plugin: plugin,
-		file:   file,
 	}
 
 	// Write some data
@@ -765,6 +766,50 @@ func TestLoggerPlugin_RotatingWriter(t *testing.T) {
 	plugin.file.Close()
 }
 
+// TestLoggerPlugin_LogsFollowRotation verifies that logs written through the
+// plugin's handler land in the new file after a size-based rotation
+func TestLoggerPlugin_LogsFollowRotation(t *testing.T) {
+	tempDir := t.TempDir()
+	logFile := filepath.Join(tempDir, "app.log")
+
+	config := &LoggerConfig{
+		Level:          "info",
+		Format:         "json",
+		Output:         "file",
+		FilePath:       logFile,
+		EnableRotation: true,
+		MaxFileSize:    300,
+		TimeFormat:     "2006-01-02",
+	}
+
+	plugin := &LoggerPlugin{}
+	require.NoError(t, plugin.Startup(context.Background(), config))
+	defer plugin.Shutdown(context.Background())
+
+	currentPath := func() string {
+		plugin.mu.RLock()
+		defer plugin.mu.RUnlock()
+		return plugin.file.Name()
+	}
+	firstPath := currentPath()
+
+	// Write until MaxFileSize is exceeded and the file is rotated
+	for i := 0; currentPath() == firstPath; i++ {
+		require.Less(t, i, 100, "expected a rotation")
+		plugin.logger.Info("before rotation", "index", i)
+	}
+	plugin.logger.Info("after rotation")
+
+	rotated, err := os.ReadFile(firstPath)
+	require.NoError(t, err)
+	current, err := os.ReadFile(currentPath())
+	require.NoError(t, err)
+
+	assert.Contains(t, string(current), "after rotation")
+	assert.NotContains(t, string(rotated), "after rotation")
+	assert.Contains(t, string(rotated), "before rotation")
+}
+
 // TestLoggerPlugin_RotateFile tests the rotateFile method
 func TestLoggerPlugin_RotateFile(t *testing.T) {
 	tempDir := t.TempDir()
@@ -846,3 +891,131 @@ func TestLoggerPlugin_CreateWriter(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggerPlugin_RotateUTC(t *testing.T) {
+	// Use a local zone far from UTC so local and UTC stamps differ
+	originalLocal := time.Local
+	time.Local = time.FixedZone("UTC+14", 14*60*60)
+	defer func() { time.Local = originalLocal }()
+
+	const timeFormat = "2006-01-02-15"
+
+	tests := []struct {
+		name      string
+		rotateUTC bool
+		expected  func() string
+	}{
+		{"utc", true, func() string { return time.Now().UTC().Format(timeFormat) }},
+		{"local", false, func() string { return time.Now().Format(timeFormat) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			logFile := filepath.Join(tempDir, "test.log")
+
+			config := &LoggerConfig{
+				Level:          "info",
+				Format:         "text",
+				Output:         "file",
+				FilePath:       logFile,
+				EnableRotation: true,
+				TimeFormat:     timeFormat,
+				RotateUTC:      tt.rotateUTC,
+			}
+
+			plugin := &LoggerPlugin{}
+			err := plugin.Startup(context.Background(), config)
+			require.NoError(t, err)
+			defer plugin.Shutdown(context.Background())
+
+			GetLogger().Info("stamped")
+
+			expectedPath := filepath.Join(tempDir, fmt.Sprintf("test-%s.log", tt.expected()))
+			_, err = os.Stat(expectedPath)
+			assert.NoError(t, err, "expected log file %s", expectedPath)
+			assert.Equal(t, tt.expected(), plugin.currentLogDate)
+
+			// No rotation is needed within the same window
+			plugin.mu.Lock()
+			assert.False(t, plugin.needsRotation())
+			plugin.mu.Unlock()
+		})
+	}
+}
+
+// TestLoggerPlugin_ReloadSwapsOutputWithoutLoss flips the output file while
+// other goroutines keep logging and checks that every line lands in a file
+func TestLoggerPlugin_ReloadSwapsOutputWithoutLoss(t *testing.T) {
+	tempDir := t.TempDir()
+	files := []string{filepath.Join(tempDir, "a.log"), filepath.Join(tempDir, "b.log")}
+
+	plugin := &LoggerPlugin{}
+	err := plugin.Startup(context.Background(), &LoggerConfig{
+		Level:    "info",
+		Format:   "json",
+		Output:   "file",
+		FilePath: files[0],
+	})
+	require.NoError(t, err)
+
+	// A logger obtained before any reload must keep working afterwards
+	early := GetLogger()
+
+	const writers, perWriter = 4, 200
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWriter {
+				if i%2 == 0 {
+					early.Info("payload", "writer", w, "seq", i)
+				} else {
+					GetLogger().Info("payload", "writer", w, "seq", i)
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 20; i++ {
+		err := plugin.Reload(context.Background(), &LoggerConfig{
+			Level:    "info",
+			Format:   "json",
+			Output:   "file",
+			FilePath: files[i%2],
+		})
+		require.NoError(t, err)
+	}
+
+	wg.Wait()
+	require.NoError(t, plugin.Shutdown(context.Background()))
+
+	seen := 0
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		seen += strings.Count(string(content), `"msg":"payload"`)
+	}
+	assert.Equal(t, writers*perWriter, seen)
+}
+
+// TestLoggerPlugin_ReloadFailureKeepsOutput checks that an invalid reload leaves the logger intact
+func TestLoggerPlugin_ReloadFailureKeepsOutput(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+
+	plugin := &LoggerPlugin{}
+	config := &LoggerConfig{Level: "info", Format: "json", Output: "file", FilePath: logFile}
+	require.NoError(t, plugin.Startup(context.Background(), config))
+
+	err := plugin.Reload(context.Background(), &LoggerConfig{Level: "info", Format: "xml", Output: "stdout"})
+	assert.Error(t, err)
+	assert.Same(t, config, plugin.config)
+
+	plugin.logger.Info("after failed reload")
+	require.NoError(t, plugin.Shutdown(context.Background()))
+
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "after failed reload")
+}
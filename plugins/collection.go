@@ -0,0 +1,333 @@
+// Package plugins provides a comprehensive plugin management system that supports
+// automatic discovery, registration, and lifecycle management of plugins.
+// This file implements plugin instances configured as the entries of map and
+// slice fields, which may be added and removed between configuration versions.
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// configInterfaceType is the reflect type of the Config interface
+var configInterfaceType = reflect.TypeOf((*Config)(nil)).Elem()
+
+// isConfigStruct reports whether t is a plugin config struct
+func isConfigStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(configInterfaceType)
+}
+
+// isConfigMap reports whether t is a map with string keys and plugin config
+// values, such as map[string]KafkaConfig
+func isConfigMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && isConfigStruct(t.Elem())
+}
+
+// isConfigSlice reports whether t is a slice of plugin configs, such as
+// []RedisConfig
+func isConfigSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && isConfigStruct(t.Elem())
+}
+
+// isConfigCollection reports whether t is a map or slice of plugin configs,
+// each entry of which is an instance of its own
+func isConfigCollection(t reflect.Type) bool {
+	return isConfigMap(t) || isConfigSlice(t)
+}
+
+// collectionInstance is a plugin instance configured by an entry of a map or
+// slice field
+type collectionInstance struct {
+	// config is an addressable copy of the entry
+	config Config
+	// configPath is the path of the entry, e.g. Kafka.orders or Redis[0]
+	configPath string
+	// instanceName is the instance name of the entry, e.g. kafka.orders or redis[0]
+	instanceName string
+}
+
+// collectionInstances returns the instances configured by the map or slice of
+// plugin configs v held by field at fieldPath. Map entries are named after
+// their key and returned in key order, e.g. kafka.orders. Slice elements are
+// named after their index, e.g. redis[0], or after the value of the element
+// field named by the key option of the plugin tag, e.g. redis[primary], so
+// they keep their instance when the slice is reordered. A name set with the
+// plugin tag replaces the field path in the instance names.
+func collectionInstances(field reflect.StructField, v reflect.Value, fieldPath string) ([]collectionInstance, error) {
+	options, err := parsePluginTag(field)
+	if err != nil {
+		return nil, err
+	}
+	baseName := options.name
+	if baseName == "" {
+		baseName = strings.ToLower(fieldPath)
+	}
+
+	var instances []collectionInstance
+	seen := make(map[string]bool)
+	add := func(id, configPath string, value reflect.Value) error {
+		// The id is part of the plugin key "pluginType:instanceName"
+		if id == "" || strings.Contains(id, ":") {
+			return fmt.Errorf("invalid plugin instance name %q at %s", id, configPath)
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicate plugin instance name %q at %s", id, configPath)
+		}
+		seen[id] = true
+
+		config := reflect.New(value.Type())
+		config.Elem().Set(value)
+		instanceName := baseName + "." + id
+		if v.Kind() == reflect.Slice {
+			instanceName = baseName + "[" + id + "]"
+		}
+		instances = append(instances, collectionInstance{
+			config:       config.Interface().(Config),
+			configPath:   configPath,
+			instanceName: instanceName,
+		})
+		return nil
+	}
+
+	if v.Kind() == reflect.Map {
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			value := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+			if err := add(key, fieldPath+"."+key, value); err != nil {
+				return nil, err
+			}
+		}
+		return instances, nil
+	}
+
+	for i := range v.Len() {
+		configPath := fmt.Sprintf("%s[%d]", fieldPath, i)
+		id := strconv.Itoa(i)
+		if options.key != "" {
+			keyValue, err := elementKey(v.Index(i), options.key)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", configPath, err)
+			}
+			id = keyValue
+		}
+		if err := add(id, configPath, v.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	return instances, nil
+}
+
+// elementKey returns the value of the field of the slice element elem named
+// name, matched case-insensitively against the koanf keys and Go names of the
+// fields, including those of embedded structs such as BaseConfig. The field
+// must hold a string, boolean or number.
+func elementKey(elem reflect.Value, name string) (string, error) {
+	field, ok := lookupKeyField(elem, name)
+	if !ok {
+		return "", fmt.Errorf("key field %q not found in %v", name, elem.Type())
+	}
+
+	switch field.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(field.Interface()), nil
+	default:
+		return "", fmt.Errorf("key field %q has unsupported type %v", name, field.Type())
+	}
+}
+
+// lookupKeyField returns the field of struct v named name, see elementKey
+func lookupKeyField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if found, ok := lookupKeyField(v.Field(i), name); ok {
+				return found, true
+			}
+			continue
+		}
+
+		key, _, _ := strings.Cut(field.Tag.Get("koanf"), ",")
+		if field.IsExported() && (strings.EqualFold(key, name) || strings.EqualFold(field.Name, name)) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// discoverCollection registers an instance for every entry of the map or slice
+// of plugin configs v held by field. The caller must hold pm.mu.
+func (pm *PluginManager[T]) discoverCollection(pluginTypes map[string]*pluginTypeEntry, field reflect.StructField, v reflect.Value, fieldPath string) error {
+	instances, err := collectionInstances(field, v, fieldPath)
+	if err != nil {
+		return fmt.Errorf("config field %s: %w", fieldPath, err)
+	}
+	for _, in := range instances {
+		if _, err := pm.registerInstance(pluginTypes, in.config, in.configPath, in.instanceName, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reloadCollection brings the instances of the map or slice field at
+// fieldPath in line with newValue: instances of removed entries are shut down
+// and unregistered, new entries are registered and started unless they are
+// lazy or disabled, and the remaining instances are reloaded like struct
+// fields. Slice elements are matched to instances by index, or by the value of
+// their key field. Started and stopped instances are recorded in report.
+func (pm *PluginManager[T]) reloadCollection(ctx context.Context, field reflect.StructField, newValue reflect.Value, fieldPath string, report *ReloadReport) error {
+	instances, err := collectionInstances(field, newValue, fieldPath)
+	if err != nil {
+		return fmt.Errorf("config field %s: %w", fieldPath, err)
+	}
+
+	wanted := make(map[string]collectionInstance, len(instances))
+	wantedKeys := make([]string, 0, len(instances))
+	for _, in := range instances {
+		pluginKey := getPluginKey(getConfigType(in.config), in.instanceName)
+		wanted[pluginKey] = in
+		wantedKeys = append(wantedKeys, pluginKey)
+	}
+
+	pm.mu.Lock()
+	var removed, added, existing []string
+	for key, entry := range pm.plugins {
+		if _, ok := wanted[key]; !ok && entry.collection == fieldPath {
+			removed = append(removed, key)
+		}
+	}
+	for _, key := range wantedKeys {
+		if _, ok := pm.plugins[key]; ok {
+			existing = append(existing, key)
+		} else {
+			added = append(added, key)
+		}
+	}
+
+	err = pm.removeInstances(ctx, removed, report)
+	if err == nil && len(added) > 0 {
+		pluginTypes := clonePluginTypes()
+		for _, key := range added {
+			in := wanted[key]
+			if _, err = pm.registerInstance(pluginTypes, in.config, in.configPath, in.instanceName, fieldPath); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = pm.resolveDependencies()
+		}
+		if err != nil {
+			// Drop the new instances so the registry stays consistent
+			for _, key := range added {
+				if _, ok := pm.plugins[key]; ok {
+					pm.dropInstance(key)
+				}
+			}
+		} else {
+			err = pm.startAdded(ctx, added, report)
+		}
+	}
+	pm.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range existing {
+		in := wanted[key]
+
+		// Keyed slice elements keep their instance when they move
+		pm.mu.Lock()
+		if entry, ok := pm.plugins[key]; ok {
+			entry.ConfigPath = in.configPath
+		}
+		pm.mu.Unlock()
+
+		if err := pm.reloadPluginConfig(ctx, in.config, in.config, in.configPath, in.instanceName, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startAdded starts the instances registered by a reload, in startup order,
+// unless they are lazy or disabled. The caller must hold pm.mu.
+func (pm *PluginManager[T]) startAdded(ctx context.Context, added []string, report *ReloadReport) error {
+	for _, key := range pm.order {
+		entry := pm.plugins[key]
+		if !slices.Contains(added, key) || entry.started || entry.lazy || !entry.enabled() {
+			continue
+		}
+		if err := pm.startWithDependencies(ctx, key, "Plugin started after being added"); err != nil {
+			return err
+		}
+		report.addStarted(key)
+	}
+	return nil
+}
+
+// removeInstances shuts down the running instances among keys, in reverse
+// startup order, and unregisters all of them. An instance whose Shutdown fails
+// stays registered. The caller must hold pm.mu.
+func (pm *PluginManager[T]) removeInstances(ctx context.Context, keys []string, report *ReloadReport) error {
+	for _, key := range slices.Backward(slices.Clone(pm.order)) {
+		if !slices.Contains(keys, key) {
+			continue
+		}
+
+		entry := pm.plugins[key]
+		if entry.started {
+			spanCtx, span := pm.startSpan(ctx, key, entry, PhaseShutdown)
+			start := time.Now()
+			err := pm.shutdownPlugin(spanCtx, key, entry.Plugin)
+			entry.timings.shutdown = time.Since(start)
+			endSpan(span, err)
+			pm.observe(key, entry, PhaseShutdown, entry.timings.shutdown, err)
+			// A plugin that timed out is considered stopped, as it is by Shutdown
+			var timeoutErr *ShutdownTimeoutError
+			if errors.As(err, &timeoutErr) {
+				pm.log().Error("Plugin shutdown timed out, removing it anyway", "key", key, "timeout", timeoutErr.Timeout)
+			} else if err != nil {
+				return fmt.Errorf("failed to stop removed plugin %s: %w", key, err)
+			}
+
+			entry.started = false
+			report.addStopped(key)
+		}
+
+		pm.dropInstance(key)
+		pm.log().Info("Plugin removed",
+			"plugin_type", entry.PluginType,
+			"instance", entry.InstanceName,
+			"key", key,
+		)
+	}
+	return nil
+}
+
+// dropInstance unregisters the instance registered under pluginKey and removes
+// it from the startup order and the dependencies of other instances, which no
+// longer wait for it. The caller must hold pm.mu.
+func (pm *PluginManager[T]) dropInstance(pluginKey string) {
+	delete(pm.plugins, pluginKey)
+	pm.order = slices.DeleteFunc(pm.order, func(key string) bool {
+		return key == pluginKey
+	})
+	for _, entry := range pm.plugins {
+		entry.dependsOn = slices.DeleteFunc(entry.dependsOn, func(key string) bool {
+			return key == pluginKey
+		})
+	}
+}
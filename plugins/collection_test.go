@@ -0,0 +1,250 @@
+package plugins
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MapTestConfig configures plugin instances as map entries
+type MapTestConfig struct {
+	Logger MockConfig
+	Kafka  map[string]MockConfig
+	Cache  map[string]MockConfig `plugin:"name=caches"`
+}
+
+func newMapTestConfig(kafka ...string) *MapTestConfig {
+	config := &MapTestConfig{
+		Logger: MockConfig{BaseConfig: BaseConfig{Type: "logger"}},
+		Kafka:  make(map[string]MockConfig),
+	}
+	for _, key := range kafka {
+		config.Kafka[key] = MockConfig{BaseConfig: BaseConfig{Type: "kafka"}, Value: key}
+	}
+	return config
+}
+
+func TestIsConfigCollection(t *testing.T) {
+	assert.True(t, isConfigMap(reflect.TypeFor[map[string]MockConfig]()))
+	assert.False(t, isConfigMap(reflect.TypeFor[map[string]*MockConfig]()))
+	assert.False(t, isConfigMap(reflect.TypeFor[map[int]MockConfig]()))
+	assert.False(t, isConfigMap(reflect.TypeFor[map[string]string]()))
+	assert.False(t, isConfigMap(reflect.TypeFor[MockConfig]()))
+
+	assert.True(t, isConfigSlice(reflect.TypeFor[[]MockConfig]()))
+	assert.False(t, isConfigSlice(reflect.TypeFor[[]*MockConfig]()))
+	assert.False(t, isConfigSlice(reflect.TypeFor[[]string]()))
+	assert.True(t, isConfigCollection(reflect.TypeFor[[]MockConfig]()))
+	assert.True(t, isConfigCollection(reflect.TypeFor[map[string]MockConfig]()))
+}
+
+func TestPluginManager_DiscoverMap(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+	takeLifecycle()
+
+	config := newMapTestConfig("b", "a")
+	config.Cache = map[string]MockConfig{"hot": {BaseConfig: BaseConfig{Type: "cache"}}}
+
+	manager := NewPluginManager[MapTestConfig]()
+	require.NoError(t, manager.DiscoverAndRegister(config))
+	require.NoError(t, manager.Startup(context.Background()))
+	assert.Equal(t, []string{"start caches.hot", "start logger", "start kafka.a", "start kafka.b"}, takeLifecycle())
+
+	entries := manager.Clone()
+	require.Contains(t, entries, "kafka:kafka.a")
+	assert.Equal(t, "Kafka.a", entries["kafka:kafka.a"].ConfigPath)
+	assert.Equal(t, "a", entries["kafka:kafka.a"].Config.(*MockConfig).Value)
+	assert.Contains(t, entries, "cache:caches.hot")
+	assert.Len(t, entries, 4)
+
+	// Map configs are copied, not shared with the discovered config
+	config.Kafka["a"] = MockConfig{BaseConfig: BaseConfig{Type: "kafka"}, Value: "changed"}
+	assert.Equal(t, "a", manager.Clone()["kafka:kafka.a"].Config.(*MockConfig).Value)
+}
+
+func TestPluginManager_ReloadMap(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+	takeLifecycle()
+
+	// The map is empty at first, so instances only appear on reload
+	oldConfig := newMapTestConfig()
+	manager := NewPluginManager[MapTestConfig]()
+	require.NoError(t, manager.DiscoverAndRegister(oldConfig))
+	require.NoError(t, manager.Startup(context.Background()))
+	assert.Equal(t, []string{"start logger"}, takeLifecycle())
+
+	// Added entries are started after their dependencies
+	added := newMapTestConfig("a", "b")
+	report, err := manager.ReloadWithReport(context.Background(), oldConfig, added)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kafka:kafka.a", "kafka:kafka.b"}, report.Started)
+	assert.Equal(t, []string{"start kafka.a", "start kafka.b"}, takeLifecycle())
+
+	// Removed entries are shut down and unregistered; changed ones are reloaded
+	changed := newMapTestConfig("b", "c")
+	changed.Kafka["b"] = MockConfig{BaseConfig: BaseConfig{Type: "kafka"}, Value: "b2"}
+	report, err = manager.ReloadWithReport(context.Background(), added, changed)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kafka:kafka.a"}, report.Stopped)
+	assert.Equal(t, []string{"kafka:kafka.c"}, report.Started)
+	assert.Equal(t, []string{"kafka:kafka.b"}, report.Reloaded)
+	assert.Equal(t, []string{"stop kafka.a", "start kafka.c"}, takeLifecycle())
+
+	entries := manager.Clone()
+	assert.NotContains(t, entries, "kafka:kafka.a")
+	assert.Equal(t, "b2", entries["kafka:kafka.b"].Config.(*MockConfig).Value)
+	assert.Len(t, entries, 3)
+
+	// Shutdown stops the remaining instances before their dependencies
+	require.NoError(t, manager.Shutdown(context.Background()))
+	assert.Equal(t, []string{"stop kafka.c", "stop kafka.b", "stop logger"}, takeLifecycle())
+}
+
+func TestPluginManager_ReloadMapErrors(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+	takeLifecycle()
+
+	oldConfig := newMapTestConfig("a")
+	manager := NewPluginManager[MapTestConfig]()
+	require.NoError(t, manager.DiscoverAndRegister(oldConfig))
+	require.NoError(t, manager.Startup(context.Background()))
+	takeLifecycle()
+
+	// An entry of an unknown plugin type is rejected and not registered
+	unknown := newMapTestConfig("a")
+	unknown.Kafka["b"] = MockConfig{BaseConfig: BaseConfig{Type: "unknown"}}
+	_, err := manager.ReloadWithReport(context.Background(), oldConfig, unknown)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "type=unknown")
+	}
+
+	// So is an entry depending on a plugin that does not exist
+	dangling := newMapTestConfig("a", "b")
+	dangling.Kafka["b"] = MockConfig{BaseConfig: BaseConfig{Type: "kafka", DependsOn: []string{"missing"}}}
+	_, err = manager.ReloadWithReport(context.Background(), oldConfig, dangling)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "depends on unknown plugin missing")
+	}
+
+	assert.Empty(t, takeLifecycle())
+	assert.Len(t, manager.Clone(), 2)
+	require.NoError(t, manager.Shutdown(context.Background()))
+	assert.Equal(t, []string{"stop kafka.a", "stop logger"}, takeLifecycle())
+}
+
+// SliceTestConfig configures plugin instances as slice elements
+type SliceTestConfig struct {
+	Logger MockConfig
+	Kafka  []MockConfig
+	Cache  []MockConfig `plugin:"name=pool,key=value"`
+}
+
+// newSliceTestConfig returns a config with a kafka instance per value and a
+// keyed cache instance per key
+func newSliceTestConfig(kafka []string, cache ...string) *SliceTestConfig {
+	config := &SliceTestConfig{Logger: MockConfig{BaseConfig: BaseConfig{Type: "logger"}}}
+	for _, value := range kafka {
+		config.Kafka = append(config.Kafka, MockConfig{BaseConfig: BaseConfig{Type: "kafka"}, Value: value})
+	}
+	for _, key := range cache {
+		config.Cache = append(config.Cache, MockConfig{BaseConfig: BaseConfig{Type: "cache"}, Value: key})
+	}
+	return config
+}
+
+func TestCollectionInstances(t *testing.T) {
+	type Keyed struct {
+		ByName  []MockConfig `plugin:"key=Value"`
+		Missing []MockConfig `plugin:"key=name"`
+		Typed   []MockConfig `plugin:"key=depends_on"`
+	}
+	fields := reflect.TypeFor[Keyed]()
+
+	elements := reflect.ValueOf([]MockConfig{{Value: "a"}, {Value: "b"}})
+	instances, err := collectionInstances(fields.Field(0), elements, "ByName")
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "byname[b]", instances[1].instanceName)
+	assert.Equal(t, "ByName[1]", instances[1].configPath)
+	assert.Equal(t, "b", instances[1].config.(*MockConfig).Value)
+
+	_, err = collectionInstances(fields.Field(1), elements, "Missing")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `key field "name" not found`)
+	}
+
+	_, err = collectionInstances(fields.Field(2), elements, "Typed")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `key field "depends_on" has unsupported type`)
+	}
+
+	duplicates := reflect.ValueOf([]MockConfig{{Value: "a"}, {Value: "a"}})
+	_, err = collectionInstances(fields.Field(0), duplicates, "ByName")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `duplicate plugin instance name "a" at ByName[1]`)
+	}
+
+	empty := reflect.ValueOf([]MockConfig{{Value: ""}})
+	_, err = collectionInstances(fields.Field(0), empty, "ByName")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `invalid plugin instance name ""`)
+	}
+}
+
+func TestPluginManager_DiscoverSlice(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+	takeLifecycle()
+
+	config := newSliceTestConfig([]string{"x", "y"}, "hot")
+
+	manager := NewPluginManager[SliceTestConfig]()
+	require.NoError(t, manager.DiscoverAndRegister(config))
+	require.NoError(t, manager.Startup(context.Background()))
+	assert.Equal(t, []string{"start pool[hot]", "start logger", "start kafka[0]", "start kafka[1]"}, takeLifecycle())
+
+	entries := manager.Clone()
+	require.Contains(t, entries, "kafka:kafka[1]")
+	assert.Equal(t, "Kafka[1]", entries["kafka:kafka[1]"].ConfigPath)
+	assert.Equal(t, "y", entries["kafka:kafka[1]"].Config.(*MockConfig).Value)
+	assert.Equal(t, "Cache[0]", entries["cache:pool[hot]"].ConfigPath)
+}
+
+func TestPluginManager_ReloadSlice(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+	takeLifecycle()
+
+	oldConfig := newSliceTestConfig([]string{"x", "y", "z"}, "a", "b")
+	manager := NewPluginManager[SliceTestConfig]()
+	require.NoError(t, manager.DiscoverAndRegister(oldConfig))
+	require.NoError(t, manager.Startup(context.Background()))
+	takeLifecycle()
+
+	// Without a key field elements are matched by index: removing the first
+	// element reloads the shifted ones and removes the last instance
+	shifted := newSliceTestConfig([]string{"y", "z"}, "a", "b")
+	report, err := manager.ReloadWithReport(context.Background(), oldConfig, shifted)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kafka:kafka[2]"}, report.Stopped)
+	assert.Equal(t, []string{"kafka:kafka[0]", "kafka:kafka[1]"}, report.Reloaded)
+	assert.Empty(t, report.Started)
+	assert.Equal(t, []string{"stop kafka[2]"}, takeLifecycle())
+	assert.Equal(t, "y", manager.Clone()["kafka:kafka[0]"].Config.(*MockConfig).Value)
+
+	// With a key field reordering keeps the instances, and only new and
+	// removed keys start and stop instances
+	reordered := newSliceTestConfig([]string{"y", "z"}, "c", "b")
+	report, err = manager.ReloadWithReport(context.Background(), shifted, reordered)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache:pool[a]"}, report.Stopped)
+	assert.Equal(t, []string{"cache:pool[c]"}, report.Started)
+	assert.Empty(t, report.Reloaded)
+	assert.Equal(t, []string{"stop pool[a]", "start pool[c]"}, takeLifecycle())
+
+	entries := manager.Clone()
+	assert.Equal(t, "Cache[1]", entries["cache:pool[b]"].ConfigPath)
+	assert.Equal(t, "Cache[0]", entries["cache:pool[c]"].ConfigPath)
+	assert.Len(t, entries, 5)
+}
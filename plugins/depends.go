@@ -0,0 +1,126 @@
+// Package plugins provides a comprehensive plugin management system that supports
+// automatic discovery, registration, and lifecycle management of plugins.
+// This file implements the dependency ordering of plugin instances used by
+// Startup and Shutdown.
+package plugins
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// matchDependency returns the keys of the plugin instances ref refers to: the
+// instance with key ref ("pluginType:instanceName", case-insensitive), or else
+// all instances of plugin type ref. Keys are returned in sorted order.
+func (pm *PluginManager[T]) matchDependency(ref string) []string {
+	for key := range pm.plugins {
+		if strings.EqualFold(key, ref) {
+			return []string{key}
+		}
+	}
+
+	var keys []string
+	for key, entry := range pm.plugins {
+		if entry.PluginType == ref {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolveDependencies resolves the dependencies of all instances to plugin keys
+// and computes the startup order. Dependencies declared in a config's DependsOn
+// must match at least one instance; those declared with RegisterOptions.DependsOn
+// are optional. The caller must hold pm.mu.
+func (pm *PluginManager[T]) resolveDependencies() error {
+	keys := make([]string, 0, len(pm.plugins))
+	for key := range pm.plugins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entry := pm.plugins[key]
+
+		var deps []string
+		add := func(ref string, required bool) error {
+			matches := pm.matchDependency(ref)
+			if len(matches) == 0 && required {
+				return fmt.Errorf("plugin %s depends on unknown plugin %s", key, ref)
+			}
+			for _, match := range matches {
+				if match != key && !slices.Contains(deps, match) {
+					deps = append(deps, match)
+				}
+			}
+			return nil
+		}
+
+		for _, ref := range entry.typeDeps {
+			if err := add(ref, false); err != nil {
+				return err
+			}
+		}
+		for _, ref := range entry.Config.baseConfigEmbedded().DependsOn {
+			if err := add(ref, true); err != nil {
+				return err
+			}
+		}
+		entry.dependsOn = deps
+	}
+
+	order, err := topologicalOrder(keys, func(key string) []string {
+		return pm.plugins[key].dependsOn
+	})
+	if err != nil {
+		return err
+	}
+	pm.order = order
+	return nil
+}
+
+// topologicalOrder sorts keys so that every key follows its dependencies. Keys
+// without an ordering constraint between them keep their relative input order.
+// It fails if the dependencies contain a cycle.
+func topologicalOrder(keys []string, dependencies func(key string) []string) ([]string, error) {
+	pending := make(map[string]int, len(keys))
+	dependents := make(map[string][]string, len(keys))
+	for _, key := range keys {
+		deps := dependencies(key)
+		pending[key] = len(deps)
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], key)
+		}
+	}
+
+	order := make([]string, 0, len(keys))
+	for len(order) < len(keys) {
+		// Pick the first key in input order whose dependencies are all placed
+		next := ""
+		for _, key := range keys {
+			if count, ok := pending[key]; ok && count == 0 {
+				next = key
+				break
+			}
+		}
+		if next == "" {
+			var cycle []string
+			for _, key := range keys {
+				if _, ok := pending[key]; ok {
+					cycle = append(cycle, key)
+				}
+			}
+			return nil, fmt.Errorf("plugin dependency cycle detected involving %s", strings.Join(cycle, ", "))
+		}
+
+		delete(pending, next)
+		order = append(order, next)
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+	return order, nil
+}
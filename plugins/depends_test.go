@@ -0,0 +1,137 @@
+package plugins
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lifecycleLog records the lifecycle calls of orderPlugin instances in order
+var lifecycleLog struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func recordLifecycle(call string) {
+	lifecycleLog.mu.Lock()
+	defer lifecycleLog.mu.Unlock()
+	lifecycleLog.calls = append(lifecycleLog.calls, call)
+}
+
+func takeLifecycle() []string {
+	lifecycleLog.mu.Lock()
+	defer lifecycleLog.mu.Unlock()
+	calls := lifecycleLog.calls
+	lifecycleLog.calls = nil
+	return calls
+}
+
+// orderPlugin records when it starts and stops
+type orderPlugin struct {
+	MockPlugin
+	name string
+}
+
+func (op *orderPlugin) SetName(instance, _ string) {
+	op.name = instance
+}
+
+func (op *orderPlugin) Startup(ctx context.Context, config any) error {
+	recordLifecycle("start " + op.name)
+	return op.MockPlugin.Startup(ctx, config)
+}
+
+func (op *orderPlugin) Shutdown(ctx context.Context) error {
+	recordLifecycle("stop " + op.name)
+	return op.MockPlugin.Shutdown(ctx)
+}
+
+type DependencyTestConfig struct {
+	Kafka  MockConfig
+	Logger MockConfig
+	Cache  MockConfig
+}
+
+func registerOrderPlugins(t *testing.T, cacheOpts RegisterOptions) {
+	t.Helper()
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	RegisterPluginType("logger", &orderPlugin{}, &MockConfig{})
+	// Types without configured instances are ignored
+	RegisterPluginType("kafka", &orderPlugin{}, &MockConfig{}, RegisterOptions{
+		AutoDiscover: true,
+		DependsOn:    []string{"logger", "metrics"},
+	})
+	RegisterPluginType("cache", &orderPlugin{}, &MockConfig{}, cacheOpts)
+	t.Cleanup(func() {
+		UnregisterPluginType("logger")
+		UnregisterPluginType("kafka")
+		UnregisterPluginType("cache")
+	})
+}
+
+func newDependencyTestConfig(cacheDeps, kafkaDeps []string) *DependencyTestConfig {
+	return &DependencyTestConfig{
+		Kafka:  MockConfig{BaseConfig: BaseConfig{Type: "kafka", DependsOn: kafkaDeps}},
+		Logger: MockConfig{BaseConfig: BaseConfig{Type: "logger"}},
+		Cache:  MockConfig{BaseConfig: BaseConfig{Type: "cache", DependsOn: cacheDeps}},
+	}
+}
+
+func TestPluginManager_DependencyOrder(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+	takeLifecycle()
+
+	manager := NewPluginManager[DependencyTestConfig]()
+	require.NoError(t, manager.DiscoverAndRegister(newDependencyTestConfig([]string{"Kafka:Kafka"}, nil)))
+
+	require.NoError(t, manager.Startup(context.Background()))
+	assert.Equal(t, []string{"start logger", "start kafka", "start cache"}, takeLifecycle())
+
+	require.NoError(t, manager.Shutdown(context.Background()))
+	assert.Equal(t, []string{"stop cache", "stop kafka", "stop logger"}, takeLifecycle())
+}
+
+func TestPluginManager_DependencyLazyStart(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true, LazyStart: true})
+	takeLifecycle()
+
+	manager := NewPluginManager[DependencyTestConfig]()
+	require.NoError(t, manager.DiscoverAndRegister(newDependencyTestConfig([]string{"kafka"}, nil)))
+
+	// Lazy plugins start on demand, after their dependencies
+	require.NoError(t, manager.EnsureStarted(context.Background(), "cache:cache"))
+	assert.Equal(t, []string{"start logger", "start kafka", "start cache"}, takeLifecycle())
+
+	require.NoError(t, manager.Startup(context.Background()))
+	assert.Empty(t, takeLifecycle())
+}
+
+func TestPluginManager_DependencyErrors(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+
+	manager := NewPluginManager[DependencyTestConfig]()
+	err := manager.DiscoverAndRegister(newDependencyTestConfig([]string{"kafka"}, []string{"cache"}))
+	assert.EqualError(t, err, "plugin dependency cycle detected involving cache:cache, kafka:kafka")
+
+	manager = NewPluginManager[DependencyTestConfig]()
+	err = manager.DiscoverAndRegister(newDependencyTestConfig([]string{"database:primary"}, nil))
+	assert.EqualError(t, err, "plugin cache:cache depends on unknown plugin database:primary")
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	deps := map[string][]string{"c": {"a"}, "d": {"c", "b"}}
+	order, err := topologicalOrder([]string{"d", "c", "b", "a"}, func(key string) []string { return deps[key] })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a", "c", "d"}, order)
+
+	deps["a"] = []string{"d"}
+	_, err = topologicalOrder([]string{"d", "c", "b", "a"}, func(key string) []string { return deps[key] })
+	assert.EqualError(t, err, "plugin dependency cycle detected involving d, c, a")
+}
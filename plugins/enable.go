@@ -0,0 +1,74 @@
+// Package plugins provides a comprehensive plugin management system that supports
+// automatic discovery, registration, and lifecycle management of plugins.
+// This file implements switching plugin instances on and off at runtime through
+// the enabled flag of their config, see BaseConfig.Enabled.
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// enabled reports whether the config of the instance enables it
+func (e *PluginEntry) enabled() bool {
+	return e.Config.baseConfigEmbedded().IsEnabled()
+}
+
+// applyEnabled switches the instance registered under pluginKey over to newCfg,
+// whose enabled flag differs from the current config: an enabled instance is
+// started, unless it is lazy, and a disabled one is shut down. On failure the
+// instance keeps its current config, so the change is retried by the next
+// reload. Instances that depend on a disabled instance keep running.
+func (pm *PluginManager[T]) applyEnabled(ctx context.Context, pluginKey string, entry *PluginEntry, newCfg Config, newHash string, report *ReloadReport) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	oldCfg, oldHash := entry.Config, entry.configHash
+	entry.Config = newCfg
+	entry.configHash = newHash
+
+	if newCfg.baseConfigEmbedded().IsEnabled() {
+		if entry.started || entry.lazy {
+			pm.log().Info("Plugin enabled", "key", pluginKey, "started", entry.started)
+			return nil
+		}
+		if err := pm.startWithDependencies(ctx, pluginKey, "Plugin started after being enabled"); err != nil {
+			entry.Config, entry.configHash = oldCfg, oldHash
+			return err
+		}
+		report.addStarted(pluginKey)
+		return nil
+	}
+
+	if !entry.started {
+		pm.log().Info("Plugin disabled", "key", pluginKey)
+		return nil
+	}
+
+	spanCtx, span := pm.startSpan(ctx, pluginKey, entry, PhaseShutdown)
+	start := time.Now()
+	err := pm.shutdownPlugin(spanCtx, pluginKey, entry.Plugin)
+	entry.timings.shutdown = time.Since(start)
+	endSpan(span, err)
+	pm.observe(pluginKey, entry, PhaseShutdown, entry.timings.shutdown, err)
+
+	// A plugin that timed out is considered stopped, as it is by Shutdown
+	var timeoutErr *ShutdownTimeoutError
+	if err != nil && !errors.As(err, &timeoutErr) {
+		entry.Config, entry.configHash = oldCfg, oldHash
+		return fmt.Errorf("failed to stop disabled plugin %s: %w", pluginKey, err)
+	}
+
+	entry.started = false
+	report.addStopped(pluginKey)
+	pm.log().Info("Plugin stopped after being disabled",
+		"plugin_type", entry.PluginType,
+		"instance", entry.InstanceName,
+		"key", pluginKey,
+		"duration", entry.timings.shutdown,
+	)
+
+	return err
+}
@@ -0,0 +1,108 @@
+package plugins
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseConfig_IsEnabled(t *testing.T) {
+	assert.True(t, (&BaseConfig{}).IsEnabled())
+	assert.True(t, (&BaseConfig{Enabled: ToPtr(true)}).IsEnabled())
+	assert.False(t, (&BaseConfig{Enabled: ToPtr(false)}).IsEnabled())
+}
+
+func TestPluginManager_DisabledInstance(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+	takeLifecycle()
+
+	// Kafka depends on the disabled logger, which must not be started for it
+	config := newDependencyTestConfig(nil, nil)
+	config.Logger.Enabled = ToPtr(false)
+
+	manager := NewPluginManager[DependencyTestConfig]()
+	require.NoError(t, manager.DiscoverAndRegister(config))
+	require.NoError(t, manager.Startup(context.Background()))
+	assert.Equal(t, []string{"start cache", "start kafka"}, takeLifecycle())
+
+	err := manager.EnsureStarted(context.Background(), "logger:logger")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "plugin logger:logger is disabled")
+	}
+
+	statuses := manager.Status()
+	require.Len(t, statuses, 3)
+	assert.Equal(t, "logger:logger", statuses[2].Key)
+	assert.False(t, statuses[2].Enabled)
+	assert.False(t, statuses[2].Started)
+	assert.True(t, statuses[0].Enabled)
+}
+
+func TestPluginManager_ReloadEnabledFlag(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+	takeLifecycle()
+
+	disabled := newDependencyTestConfig(nil, nil)
+	disabled.Cache.Enabled = ToPtr(false)
+
+	manager := NewPluginManager[DependencyTestConfig]()
+	require.NoError(t, manager.DiscoverAndRegister(disabled))
+	require.NoError(t, manager.Startup(context.Background()))
+	assert.Equal(t, []string{"start logger", "start kafka"}, takeLifecycle())
+
+	// Enabling the instance starts it
+	enabled := newDependencyTestConfig(nil, nil)
+	report, err := manager.ReloadWithReport(context.Background(), disabled, enabled)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache:cache"}, report.Started)
+	assert.Empty(t, report.Reloaded)
+	assert.Equal(t, []string{"start cache"}, takeLifecycle())
+
+	// Disabling it again shuts it down instead of reloading it
+	report, err = manager.ReloadWithReport(context.Background(), enabled, disabled)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache:cache"}, report.Stopped)
+	assert.Empty(t, report.Reloaded)
+	assert.Equal(t, []string{"stop cache"}, takeLifecycle())
+
+	// Changes to a disabled instance are kept without starting it
+	changed := newDependencyTestConfig(nil, nil)
+	changed.Cache.Enabled = ToPtr(false)
+	changed.Cache.DependsOn = []string{"logger"}
+	report, err = manager.ReloadWithReport(context.Background(), disabled, changed)
+	require.NoError(t, err)
+	assert.Empty(t, report.Started)
+	assert.Empty(t, report.Reloaded)
+	assert.Empty(t, takeLifecycle())
+
+	entry := manager.Clone()["cache:cache"]
+	assert.False(t, entry.started)
+	assert.Equal(t, []string{"logger"}, entry.Config.baseConfigEmbedded().DependsOn)
+
+	require.NoError(t, manager.Shutdown(context.Background()))
+	assert.Equal(t, []string{"stop kafka", "stop logger"}, takeLifecycle())
+}
+
+func TestPluginManager_ReloadEnabledLazy(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true, LazyStart: true})
+	takeLifecycle()
+
+	disabled := newDependencyTestConfig(nil, nil)
+	disabled.Cache.Enabled = ToPtr(false)
+
+	manager := NewPluginManager[DependencyTestConfig]()
+	require.NoError(t, manager.DiscoverAndRegister(disabled))
+	require.NoError(t, manager.Startup(context.Background()))
+	takeLifecycle()
+
+	// An enabled lazy instance still waits for EnsureStarted
+	report, err := manager.ReloadWithReport(context.Background(), disabled, newDependencyTestConfig(nil, nil))
+	require.NoError(t, err)
+	assert.Empty(t, report.Started)
+	assert.Empty(t, takeLifecycle())
+
+	require.NoError(t, manager.EnsureStarted(context.Background(), "cache:cache"))
+	assert.Equal(t, []string{"start cache"}, takeLifecycle())
+}
@@ -6,12 +6,17 @@ package plugins
 
 import (
 	"context"
+	"fmt"
 	"sync"
 )
 
 // Plugin defines the core interface that all vcfg plugins must implement.
 // It provides lifecycle management methods for plugin initialization,
 // configuration reloading, and cleanup.
+//
+// The config passed to Startup and Reload is a deep copy owned by the plugin.
+// Plugins may retain it safely: later reloads or changes to the manager's
+// configuration never mutate a config a plugin has already received.
 type Plugin interface {
 	// Startup initializes the plugin with the provided configuration.
 	// It should perform all necessary setup operations and return an error
@@ -25,6 +30,15 @@ type Plugin interface {
 	Shutdown(ctx context.Context) error
 }
 
+// Named is implemented by plugins that want to know which instance they are, e.g.
+// to label their logs and metrics. SetName is called before every Startup with the
+// instance name and configuration path the plugin was discovered under, such as
+// "client.kafka" and "Client.Kafka".
+type Named interface {
+	// SetName receives the instance name and configuration path of the plugin
+	SetName(instance, configPath string)
+}
+
 // Config defines the interface for plugin configuration structures.
 // All plugin configurations must embed BaseConfig and implement this interface.
 type Config interface {
@@ -37,6 +51,15 @@ type Config interface {
 type BaseConfig struct {
 	// Type identifies the plugin type for registration and instantiation
 	Type string `json:"type,omitempty" yaml:"type,omitempty" koanf:"type"`
+	// DependsOn lists the plugins this instance needs running before it starts,
+	// either as plugin keys ("pluginType:instanceName") or as plugin types,
+	// which refer to all instances of that type. The instance is shut down
+	// before its dependencies.
+	DependsOn []string `json:"depends_on,omitempty" yaml:"depends_on,omitempty" koanf:"depends_on"`
+	// Enabled switches the instance on or off; unset means enabled. A disabled
+	// instance is registered but not started, and a reload that flips the flag
+	// starts or shuts it down.
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty" koanf:"enabled"`
 }
 
 // PluginPtr is a generic constraint that ensures a type is both a Plugin
@@ -57,6 +80,18 @@ type ConfigPtr[T any] interface {
 type RegisterOptions struct {
 	// AutoDiscover enables automatic discovery and registration of this plugin type
 	AutoDiscover bool
+	// LazyStart defers Startup of discovered instances until they are explicitly
+	// requested via PluginManager.EnsureStarted, instead of starting them with all
+	// other plugins. Useful for expensive plugins that are rarely used.
+	LazyStart bool
+	// SwapOnReload replaces running instances on a config change instead of calling
+	// Reload: a new instance is started with the new config while the old one keeps
+	// serving, and the old one is shut down only after the new one is ready.
+	// Plugins implementing Swapper are notified before the switch.
+	SwapOnReload bool
+	// DependsOn lists plugin types whose instances start before, and stop after,
+	// every instance of this type. Types without configured instances are ignored.
+	DependsOn []string
 }
 
 // baseConfigEmbedded implements the Config interface by returning the embedded BaseConfig.
@@ -65,6 +100,12 @@ func (bc *BaseConfig) baseConfigEmbedded() *BaseConfig {
 	return bc
 }
 
+// IsEnabled reports whether the instance is enabled, which it is unless Enabled
+// is set to false.
+func (bc *BaseConfig) IsEnabled() bool {
+	return bc.Enabled == nil || *bc.Enabled
+}
+
 // globalPluginTypeRegistry manages the global registry of plugin types.
 // It provides thread-safe access to plugin and configuration factories.
 type globalPluginTypeRegistry struct {
@@ -85,6 +126,12 @@ type pluginTypeEntry struct {
 	PluginType string
 	// AutoDiscover indicates if this plugin type supports auto-discovery
 	AutoDiscover bool
+	// LazyStart indicates that instances are started on first use only
+	LazyStart bool
+	// SwapOnReload indicates that config changes start a new instance instead of reloading
+	SwapOnReload bool
+	// DependsOn lists plugin types that start before instances of this type
+	DependsOn []string
 }
 
 // pluginFactory is a function type that creates new plugin instances.
@@ -108,4 +155,107 @@ type PluginEntry struct {
 	ConfigPath string
 	// started tracks whether this plugin instance has been started
 	started bool
+	// lazy marks instances whose startup is deferred until EnsureStarted is called
+	lazy bool
+	// configHash is the hash of the config the plugin currently runs with,
+	// used to skip reloads when a plugin's section did not change
+	configHash string
+	// backoff tracks consecutive reload failures and throttles further attempts
+	backoff reloadBackoff
+	// swap marks instances that are replaced by a new generation on reload
+	swap bool
+	// factory creates the next generation of a swapped instance
+	factory pluginFactory
+	// generation counts how many times the instance has been swapped
+	generation uint64
+	// timings records how long the instance's lifecycle calls took
+	timings pluginTimings
+	// typeDeps are the dependencies declared with RegisterOptions.DependsOn
+	typeDeps []string
+	// dependsOn holds the resolved keys of the instances this one depends on
+	dependsOn []string
+	// collection is the path of the map or slice field holding the config, ""
+	// if the config is a struct field
+	collection string
+}
+
+// DiscoveryError reports that a discovered plugin config could not be copied
+// into the config type registered for its plugin, e.g. because the field's
+// type tag names a plugin registered for a different config struct.
+type DiscoveryError struct {
+	// FieldPath is the path of the config field, e.g. "Services.Cache"
+	FieldPath string
+	// PluginType is the plugin type the field resolved to
+	PluginType string
+	// Err is the underlying copy failure
+	Err error
+}
+
+// Error implements the error interface
+func (e *DiscoveryError) Error() string {
+	return fmt.Sprintf("failed to copy config for %s: %v", e.FieldPath, e.Err)
+}
+
+// Unwrap returns the underlying copy failure
+func (e *DiscoveryError) Unwrap() error {
+	return e.Err
+}
+
+// ReloadReport describes the outcome of a plugin reload pass triggered by a
+// configuration change.
+type ReloadReport struct {
+	// Reloaded lists the keys of plugin instances whose Reload was called successfully
+	Reloaded []string
+	// Throttled lists the keys of changed plugin instances whose reload was skipped
+	// because they are backing off after repeated failures
+	Throttled []string
+	// Skipped lists the keys of evaluated plugin instances whose configuration did
+	// not change, so Reload was not called
+	Skipped []string
+	// Started lists the keys of plugin instances started by the reload, e.g.
+	// because their config enabled them
+	Started []string
+	// Stopped lists the keys of plugin instances shut down by the reload, e.g.
+	// because their config disabled them
+	Stopped []string
+}
+
+// addReloaded records a successfully reloaded plugin. It is a no-op on a nil report.
+func (r *ReloadReport) addReloaded(pluginKey string) {
+	if r == nil {
+		return
+	}
+	r.Reloaded = append(r.Reloaded, pluginKey)
+}
+
+// addSkipped records a plugin whose configuration was unchanged. It is a no-op on a nil report.
+func (r *ReloadReport) addSkipped(pluginKey string) {
+	if r == nil {
+		return
+	}
+	r.Skipped = append(r.Skipped, pluginKey)
+}
+
+// addThrottled records a plugin whose reload was skipped. It is a no-op on a nil report.
+func (r *ReloadReport) addThrottled(pluginKey string) {
+	if r == nil {
+		return
+	}
+	r.Throttled = append(r.Throttled, pluginKey)
+}
+
+// addStarted records a plugin started by the reload. It is a no-op on a nil report.
+func (r *ReloadReport) addStarted(pluginKey string) {
+	if r == nil {
+		return
+	}
+	r.Started = append(r.Started, pluginKey)
+}
+
+// addStopped records a plugin shut down by the reload. It is a no-op on a nil report.
+func (r *ReloadReport) addStopped(pluginKey string) {
+	if r == nil {
+		return
+	}
+	r.Stopped = append(r.Stopped, pluginKey)
 }
@@ -5,10 +5,15 @@ package plugins
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
-	"strings"
+	"slices"
 	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/nextpkg/vcfg/slogs"
 )
@@ -21,6 +26,25 @@ type PluginManager[T any] struct {
 	mu sync.RWMutex
 	// plugins stores plugin entries indexed by "pluginType:instanceName" keys
 	plugins map[string]*PluginEntry
+	// order lists the plugin keys in startup order, dependencies first
+	order []string
+	// collections reports whether the discovered config has map or slice fields
+	// of plugin configs, whose instances may be added by a reload
+	collections bool
+	// backoffBase is the reload backoff after the first consecutive failure
+	backoffBase time.Duration
+	// backoffMax caps the reload backoff
+	backoffMax time.Duration
+	// shutdownTimeout bounds the Shutdown call of each plugin, 0 for no limit
+	shutdownTimeout time.Duration
+	// observer is notified of lifecycle calls, nil if none
+	observer Observer
+	// tracer records a span per lifecycle call, nil to disable
+	tracer trace.Tracer
+	// logger receives the log output, nil for the slogs logger
+	logger *slog.Logger
+	// now returns the current time; replaceable in tests
+	now func() time.Time
 }
 
 // NewPluginManager creates a new plugin manager instance for configuration type T.
@@ -28,8 +52,31 @@ type PluginManager[T any] struct {
 // discover and manage plugin instances.
 func NewPluginManager[T any]() *PluginManager[T] {
 	return &PluginManager[T]{
-		plugins: make(map[string]*PluginEntry),
+		plugins:     make(map[string]*PluginEntry),
+		backoffBase: defaultReloadBackoff,
+		backoffMax:  defaultMaxReloadBackoff,
+		now:         time.Now,
+
+		shutdownTimeout: defaultShutdownTimeout,
+	}
+}
+
+// SetLogger routes the log output of the manager to logger instead of the
+// slogs package logger; nil restores the default. Set it before plugins are
+// discovered.
+func (pm *PluginManager[T]) SetLogger(logger *slog.Logger) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.logger = logger
+}
+
+// log returns the logger of the manager
+func (pm *PluginManager[T]) log() *slog.Logger {
+	if pm.logger != nil {
+		return pm.logger
 	}
+	return slogs.Logger()
 }
 
 // DiscoverAndRegister automatically discovers plugin configurations from the provided config struct
@@ -41,7 +88,7 @@ func (pm *PluginManager[T]) DiscoverAndRegister(config *T) error {
 
 	pluginTypes := clonePluginTypes()
 	if len(pluginTypes) == 0 {
-		slogs.Info("No plugin types registered for auto-discovery")
+		pm.log().Info("No plugin types registered for auto-discovery")
 		return nil
 	}
 
@@ -82,60 +129,31 @@ func (pm *PluginManager[T]) DiscoverAndRegister(config *T) error {
 				}
 			}
 
+			// Each entry of a map or slice of plugin configs is an instance of its own
+			if isConfigCollection(fieldType.Type) {
+				pm.collections = true
+				if err := pm.discoverCollection(pluginTypes, fieldType, fieldValue, fieldPath); err != nil {
+					return err
+				}
+				continue
+			}
+
 			// Check if this field implements Config interface
 			if fieldValue.Kind() == reflect.Struct && fieldValue.CanAddr() {
 				fieldInterface := fieldValue.Addr().Interface()
 				if oldConfig, ok := fieldInterface.(Config); ok {
-					pluginType := getConfigType(oldConfig)
-
-					slogs.Debug("Found config field",
-						"path", fieldPath,
-						"type", pluginType,
-						"raw_type", oldConfig.baseConfigEmbedded().Type,
-					)
-
-					// Check if we have a registered plugin type for this config
-					entry, exists := pluginTypes[pluginType]
-					if !exists {
-						return fmt.Errorf("config field does not have a registered plugin type, type=%s", pluginType)
-					}
-
-					// Create newPlugin and config instances
-					newPlugin := entry.PluginFactory()
-					newConfig := entry.ConfigFactory()
-
-					// Copy configuration values from oldConfig to newConfig
-					if err := copyConfig(oldConfig, newConfig); err != nil {
-						return fmt.Errorf("failed to copy config for %s: %w", fieldPath, err)
-					}
-
 					// Use field path as instance name to support multiple instances
-					// This allows the same plugin type to have different instances based on config location
-					instanceName := strings.ToLower(fieldPath)
-
-					pluginKey := getPluginKey(pluginType, instanceName)
-
-					// Check if plugin instance already exists
-					if _, exists := pm.plugins[pluginKey]; exists {
-						return fmt.Errorf("plugin instance %s already registered", pluginKey)
+					// This allows the same plugin type to have different instances based on config location,
+					// unless the field names the instance with a plugin:"name=..." tag
+					instanceName, err := getInstanceName(fieldType, fieldPath)
+					if err != nil {
+						return fmt.Errorf("config field %s: %w", fieldPath, err)
 					}
 
-					pm.plugins[pluginKey] = &PluginEntry{
-						Plugin:       newPlugin,
-						Config:       newConfig,
-						PluginType:   pluginType,
-						InstanceName: instanceName,
-						ConfigPath:   fieldPath,
-						started:      false,
+					if _, err := pm.registerInstance(pluginTypes, oldConfig, fieldPath, instanceName, ""); err != nil {
+						return err
 					}
 
-					slogs.Debug("Plugin registered",
-						"type", entry.PluginType,
-						"instance", instanceName,
-						"key", pluginKey,
-						"config_path", fieldPath,
-					)
-
 					// Continue to process other fields instead of returning
 					continue
 				}
@@ -156,67 +174,238 @@ func (pm *PluginManager[T]) DiscoverAndRegister(config *T) error {
 		return err
 	}
 
+	if err := pm.resolveDependencies(); err != nil {
+		return err
+	}
+
 	if len(pm.plugins) == 0 {
-		slogs.Info("No plugins discovered for auto-registration")
+		pm.log().Info("No plugins discovered for auto-registration")
 	}
 
 	return nil
 }
 
-// Startup starts all registered plugins with context
+// registerInstance registers a plugin instance for oldConfig, the plugin config
+// found at fieldPath, under instanceName and returns its plugin key. collection
+// is the path of the map or slice field holding the config, "" for struct fields. The
+// instance receives a copy of oldConfig. The caller must hold pm.mu.
+func (pm *PluginManager[T]) registerInstance(pluginTypes map[string]*pluginTypeEntry, oldConfig Config, fieldPath, instanceName, collection string) (string, error) {
+	pluginType := getConfigType(oldConfig)
+
+	pm.log().Debug("Found config field",
+		"path", fieldPath,
+		"type", pluginType,
+		"raw_type", oldConfig.baseConfigEmbedded().Type,
+	)
+
+	// Check if we have a registered plugin type for this config
+	entry, exists := pluginTypes[pluginType]
+	if !exists {
+		return "", fmt.Errorf("config field does not have a registered plugin type, type=%s", pluginType)
+	}
+
+	// Create newPlugin and config instances
+	newPlugin := entry.PluginFactory()
+	newConfig := entry.ConfigFactory()
+
+	// The field's type tag may point at a plugin registered for another config struct
+	if fieldConfigType, registeredType := reflect.TypeOf(oldConfig), reflect.TypeOf(newConfig); fieldConfigType != registeredType {
+		return "", &DiscoveryError{
+			FieldPath:  fieldPath,
+			PluginType: pluginType,
+			Err: fmt.Errorf("field has type %v, but plugin type %q is registered with config type %v",
+				fieldConfigType, pluginType, registeredType),
+		}
+	}
+
+	// Copy configuration values from oldConfig to newConfig
+	if err := copyConfig(oldConfig, newConfig); err != nil {
+		return "", &DiscoveryError{FieldPath: fieldPath, PluginType: pluginType, Err: err}
+	}
+
+	pluginKey := getPluginKey(pluginType, instanceName)
+
+	// Check if plugin instance already exists
+	if _, exists := pm.plugins[pluginKey]; exists {
+		return "", fmt.Errorf("plugin instance %s already registered", pluginKey)
+	}
+
+	pm.plugins[pluginKey] = &PluginEntry{
+		Plugin:       newPlugin,
+		Config:       newConfig,
+		PluginType:   pluginType,
+		InstanceName: instanceName,
+		ConfigPath:   fieldPath,
+		started:      false,
+		lazy:         entry.LazyStart,
+		configHash:   hashConfig(newConfig),
+		swap:         entry.SwapOnReload,
+		factory:      entry.PluginFactory,
+		typeDeps:     entry.DependsOn,
+		collection:   collection,
+	}
+
+	pm.log().Debug("Plugin registered",
+		"type", entry.PluginType,
+		"instance", instanceName,
+		"key", pluginKey,
+		"config_path", fieldPath,
+		"lazy", entry.LazyStart,
+		"enabled", newConfig.baseConfigEmbedded().IsEnabled(),
+	)
+
+	return pluginKey, nil
+}
+
+// Startup starts all registered plugins with context, dependencies first (see
+// BaseConfig.DependsOn and RegisterOptions.DependsOn). Plugins registered with
+// LazyStart are skipped unless another plugin depends on them; they are started
+// by EnsureStarted. Disabled plugins (see BaseConfig.Enabled) are not started,
+// not even as dependencies.
 func (pm *PluginManager[T]) Startup(ctx context.Context) error {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
-	for pluginKey, entry := range pm.plugins {
-		if entry.started {
+	for _, pluginKey := range pm.order {
+		entry := pm.plugins[pluginKey]
+		if entry.started || entry.lazy || !entry.enabled() {
 			continue
 		}
 
-		if err := entry.Plugin.Startup(ctx, entry.Config); err != nil {
-			return fmt.Errorf("failed to start plugin %s: %w", pluginKey, err)
+		if err := pm.startWithDependencies(ctx, pluginKey, "Plugin started"); err != nil {
+			return err
 		}
+	}
 
-		entry.started = true
-		slogs.Info("Plugin started",
-			"plugin_type", entry.PluginType,
-			"instance", entry.InstanceName,
-			"key", pluginKey,
-		)
+	pm.log().Info("All plugins started", "count", len(pm.plugins))
+
+	return nil
+}
+
+// EnsureStarted starts the plugin registered under pluginKey if it is not running yet.
+// It is primarily used for plugins registered with LazyStart, but works for any
+// registered plugin and is a no-op for plugins that are already started.
+// The key has the form "pluginType:instanceName", as returned by Clone.
+func (pm *PluginManager[T]) EnsureStarted(ctx context.Context, pluginKey string) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	entry, exists := pm.plugins[pluginKey]
+	if !exists {
+		return fmt.Errorf("plugin %s is not registered", pluginKey)
+	}
+
+	if entry.started {
+		return nil
+	}
+	if !entry.enabled() {
+		return fmt.Errorf("plugin %s is disabled", pluginKey)
+	}
+
+	return pm.startWithDependencies(ctx, pluginKey, "Plugin started on demand")
+}
+
+// startWithDependencies starts the plugin registered under pluginKey after the
+// dependencies that are not running yet. Disabled dependencies are skipped.
+// The caller must hold pm.mu.
+func (pm *PluginManager[T]) startWithDependencies(ctx context.Context, pluginKey, msg string) error {
+	entry := pm.plugins[pluginKey]
+	for _, dep := range entry.dependsOn {
+		if pm.plugins[dep].started {
+			continue
+		}
+		if !pm.plugins[dep].enabled() {
+			pm.log().Warn("Plugin dependency is disabled", "key", pluginKey, "dependency", dep)
+			continue
+		}
+		if err := pm.startWithDependencies(ctx, dep, "Plugin started as dependency"); err != nil {
+			return err
+		}
+	}
+
+	spanCtx, span := pm.startSpan(ctx, pluginKey, entry, PhaseStartup)
+	start := time.Now()
+	err := startPlugin(spanCtx, entry.Plugin, entry, entry.Config)
+	entry.timings.startup = time.Since(start)
+	endSpan(span, err)
+	pm.observe(pluginKey, entry, PhaseStartup, entry.timings.startup, err)
+	if err != nil {
+		return fmt.Errorf("failed to start plugin %s: %w", pluginKey, err)
 	}
 
-	slogs.Info("All plugins started", "count", len(pm.plugins))
+	entry.started = true
+	pm.log().Info(msg,
+		"plugin_type", entry.PluginType,
+		"instance", entry.InstanceName,
+		"key", pluginKey,
+		"duration", entry.timings.startup,
+	)
 
 	return nil
 }
 
-// Shutdown stops all running plugins with context
+// startPlugin starts plugin as an instance of entry with a copy of config.
+// Plugins implementing Named learn their instance name and path first.
+func startPlugin(ctx context.Context, plugin Plugin, entry *PluginEntry, config Config) error {
+	if named, ok := plugin.(Named); ok {
+		named.SetName(entry.InstanceName, entry.ConfigPath)
+	}
+	return plugin.Startup(ctx, cloneConfig(config))
+}
+
+// Shutdown stops all running plugins with context, in reverse startup order so
+// plugins stop before the plugins they depend on. Each plugin gets the deadline
+// set with SetShutdownTimeout; a plugin that exceeds it is logged, considered
+// stopped and skipped, and the remaining plugins are shut down. The timeouts are
+// reported as ShutdownTimeoutErrors joined into the returned error.
 func (pm *PluginManager[T]) Shutdown(ctx context.Context) error {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
-	for pluginKey, entry := range pm.plugins {
+	var timeouts []error
+	for _, pluginKey := range slices.Backward(pm.order) {
+		entry := pm.plugins[pluginKey]
 		if !entry.started {
 			continue
 		}
 
-		if err := entry.Plugin.Shutdown(ctx); err != nil {
-			return fmt.Errorf("failed to stop plugin %s: %w", pluginKey, err)
+		spanCtx, span := pm.startSpan(ctx, pluginKey, entry, PhaseShutdown)
+		start := time.Now()
+		err := pm.shutdownPlugin(spanCtx, pluginKey, entry.Plugin)
+		entry.timings.shutdown = time.Since(start)
+		endSpan(span, err)
+		pm.observe(pluginKey, entry, PhaseShutdown, entry.timings.shutdown, err)
+
+		var timeoutErr *ShutdownTimeoutError
+		if errors.As(err, &timeoutErr) {
+			entry.started = false
+			timeouts = append(timeouts, err)
+			pm.log().Error("Plugin shutdown timed out, skipping",
+				"plugin_type", entry.PluginType,
+				"instance", entry.InstanceName,
+				"key", pluginKey,
+				"timeout", timeoutErr.Timeout,
+			)
+			continue
+		}
+		if err != nil {
+			return errors.Join(append(timeouts, fmt.Errorf("failed to stop plugin %s: %w", pluginKey, err))...)
 		}
 
 		entry.started = false
-		slogs.Info("Plugin stopped",
+		pm.log().Info("Plugin stopped",
 			"plugin_type", entry.PluginType,
 			"instance", entry.InstanceName,
 			"key", pluginKey,
+			"duration", entry.timings.shutdown,
 		)
 	}
 
 	if len(pm.plugins) > 0 {
-		slogs.Info("All plugins stopped", "count", len(pm.plugins))
+		pm.log().Info("All plugins stopped", "count", len(pm.plugins))
 	}
 
-	return nil
+	return errors.Join(timeouts...)
 }
 
 // Reload intelligently handles configuration changes by automatically
@@ -224,17 +413,30 @@ func (pm *PluginManager[T]) Shutdown(ctx context.Context) error {
 // This method uses reflection to recursively iterate through configuration struct fields
 // and automatically reloads plugins when their corresponding configuration implements
 // the Config interface and has changed.
+//
+// oldConfig and newConfig are expected to be fully merged configurations (all sources,
+// including env and CLI overrides), so plugins always receive the effective values
+// and changes masked by a higher-priority source do not trigger a reload.
 func (pm *PluginManager[T]) Reload(ctx context.Context, oldConfig, newConfig *T) error {
+	_, err := pm.ReloadWithReport(ctx, oldConfig, newConfig)
+	return err
+}
+
+// ReloadWithReport behaves like Reload and additionally reports which plugin
+// instances were reloaded. The report is returned even if a reload failed.
+func (pm *PluginManager[T]) ReloadWithReport(ctx context.Context, oldConfig, newConfig *T) (*ReloadReport, error) {
+	report := &ReloadReport{}
+
 	pm.mu.RLock()
-	if len(pm.plugins) == 0 {
+	if len(pm.plugins) == 0 && !pm.collections {
 		pm.mu.RUnlock()
-		slogs.Debug("No plugins registered, no plugin need reload")
-		return nil
+		pm.log().Debug("No plugins registered, no plugin need reload")
+		return report, nil
 	}
 	pm.mu.RUnlock()
 
 	if oldConfig == nil || newConfig == nil {
-		return nil
+		return report, nil
 	}
 
 	// Use reflection to recursively iterate through configuration fields
@@ -242,12 +444,21 @@ func (pm *PluginManager[T]) Reload(ctx context.Context, oldConfig, newConfig *T)
 	newValue := reflect.ValueOf(newConfig)
 
 	// Start recursive traversal
-	return pm.handleConfigChangeRecursive(ctx, oldValue, newValue, "")
+	err := pm.handleConfigChangeRecursive(ctx, oldValue, newValue, "", report)
+
+	pm.log().Debug("Plugin reload evaluation finished",
+		"reloaded", report.Reloaded,
+		"skipped", report.Skipped,
+		"throttled", report.Throttled,
+		"error", err,
+	)
+
+	return report, err
 }
 
 // handleConfigChangeRecursive recursively traverses configuration structures to detect
 // plugin configuration changes at any nesting level with multi-instance support
-func (pm *PluginManager[T]) handleConfigChangeRecursive(ctx context.Context, oldValue, newValue reflect.Value, fieldPath string) error {
+func (pm *PluginManager[T]) handleConfigChangeRecursive(ctx context.Context, oldValue, newValue reflect.Value, fieldPath string, report *ReloadReport) error {
 	// Handle pointers
 	if oldValue.Kind() == reflect.Ptr {
 		oldValue = oldValue.Elem()
@@ -268,7 +479,7 @@ func (pm *PluginManager[T]) handleConfigChangeRecursive(ctx context.Context, old
 	for i := range oldValue.NumField() {
 		fieldType := oldType.Field(i)
 
-		slogs.Debug("Processing field", "name", fieldType.Name, "path", fieldPath)
+		pm.log().Debug("Processing field", "name", fieldType.Name, "path", fieldPath)
 
 		vOldField := oldValue.Field(i)
 		vNewField := newValue.Field(i)
@@ -281,6 +492,14 @@ func (pm *PluginManager[T]) handleConfigChangeRecursive(ctx context.Context, old
 		// Build field path for logging
 		currentFieldPath := getFieldPath(fieldPath, fieldType.Name)
 
+		// Instances of map and slice entries follow the entries of the new value
+		if isConfigCollection(fieldType.Type) {
+			if err := pm.reloadCollection(ctx, fieldType, vNewField, currentFieldPath, report); err != nil {
+				errors = append(errors, err)
+			}
+			continue
+		}
+
 		// Check if the field implements Config interface
 		if vOldField.Kind() == reflect.Struct {
 			// Try to get config interface from the field
@@ -288,14 +507,20 @@ func (pm *PluginManager[T]) handleConfigChangeRecursive(ctx context.Context, old
 			iNewField := toInterface(vNewField)
 
 			if iOldField != nil {
-				if config, ok := iOldField.(Config); ok && !reflect.DeepEqual(iOldField, iNewField) {
-					// Process plugin config change but don't return immediately
-					if err := pm.reloadPluginConfig(ctx, config, iNewField, currentFieldPath); err != nil {
+				if config, ok := iOldField.(Config); ok {
+					// Process plugin config change but don't return immediately.
+					// Whether the plugin actually reloads is decided by its config hash.
+					instanceName, err := getInstanceName(fieldType, currentFieldPath)
+					if err != nil {
+						errors = append(errors, fmt.Errorf("config field %s: %w", currentFieldPath, err))
+						continue
+					}
+					if err := pm.reloadPluginConfig(ctx, config, iNewField, currentFieldPath, instanceName, report); err != nil {
 						errors = append(errors, err)
 					}
 				} else {
 					// If not a plugin config, recursively check nested structures
-					if err := pm.handleConfigChangeRecursive(ctx, vOldField, vNewField, currentFieldPath); err != nil {
+					if err := pm.handleConfigChangeRecursive(ctx, vOldField, vNewField, currentFieldPath, report); err != nil {
 						errors = append(errors, err)
 					}
 				}
@@ -310,15 +535,17 @@ func (pm *PluginManager[T]) handleConfigChangeRecursive(ctx context.Context, old
 	return nil
 }
 
-// reloadPluginConfig handles the plugin reload logic
-func (pm *PluginManager[T]) reloadPluginConfig(ctx context.Context, config Config, newConfig any, fieldPath string) error {
+// reloadPluginConfig handles the plugin reload logic and records reloaded
+// plugins in report, which may be nil. The plugin's Reload is only called when
+// the hash of newConfig differs from the hash of the config the plugin currently
+// runs with. instanceName is the name the instance was discovered under, see
+// getInstanceName.
+func (pm *PluginManager[T]) reloadPluginConfig(ctx context.Context, config Config, newConfig any, fieldPath, instanceName string, report *ReloadReport) error {
 	pluginType := getConfigType(config)
 
-	// Use field path as instance name for consistency with auto-discovery
-	instanceName := strings.ToLower(fieldPath)
 	pluginKey := getPluginKey(pluginType, instanceName)
 
-	slogs.Debug("Smart config change detected",
+	pm.log().Debug("Smart config change detected",
 		"field", fieldPath,
 		"plugin_type", pluginType,
 		"instance", instanceName,
@@ -326,13 +553,13 @@ func (pm *PluginManager[T]) reloadPluginConfig(ctx context.Context, config Confi
 	)
 
 	pm.mu.RLock()
-	slogs.Debug("Searching for plugin",
+	pm.log().Debug("Searching for plugin",
 		"target_key", pluginKey,
 		"total_registered", len(pm.plugins),
 	)
 
 	for key, entry := range pm.plugins {
-		slogs.Debug("Registered plugin",
+		pm.log().Debug("Registered plugin",
 			"key", key,
 			"type", entry.PluginType,
 			"instance", entry.InstanceName,
@@ -346,25 +573,100 @@ func (pm *PluginManager[T]) reloadPluginConfig(ctx context.Context, config Confi
 	pm.mu.RUnlock()
 
 	if exists {
-		slogs.Debug("Plugin found", "key", pluginKey, "started", entry.started)
+		newHash := hashConfig(newConfig)
+		if newHash == entry.configHash {
+			report.addSkipped(pluginKey)
+			pm.log().Debug("Plugin config hash unchanged, skipping reload", "key", pluginKey)
+			return nil
+		}
+
+		pm.log().Debug("Plugin found", "key", pluginKey, "started", entry.started)
+
+		// newConfig points into the manager's live configuration; keep and hand
+		// out private copies so neither side observes the other's mutations
+		newCfg := cloneConfig(newConfig)
+		if newCfg == nil {
+			return fmt.Errorf("config for plugin %s has unexpected type %T", pluginKey, newConfig)
+		}
+
+		// Flipping the enabled flag starts or shuts the plugin down instead
+		pm.mu.RLock()
+		wasEnabled := entry.enabled()
+		pm.mu.RUnlock()
+		if newCfg.baseConfigEmbedded().IsEnabled() != wasEnabled {
+			return pm.applyEnabled(ctx, pluginKey, entry, newCfg, newHash, report)
+		}
 
 		if entry.started {
-			// Reload registered plugin
-			slogs.Debug("Reloading plugin", "key", pluginKey)
-			if err := entry.Plugin.Reload(ctx, newConfig); err != nil {
+			// Throttle plugins that keep failing; the hash is left unchanged so
+			// the pending change is retried on a reload after the backoff expires
+			pm.mu.RLock()
+			until := entry.backoff.until
+			pm.mu.RUnlock()
+			if pm.now().Before(until) {
+				report.addThrottled(pluginKey)
+				pm.log().Warn("Plugin reload throttled after repeated failures",
+					"key", pluginKey,
+					"retry_after", until,
+				)
+				return nil
+			}
+
+			// Reload registered plugin, or replace it with a new generation
+			var err error
+			spanCtx, span := pm.startSpan(ctx, pluginKey, entry, PhaseReload)
+			start := time.Now()
+			if entry.swap && entry.factory != nil {
+				pm.log().Debug("Swapping plugin", "key", pluginKey)
+				err = pm.swapPlugin(spanCtx, pluginKey, entry, newCfg)
+			} else {
+				pm.log().Debug("Reloading plugin", "key", pluginKey)
+				err = entry.Plugin.Reload(spanCtx, cloneConfig(newCfg))
+			}
+			duration := time.Since(start)
+			endSpan(span, err)
+			pm.observe(pluginKey, entry, PhaseReload, duration, err)
+			if err != nil {
+				pm.mu.Lock()
+				entry.timings.reload = duration
+				delay := pm.recordReloadFailure(entry, err)
+				failures := entry.backoff.failures
+				pm.mu.Unlock()
+
+				pm.log().Warn("Plugin reload failed, backing off",
+					"key", pluginKey,
+					"failures", failures,
+					"backoff", delay,
+				)
 				return fmt.Errorf("smart plugin reload failed, key=%s, err=%w", pluginKey, err)
 			}
 
 			// Update config for registered plugins
-			if newCfg, ok := newConfig.(Config); ok {
-				entry.Config = newCfg
-			}
-			slogs.Debug("Plugin reloaded successfully", "key", pluginKey)
+			pm.mu.Lock()
+			entry.Config = newCfg
+			entry.configHash = newHash
+			entry.backoff = reloadBackoff{}
+			entry.timings.reload = duration
+			pm.mu.Unlock()
+
+			report.addReloaded(pluginKey)
+			pm.log().Debug("Plugin reloaded successfully", "key", pluginKey, "duration", duration)
 		} else {
-			slogs.Warn("Plugin found but not started", "key", pluginKey)
+			// Keep the latest config so a deferred start uses current values
+			pm.mu.Lock()
+			entry.Config = newCfg
+			entry.configHash = newHash
+			pm.mu.Unlock()
+			if !newCfg.baseConfigEmbedded().IsEnabled() {
+				pm.log().Debug("Disabled plugin config updated", "key", pluginKey)
+			} else if entry.lazy {
+				pm.log().Debug("Lazy plugin not started yet, config updated", "key", pluginKey)
+			} else {
+				pm.log().Warn("Plugin found but not started", "key", pluginKey)
+			}
 		}
-	} else {
-		slogs.Warn("Plugin not found in registry", "key", pluginKey)
+	} else if !reflect.DeepEqual(config, newConfig) {
+		pm.log().Warn("Plugin not found in registry", "key", pluginKey)
 	}
 
 	return nil
@@ -386,6 +688,16 @@ func (pm *PluginManager[T]) Clone() map[string]*PluginEntry {
 			InstanceName: entry.InstanceName,
 			ConfigPath:   entry.ConfigPath,
 			started:      entry.started,
+			lazy:         entry.lazy,
+			configHash:   entry.configHash,
+			backoff:      entry.backoff,
+			swap:         entry.swap,
+			factory:      entry.factory,
+			generation:   entry.generation,
+			timings:      entry.timings,
+			typeDeps:     entry.typeDeps,
+			dependsOn:    entry.dependsOn,
+			collection:   entry.collection,
 		}
 	}
 	return cloned
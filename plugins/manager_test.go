@@ -1,12 +1,15 @@
 package plugins
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log/slog"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockPluginWithError is a plugin that can simulate errors
@@ -555,11 +558,11 @@ func TestPluginManager_ReloadPluginConfig(t *testing.T) {
 		Value:      "new_value",
 	}
 
-	err = manager.reloadPluginConfig(context.Background(), &config.TestPlugin, newConfig, "TestPlugin")
+	err = manager.reloadPluginConfig(context.Background(), &config.TestPlugin, newConfig, "TestPlugin", "testplugin", nil)
 	assert.NoError(t, err)
 
 	// Test reloading non-existent plugin
-	err = manager.reloadPluginConfig(context.Background(), &config.TestPlugin, newConfig, "NonExistentPlugin")
+	err = manager.reloadPluginConfig(context.Background(), &config.TestPlugin, newConfig, "NonExistentPlugin", "nonexistentplugin", nil)
 	assert.NoError(t, err) // Should not error, just log warning
 }
 
@@ -651,3 +654,404 @@ func TestPluginManager_Clone(t *testing.T) {
 		assert.False(t, originalPlugins[key].started)
 	}
 }
+
+func TestPluginManager_LazyStart(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	manager := NewPluginManager[SimpleTestConfig]()
+
+	RegisterPluginType("lazy", &MockPlugin{}, &MockConfig{}, RegisterOptions{AutoDiscover: true, LazyStart: true})
+	defer UnregisterPluginType("lazy")
+
+	config := &SimpleTestConfig{
+		TestPlugin: MockConfig{
+			BaseConfig: BaseConfig{Type: "lazy"},
+			Value:      "initial",
+		},
+	}
+
+	err := manager.DiscoverAndRegister(config)
+	assert.NoError(t, err)
+
+	// Startup must skip the lazy plugin
+	err = manager.Startup(context.Background())
+	assert.NoError(t, err)
+
+	entry := manager.Clone()["lazy:testplugin"]
+	assert.NotNil(t, entry)
+	assert.True(t, entry.lazy)
+	assert.False(t, entry.started)
+	assert.False(t, entry.Plugin.(*MockPlugin).started)
+
+	// Config changes before first use are kept for the deferred start
+	newConfig := &SimpleTestConfig{
+		TestPlugin: MockConfig{
+			BaseConfig: BaseConfig{Type: "lazy"},
+			Value:      "updated",
+		},
+	}
+	err = manager.Reload(context.Background(), config, newConfig)
+	assert.NoError(t, err)
+
+	// Start on demand
+	err = manager.EnsureStarted(context.Background(), "lazy:testplugin")
+	assert.NoError(t, err)
+
+	entry = manager.Clone()["lazy:testplugin"]
+	assert.True(t, entry.started)
+	mockPlugin := entry.Plugin.(*MockPlugin)
+	assert.True(t, mockPlugin.started)
+	assert.Equal(t, "updated", mockPlugin.config.(*MockConfig).Value)
+
+	// Calling again is a no-op
+	err = manager.EnsureStarted(context.Background(), "lazy:testplugin")
+	assert.NoError(t, err)
+
+	// Lazy plugins that were started are shut down like any other plugin
+	err = manager.Shutdown(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, mockPlugin.started)
+}
+
+func TestPluginManager_EnsureStartedUnknownKey(t *testing.T) {
+	manager := NewPluginManager[SimpleTestConfig]()
+
+	err := manager.EnsureStarted(context.Background(), "missing:plugin")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not registered")
+}
+
+// countingPlugin counts Reload calls
+type countingPlugin struct {
+	MockPlugin
+	reloads int
+}
+
+func (cp *countingPlugin) Reload(ctx context.Context, config any) error {
+	cp.reloads++
+	return cp.MockPlugin.Reload(ctx, config)
+}
+
+func TestPluginManager_ReloadSkipsUnchangedHash(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	type HashTestConfig struct {
+		Name       string
+		TestPlugin MockConfig
+	}
+
+	RegisterPluginType("counting", &countingPlugin{}, &MockConfig{})
+	defer UnregisterPluginType("counting")
+
+	manager := NewPluginManager[HashTestConfig]()
+
+	oldConfig := &HashTestConfig{
+		Name:       "old",
+		TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "counting"}, Value: "v1"},
+	}
+	err := manager.DiscoverAndRegister(oldConfig)
+	assert.NoError(t, err)
+	err = manager.Startup(context.Background())
+	assert.NoError(t, err)
+
+	plugin := manager.Clone()["counting:testplugin"].Plugin.(*countingPlugin)
+
+	// Unrelated change: section hash unchanged, no reload
+	unrelated := &HashTestConfig{
+		Name:       "new",
+		TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "counting"}, Value: "v1"},
+	}
+	report, err := manager.ReloadWithReport(context.Background(), oldConfig, unrelated)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Reloaded)
+	assert.Equal(t, 0, plugin.reloads)
+
+	// Section change reloads once and stores the new hash
+	changed := &HashTestConfig{
+		Name:       "new",
+		TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "counting"}, Value: "v2"},
+	}
+	report, err = manager.ReloadWithReport(context.Background(), unrelated, changed)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"counting:testplugin"}, report.Reloaded)
+	assert.Equal(t, 1, plugin.reloads)
+
+	// Replaying the same transition is a no-op since the plugin already runs with v2
+	report, err = manager.ReloadWithReport(context.Background(), unrelated, changed)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Reloaded)
+	assert.Equal(t, 1, plugin.reloads)
+}
+
+// retainConfig is a plugin config with reference-typed fields
+type retainConfig struct {
+	BaseConfig
+	Tags   []string          `json:"tags"`
+	Labels map[string]string `json:"labels"`
+}
+
+func TestPluginManager_PluginRetainsIsolatedConfig(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	type RetainAppConfig struct {
+		Cache retainConfig
+	}
+
+	RegisterPluginType("retain", &MockPlugin{}, &retainConfig{})
+	defer UnregisterPluginType("retain")
+
+	manager := NewPluginManager[RetainAppConfig]()
+
+	oldConfig := &RetainAppConfig{
+		Cache: retainConfig{BaseConfig: BaseConfig{Type: "retain"}, Tags: []string{"a"}, Labels: map[string]string{"env": "dev"}},
+	}
+	assert.NoError(t, manager.DiscoverAndRegister(oldConfig))
+	assert.NoError(t, manager.Startup(context.Background()))
+
+	plugin := manager.Clone()["retain:cache"].Plugin.(*MockPlugin)
+	started := plugin.config.(*retainConfig)
+
+	// Mutating the manager's config after Startup must not leak into the plugin
+	oldConfig.Cache.Tags[0] = "mutated"
+	oldConfig.Cache.Labels["env"] = "mutated"
+	assert.Equal(t, []string{"a"}, started.Tags)
+	assert.Equal(t, "dev", started.Labels["env"])
+
+	newConfig := &RetainAppConfig{
+		Cache: retainConfig{BaseConfig: BaseConfig{Type: "retain"}, Tags: []string{"b"}, Labels: map[string]string{"env": "prod"}},
+	}
+	report, err := manager.ReloadWithReport(context.Background(), oldConfig, newConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"retain:cache"}, report.Reloaded)
+
+	reloaded := plugin.config.(*retainConfig)
+	assert.NotSame(t, &newConfig.Cache, reloaded)
+
+	// Mutating the manager's config after Reload must not leak into the plugin
+	newConfig.Cache.Tags[0] = "mutated"
+	newConfig.Cache.Labels["env"] = "mutated"
+	newConfig.Cache.Labels["extra"] = "added"
+	assert.Equal(t, []string{"b"}, reloaded.Tags)
+	assert.Equal(t, map[string]string{"env": "prod"}, reloaded.Labels)
+
+	// Nor may the plugin's own mutations alter the manager's stored copy
+	reloaded.Tags[0] = "plugin"
+	stored := manager.Clone()["retain:cache"].Config.(*retainConfig)
+	assert.Equal(t, []string{"b"}, stored.Tags)
+}
+
+// otherConfig is a plugin config unrelated to MockConfig
+type otherConfig struct {
+	BaseConfig
+	Enabled bool `json:"enabled"`
+}
+
+func TestPluginManager_DiscoverConfigTypeMismatch(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	type MismatchAppConfig struct {
+		Services struct {
+			Cache MockConfig
+		}
+	}
+
+	RegisterPluginType("other", &MockPlugin{}, &otherConfig{})
+	defer UnregisterPluginType("other")
+
+	manager := NewPluginManager[MismatchAppConfig]()
+
+	config := &MismatchAppConfig{}
+	config.Services.Cache = MockConfig{BaseConfig: BaseConfig{Type: "other"}}
+
+	err := manager.DiscoverAndRegister(config)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Services.Cache")
+		assert.Contains(t, err.Error(), "*plugins.MockConfig")
+		assert.Contains(t, err.Error(), `"other"`)
+		assert.Contains(t, err.Error(), "*plugins.otherConfig")
+
+		var discoveryErr *DiscoveryError
+		if assert.ErrorAs(t, err, &discoveryErr) {
+			assert.Equal(t, "Services.Cache", discoveryErr.FieldPath)
+			assert.Equal(t, "other", discoveryErr.PluginType)
+		}
+	}
+	assert.Empty(t, manager.Clone())
+}
+
+// namedPlugin records the name it was given before Startup
+type namedPlugin struct {
+	MockPlugin
+	instance   string
+	configPath string
+	namedFirst bool
+}
+
+func (np *namedPlugin) SetName(instance, configPath string) {
+	np.instance = instance
+	np.configPath = configPath
+}
+
+func (np *namedPlugin) Startup(ctx context.Context, config any) error {
+	np.namedFirst = np.instance != ""
+	return np.MockPlugin.Startup(ctx, config)
+}
+
+func TestPluginManager_NamedPlugin(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	type NamedAppConfig struct {
+		Client struct {
+			Kafka MockConfig
+		}
+	}
+
+	RegisterPluginType("named", &namedPlugin{}, &MockConfig{}, RegisterOptions{AutoDiscover: true})
+	defer UnregisterPluginType("named")
+
+	manager := NewPluginManager[NamedAppConfig]()
+
+	config := &NamedAppConfig{}
+	config.Client.Kafka = MockConfig{BaseConfig: BaseConfig{Type: "named"}}
+
+	err := manager.DiscoverAndRegister(config)
+	assert.NoError(t, err)
+	err = manager.Startup(context.Background())
+	assert.NoError(t, err)
+
+	entry := manager.Clone()["named:client.kafka"]
+	if !assert.NotNil(t, entry) {
+		return
+	}
+
+	plugin := entry.Plugin.(*namedPlugin)
+	assert.Equal(t, "client.kafka", plugin.instance)
+	assert.Equal(t, "Client.Kafka", plugin.configPath)
+	assert.True(t, plugin.namedFirst, "SetName must be called before Startup")
+}
+
+func TestPluginManager_InstanceNameTag(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	type TaggedAppConfig struct {
+		Client struct {
+			Kafka MockConfig `plugin:"name=primary-kafka"`
+		}
+		Backup MockConfig
+	}
+
+	RegisterPluginType("counting", &countingPlugin{}, &MockConfig{})
+	defer UnregisterPluginType("counting")
+
+	manager := NewPluginManager[TaggedAppConfig]()
+
+	oldConfig := &TaggedAppConfig{Backup: MockConfig{BaseConfig: BaseConfig{Type: "counting"}}}
+	oldConfig.Client.Kafka = MockConfig{BaseConfig: BaseConfig{Type: "counting"}, Value: "v1"}
+
+	err := manager.DiscoverAndRegister(oldConfig)
+	require.NoError(t, err)
+	err = manager.Startup(context.Background())
+	require.NoError(t, err)
+
+	plugins := manager.Clone()
+	require.Contains(t, plugins, "counting:primary-kafka")
+	assert.Contains(t, plugins, "counting:backup")
+	assert.Equal(t, "primary-kafka", plugins["counting:primary-kafka"].InstanceName)
+	assert.Equal(t, "Client.Kafka", plugins["counting:primary-kafka"].ConfigPath)
+
+	// Reloads find the instance under its tagged name
+	newConfig := &TaggedAppConfig{Backup: oldConfig.Backup}
+	newConfig.Client.Kafka = MockConfig{BaseConfig: BaseConfig{Type: "counting"}, Value: "v2"}
+
+	report, err := manager.ReloadWithReport(context.Background(), oldConfig, newConfig)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"counting:primary-kafka"}, report.Reloaded)
+	assert.Equal(t, 1, plugins["counting:primary-kafka"].Plugin.(*countingPlugin).reloads)
+}
+
+func TestPluginManager_InstanceNameTagErrors(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	RegisterPluginType("mock", &MockPlugin{}, &MockConfig{})
+	defer UnregisterPluginType("mock")
+
+	t.Run("duplicate name", func(t *testing.T) {
+		type DuplicateAppConfig struct {
+			Primary   MockConfig `plugin:"name=kafka"`
+			Secondary MockConfig `plugin:"name=kafka"`
+		}
+
+		config := &DuplicateAppConfig{
+			Primary:   MockConfig{BaseConfig: BaseConfig{Type: "mock"}},
+			Secondary: MockConfig{BaseConfig: BaseConfig{Type: "mock"}},
+		}
+		err := NewPluginManager[DuplicateAppConfig]().DiscoverAndRegister(config)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "plugin instance mock:kafka already registered")
+		}
+	})
+
+	t.Run("invalid tag", func(t *testing.T) {
+		type InvalidAppConfig struct {
+			Kafka MockConfig `plugin:"label=kafka"`
+		}
+
+		config := &InvalidAppConfig{Kafka: MockConfig{BaseConfig: BaseConfig{Type: "mock"}}}
+		err := NewPluginManager[InvalidAppConfig]().DiscoverAndRegister(config)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "config field Kafka")
+			assert.Contains(t, err.Error(), `unknown plugin tag option "label=kafka"`)
+		}
+	})
+}
+
+func TestPluginManager_SetLogger(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	RegisterPluginType("flaky", &flakyPlugin{}, &MockConfig{})
+	defer UnregisterPluginType("flaky")
+
+	var buf bytes.Buffer
+	manager := NewPluginManager[SimpleTestConfig]()
+	manager.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	config := &SimpleTestConfig{TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "flaky"}, Value: "v0"}}
+	require.NoError(t, manager.DiscoverAndRegister(config))
+	require.NoError(t, manager.Startup(context.Background()))
+	require.NoError(t, manager.Shutdown(context.Background()))
+
+	assert.Contains(t, buf.String(), `msg="Plugin started"`)
+	assert.Contains(t, buf.String(), "key=flaky:testplugin")
+}
@@ -0,0 +1,67 @@
+// Package plugins provides a comprehensive plugin management system that supports
+// automatic discovery, registration, and lifecycle management of plugins.
+// This file implements the observation of plugin lifecycle calls, e.g. to export
+// their durations and failures as metrics.
+package plugins
+
+import "time"
+
+// LifecyclePhase names a plugin lifecycle call.
+type LifecyclePhase string
+
+const (
+	// PhaseStartup is a call of Startup
+	PhaseStartup LifecyclePhase = "startup"
+	// PhaseReload is a call of Reload, or the swap of a SwapOnReload instance
+	PhaseReload LifecyclePhase = "reload"
+	// PhaseShutdown is a call of Shutdown
+	PhaseShutdown LifecyclePhase = "shutdown"
+)
+
+// LifecycleEvent describes a completed lifecycle call of a plugin instance.
+type LifecycleEvent struct {
+	// Key is the registry key in the form "pluginType:instanceName"
+	Key string
+	// PluginType identifies the type of the plugin
+	PluginType string
+	// InstanceName is the unique name of the plugin instance
+	InstanceName string
+	// Phase is the lifecycle call that completed
+	Phase LifecyclePhase
+	// Duration is how long the call took
+	Duration time.Duration
+	// Err is the error returned by the call, nil on success
+	Err error
+}
+
+// Observer is notified after every plugin lifecycle call. It is called
+// synchronously, possibly while the manager holds its lock, so it must return
+// quickly and must not call back into the PluginManager.
+type Observer interface {
+	PluginLifecycle(event LifecycleEvent)
+}
+
+// SetObserver registers o to be notified of plugin lifecycle calls, replacing
+// any previous observer; nil disables notifications. Set it before plugins
+// are started.
+func (pm *PluginManager[T]) SetObserver(o Observer) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.observer = o
+}
+
+// observe notifies the observer, if any, of a completed lifecycle call of entry
+func (pm *PluginManager[T]) observe(key string, entry *PluginEntry, phase LifecyclePhase, duration time.Duration, err error) {
+	if pm.observer == nil {
+		return
+	}
+	pm.observer.PluginLifecycle(LifecycleEvent{
+		Key:          key,
+		PluginType:   entry.PluginType,
+		InstanceName: entry.InstanceName,
+		Phase:        phase,
+		Duration:     duration,
+		Err:          err,
+	})
+}
@@ -0,0 +1,55 @@
+package plugins
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingObserver records the lifecycle events it is notified of
+type recordingObserver struct {
+	events []LifecycleEvent
+}
+
+func (o *recordingObserver) PluginLifecycle(event LifecycleEvent) {
+	o.events = append(o.events, event)
+}
+
+func TestPluginManager_SetObserver(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	RegisterPluginType("flaky", &flakyPlugin{}, &MockConfig{})
+	defer UnregisterPluginType("flaky")
+
+	manager := NewPluginManager[SimpleTestConfig]()
+	observer := &recordingObserver{}
+	manager.SetObserver(observer)
+
+	config := &SimpleTestConfig{TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "flaky"}, Value: "v0"}}
+	require.NoError(t, manager.DiscoverAndRegister(config))
+	require.NoError(t, manager.Startup(context.Background()))
+	manager.Clone()["flaky:testplugin"].Plugin.(*flakyPlugin).failReloads = 1
+
+	newConfig := &SimpleTestConfig{TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "flaky"}, Value: "v1"}}
+	require.Error(t, manager.Reload(context.Background(), config, newConfig))
+	require.NoError(t, manager.Shutdown(context.Background()))
+
+	require.Len(t, observer.events, 3)
+	phases := []LifecyclePhase{PhaseStartup, PhaseReload, PhaseShutdown}
+	for i, event := range observer.events {
+		assert.Equal(t, phases[i], event.Phase)
+		assert.Equal(t, "flaky", event.PluginType)
+		assert.Equal(t, "flaky:testplugin", event.Key)
+		assert.GreaterOrEqual(t, event.Duration, time.Duration(0))
+	}
+	assert.NoError(t, observer.events[0].Err)
+	assert.EqualError(t, observer.events[1].Err, "broker unreachable")
+	assert.NoError(t, observer.events[2].Err)
+}
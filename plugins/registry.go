@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"maps"
 	"reflect"
+	"slices"
 	"sync"
 
 	"github.com/nextpkg/vcfg/slogs"
@@ -73,10 +74,16 @@ func RegisterPluginType[P PluginPtr[PT], C ConfigPtr[CT], PT any, CT any](plugin
 		return reflect.New(reflect.TypeOf(*c)).Interface().(Config)
 	}
 
-	// Determine auto-discovery setting
+	// Determine auto-discovery and startup settings
 	autoDiscover := true
+	lazyStart := false
+	swapOnReload := false
+	var dependsOn []string
 	if len(opts) > 0 {
 		autoDiscover = opts[0].AutoDiscover
+		lazyStart = opts[0].LazyStart
+		swapOnReload = opts[0].SwapOnReload
+		dependsOn = slices.Clone(opts[0].DependsOn)
 	}
 
 	registry.pluginTypes[pluginType] = &pluginTypeEntry{
@@ -84,9 +91,12 @@ func RegisterPluginType[P PluginPtr[PT], C ConfigPtr[CT], PT any, CT any](plugin
 		PluginFactory: pluginFactory,
 		ConfigFactory: configFactory,
 		AutoDiscover:  autoDiscover,
+		LazyStart:     lazyStart,
+		SwapOnReload:  swapOnReload,
+		DependsOn:     dependsOn,
 	}
 
-	slogs.Info("Plugin type registered", "PluginType", pluginType, "auto_discover", autoDiscover)
+	slogs.Info("Plugin type registered", "PluginType", pluginType, "auto_discover", autoDiscover, "lazy_start", lazyStart, "swap_on_reload", swapOnReload)
 }
 
 // ListPluginTypes returns a list of all registered plugin type names
@@ -0,0 +1,77 @@
+// Package plugins provides a comprehensive plugin management system that supports
+// automatic discovery, registration, and lifecycle management of plugins.
+// This file implements the per-plugin shutdown deadline that keeps a hanging
+// plugin from blocking the shutdown of all others.
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultShutdownTimeout bounds the Shutdown call of a single plugin
+	defaultShutdownTimeout = 30 * time.Second
+	// shutdownGracePeriod is how long a plugin may take to return once its
+	// shutdown context expired before it is abandoned
+	shutdownGracePeriod = 250 * time.Millisecond
+)
+
+// ShutdownTimeoutError reports a plugin whose Shutdown did not return in time.
+// The plugin is considered stopped and its Shutdown call is abandoned.
+type ShutdownTimeoutError struct {
+	// Key is the registry key of the plugin instance
+	Key string
+	// Timeout is the deadline the plugin exceeded
+	Timeout time.Duration
+}
+
+// Error implements the error interface.
+func (e *ShutdownTimeoutError) Error() string {
+	return fmt.Sprintf("plugin %s did not shut down within %s", e.Key, e.Timeout)
+}
+
+// SetShutdownTimeout sets how long Shutdown waits for each plugin, 30 seconds by
+// default. Each plugin's Shutdown receives a context with this deadline; if it
+// does not return in time, plus a short grace period, it is abandoned and the
+// next plugin is shut down.
+// A timeout of zero or less only applies the deadline of the caller's context.
+func (pm *PluginManager[T]) SetShutdownTimeout(timeout time.Duration) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.shutdownTimeout = timeout
+}
+
+// shutdownPlugin calls plugin.Shutdown with the per-plugin deadline and waits
+// until it returns or the deadline expires. The caller must hold pm.mu.
+func (pm *PluginManager[T]) shutdownPlugin(ctx context.Context, pluginKey string, plugin Plugin) error {
+	timeout := pm.shutdownTimeout
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- plugin.Shutdown(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// Give a plugin that honours the context a chance to report its own error
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(shutdownGracePeriod):
+		}
+		if timeout <= 0 {
+			return ctx.Err()
+		}
+		return &ShutdownTimeoutError{Key: pluginKey, Timeout: timeout}
+	}
+}
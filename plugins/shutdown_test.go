@@ -0,0 +1,99 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hangingPlugin ignores its shutdown context and blocks until released
+type hangingPlugin struct {
+	orderPlugin
+	release chan struct{}
+}
+
+// hangingRelease is handed to hangingPlugin instances when they start
+var hangingRelease = struct {
+	sync.Mutex
+	ch chan struct{}
+}{}
+
+func (hp *hangingPlugin) Startup(ctx context.Context, config any) error {
+	hangingRelease.Lock()
+	hp.release = hangingRelease.ch
+	hangingRelease.Unlock()
+	return hp.orderPlugin.Startup(ctx, config)
+}
+
+func (hp *hangingPlugin) Shutdown(ctx context.Context) error {
+	recordLifecycle("stop " + hp.name)
+	<-hp.release
+	return nil
+}
+
+// ctxPlugin honours its shutdown context and reports why it stopped
+type ctxPlugin struct {
+	orderPlugin
+}
+
+func (cp *ctxPlugin) Shutdown(ctx context.Context) error {
+	recordLifecycle("stop " + cp.name)
+	<-ctx.Done()
+	return errors.New("flush aborted")
+}
+
+func TestPluginManager_ShutdownTimeout(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+	UnregisterPluginType("kafka")
+	RegisterPluginType("kafka", &hangingPlugin{}, &MockConfig{}, RegisterOptions{
+		AutoDiscover: true,
+		DependsOn:    []string{"logger"},
+	})
+	release := make(chan struct{})
+	hangingRelease.Lock()
+	hangingRelease.ch = release
+	hangingRelease.Unlock()
+	t.Cleanup(func() { close(release) })
+	takeLifecycle()
+
+	manager := NewPluginManager[DependencyTestConfig]()
+	manager.SetShutdownTimeout(50 * time.Millisecond)
+	require.NoError(t, manager.DiscoverAndRegister(newDependencyTestConfig([]string{"kafka"}, nil)))
+	require.NoError(t, manager.Startup(context.Background()))
+	takeLifecycle()
+
+	// The hanging plugin is skipped and the shutdown proceeds with its dependency
+	start := time.Now()
+	err := manager.Shutdown(context.Background())
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	var timeoutErr *ShutdownTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, "kafka:kafka", timeoutErr.Key)
+	assert.EqualError(t, err, "plugin kafka:kafka did not shut down within 50ms")
+	assert.Equal(t, []string{"stop cache", "stop kafka", "stop logger"}, takeLifecycle())
+
+	for _, status := range manager.Status() {
+		assert.False(t, status.Started, status.Key)
+	}
+}
+
+func TestPluginManager_ShutdownContextError(t *testing.T) {
+	registerOrderPlugins(t, RegisterOptions{AutoDiscover: true})
+	UnregisterPluginType("logger")
+	RegisterPluginType("logger", &ctxPlugin{}, &MockConfig{})
+
+	manager := NewPluginManager[DependencyTestConfig]()
+	manager.SetShutdownTimeout(20 * time.Millisecond)
+	require.NoError(t, manager.DiscoverAndRegister(newDependencyTestConfig(nil, nil)))
+	require.NoError(t, manager.Startup(context.Background()))
+
+	// A plugin that honours the deadline reports its own error
+	err := manager.Shutdown(context.Background())
+	assert.EqualError(t, err, "failed to stop plugin logger:logger: flush aborted")
+}
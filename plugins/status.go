@@ -0,0 +1,145 @@
+// Package plugins provides a comprehensive plugin management system that supports
+// automatic discovery, registration, and lifecycle management of plugins.
+// This file implements plugin status reporting, lifecycle timings and the reload
+// backoff applied to plugins whose Reload keeps failing.
+package plugins
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// defaultReloadBackoff is the delay after the first failed reload of a plugin
+	defaultReloadBackoff = time.Second
+	// defaultMaxReloadBackoff caps the delay between reload attempts of a failing plugin
+	defaultMaxReloadBackoff = 5 * time.Minute
+)
+
+// PluginStatus is a point-in-time snapshot of a registered plugin instance.
+type PluginStatus struct {
+	// Key is the registry key in the form "pluginType:instanceName"
+	Key string
+	// PluginType identifies the type of the plugin
+	PluginType string
+	// InstanceName is the unique name of the plugin instance
+	InstanceName string
+	// ConfigPath is the configuration path of the plugin's config
+	ConfigPath string
+	// Started reports whether the plugin is running
+	Started bool
+	// Lazy reports whether the plugin was registered with LazyStart
+	Lazy bool
+	// Enabled reports whether the plugin's config enables it, see BaseConfig.Enabled
+	Enabled bool
+	// ReloadFailures counts consecutive failed reloads, reset by a successful reload
+	ReloadFailures int
+	// LastReloadError is the error of the most recent failed reload, nil after a success
+	LastReloadError error
+	// BackoffUntil is the time before which reloads are throttled; zero if not backing off
+	BackoffUntil time.Time
+	// Generation counts how often a SwapOnReload instance was replaced, 0 for the first instance
+	Generation uint64
+	// StartupDuration is how long the most recent Startup took
+	StartupDuration time.Duration
+	// LastReloadDuration is how long the most recent Reload or swap took, successful or not
+	LastReloadDuration time.Duration
+	// ShutdownDuration is how long the most recent Shutdown took
+	ShutdownDuration time.Duration
+}
+
+// pluginTimings records the wall-clock duration of a plugin's lifecycle calls
+type pluginTimings struct {
+	// startup is the duration of the most recent Startup
+	startup time.Duration
+	// reload is the duration of the most recent Reload or swap
+	reload time.Duration
+	// shutdown is the duration of the most recent Shutdown
+	shutdown time.Duration
+}
+
+// reloadBackoff tracks the reload failures of a single plugin instance
+type reloadBackoff struct {
+	// failures counts consecutive failed reloads
+	failures int
+	// lastErr is the error of the most recent failed reload
+	lastErr error
+	// until is the time before which reload attempts are skipped
+	until time.Time
+}
+
+// SetReloadBackoff configures the throttling of plugins whose Reload fails repeatedly.
+// After the n-th consecutive failure further reloads of that plugin are skipped for
+// base * 2^(n-1), capped at max. A successful reload resets the backoff.
+// A base of zero or less disables throttling.
+func (pm *PluginManager[T]) SetReloadBackoff(base, max time.Duration) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.backoffBase = base
+	pm.backoffMax = max
+}
+
+// backoffDelay returns the delay applied after the given number of consecutive failures
+func (pm *PluginManager[T]) backoffDelay(failures int) time.Duration {
+	if pm.backoffBase <= 0 || failures <= 0 {
+		return 0
+	}
+
+	delay := pm.backoffBase
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if pm.backoffMax > 0 && delay >= pm.backoffMax {
+			return pm.backoffMax
+		}
+	}
+	if pm.backoffMax > 0 && delay > pm.backoffMax {
+		return pm.backoffMax
+	}
+	return delay
+}
+
+// recordReloadFailure increments the failure count of entry and schedules its next attempt.
+// The caller must hold pm.mu.
+func (pm *PluginManager[T]) recordReloadFailure(entry *PluginEntry, err error) time.Duration {
+	entry.backoff.failures++
+	entry.backoff.lastErr = err
+
+	delay := pm.backoffDelay(entry.backoff.failures)
+	entry.backoff.until = pm.now().Add(delay)
+	return delay
+}
+
+// Status returns a snapshot of all registered plugin instances sorted by key.
+func (pm *PluginManager[T]) Status() []PluginStatus {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	statuses := make([]PluginStatus, 0, len(pm.plugins))
+	for key, entry := range pm.plugins {
+		status := PluginStatus{
+			Key:                key,
+			PluginType:         entry.PluginType,
+			InstanceName:       entry.InstanceName,
+			ConfigPath:         entry.ConfigPath,
+			Started:            entry.started,
+			Lazy:               entry.lazy,
+			Enabled:            entry.enabled(),
+			ReloadFailures:     entry.backoff.failures,
+			LastReloadError:    entry.backoff.lastErr,
+			Generation:         entry.generation,
+			StartupDuration:    entry.timings.startup,
+			LastReloadDuration: entry.timings.reload,
+			ShutdownDuration:   entry.timings.shutdown,
+		}
+		if entry.backoff.until.After(pm.now()) {
+			status.BackoffUntil = entry.backoff.until
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Key < statuses[j].Key
+	})
+	return statuses
+}
@@ -0,0 +1,158 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyPlugin fails its first failReloads reloads
+type flakyPlugin struct {
+	MockPlugin
+	failReloads int
+	attempts    int
+}
+
+func (fp *flakyPlugin) Reload(ctx context.Context, config any) error {
+	fp.attempts++
+	if fp.attempts <= fp.failReloads {
+		return errors.New("broker unreachable")
+	}
+	return fp.MockPlugin.Reload(ctx, config)
+}
+
+func TestPluginManager_BackoffDelay(t *testing.T) {
+	manager := NewPluginManager[SimpleTestConfig]()
+	manager.SetReloadBackoff(time.Second, 10*time.Second)
+
+	assert.Equal(t, time.Duration(0), manager.backoffDelay(0))
+	assert.Equal(t, time.Second, manager.backoffDelay(1))
+	assert.Equal(t, 2*time.Second, manager.backoffDelay(2))
+	assert.Equal(t, 8*time.Second, manager.backoffDelay(4))
+	assert.Equal(t, 10*time.Second, manager.backoffDelay(5))
+	assert.Equal(t, 10*time.Second, manager.backoffDelay(100))
+
+	manager.SetReloadBackoff(0, 0)
+	assert.Equal(t, time.Duration(0), manager.backoffDelay(3))
+}
+
+func TestPluginManager_ReloadBackoff(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	RegisterPluginType("flaky", &flakyPlugin{}, &MockConfig{})
+	defer UnregisterPluginType("flaky")
+
+	manager := NewPluginManager[SimpleTestConfig]()
+	manager.SetReloadBackoff(time.Second, time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	manager.now = func() time.Time { return now }
+
+	config := &SimpleTestConfig{TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "flaky"}, Value: "v0"}}
+	require.NoError(t, manager.DiscoverAndRegister(config))
+	require.NoError(t, manager.Startup(context.Background()))
+
+	const key = "flaky:testplugin"
+	plugin := manager.Clone()[key].Plugin.(*flakyPlugin)
+	plugin.failReloads = 3
+
+	version := 0
+	reload := func() *ReloadReport {
+		version++
+		newConfig := &SimpleTestConfig{TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "flaky"}, Value: fmt.Sprintf("v%d", version)}}
+		report, _ := manager.ReloadWithReport(context.Background(), config, newConfig)
+		config = newConfig
+		return report
+	}
+
+	// Each failure doubles the backoff window
+	var delays []time.Duration
+	for failures := 1; failures <= 3; failures++ {
+		reload()
+		status := manager.Status()[0]
+		assert.Equal(t, failures, status.ReloadFailures)
+		assert.EqualError(t, status.LastReloadError, "broker unreachable")
+		delays = append(delays, status.BackoffUntil.Sub(now))
+
+		// Changes within the window are throttled without calling the plugin
+		attempts := plugin.attempts
+		report := reload()
+		assert.Equal(t, []string{key}, report.Throttled)
+		assert.Equal(t, attempts, plugin.attempts)
+
+		now = status.BackoffUntil
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, delays)
+
+	// A successful reload clears the backoff state
+	report := reload()
+	assert.Equal(t, []string{key}, report.Reloaded)
+	status := manager.Status()[0]
+	assert.Equal(t, 0, status.ReloadFailures)
+	assert.NoError(t, status.LastReloadError)
+	assert.True(t, status.BackoffUntil.IsZero())
+	assert.True(t, status.Started)
+	assert.Equal(t, "TestPlugin", status.ConfigPath)
+}
+
+// slowPlugin sleeps in every lifecycle call
+type slowPlugin struct {
+	MockPlugin
+}
+
+func (sp *slowPlugin) Startup(ctx context.Context, config any) error {
+	time.Sleep(10 * time.Millisecond)
+	return sp.MockPlugin.Startup(ctx, config)
+}
+
+func (sp *slowPlugin) Reload(ctx context.Context, config any) error {
+	time.Sleep(20 * time.Millisecond)
+	return sp.MockPlugin.Reload(ctx, config)
+}
+
+func (sp *slowPlugin) Shutdown(ctx context.Context) error {
+	time.Sleep(10 * time.Millisecond)
+	return sp.MockPlugin.Shutdown(ctx)
+}
+
+func TestPluginManager_StatusDurations(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	RegisterPluginType("slow", &slowPlugin{}, &MockConfig{})
+	defer UnregisterPluginType("slow")
+
+	manager := NewPluginManager[SimpleTestConfig]()
+
+	config := &SimpleTestConfig{TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "slow"}, Value: "v0"}}
+	require.NoError(t, manager.DiscoverAndRegister(config))
+
+	status := manager.Status()[0]
+	assert.Zero(t, status.StartupDuration)
+	assert.Zero(t, status.LastReloadDuration)
+
+	require.NoError(t, manager.Startup(context.Background()))
+	status = manager.Status()[0]
+	assert.GreaterOrEqual(t, status.StartupDuration, 10*time.Millisecond)
+	assert.Zero(t, status.LastReloadDuration)
+
+	newConfig := &SimpleTestConfig{TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "slow"}, Value: "v1"}}
+	require.NoError(t, manager.Reload(context.Background(), config, newConfig))
+	status = manager.Status()[0]
+	assert.GreaterOrEqual(t, status.LastReloadDuration, 20*time.Millisecond)
+
+	require.NoError(t, manager.Shutdown(context.Background()))
+	status = manager.Status()[0]
+	assert.GreaterOrEqual(t, status.ShutdownDuration, 10*time.Millisecond)
+}
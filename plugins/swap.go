@@ -0,0 +1,63 @@
+// Package plugins provides a comprehensive plugin management system that supports
+// automatic discovery, registration, and lifecycle management of plugins.
+// This file implements swap-on-reload, which replaces a running plugin instance
+// with a new generation without a gap in service.
+package plugins
+
+import (
+	"context"
+	"fmt"
+)
+
+// Swapper is implemented by plugins registered with SwapOnReload that need to hand
+// over work to their successor, e.g. to redirect traffic or drain in-flight requests.
+type Swapper interface {
+	// Swap is called on the running instance once next has started successfully
+	// and before the running instance is shut down. Returning an error aborts the
+	// swap: next is shut down and the running instance keeps serving.
+	Swap(ctx context.Context, next Plugin) error
+}
+
+// swapPlugin starts a new generation of entry's plugin with newCfg, switches the
+// entry over to it and then shuts the previous generation down. The previous
+// generation keeps serving until the new one is ready; if starting or handing
+// over fails it remains the active instance. Either generation is shut down
+// within the shutdown timeout, so a hanging Shutdown does not block the reload.
+func (pm *PluginManager[T]) swapPlugin(ctx context.Context, pluginKey string, entry *PluginEntry, newCfg Config) error {
+	next := entry.factory()
+	if err := startPlugin(ctx, next, entry, newCfg); err != nil {
+		return fmt.Errorf("failed to start new generation: %w", err)
+	}
+
+	pm.mu.RLock()
+	prev := entry.Plugin
+	pm.mu.RUnlock()
+
+	if swapper, ok := prev.(Swapper); ok {
+		if err := swapper.Swap(ctx, next); err != nil {
+			pm.mu.RLock()
+			shutdownErr := pm.shutdownPlugin(ctx, pluginKey, next)
+			pm.mu.RUnlock()
+			if shutdownErr != nil {
+				pm.log().Warn("Failed to stop rejected plugin generation", "key", pluginKey, "error", shutdownErr)
+			}
+			return fmt.Errorf("failed to hand over to new generation: %w", err)
+		}
+	}
+
+	pm.mu.Lock()
+	entry.Plugin = next
+	entry.generation++
+	generation := entry.generation
+	pm.mu.Unlock()
+
+	pm.mu.RLock()
+	err := pm.shutdownPlugin(ctx, pluginKey, prev)
+	pm.mu.RUnlock()
+	if err != nil {
+		pm.log().Warn("Failed to stop previous plugin generation", "key", pluginKey, "generation", generation-1, "error", err)
+	}
+
+	pm.log().Info("Plugin swapped", "key", pluginKey, "generation", generation)
+	return nil
+}
@@ -0,0 +1,192 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// swapEvents records the lifecycle of generationPlugin instances in order
+var swapEvents struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func recordSwapEvent(event string) {
+	swapEvents.mu.Lock()
+	defer swapEvents.mu.Unlock()
+	swapEvents.events = append(swapEvents.events, event)
+}
+
+func takeSwapEvents() []string {
+	swapEvents.mu.Lock()
+	defer swapEvents.mu.Unlock()
+	events := swapEvents.events
+	swapEvents.events = nil
+	return events
+}
+
+// generationPlugin is named after its config value. It refuses to start with
+// the value "broken" and refuses handover to a successor named "rejected".
+type generationPlugin struct {
+	name    string
+	serving bool
+}
+
+func (gp *generationPlugin) Startup(_ context.Context, config any) error {
+	name := config.(*MockConfig).Value
+	if name == "broken" {
+		return errors.New("cannot start")
+	}
+	gp.name = name
+	gp.serving = true
+	recordSwapEvent("start:" + name)
+	return nil
+}
+
+func (gp *generationPlugin) Reload(context.Context, any) error {
+	recordSwapEvent("reload:" + gp.name)
+	return nil
+}
+
+func (gp *generationPlugin) Swap(_ context.Context, next Plugin) error {
+	successor := next.(*generationPlugin)
+	if !successor.serving {
+		return errors.New("successor is not ready")
+	}
+	if successor.name == "rejected" {
+		return errors.New("handover refused")
+	}
+	recordSwapEvent("swap:" + gp.name + "->" + successor.name)
+	return nil
+}
+
+func (gp *generationPlugin) Shutdown(context.Context) error {
+	gp.serving = false
+	recordSwapEvent("stop:" + gp.name)
+	return nil
+}
+
+func TestPluginManager_SwapOnReload(t *testing.T) {
+	// Clean up registry before test
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	RegisterPluginType("generation", &generationPlugin{}, &MockConfig{}, RegisterOptions{AutoDiscover: true, SwapOnReload: true})
+	defer UnregisterPluginType("generation")
+	takeSwapEvents()
+
+	manager := NewPluginManager[SimpleTestConfig]()
+	manager.SetReloadBackoff(0, 0)
+	ctx := context.Background()
+
+	newConfig := func(value string) *SimpleTestConfig {
+		return &SimpleTestConfig{TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "generation"}, Value: value}}
+	}
+
+	config := newConfig("v0")
+	require.NoError(t, manager.DiscoverAndRegister(config))
+	require.NoError(t, manager.Startup(ctx))
+
+	const key = "generation:testplugin"
+	first := manager.Clone()[key].Plugin.(*generationPlugin)
+	assert.Equal(t, []string{"start:v0"}, takeSwapEvents())
+
+	// The old instance is stopped only after the new one started and took over
+	next := newConfig("v1")
+	report, err := manager.ReloadWithReport(ctx, config, next)
+	require.NoError(t, err)
+	assert.Equal(t, []string{key}, report.Reloaded)
+	assert.Equal(t, []string{"start:v1", "swap:v0->v1", "stop:v0"}, takeSwapEvents())
+	config = next
+
+	second := manager.Clone()[key].Plugin.(*generationPlugin)
+	assert.NotSame(t, first, second)
+	assert.True(t, second.serving)
+	assert.False(t, first.serving)
+	assert.Equal(t, uint64(1), manager.Status()[0].Generation)
+
+	// A new generation that fails to start leaves the old one serving
+	_, err = manager.ReloadWithReport(ctx, config, newConfig("broken"))
+	assert.ErrorContains(t, err, "failed to start new generation")
+	assert.Empty(t, takeSwapEvents())
+	assert.Same(t, second, manager.Clone()[key].Plugin)
+	assert.True(t, second.serving)
+
+	// A refused handover stops the new generation and keeps the old one
+	_, err = manager.ReloadWithReport(ctx, config, newConfig("rejected"))
+	assert.ErrorContains(t, err, "handover refused")
+	assert.Equal(t, []string{"start:rejected", "stop:rejected"}, takeSwapEvents())
+	assert.Same(t, second, manager.Clone()[key].Plugin)
+	assert.Equal(t, uint64(1), manager.Status()[0].Generation)
+
+	// Shutdown stops the current generation only
+	require.NoError(t, manager.Shutdown(ctx))
+	assert.Equal(t, []string{"stop:v1"}, takeSwapEvents())
+}
+
+// hangingGenerationPlugin is a generationPlugin whose Shutdown blocks until
+// released
+type hangingGenerationPlugin struct {
+	generationPlugin
+	release chan struct{}
+}
+
+func (hp *hangingGenerationPlugin) Swap(ctx context.Context, next Plugin) error {
+	return hp.generationPlugin.Swap(ctx, &next.(*hangingGenerationPlugin).generationPlugin)
+}
+
+func (hp *hangingGenerationPlugin) Shutdown(ctx context.Context) error {
+	_ = hp.generationPlugin.Shutdown(ctx)
+	<-hangingRelease.ch
+	return nil
+}
+
+func TestPluginManager_SwapShutdownTimeout(t *testing.T) {
+	registry := getGlobalPluginRegistry()
+	registry.mu.Lock()
+	registry.pluginTypes = make(map[string]*pluginTypeEntry)
+	registry.mu.Unlock()
+
+	RegisterPluginType("generation", &hangingGenerationPlugin{}, &MockConfig{}, RegisterOptions{AutoDiscover: true, SwapOnReload: true})
+	defer UnregisterPluginType("generation")
+	release := make(chan struct{})
+	hangingRelease.Lock()
+	hangingRelease.ch = release
+	hangingRelease.Unlock()
+	t.Cleanup(func() { close(release) })
+	takeSwapEvents()
+
+	manager := NewPluginManager[SimpleTestConfig]()
+	manager.SetReloadBackoff(0, 0)
+	manager.SetShutdownTimeout(20 * time.Millisecond)
+	ctx := context.Background()
+
+	newConfig := func(value string) *SimpleTestConfig {
+		return &SimpleTestConfig{TestPlugin: MockConfig{BaseConfig: BaseConfig{Type: "generation"}, Value: value}}
+	}
+	config := newConfig("v0")
+	require.NoError(t, manager.DiscoverAndRegister(config))
+	require.NoError(t, manager.Startup(ctx))
+
+	// The hanging previous generation is abandoned after the shutdown timeout
+	start := time.Now()
+	report, err := manager.ReloadWithReport(ctx, config, newConfig("v1"))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, []string{"generation:testplugin"}, report.Reloaded)
+
+	// So is a rejected new generation
+	start = time.Now()
+	_, err = manager.ReloadWithReport(ctx, newConfig("v1"), newConfig("rejected"))
+	assert.ErrorContains(t, err, "handover refused")
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, []string{"start:v0", "start:v1", "swap:v0->v1", "stop:v0", "start:rejected", "stop:rejected"}, takeSwapEvents())
+}
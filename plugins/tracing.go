@@ -0,0 +1,45 @@
+// Package plugins provides a comprehensive plugin management system that supports
+// automatic discovery, registration, and lifecycle management of plugins.
+// This file implements the OpenTelemetry spans recorded for plugin lifecycle calls.
+package plugins
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// SetTracer records a span named "vcfg.plugin.<phase>" for every Startup,
+// Reload and Shutdown call with tracer; nil disables tracing. The span is
+// passed to the plugin in the call's context, so spans created by the plugin
+// become its children. Set it before plugins are started.
+func (pm *PluginManager[T]) SetTracer(tracer trace.Tracer) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.tracer = tracer
+}
+
+// startSpan starts the span of a lifecycle call of entry, a no-op span without a tracer
+func (pm *PluginManager[T]) startSpan(ctx context.Context, key string, entry *PluginEntry, phase LifecyclePhase) (context.Context, trace.Span) {
+	var tracer trace.Tracer = noop.Tracer{}
+	if pm.tracer != nil {
+		tracer = pm.tracer
+	}
+	return tracer.Start(ctx, "vcfg.plugin."+string(phase), trace.WithAttributes(
+		attribute.String("vcfg.plugin.key", key),
+		attribute.String("vcfg.plugin.type", entry.PluginType),
+	))
+}
+
+// endSpan ends the span of a lifecycle call, recording err as its failure
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -4,8 +4,12 @@
 package plugins
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -33,6 +37,67 @@ func getFieldPath(currentPath, fieldName string) string {
 	return fieldName
 }
 
+// pluginTag is the struct tag holding the options of a plugin config field,
+// e.g. `plugin:"name=primary-kafka"`
+const pluginTag = "plugin"
+
+// pluginTagOptions are the options of a plugin tag
+type pluginTagOptions struct {
+	// name replaces the field path in instance names
+	name string
+	// key names the field of slice elements that identifies their instances
+	key string
+}
+
+// parsePluginTag returns the options of the plugin tag of field. The options
+// are comma separated, e.g. `plugin:"name=redis,key=name"`.
+func parsePluginTag(field reflect.StructField) (pluginTagOptions, error) {
+	var options pluginTagOptions
+	for _, option := range strings.Split(field.Tag.Get(pluginTag), ",") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(option, "=")
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "name":
+			// The name is part of the plugin key "pluginType:instanceName"
+			if value == "" || strings.Contains(value, ":") {
+				return options, fmt.Errorf("invalid plugin instance name %q", value)
+			}
+			options.name = value
+		case "key":
+			if value == "" {
+				return options, fmt.Errorf("plugin tag option key requires a field name")
+			}
+			if !isConfigSlice(field.Type) {
+				return options, fmt.Errorf("plugin tag option key requires a slice of plugin configs")
+			}
+			options.key = value
+		default:
+			return options, fmt.Errorf("unknown plugin tag option %q", option)
+		}
+	}
+	return options, nil
+}
+
+// getInstanceName returns the instance name of the plugin config held by field
+// at fieldPath. The name option of the field's plugin tag takes precedence, so
+// instance names stay stable when the config struct is reorganized; otherwise
+// the lowercased field path is used.
+func getInstanceName(field reflect.StructField, fieldPath string) (string, error) {
+	options, err := parsePluginTag(field)
+	if err != nil {
+		return "", err
+	}
+	if options.name != "" {
+		return options.name, nil
+	}
+	return strings.ToLower(fieldPath), nil
+}
+
 // getConfigType extracts the plugin type from a configuration object.
 // It first checks if the config has an embedded BaseConfig with a Type field.
 // If not found, it derives the type from the struct name by removing common suffixes
@@ -110,12 +175,173 @@ func copyConfig[T Config](src, dst T) error {
 			continue
 		}
 
-		dstField.Set(srcField)
+		deepCopyValue(dstField, srcField)
 	}
 
 	return nil
 }
 
+// cloneConfig returns an independently owned deep copy of config as a pointer.
+// config may be a pointer to a config struct or the struct value itself. It
+// returns nil if config is nil or the copy does not implement Config. Plugins
+// receive such copies on Startup and Reload so they may retain them without
+// observing later changes made by the manager.
+func cloneConfig(config any) Config {
+	value := reflect.ValueOf(config)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	clone := reflect.New(value.Type())
+	deepCopyValue(clone.Elem(), value)
+	cfg, _ := clone.Interface().(Config)
+	return cfg
+}
+
+// DeepCopy returns a copy of *v that shares no pointers, slices, maps or
+// interfaces with v. It returns nil if v is nil.
+func DeepCopy[V any](v *V) *V {
+	if v == nil {
+		return nil
+	}
+	clone := new(V)
+	deepCopyValue(reflect.ValueOf(clone).Elem(), reflect.ValueOf(v).Elem())
+	return clone
+}
+
+// deepCopyValue copies src into dst, duplicating pointers, slices, maps and
+// interfaces so that dst shares no mutable state with src. Unexported struct
+// fields are copied by value.
+func deepCopyValue(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+		elem := reflect.New(src.Type().Elem())
+		deepCopyValue(elem.Elem(), src.Elem())
+		dst.Set(elem)
+	case reflect.Interface:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+		inner := src.Elem()
+		elem := reflect.New(inner.Type()).Elem()
+		deepCopyValue(elem, inner)
+		dst.Set(elem)
+	case reflect.Struct:
+		dst.Set(src)
+		for i := range src.NumField() {
+			if dst.Field(i).CanSet() {
+				deepCopyValue(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+		slice := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := range src.Len() {
+			deepCopyValue(slice.Index(i), src.Index(i))
+		}
+		dst.Set(slice)
+	case reflect.Array:
+		for i := range src.Len() {
+			deepCopyValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			elem := reflect.New(src.Type().Elem()).Elem()
+			deepCopyValue(elem, iter.Value())
+			m.SetMapIndex(iter.Key(), elem)
+		}
+		dst.Set(m)
+	default:
+		dst.Set(src)
+	}
+}
+
+// hashConfig computes a stable hash of a configuration value. Pointers and
+// interfaces are followed, map entries are hashed in sorted key order and
+// unexported fields are ignored, so equal configurations always produce the
+// same hash regardless of map iteration order or pointer identity.
+func hashConfig(v any) string {
+	h := sha256.New()
+	writeHashValue(h, reflect.ValueOf(v))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeHashValue writes a canonical, type-tagged representation of v to h.
+func writeHashValue(h hash.Hash, v reflect.Value) {
+	if !v.IsValid() {
+		h.Write([]byte("nil;"))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			h.Write([]byte("nil;"))
+			return
+		}
+		writeHashValue(h, v.Elem())
+
+	case reflect.Struct:
+		t := v.Type()
+		fmt.Fprintf(h, "%s{", t)
+		for i := range v.NumField() {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			fmt.Fprintf(h, "%s:", t.Field(i).Name)
+			writeHashValue(h, v.Field(i))
+		}
+		h.Write([]byte("}"))
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprintf("%#v", keys[i].Interface()) < fmt.Sprintf("%#v", keys[j].Interface())
+		})
+		fmt.Fprintf(h, "map[%d]{", len(keys))
+		for _, key := range keys {
+			writeHashValue(h, key)
+			h.Write([]byte("="))
+			writeHashValue(h, v.MapIndex(key))
+		}
+		h.Write([]byte("}"))
+
+	case reflect.Slice, reflect.Array:
+		fmt.Fprintf(h, "[%d]{", v.Len())
+		for i := range v.Len() {
+			writeHashValue(h, v.Index(i))
+		}
+		h.Write([]byte("}"))
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		// Identity based kinds carry no configuration data
+		fmt.Fprintf(h, "%s;", v.Type())
+
+	default:
+		fmt.Fprintf(h, "%#v;", v.Interface())
+	}
+}
+
 func ToPtr[T any](t T) *T {
 	return &t
 }
@@ -111,6 +111,60 @@ func TestGetFieldPath(t *testing.T) {
 	}
 }
 
+func TestGetInstanceName(t *testing.T) {
+	type tagged struct {
+		Plain    MockConfig
+		Named    MockConfig `plugin:"name=primary-kafka"`
+		Spaced   MockConfig `plugin:" name = primary-kafka "`
+		NoName   MockConfig `plugin:""`
+		Empty    MockConfig `plugin:"name="`
+		Colon    MockConfig `plugin:"name=kafka:primary"`
+		Unknown  MockConfig `plugin:"label=primary"`
+		Trailing MockConfig `plugin:"name=primary-kafka,"`
+		Keyed    MockConfig `plugin:"key=value"`
+	}
+
+	tests := []struct {
+		field       string
+		expected    string
+		expectError bool
+	}{
+		{field: "Plain", expected: "client.plain"},
+		{field: "Named", expected: "primary-kafka"},
+		{field: "Spaced", expected: "primary-kafka"},
+		{field: "NoName", expected: "client.noname"},
+		{field: "Empty", expectError: true},
+		{field: "Colon", expectError: true},
+		{field: "Unknown", expectError: true},
+		{field: "Trailing", expected: "primary-kafka"},
+		{field: "Keyed", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := reflect.TypeOf(tagged{}).FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+
+			result, err := getInstanceName(field, getFieldPath("Client", tt.field))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("getInstanceName(%s) expected error but got %q", tt.field, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("getInstanceName(%s) unexpected error = %v", tt.field, err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("getInstanceName(%s) = %q, want %q", tt.field, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestGetConfigType(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -319,3 +373,102 @@ func contains(s, substr string) bool {
 			return false
 		}()))
 }
+
+// TestHashConfig tests that config hashes are stable and content based
+func TestHashConfig(t *testing.T) {
+	type MapConfig struct {
+		BaseConfig
+		Labels  map[string]string
+		Servers []string
+		Limit   *int
+	}
+
+	limit := 10
+	otherLimit := 10
+
+	a := &MapConfig{Labels: map[string]string{"a": "1", "b": "2", "c": "3"}, Servers: []string{"x"}, Limit: &limit}
+	b := &MapConfig{Labels: map[string]string{"c": "3", "b": "2", "a": "1"}, Servers: []string{"x"}, Limit: &otherLimit}
+
+	// Same content hashes the same regardless of map order and pointer identity
+	for range 10 {
+		if hashConfig(a) != hashConfig(b) {
+			t.Fatal("expected equal hashes for equal configs")
+		}
+	}
+
+	// Any change alters the hash
+	b.Labels["b"] = "changed"
+	if hashConfig(a) == hashConfig(b) {
+		t.Error("expected different hashes after map change")
+	}
+
+	c := &MapConfig{Labels: a.Labels, Servers: []string{"x", "y"}, Limit: &limit}
+	if hashConfig(a) == hashConfig(c) {
+		t.Error("expected different hashes after slice change")
+	}
+
+	// Values of different types are not confused
+	if hashConfig(&TestConfig{Name: "1"}) == hashConfig(&TestConfig{Value: 1}) {
+		t.Error("expected different hashes for different fields")
+	}
+}
+
+// TestCloneConfig tests that cloned configs share no mutable state with the source
+func TestCloneConfig(t *testing.T) {
+	type MapConfig struct {
+		BaseConfig
+		Labels  map[string]string
+		Servers []string
+		Limit   *int
+	}
+
+	limit := 10
+	src := &MapConfig{Labels: map[string]string{"a": "1"}, Servers: []string{"x"}, Limit: &limit}
+
+	clone, ok := cloneConfig(src).(*MapConfig)
+	if !ok {
+		t.Fatalf("cloneConfig() returned %T, want *MapConfig", cloneConfig(src))
+	}
+	if !reflect.DeepEqual(src, clone) {
+		t.Fatalf("cloneConfig() = %+v, want %+v", clone, src)
+	}
+
+	src.Labels["a"] = "changed"
+	src.Servers[0] = "changed"
+	*src.Limit = 20
+	if clone.Labels["a"] != "1" || clone.Servers[0] != "x" || *clone.Limit != 10 {
+		t.Errorf("clone observed source mutation: %+v", clone)
+	}
+
+	// Struct values are cloned into a pointer
+	if _, ok := cloneConfig(TestConfig{Name: "value"}).(*TestConfig); !ok {
+		t.Error("expected struct value to be cloned into *TestConfig")
+	}
+
+	if cloneConfig(nil) != nil {
+		t.Error("expected nil clone for nil config")
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	type Nested struct {
+		Labels map[string][]string
+		Any    any
+	}
+	src := &Nested{Labels: map[string][]string{"a": {"1"}}, Any: []int{1}}
+
+	clone := DeepCopy(src)
+	if clone == src || !reflect.DeepEqual(src, clone) {
+		t.Fatalf("DeepCopy() = %+v, want an equal copy of %+v", clone, src)
+	}
+
+	src.Labels["a"][0] = "changed"
+	src.Any.([]int)[0] = 2
+	if clone.Labels["a"][0] != "1" || clone.Any.([]int)[0] != 1 {
+		t.Errorf("copy observed source mutation: %+v", clone)
+	}
+
+	if DeepCopy[Nested](nil) != nil {
+		t.Error("expected nil copy of nil")
+	}
+}
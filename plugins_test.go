@@ -0,0 +1,234 @@
+package vcfg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nextpkg/vcfg/plugins"
+)
+
+// recorderConfig is a plugin configuration used by manager level plugin tests
+type recorderConfig struct {
+	plugins.BaseConfig `koanf:",squash"`
+	Host               string `koanf:"host"`
+	Port               int    `koanf:"port" default:"6379"`
+}
+
+// recorderPlugin records every configuration it receives
+type recorderPlugin struct {
+	mu       sync.Mutex
+	startups []recorderConfig
+	reloads  []recorderConfig
+	shutdown int
+}
+
+func (p *recorderPlugin) Startup(ctx context.Context, config any) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.startups = append(p.startups, *config.(*recorderConfig))
+	return nil
+}
+
+func (p *recorderPlugin) Reload(ctx context.Context, config any) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reloads = append(p.reloads, *config.(*recorderConfig))
+	return nil
+}
+
+func (p *recorderPlugin) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.shutdown++
+	return nil
+}
+
+func (p *recorderPlugin) reloadCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.reloads)
+}
+
+func (p *recorderPlugin) lastReload() recorderConfig {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reloads[len(p.reloads)-1]
+}
+
+func init() {
+	plugins.RegisterPluginType("", &recorderPlugin{}, &recorderConfig{})
+}
+
+// RecorderAppConfig is an application config holding a single recorder plugin
+type RecorderAppConfig struct {
+	Name  string         `koanf:"name"`
+	Cache recorderConfig `koanf:"cache"`
+}
+
+// getRecorder returns the recorder plugin instance registered for the given key
+func getRecorder[T any](t *testing.T, cm *ConfigManager[T], key string) *recorderPlugin {
+	t.Helper()
+	entry, ok := cm.pluginManager.Clone()[key]
+	require.True(t, ok, "plugin %s not registered", key)
+	return entry.Plugin.(*recorderPlugin)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestReload_PreservesEnvOverridesForPlugins(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "cache:\n  host: filehost\n  port: 1000\n")
+
+	t.Setenv("RECORDER_CACHE_PORT", "2000")
+
+	cm, err := NewBuilder[RecorderAppConfig]().
+		AddFile(configFile).
+		AddEnv("RECORDER_").
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	plugin := getRecorder(t, cm, "recorder:cache")
+	require.Len(t, plugin.startups, 1)
+	assert.Equal(t, "filehost", plugin.startups[0].Host)
+	assert.Equal(t, 2000, plugin.startups[0].Port)
+
+	// Change only the file; the env override must survive the reload
+	writeFile(t, configFile, "cache:\n  host: newhost\n  port: 1001\n")
+	require.NoError(t, cm.reload(context.Background()))
+
+	require.Equal(t, 1, plugin.reloadCount())
+	reloaded := plugin.lastReload()
+	assert.Equal(t, "newhost", reloaded.Host)
+	assert.Equal(t, 2000, reloaded.Port)
+	assert.Equal(t, 2000, cm.Get().Cache.Port)
+
+	// A file change that is fully masked by the env override is not a plugin change
+	writeFile(t, configFile, "cache:\n  host: newhost\n  port: 1002\n")
+	require.NoError(t, cm.reload(context.Background()))
+	assert.Equal(t, 1, plugin.reloadCount())
+}
+
+// lazyConfig is the configuration of a plugin registered with LazyStart
+type lazyConfig struct {
+	plugins.BaseConfig `koanf:",squash"`
+	DSN                string `koanf:"dsn"`
+}
+
+// lazyPlugin counts how often it was started
+type lazyPlugin struct {
+	startups int
+}
+
+func (p *lazyPlugin) Startup(ctx context.Context, config any) error {
+	p.startups++
+	return nil
+}
+
+func (p *lazyPlugin) Reload(ctx context.Context, config any) error { return nil }
+
+func (p *lazyPlugin) Shutdown(ctx context.Context) error { return nil }
+
+func init() {
+	plugins.RegisterPluginType("", &lazyPlugin{}, &lazyConfig{}, plugins.RegisterOptions{AutoDiscover: true, LazyStart: true})
+}
+
+func TestBuilder_LazyPluginStartsOnDemand(t *testing.T) {
+	type AppConfig struct {
+		Warehouse lazyConfig `koanf:"warehouse"`
+	}
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "warehouse:\n  dsn: expensive://db\n")
+
+	cm, err := NewBuilder[AppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	entry := cm.pluginManager.Clone()["lazy:warehouse"]
+	require.NotNil(t, entry)
+	plugin := entry.Plugin.(*lazyPlugin)
+	assert.Equal(t, 0, plugin.startups)
+
+	require.NoError(t, cm.EnsurePluginStarted(context.Background(), "lazy:warehouse"))
+	assert.Equal(t, 1, plugin.startups)
+
+	require.NoError(t, cm.EnsurePluginStarted(context.Background(), "lazy:warehouse"))
+	assert.Equal(t, 1, plugin.startups)
+
+	assert.Error(t, cm.EnsurePluginStarted(context.Background(), "lazy:missing"))
+}
+
+func TestBuilder_PluginConfigCopyFailureIsTyped(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	// The cache field is a recorderConfig, but its type tag names the lazy plugin
+	writeFile(t, configFile, "cache:\n  type: lazy\n  host: filehost\n")
+
+	_, err := NewBuilder[RecorderAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.Error(t, err)
+
+	var configErr *ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, ErrorTypePluginFailure, configErr.Type)
+	assert.Equal(t, "Cache", configErr.Source)
+	assert.ErrorIs(t, err, NewPluginError("", "", nil))
+	assert.Contains(t, err.Error(), "*vcfg.lazyConfig")
+}
+
+func TestConfigManager_EnablePluginsConcurrent(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "cache:\n  host: localhost\n")
+
+	cm, err := NewBuilder[RecorderAppConfig]().
+		AddFile(configFile).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- cm.EnablePlugins()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Len(t, cm.PluginStatus(), 1)
+
+	// Calling again after registration is a no-op
+	require.NoError(t, cm.EnablePlugins())
+	require.NoError(t, cm.StartPlugins(context.Background()))
+	assert.Len(t, getRecorder(t, cm, "recorder:cache").startups, 1)
+
+	// Plugins registered by Build are not registered again
+	built, err := NewBuilder[RecorderAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer built.Close()
+	assert.NoError(t, built.EnablePlugins())
+}
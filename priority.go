@@ -0,0 +1,43 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements source priorities, which order the sources by kind
+// instead of by registration order.
+package vcfg
+
+import (
+	"sort"
+
+	"github.com/nextpkg/vcfg/providers"
+)
+
+// Source kinds for WithPriority, as reported by SourceInfo.Kind.
+const (
+	// SourceDefaults are defaults files added with AddDefaultsFile
+	SourceDefaults = providers.KindDefaults
+	// SourceFile are local files, file sets and directories
+	SourceFile = providers.KindFile
+	// SourceEnv are environment variables and env blobs
+	SourceEnv = providers.KindEnv
+	// SourceFlags are command line flags
+	SourceFlags = providers.KindFlags
+	// SourceRemote are remote services such as Consul, Vault, etcd or HTTP
+	SourceRemote = providers.KindRemote
+	// SourceCustom are all other providers
+	SourceCustom = providers.KindCustom
+)
+
+// sortByPriority stably reorders configs so that sources of kinds listed in
+// priority are merged after all others, in the listed order. Sources of the
+// same kind keep their registration order.
+func sortByPriority(configs []providers.ProviderConfig, priority []string) {
+	if len(priority) == 0 {
+		return
+	}
+
+	rank := make(map[string]int, len(priority))
+	for i, kind := range priority {
+		rank[kind] = i + 1
+	}
+	sort.SliceStable(configs, func(i, j int) bool {
+		return rank[configs[i].Kind] < rank[configs[j].Kind]
+	})
+}
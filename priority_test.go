@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/knadh/koanf/providers/rawbytes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -72,3 +73,61 @@ func TestConfigSourcePriority(t *testing.T) {
 	assert.Equal(t, "filehost", cfg3.Server.Host) // Should be overridden by file
 	assert.Equal(t, 8080, cfg3.Server.Port)       // Should be overridden by file
 }
+
+type PriorityTestConfig struct {
+	Name  string `koanf:"name"`
+	Port  int    `koanf:"port"`
+	Debug bool   `koanf:"debug"`
+}
+
+func TestBuilder_WithPriority(t *testing.T) {
+	dir := t.TempDir()
+	defaultsFile := filepath.Join(dir, "defaults.yaml")
+	configFile := filepath.Join(dir, "config.yaml")
+	localFile := filepath.Join(dir, "local.yaml")
+	writeFile(t, defaultsFile, "name: defaults\nport: 1\ndebug: true\n")
+	writeFile(t, configFile, "name: file\nport: 80\n")
+	writeFile(t, localFile, "port: 81\n")
+	t.Setenv("VCFGPRIO_PORT", "8080")
+	t.Setenv("VCFGPRIO_NAME", "env")
+
+	// Registered last, the file would win by default
+	cm, err := NewBuilder[PriorityTestConfig]().
+		AddEnv("VCFGPRIO_").
+		AddProvider(rawbytes.Provider([]byte(`{"port": 9090}`))).
+		AddFile(configFile).
+		AddFile(localFile).
+		AddDefaultsFile(defaultsFile).
+		WithPriority(SourceFile, SourceEnv).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, PriorityTestConfig{Name: "env", Port: 8080, Debug: true}, *cm.Get())
+
+	var kinds, names []string
+	for _, source := range cm.Providers() {
+		kinds = append(kinds, source.Kind)
+		names = append(names, source.Name)
+	}
+	assert.Equal(t, []string{SourceDefaults, SourceCustom, SourceFile, SourceFile, SourceEnv}, kinds)
+	assert.Equal(t, []string{configFile, localFile, "env:VCFGPRIO_"}, names[2:])
+
+	source, _ := cm.Origin("port")
+	assert.Equal(t, "env:VCFGPRIO_", source)
+	source, _ = cm.Origin("debug")
+	assert.Equal(t, "defaults:"+defaultsFile, source)
+
+	// Defaults can be raised like any other kind
+	cm, err = New[PriorityTestConfig](
+		WithFile(configFile),
+		WithDefaultsFile(defaultsFile),
+		WithPriority(SourceFile, SourceDefaults),
+	)
+	require.NoError(t, err)
+	defer cm.Close()
+	assert.Equal(t, "defaults", cm.Get().Name)
+
+	_, err = NewBuilder[PriorityTestConfig]().AddFile(configFile).WithPriority(SourceEnv, SourceEnv).Build(context.Background())
+	assert.ErrorContains(t, err, "repeated source kind")
+}
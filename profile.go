@@ -0,0 +1,46 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements profile overlays: optional per-environment files such as
+// config.prod.yaml layered over each file source.
+package vcfg
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/nextpkg/vcfg/providers"
+)
+
+// profilePath returns the path of the profile overlay of a file, e.g.
+// config.prod.yaml for config.yaml and profile "prod"
+func profilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
+
+// withProfileOverlays returns sources with the profile overlay of every file
+// source inserted right after it. Overlays are optional: missing files are
+// skipped when loading.
+func withProfileOverlays(sources []any, profile string) ([]any, error) {
+	if profile == "" {
+		return sources, nil
+	}
+
+	layered := make([]any, 0, 2*len(sources))
+	for _, source := range sources {
+		layered = append(layered, source)
+
+		path, ok := source.(string)
+		if !ok {
+			continue
+		}
+		overlay, err := providers.NewFileSet([]string{profilePath(path, profile)})
+		if err != nil {
+			return nil, err
+		}
+		layered = append(layered, providers.ProviderConfig{
+			Name:     "profile:" + overlay.Paths()[0],
+			Provider: overlay,
+		})
+	}
+	return layered, nil
+}
@@ -0,0 +1,58 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_WithProfile(t *testing.T) {
+	type ProfileConfig struct {
+		Name  string `koanf:"name"`
+		Port  int    `koanf:"port"`
+		Debug bool   `koanf:"debug"`
+		Cache string `koanf:"cache"`
+	}
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	cacheFile := filepath.Join(dir, "cache.json")
+	writeFile(t, configFile, "name: app\nport: 80\ndebug: true\n")
+	writeFile(t, filepath.Join(dir, "config.prod.yaml"), "port: 8080\ndebug: false\n")
+	writeFile(t, cacheFile, `{"cache": "local", "port": 9090}`)
+	writeFile(t, filepath.Join(dir, "config.dev.yaml"), "name: dev\n")
+
+	// The overlay of config.yaml is merged before cache.json; cache.prod.json is missing
+	cm, err := NewBuilder[ProfileConfig]().
+		AddFile(configFile).
+		AddFile(cacheFile).
+		WithProfile("prod").
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	assert.Equal(t, ProfileConfig{Name: "app", Port: 9090, Debug: false, Cache: "local"}, *cm.Get())
+	require.Len(t, cm.Providers(), 4)
+	assert.Equal(t, "profile:"+filepath.Join(dir, "config.prod.yaml"), cm.Providers()[1].Name)
+	source, _ := cm.Origin("debug")
+	assert.Equal(t, cm.Providers()[1].Name, source)
+
+	// Without a profile only the base files are loaded
+	cm, err = New[ProfileConfig](WithFile(configFile), WithProfile(""))
+	require.NoError(t, err)
+	defer cm.Close()
+	assert.Equal(t, ProfileConfig{Name: "app", Port: 80, Debug: true}, *cm.Get())
+	assert.Len(t, cm.Providers(), 1)
+
+	_, err = NewBuilder[ProfileConfig]().AddFile(configFile).WithProfile("../prod").Build(context.Background())
+	assert.ErrorContains(t, err, "invalid profile name")
+}
+
+func TestProfilePath(t *testing.T) {
+	assert.Equal(t, "config.prod.yaml", profilePath("config.yaml", "prod"))
+	assert.Equal(t, filepath.Join("etc", "app.dev.json"), profilePath(filepath.Join("etc", "app.json"), "dev"))
+	assert.Equal(t, "config.prod", profilePath("config", "prod"))
+}
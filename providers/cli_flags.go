@@ -0,0 +1,69 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements CliFlags, a provider for the flags of a urfave/cli
+// command that reports only flags set explicitly.
+package providers
+
+import (
+	"errors"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+	"github.com/urfave/cli/v3"
+)
+
+// CliFlags is a provider that reads the flags of a urfave/cli command and of
+// its parent commands. Only flags that were set, on the command line or
+// through a flag's environment sources, are reported, so flags left at their
+// default values don't override the values of other sources.
+//
+// Flag names map to configuration keys split by the delimiter, so the flag
+// --server.port sets the key server.port when the delimiter is ".". A flag of a
+// subcommand takes precedence over a parent flag of the same name.
+type CliFlags struct {
+	cmd   *cli.Command
+	delim string
+}
+
+// NewCliFlags creates a provider for the flags of cmd that have been set.
+func NewCliFlags(cmd *cli.Command, delim string) *CliFlags {
+	return &CliFlags{cmd: cmd, delim: delim}
+}
+
+// Name returns an identifier for the provider, e.g. "cli:serve".
+func (c *CliFlags) Name() string {
+	return "cli:" + c.cmd.Name
+}
+
+// Read implements the koanf.Provider interface by returning the values of the
+// flags that were set.
+func (c *CliFlags) Read() (map[string]any, error) {
+	values := make(map[string]any)
+
+	// Walk from the root command so subcommand flags are applied last
+	lineage := c.cmd.Lineage()
+	for i := len(lineage) - 1; i >= 0; i-- {
+		for _, flag := range lineage[i].Flags {
+			if !flag.IsSet() || len(flag.Names()) == 0 {
+				continue
+			}
+			values[flag.Names()[0]] = flag.Get()
+		}
+	}
+
+	if c.delim == "" {
+		return values, nil
+	}
+	return maps.Unflatten(values, c.delim), nil
+}
+
+// ReadBytes implements the koanf.Provider interface but is not supported.
+func (c *CliFlags) ReadBytes() ([]byte, error) {
+	return nil, errors.New("ReadBytes method not implemented, use Read instead")
+}
+
+// RequiredParser implements the ParserProvider interface. Flag values are
+// read directly, so no parser is required.
+func (c *CliFlags) RequiredParser() koanf.Parser {
+	return nil
+}
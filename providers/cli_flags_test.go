@@ -0,0 +1,68 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestCliFlags_Read(t *testing.T) {
+	var data map[string]any
+	root := &cli.Command{
+		Name: "app",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "name", Value: "default"},
+			&cli.IntFlag{Name: "server.port", Value: 80},
+			&cli.StringFlag{Name: "log.level", Value: "info"},
+		},
+		Commands: []*cli.Command{{
+			Name: "serve",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{Name: "debug"},
+				&cli.StringFlag{Name: "log.level", Value: "warn"},
+			},
+			Action: func(ctx context.Context, cmd *cli.Command) error {
+				p := NewCliFlags(cmd, ".")
+				assert.Equal(t, "cli:serve", p.Name())
+				assert.Equal(t, KindFlags, ProviderKind(p))
+				assert.Nil(t, p.RequiredParser())
+
+				var err error
+				data, err = p.Read()
+				return err
+			},
+		}},
+	}
+
+	args := []string{"app", "--server.port", "9090", "--log.level", "debug", "serve", "--log.level", "error"}
+	require.NoError(t, root.Run(context.Background(), args))
+
+	// Unset flags are left out, parent flags are included and the
+	// subcommand's flag wins over the parent flag of the same name
+	assert.Equal(t, map[string]any{
+		"server": map[string]any{"port": 9090},
+		"log":    map[string]any{"level": "error"},
+	}, data)
+}
+
+func TestCliFlags_EmptyDelimiter(t *testing.T) {
+	var data map[string]any
+	cmd := &cli.Command{
+		Name:  "app",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "server.host"}},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			var err error
+			data, err = NewCliFlags(cmd, "").Read()
+			return err
+		},
+	}
+
+	require.NoError(t, cmd.Run(context.Background(), []string{"app", "--server.host", "example.com"}))
+	assert.Equal(t, map[string]any{"server.host": "example.com"}, data)
+
+	_, err := NewCliFlags(cmd, "").ReadBytes()
+	assert.Error(t, err)
+}
@@ -169,3 +169,9 @@ func (w *CliProviderWrapper) Read() (map[string]any, error) {
 func (w *CliProviderWrapper) ReadBytes() ([]byte, error) {
 	return w.original.ReadBytes()
 }
+
+// RequiredParser implements the ParserProvider interface. Flag values are
+// read directly, so no parser is required.
+func (w *CliProviderWrapper) RequiredParser() koanf.Parser {
+	return nil
+}
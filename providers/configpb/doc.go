@@ -0,0 +1,5 @@
+// Package configpb contains the generated gRPC client and server code for the
+// ConfigService consumed by providers.GRPCProvider.
+package configpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative config.proto
@@ -0,0 +1,299 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements a provider that reads configuration from the Consul KV
+// store and watches it with blocking queries.
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	// consulRequestTimeout bounds a plain read of the KV store
+	consulRequestTimeout = 10 * time.Second
+	// consulDefaultWait is how long a blocking query waits for a change
+	consulDefaultWait = 5 * time.Minute
+	// consulRetryDelay is the pause after a failed blocking query
+	consulRetryDelay = time.Second
+)
+
+// ConsulOption configures a ConsulProvider.
+type ConsulOption func(*ConsulProvider)
+
+// WithConsulToken sets the ACL token sent with every request.
+func WithConsulToken(token string) ConsulOption {
+	return func(c *ConsulProvider) {
+		c.token = token
+	}
+}
+
+// WithConsulDatacenter reads from the given datacenter instead of the agent's own.
+func WithConsulDatacenter(datacenter string) ConsulOption {
+	return func(c *ConsulProvider) {
+		c.datacenter = datacenter
+	}
+}
+
+// WithConsulHTTPClient sets the HTTP client used for requests, e.g. one with TLS
+// client certificates. Its timeout must exceed the blocking query wait time.
+func WithConsulHTTPClient(client *http.Client) ConsulOption {
+	return func(c *ConsulProvider) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
+// WithConsulWaitTime sets how long a blocking query waits for a change before
+// it is re-issued. Consul caps the wait at 10 minutes.
+func WithConsulWaitTime(wait time.Duration) ConsulOption {
+	return func(c *ConsulProvider) {
+		if wait > 0 {
+			c.wait = wait
+		}
+	}
+}
+
+// ConsulProvider reads all keys below a prefix from the Consul KV store and nests
+// them by their path segments, e.g. with the prefix "app/" the key
+// "app/server/port" becomes server.port. Watch uses blocking queries, so changes
+// are reported as soon as Consul commits them.
+type ConsulProvider struct {
+	// client performs the HTTP requests
+	client *http.Client
+	// endpoint is the URL of the KV entries below the prefix
+	endpoint *url.URL
+	// prefix is the key prefix holding the configuration
+	prefix string
+	// token is the ACL token, empty for anonymous access
+	token string
+	// datacenter selects the datacenter to read from, empty for the agent's own
+	datacenter string
+	// wait is how long a blocking query waits for a change
+	wait time.Duration
+
+	// mu protects the watch state
+	mu sync.Mutex
+	// cancel stops the watch loop, nil if not watching
+	cancel context.CancelFunc
+	// done is closed when the watch loop has exited
+	done chan struct{}
+}
+
+// consulKVPair is an entry of a Consul KV list response
+type consulKVPair struct {
+	Key   string
+	Value []byte
+}
+
+// NewConsulProvider creates a provider for the keys below prefix on the Consul
+// agent at addr, e.g. "http://localhost:8500". An address without scheme uses http.
+func NewConsulProvider(addr, prefix string, opts ...ConsulOption) (*ConsulProvider, error) {
+	if addr == "" {
+		return nil, errors.New("consul provider requires an address")
+	}
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+
+	base, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid consul address %s: %w", addr, err)
+	}
+
+	c := &ConsulProvider{
+		client:   &http.Client{},
+		endpoint: base.JoinPath("v1", "kv", prefix),
+		prefix:   prefix,
+		wait:     consulDefaultWait,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+
+	return c, nil
+}
+
+// Name returns an identifier for the provider, e.g. "consul:app/".
+func (c *ConsulProvider) Name() string {
+	return "consul:" + c.prefix
+}
+
+// Read implements the koanf.Provider interface by listing all keys below the
+// prefix. Values are returned as strings and converted to the field types when
+// the configuration is unmarshaled.
+func (c *ConsulProvider) Read() (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), consulRequestTimeout)
+	defer cancel()
+
+	pairs, _, err := c.list(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	flat := make(map[string]any, len(pairs))
+	for _, pair := range pairs {
+		path := strings.Trim(strings.TrimPrefix(pair.Key, c.prefix), "/")
+		if path == "" || strings.HasSuffix(pair.Key, "/") {
+			continue // Folders carry no value
+		}
+		flat[path] = string(pair.Value)
+	}
+
+	return maps.Unflatten(flat, "/"), nil
+}
+
+// ReadBytes implements the koanf.Provider interface but is not supported,
+// since the configuration is spread over many keys.
+func (c *ConsulProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("ReadBytes method not implemented, use Read instead")
+}
+
+// RequiredParser implements the ParserProvider interface. Read returns nested
+// values itself, so no parser is required.
+func (c *ConsulProvider) RequiredParser() koanf.Parser {
+	return nil
+}
+
+// list fetches the entries below the prefix and the KV index they reflect. With
+// a non-zero index the request is a blocking query that returns once the index
+// moves past it or the wait time elapses.
+func (c *ConsulProvider) list(ctx context.Context, index uint64) ([]consulKVPair, uint64, error) {
+	query := url.Values{}
+	query.Set("recurse", "true")
+	if c.datacenter != "" {
+		query.Set("dc", c.datacenter)
+	}
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", c.wait.String())
+	}
+
+	endpoint := *c.endpoint
+	endpoint.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read consul prefix %s: %w", c.prefix, err)
+	}
+	defer resp.Body.Close()
+
+	newIndex, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// No keys below the prefix yet
+		return nil, newIndex, nil
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, 0, fmt.Errorf("failed to read consul prefix %s: %s: %s",
+			c.prefix, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var pairs []consulKVPair
+	if err := json.NewDecoder(resp.Body).Decode(&pairs); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode consul response for prefix %s: %w", c.prefix, err)
+	}
+	return pairs, newIndex, nil
+}
+
+// Watch starts blocking queries on the prefix and calls cb with the new KV index
+// whenever it changes. Failed queries are reported through cb and retried after
+// a short delay until Unwatch is called.
+func (c *ConsulProvider) Watch(cb func(event any, err error)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		return nil // Already watching
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	go c.watchLoop(ctx, cb, c.done)
+
+	return nil
+}
+
+// watchLoop issues blocking queries until ctx is cancelled
+func (c *ConsulProvider) watchLoop(ctx context.Context, cb func(event any, err error), done chan struct{}) {
+	defer close(done)
+
+	var index uint64
+	for {
+		_, newIndex, err := c.list(ctx, index)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			cb(nil, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(consulRetryDelay):
+			}
+			continue
+		}
+
+		switch {
+		case index == 0:
+			// The first query establishes the baseline
+		case newIndex < index:
+			// The index went backwards, e.g. after a snapshot restore; start over
+			cb(newIndex, nil)
+		case newIndex > index:
+			cb(newIndex, nil)
+		}
+
+		if newIndex == 0 {
+			// Without an index blocking is not possible; avoid a busy loop
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(consulRetryDelay):
+			}
+		}
+		index = newIndex
+	}
+}
+
+// Unwatch stops watching and waits for the watch loop to exit.
+func (c *ConsulProvider) Unwatch() error {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.cancel, c.done = nil, nil
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return nil // Not watching
+	}
+
+	cancel()
+	<-done
+	return nil
+}
@@ -0,0 +1,175 @@
+package providers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConsul serves the KV list endpoint with blocking query support
+type fakeConsul struct {
+	mu      sync.Mutex
+	index   uint64
+	pairs   map[string]string
+	changed chan struct{}
+	// requests records the query and token of every request
+	requests []*http.Request
+}
+
+func newFakeConsul(pairs map[string]string) *fakeConsul {
+	return &fakeConsul{index: 1, pairs: pairs, changed: make(chan struct{})}
+}
+
+// set updates a key and wakes up blocking queries
+func (f *fakeConsul) set(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pairs[key] = value
+	f.index++
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r)
+	changed := f.changed
+	index := f.index
+	f.mu.Unlock()
+
+	if r.Header.Get("X-Consul-Token") != "secret" {
+		http.Error(w, "ACL not found", http.StatusForbidden)
+		return
+	}
+
+	// Block until the index moves past the requested one or the wait elapses
+	if waitIndex, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64); waitIndex >= index {
+		wait, _ := time.ParseDuration(r.URL.Query().Get("wait"))
+		select {
+		case <-changed:
+		case <-time.After(wait):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prefix := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	type pair struct {
+		Key   string
+		Value []byte
+	}
+	var pairs []pair
+	for key, value := range f.pairs {
+		if strings.HasPrefix(key, prefix) {
+			pairs = append(pairs, pair{Key: key, Value: []byte(value)})
+		}
+	}
+
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(f.index, 10))
+	if len(pairs) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(pairs)
+}
+
+func (f *fakeConsul) lastRequest() *http.Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[len(f.requests)-1]
+}
+
+func TestConsulProvider_Read(t *testing.T) {
+	consul := newFakeConsul(map[string]string{
+		"app/":            "",
+		"app/name":        "demo",
+		"app/server/port": "8080",
+		"app/tls/":        "",
+		"application/x":   "other",
+	})
+	server := httptest.NewServer(consul)
+	defer server.Close()
+
+	provider, err := NewConsulProvider(server.URL, "app/", WithConsulToken("secret"), WithConsulDatacenter("dc2"))
+	require.NoError(t, err)
+	assert.Equal(t, "consul:app/", provider.Name())
+	assert.Nil(t, provider.RequiredParser())
+
+	data, err := provider.Read()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":   "demo",
+		"server": map[string]any{"port": "8080"},
+	}, data)
+
+	req := consul.lastRequest()
+	assert.Equal(t, "/v1/kv/app/", req.URL.Path)
+	assert.Equal(t, "dc2", req.URL.Query().Get("dc"))
+	assert.Equal(t, "true", req.URL.Query().Get("recurse"))
+
+	// An empty prefix is not an error
+	empty, err := NewConsulProvider(strings.TrimPrefix(server.URL, "http://"), "missing/", WithConsulToken("secret"))
+	require.NoError(t, err)
+	data, err = empty.Read()
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	// Errors include the status returned by Consul
+	denied, err := NewConsulProvider(server.URL, "app/")
+	require.NoError(t, err)
+	_, err = denied.Read()
+	assert.ErrorContains(t, err, "403 Forbidden: ACL not found")
+
+	_, err = NewConsulProvider("", "app/")
+	assert.Error(t, err)
+}
+
+func TestConsulProvider_Watch(t *testing.T) {
+	consul := newFakeConsul(map[string]string{"app/name": "demo"})
+	server := httptest.NewServer(consul)
+	defer server.Close()
+
+	provider, err := NewConsulProvider(server.URL, "app/",
+		WithConsulToken("secret"),
+		WithConsulWaitTime(time.Minute),
+	)
+	require.NoError(t, err)
+
+	received := make(chan any, 4)
+	require.NoError(t, provider.Watch(func(event any, err error) {
+		if err == nil {
+			received <- event
+		}
+	}))
+	// A second Watch is a no-op while watching
+	require.NoError(t, provider.Watch(func(any, error) {}))
+
+	// Wait for the blocking query to be issued
+	require.Eventually(t, func() bool {
+		return consul.lastRequest().URL.Query().Get("index") == "1"
+	}, 5*time.Second, time.Millisecond)
+	assert.Equal(t, "1m0s", consul.lastRequest().URL.Query().Get("wait"))
+
+	consul.set("app/name", "changed")
+	select {
+	case event := <-received:
+		assert.Equal(t, uint64(2), event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("change was not reported")
+	}
+
+	require.NoError(t, provider.Unwatch())
+	require.NoError(t, provider.Unwatch())
+	assert.Empty(t, received)
+}
@@ -8,7 +8,6 @@ import (
 	"errors"
 
 	"github.com/knadh/koanf/parsers/json"
-	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/v2"
 )
 
@@ -74,5 +73,5 @@ func (p *CustomYAMLProvider) Read() (map[string]interface{}, error) {
 
 // RequiredParser implements ParserProvider interface
 func (p *CustomYAMLProvider) RequiredParser() koanf.Parser {
-	return yaml.Parser()
+	return YAMLParser()
 }
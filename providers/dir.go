@@ -0,0 +1,211 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements Dir, a provider that merges the files of a drop-in
+// configuration directory such as conf.d.
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+)
+
+// Dir treats the files of a directory that match a glob pattern as a single
+// configuration source. The matching files are merged in lexical order of their
+// names, so fragments are usually prefixed with a number, e.g. "10-base.yaml"
+// and "50-local.yaml". Each file is parsed according to its extension, and
+// hidden files and subdirectories are ignored.
+//
+// The directory is listed on every read, so fragments added or removed while the
+// application runs are picked up by the next reload. A missing directory has no
+// fragments.
+type Dir struct {
+	// dir is the absolute path of the directory
+	dir string
+	// pattern selects the files of the directory, e.g. "*.yaml"
+	pattern string
+
+	mu       sync.Mutex
+	watcher  *fsnotify.Watcher
+	callback func(event any, err error)
+}
+
+// NewDir creates a Dir for the files of dir matching pattern, using the syntax
+// of filepath.Match. An empty pattern matches all files.
+func NewDir(dir, pattern string) (*Dir, error) {
+	if pattern == "" {
+		pattern = "*"
+	}
+	if strings.ContainsRune(pattern, filepath.Separator) {
+		return nil, fmt.Errorf("pattern %q must match file names, not paths", pattern)
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dir{dir: absDir, pattern: pattern}, nil
+}
+
+// Name returns an identifier for the directory source built from its absolute
+// path and pattern, e.g. "dir:/etc/app/conf.d/*.yaml".
+func (d *Dir) Name() string {
+	return "dir:" + filepath.Join(d.dir, d.pattern)
+}
+
+// Files returns the absolute paths of the matching files in merge order.
+func (d *Dir) Files() ([]string, error) {
+	entries, err := os.ReadDir(d.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", d.dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !d.matches(entry.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(d.dir, entry.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Read implements the koanf.Provider interface by parsing and merging the
+// matching files in order.
+func (d *Dir) Read() (map[string]any, error) {
+	files, err := d.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	factory := NewProviderFactory()
+	k := koanf.New(".")
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			// Removed since the directory was listed
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if err := k.Load(rawbytes.Provider(data), factory.getParserForFile(path)); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	return k.Raw(), nil
+}
+
+// ReadBytes implements the koanf.Provider interface but is not supported,
+// since the files of a directory may use different formats.
+func (d *Dir) ReadBytes() ([]byte, error) {
+	return nil, errors.New("ReadBytes method not implemented, use Read instead")
+}
+
+// RequiredParser implements the ParserProvider interface. The directory parses
+// its files itself, so no parser is required.
+func (d *Dir) RequiredParser() koanf.Parser {
+	return nil
+}
+
+// Watch starts watching the directory and calls cb when a matching file is
+// created, written, renamed or removed. The directory must exist.
+func (d *Dir) Watch(cb func(event any, err error)) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.watcher != nil {
+		return nil // Already watching
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(d.dir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %w", d.dir, err)
+	}
+
+	d.watcher = watcher
+	d.callback = cb
+	go d.processEvents(watcher)
+
+	return nil
+}
+
+// Unwatch stops watching the directory.
+func (d *Dir) Unwatch() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.watcher == nil {
+		return nil // Not watching
+	}
+
+	err := d.watcher.Close()
+	d.watcher = nil
+	return err
+}
+
+// processEvents forwards the events for matching files until the watcher is closed
+func (d *Dir) processEvents(watcher *fsnotify.Watcher) {
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return // Watcher closed
+			}
+			// Attribute changes alone do not change the content
+			if event.Op == fsnotify.Chmod || !d.matches(filepath.Base(event.Name)) {
+				continue
+			}
+			d.notify(nil)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return // Watcher closed
+			}
+			d.notify(err)
+		}
+	}
+}
+
+// notify calls the callback unless watching was stopped meanwhile
+func (d *Dir) notify(err error) {
+	d.mu.Lock()
+	cb := d.callback
+	watching := d.watcher != nil
+	d.mu.Unlock()
+
+	if watching && cb != nil {
+		cb(nil, err)
+	}
+}
+
+// matches reports whether a file name belongs to the directory source
+func (d *Dir) matches(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	matched, _ := filepath.Match(d.pattern, name)
+	return matched
+}
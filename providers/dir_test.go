@@ -0,0 +1,106 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestDir_ReadMergesInLexicalOrder(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20-override.json"), []byte(`{"server": {"port": 8080}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "10-base.yaml"), []byte("server:\n  host: base\n  port: 80\nname: app\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "30-local.yml"), []byte("name: local\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".10-hidden.yaml"), []byte("name: hidden\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# not config\n"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub.yaml"), 0755))
+
+	all, err := NewDir(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, "dir:"+filepath.Join(dir, "*"), all.Name())
+
+	source, err := NewDir(dir, "*.y*ml")
+	require.NoError(t, err)
+	files, err := source.Files()
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "10-base.yaml"), filepath.Join(dir, "30-local.yml")}, files)
+
+	source, err = NewDir(dir, "[0-9]*")
+	require.NoError(t, err)
+	k := koanf.New(".")
+	require.NoError(t, k.Load(source, source.RequiredParser()))
+	assert.Equal(t, "base", k.String("server.host"))
+	assert.Equal(t, 8080, k.Int("server.port"))
+	assert.Equal(t, "local", k.String("name"))
+
+	_, err = source.ReadBytes()
+	assert.Error(t, err)
+
+	// A missing directory has no fragments
+	missing, err := NewDir(filepath.Join(dir, "conf.d"), "*.yaml")
+	require.NoError(t, err)
+	data, err := missing.Read()
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestDir_Errors(t *testing.T) {
+	_, err := NewDir(".", "[")
+	assert.ErrorContains(t, err, "invalid pattern")
+	_, err = NewDir(".", filepath.Join("conf.d", "*.yaml"))
+	assert.ErrorContains(t, err, "must match file names")
+
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.json")
+	require.NoError(t, os.WriteFile(broken, []byte("{not json"), 0644))
+
+	source, err := NewDir(dir, "*.json")
+	require.NoError(t, err)
+	_, err = source.Read()
+	assert.ErrorContains(t, err, broken)
+
+	missing, err := NewDir(filepath.Join(dir, "conf.d"), "")
+	require.NoError(t, err)
+	assert.Error(t, missing.Watch(func(any, error) {}))
+}
+
+func TestDir_Watch(t *testing.T) {
+	dir := t.TempDir()
+	source, err := NewDir(dir, "*.yaml")
+	require.NoError(t, err)
+
+	var events atomic.Int32
+	require.NoError(t, source.Watch(func(event any, err error) {
+		if err == nil {
+			events.Inc()
+		}
+	}))
+	require.NoError(t, source.Watch(func(any, error) {}))
+
+	// Files not matching the pattern are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(0), events.Load())
+
+	// Adding and removing fragments is reported
+	fragment := filepath.Join(dir, "50-extra.yaml")
+	require.NoError(t, os.WriteFile(fragment, []byte("extra: true\n"), 0644))
+	assert.Eventually(t, func() bool { return events.Load() > 0 }, time.Second, 10*time.Millisecond)
+
+	created := events.Load()
+	require.NoError(t, os.Remove(fragment))
+	assert.Eventually(t, func() bool { return events.Load() > created }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, source.Unwatch())
+	require.NoError(t, source.Unwatch())
+	stopped := events.Load()
+	require.NoError(t, os.WriteFile(fragment, []byte("extra: false\n"), 0644))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, stopped, events.Load())
+}
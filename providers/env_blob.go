@@ -0,0 +1,76 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements a provider that reads a whole configuration document
+// from a single base64-encoded environment variable.
+package providers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/v2"
+)
+
+// EnvBlob is a provider that reads an entire configuration document from a
+// base64-encoded environment variable, as injected by some PaaS platforms.
+// The variable is read on every load, so reloads observe a changed value.
+type EnvBlob struct {
+	// varName is the environment variable holding the encoded document
+	varName string
+}
+
+// NewEnvBlob creates a provider for the base64-encoded document stored in varName.
+func NewEnvBlob(varName string) *EnvBlob {
+	return &EnvBlob{varName: varName}
+}
+
+// ReadBytes implements the koanf.Provider interface by returning the decoded document.
+// Both padded and unpadded standard base64 are accepted and whitespace such as line
+// wrapping is ignored. It fails if the variable is unset or not valid base64.
+func (e *EnvBlob) ReadBytes() ([]byte, error) {
+	value, ok := os.LookupEnv(e.varName)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s is not set", e.varName)
+	}
+
+	encoded := strings.Join(strings.Fields(value), "")
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(encoded)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("environment variable %s is not valid base64: %w", e.varName, err)
+	}
+
+	return data, nil
+}
+
+// Read implements the koanf.Provider interface but is not supported.
+// The decoded document must be parsed with a parser matching its format.
+func (e *EnvBlob) Read() (map[string]any, error) {
+	return nil, errors.New("Read method not implemented, use ReadBytes instead")
+}
+
+// ParserForFormat returns the parser for a configuration format name such as
+// "yaml", "yml", "json", "ini", "properties" or "hcl". Names are case-insensitive
+// and may carry a leading dot.
+func ParserForFormat(format string) (koanf.Parser, error) {
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "yaml", "yml":
+		return YAMLParser(), nil
+	case "json":
+		return json.Parser(), nil
+	case "ini":
+		return INIParser(), nil
+	case "properties":
+		return PropertiesParser(), nil
+	case "hcl", "tfvars":
+		return HCLParser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported configuration format %q", format)
+	}
+}
@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvBlob_ReadBytes(t *testing.T) {
+	doc := "name: blob\nport: 8080\n"
+
+	t.Setenv("TEST_BLOB_PADDED", base64.StdEncoding.EncodeToString([]byte(doc)))
+	data, err := NewEnvBlob("TEST_BLOB_PADDED").ReadBytes()
+	require.NoError(t, err)
+	assert.Equal(t, doc, string(data))
+
+	// Unpadded and line-wrapped values are accepted
+	raw := base64.RawStdEncoding.EncodeToString([]byte(doc))
+	t.Setenv("TEST_BLOB_WRAPPED", raw[:10]+"\n"+raw[10:])
+	data, err = NewEnvBlob("TEST_BLOB_WRAPPED").ReadBytes()
+	require.NoError(t, err)
+	assert.Equal(t, doc, string(data))
+
+	_, err = NewEnvBlob("TEST_BLOB_MISSING").ReadBytes()
+	assert.ErrorContains(t, err, "not set")
+
+	t.Setenv("TEST_BLOB_INVALID", "not base64!")
+	_, err = NewEnvBlob("TEST_BLOB_INVALID").ReadBytes()
+	assert.ErrorContains(t, err, "not valid base64")
+
+	_, err = NewEnvBlob("TEST_BLOB_PADDED").Read()
+	assert.Error(t, err)
+}
+
+func TestParserForFormat(t *testing.T) {
+	for _, format := range []string{"yaml", "YML", ".yaml"} {
+		parser, err := ParserForFormat(format)
+		require.NoError(t, err)
+		assert.IsType(t, YAMLParser(), parser)
+	}
+
+	parser, err := ParserForFormat("json")
+	require.NoError(t, err)
+	assert.IsType(t, json.Parser(), parser)
+
+	parser, err = ParserForFormat("INI")
+	require.NoError(t, err)
+	assert.IsType(t, INIParser(), parser)
+
+	parser, err = ParserForFormat("properties")
+	require.NoError(t, err)
+	assert.IsType(t, PropertiesParser(), parser)
+
+	parser, err = ParserForFormat("hcl")
+	require.NoError(t, err)
+	assert.IsType(t, HCLParser(), parser)
+
+	_, err = ParserForFormat("xml")
+	assert.Error(t, err)
+}
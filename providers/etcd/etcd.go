@@ -0,0 +1,258 @@
+// Package etcd implements a configuration provider that reads all keys below an
+// etcd key prefix and watches them for changes. Keys are converted into a nested
+// map by splitting their path on a delimiter, e.g. with the prefix "/app/" the key
+// "/app/server/port" becomes server.port.
+//
+// The provider implements koanf.Provider and the Watch/Unwatch methods used by
+// ConfigManager.EnableWatch, so configuration stored in etcd hot-reloads like a
+// local file:
+//
+//	p, err := etcd.New(etcd.Config{Endpoints: []string{"localhost:2379"}, Prefix: "/app/"})
+//	if err != nil { ... }
+//	defer p.Close()
+//
+//	cm, err := vcfg.NewBuilder[AppConfig]().AddNamedProvider(p.Name(), p, nil).WithWatch().Build(ctx)
+package etcd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// defaultDelim separates the segments of a key path
+	defaultDelim = "/"
+	// defaultDialTimeout bounds establishing the connection when none is configured
+	defaultDialTimeout = 5 * time.Second
+	// defaultRequestTimeout bounds a single read when none is configured
+	defaultRequestTimeout = 10 * time.Second
+	// watchRetryDelay is the pause before re-opening a closed watch
+	watchRetryDelay = time.Second
+)
+
+// Config configures a Provider created by New.
+type Config struct {
+	// Endpoints lists the etcd cluster members, e.g. "https://etcd-0:2379"
+	Endpoints []string
+	// Prefix is the key prefix holding the configuration, e.g. "/app/config/"
+	Prefix string
+	// Delim separates key path segments below the prefix, "/" if empty
+	Delim string
+	// Username enables authentication if set
+	Username string
+	// Password is the password of Username
+	Password string
+	// TLS enables TLS with the given client configuration if set
+	TLS *tls.Config
+	// DialTimeout bounds establishing the connection, 5s if zero
+	DialTimeout time.Duration
+	// RequestTimeout bounds a single read, 10s if zero
+	RequestTimeout time.Duration
+}
+
+// Provider reads the keys below a prefix from etcd. Read fetches all keys with a
+// single range request; Watch uses etcd's native watch on the prefix and calls
+// back for every batch of changes.
+type Provider struct {
+	// kv performs range reads
+	kv clientv3.KV
+	// watcher opens watches on the prefix
+	watcher clientv3.Watcher
+	// client is closed by Close if the provider created it, nil otherwise
+	client *clientv3.Client
+	// prefix is the key prefix holding the configuration
+	prefix string
+	// delim separates key path segments below the prefix
+	delim string
+	// requestTimeout bounds a single read
+	requestTimeout time.Duration
+
+	// mu protects the watch state
+	mu sync.Mutex
+	// cancel stops the watch loop, nil if not watching
+	cancel context.CancelFunc
+	// done is closed when the watch loop has exited
+	done chan struct{}
+}
+
+// New connects to the etcd cluster described by cfg and returns a provider for
+// cfg.Prefix. The connection is owned by the provider and released by Close.
+func New(cfg Config) (*Provider, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, errors.New("etcd provider requires at least one endpoint")
+	}
+
+	dialTimeout := cfg.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = defaultDialTimeout
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   cfg.Endpoints,
+		DialTimeout: dialTimeout,
+		TLS:         cfg.TLS,
+		Username:    cfg.Username,
+		Password:    cfg.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to etcd: %w", err)
+	}
+
+	p := newProvider(client.KV, client.Watcher, cfg.Prefix, cfg.Delim, cfg.RequestTimeout)
+	p.client = client
+	return p, nil
+}
+
+// NewWithClient returns a provider for prefix that uses an existing client.
+// The client is owned by the caller; Close does not close it.
+func NewWithClient(client *clientv3.Client, prefix string) *Provider {
+	return newProvider(client.KV, client.Watcher, prefix, "", 0)
+}
+
+// newProvider creates a provider from the client parts it uses
+func newProvider(kv clientv3.KV, watcher clientv3.Watcher, prefix, delim string, requestTimeout time.Duration) *Provider {
+	if delim == "" {
+		delim = defaultDelim
+	}
+	if requestTimeout <= 0 {
+		requestTimeout = defaultRequestTimeout
+	}
+	return &Provider{
+		kv:             kv,
+		watcher:        watcher,
+		prefix:         prefix,
+		delim:          delim,
+		requestTimeout: requestTimeout,
+	}
+}
+
+// SourceKind reports the provider as a remote source, see providers.KindRemote.
+func (p *Provider) SourceKind() string {
+	return "remote"
+}
+
+// Name returns an identifier for the provider, e.g. "etcd:/app/config/".
+func (p *Provider) Name() string {
+	return "etcd:" + p.prefix
+}
+
+// Read implements the koanf.Provider interface by fetching all keys below the
+// prefix and nesting them by their path segments. Values are returned as strings
+// and converted to the field types when the configuration is unmarshaled.
+func (p *Provider) Read() (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.requestTimeout)
+	defer cancel()
+
+	resp, err := p.kv.Get(ctx, p.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read etcd prefix %s: %w", p.prefix, err)
+	}
+
+	flat := make(map[string]any, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		path := strings.Trim(strings.TrimPrefix(string(kv.Key), p.prefix), p.delim)
+		if path == "" {
+			continue // The prefix key itself carries no path
+		}
+		flat[path] = string(kv.Value)
+	}
+
+	return maps.Unflatten(flat, p.delim), nil
+}
+
+// ReadBytes implements the koanf.Provider interface but is not supported,
+// since the configuration is spread over many keys.
+func (p *Provider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("ReadBytes method not implemented, use Read instead")
+}
+
+// RequiredParser implements the ParserProvider interface. Read returns nested
+// values itself, so no parser is required.
+func (p *Provider) RequiredParser() koanf.Parser {
+	return nil
+}
+
+// Watch watches all keys below the prefix and calls cb with the etcd events of
+// every change. Watch errors are reported through cb; a watch closed by the
+// server, e.g. after a compaction, is re-opened until Unwatch is called.
+func (p *Provider) Watch(cb func(event any, err error)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		return nil // Already watching
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+	go p.watchLoop(ctx, cb, p.done)
+
+	return nil
+}
+
+// watchLoop delivers watch responses until ctx is cancelled
+func (p *Provider) watchLoop(ctx context.Context, cb func(event any, err error), done chan struct{}) {
+	defer close(done)
+
+	for reopened := false; ; reopened = true {
+		// Require a leader so a partitioned member does not silently stall the watch
+		watchCh := p.watcher.Watch(clientv3.WithRequireLeader(ctx), p.prefix, clientv3.WithPrefix())
+		if reopened {
+			// Changes made while the watch was closed were not observed; report
+			// a change so the configuration is re-read
+			cb(nil, nil)
+		}
+		for resp := range watchCh {
+			if err := resp.Err(); err != nil {
+				cb(nil, fmt.Errorf("watch on etcd prefix %s failed: %w", p.prefix, err))
+				continue
+			}
+			if len(resp.Events) > 0 {
+				cb(resp.Events, nil)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+// Unwatch stops watching and waits for the watch loop to exit.
+func (p *Provider) Unwatch() error {
+	p.mu.Lock()
+	cancel, done := p.cancel, p.done
+	p.cancel, p.done = nil, nil
+	p.mu.Unlock()
+
+	if cancel == nil {
+		return nil // Not watching
+	}
+
+	cancel()
+	<-done
+	return nil
+}
+
+// Close stops watching and closes the client if the provider created it.
+func (p *Provider) Close() error {
+	if err := p.Unwatch(); err != nil {
+		return err
+	}
+	if p.client != nil {
+		return p.client.Close()
+	}
+	return nil
+}
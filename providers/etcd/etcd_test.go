@@ -0,0 +1,211 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeKV serves range reads from an in-memory key space
+type fakeKV struct {
+	clientv3.KV
+
+	mu   sync.Mutex
+	data map[string]string
+	err  error
+}
+
+func (f *fakeKV) Get(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	resp := &clientv3.GetResponse{}
+	for k, v := range f.data {
+		if strings.HasPrefix(k, key) {
+			resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(v)})
+		}
+	}
+	sort.Slice(resp.Kvs, func(i, j int) bool { return string(resp.Kvs[i].Key) < string(resp.Kvs[j].Key) })
+	return resp, nil
+}
+
+// fakeWatcher hands out watch channels that tests feed through responses
+type fakeWatcher struct {
+	clientv3.Watcher
+
+	mu      sync.Mutex
+	opened  int
+	prefix  string
+	current chan clientv3.WatchResponse
+}
+
+func (f *fakeWatcher) Watch(ctx context.Context, key string, _ ...clientv3.OpOption) clientv3.WatchChan {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan clientv3.WatchResponse)
+	f.opened++
+	f.prefix = key
+	f.current = ch
+	go func() {
+		<-ctx.Done()
+		f.close(ch)
+	}()
+	return ch
+}
+
+// send delivers resp on the current watch channel
+func (f *fakeWatcher) send(resp clientv3.WatchResponse) {
+	f.mu.Lock()
+	ch := f.current
+	f.mu.Unlock()
+	ch <- resp
+}
+
+// close closes ch if it is still the current watch channel
+func (f *fakeWatcher) close(ch chan clientv3.WatchResponse) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.current == ch {
+		close(ch)
+		f.current = nil
+	}
+}
+
+func (f *fakeWatcher) openCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.opened
+}
+
+func TestProvider_Read(t *testing.T) {
+	kv := &fakeKV{data: map[string]string{
+		"/app/":                "ignored",
+		"/app/name":            "demo",
+		"/app/server/port":     "8080",
+		"/app/server/tls/cert": "/etc/cert.pem",
+		"/application/name":    "other",
+		"/other/name":          "unrelated",
+	}}
+	provider := newProvider(kv, &fakeWatcher{}, "/app/", "", 0)
+
+	assert.Equal(t, "etcd:/app/", provider.Name())
+	assert.Nil(t, provider.RequiredParser())
+
+	data, err := provider.Read()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name": "demo",
+		"server": map[string]any{
+			"port": "8080",
+			"tls":  map[string]any{"cert": "/etc/cert.pem"},
+		},
+	}, data)
+
+	_, err = provider.ReadBytes()
+	assert.Error(t, err)
+
+	// A custom delimiter splits flat key names
+	kv = &fakeKV{data: map[string]string{"app.server.port": "9090"}}
+	data, err = newProvider(kv, &fakeWatcher{}, "app.", ".", 0).Read()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"server": map[string]any{"port": "9090"}}, data)
+
+	kv.err = errors.New("connection refused")
+	_, err = newProvider(kv, &fakeWatcher{}, "app.", ".", 0).Read()
+	assert.ErrorContains(t, err, "failed to read etcd prefix app.")
+}
+
+func TestProvider_Watch(t *testing.T) {
+	watcher := &fakeWatcher{}
+	provider := newProvider(&fakeKV{}, watcher, "/app/", "", 0)
+
+	type notification struct {
+		event any
+		err   error
+	}
+	received := make(chan notification, 8)
+	require.NoError(t, provider.Watch(func(event any, err error) {
+		received <- notification{event, err}
+	}))
+	// A second Watch is a no-op while watching
+	require.NoError(t, provider.Watch(func(any, error) {}))
+
+	next := func() notification {
+		t.Helper()
+		select {
+		case n := <-received:
+			return n
+		case <-time.After(5 * time.Second):
+			t.Fatal("no notification delivered")
+			return notification{}
+		}
+	}
+
+	require.Eventually(t, func() bool { return watcher.openCount() == 1 }, 5*time.Second, time.Millisecond)
+	assert.Equal(t, "/app/", watcher.prefix)
+
+	// Events are forwarded
+	event := &clientv3.Event{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Key: []byte("/app/name"), Value: []byte("new")}}
+	watcher.send(clientv3.WatchResponse{Events: []*clientv3.Event{event}})
+	n := next()
+	require.NoError(t, n.err)
+	assert.Equal(t, []*clientv3.Event{event}, n.event)
+
+	// Errors are reported, progress notifications are not
+	watcher.send(clientv3.WatchResponse{CompactRevision: 42})
+	assert.ErrorContains(t, next().err, "watch on etcd prefix /app/ failed")
+	watcher.send(clientv3.WatchResponse{})
+	assert.Empty(t, received)
+
+	// A watch closed by the server is re-opened and forces a re-read
+	watcher.mu.Lock()
+	ch := watcher.current
+	watcher.mu.Unlock()
+	watcher.close(ch)
+
+	n = next()
+	assert.NoError(t, n.err)
+	assert.Nil(t, n.event)
+	assert.Equal(t, 2, watcher.openCount())
+
+	require.NoError(t, provider.Unwatch())
+	require.NoError(t, provider.Unwatch())
+	assert.Empty(t, received)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{Prefix: "/app/"})
+	assert.ErrorContains(t, err, "at least one endpoint")
+
+	// Without authentication the client connects lazily, so no server is needed
+	provider, err := New(Config{
+		Endpoints: []string{"127.0.0.1:1"},
+		Prefix:    "/app/",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "etcd:/app/", provider.Name())
+	assert.NoError(t, provider.Close())
+
+	// With authentication the client must reach the cluster to obtain a token
+	_, err = New(Config{
+		Endpoints:   []string{"127.0.0.1:1"},
+		Username:    "user",
+		Password:    "secret",
+		DialTimeout: 50 * time.Millisecond,
+	})
+	assert.ErrorContains(t, err, "failed to connect to etcd")
+}
@@ -0,0 +1,119 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements FileSet, a provider that merges an ordered list of files
+// into one logical configuration source.
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+)
+
+// FileSet treats a fixed, ordered list of files as a single configuration source.
+// Files are merged in order, later files overriding earlier ones, and files that do
+// not exist are skipped. When watched, a change to any file in the set is reported
+// as a change of the set, so the merged whole is reloaded once.
+type FileSet struct {
+	// files watches the individual files of the set in merge order
+	files []*FileWatcher
+	// parsers holds the parser for each file, chosen by file extension
+	parsers []koanf.Parser
+}
+
+// NewFileSet creates a FileSet for the given paths. The parser of each file is
+// detected from its extension like for single file sources.
+func NewFileSet(paths []string) (*FileSet, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("file set requires at least one path")
+	}
+
+	factory := NewProviderFactory()
+	set := &FileSet{}
+	for _, path := range paths {
+		fileWatcher, err := NewFileWatcher(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file watcher for %s: %w", path, err)
+		}
+		set.files = append(set.files, fileWatcher)
+		set.parsers = append(set.parsers, factory.getParserForFile(path))
+	}
+
+	return set, nil
+}
+
+// Name returns an identifier for the set built from the absolute paths of its files.
+func (s *FileSet) Name() string {
+	return "fileset:" + strings.Join(s.Paths(), ",")
+}
+
+// Paths returns the absolute paths of the files in merge order.
+func (s *FileSet) Paths() []string {
+	paths := make([]string, 0, len(s.files))
+	for _, file := range s.files {
+		paths = append(paths, file.GetFilePath())
+	}
+	return paths
+}
+
+// Read implements the koanf.Provider interface by parsing and merging all
+// existing files of the set in order.
+func (s *FileSet) Read() (map[string]any, error) {
+	k := koanf.New(".")
+	for i, file := range s.files {
+		data, err := file.ReadBytes()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.GetFilePath(), err)
+		}
+
+		if err := k.Load(rawbytes.Provider(data), s.parsers[i]); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file.GetFilePath(), err)
+		}
+	}
+
+	return k.Raw(), nil
+}
+
+// ReadBytes implements the koanf.Provider interface but is not supported,
+// since the files of a set may use different formats.
+func (s *FileSet) ReadBytes() ([]byte, error) {
+	return nil, errors.New("ReadBytes method not implemented, use Read instead")
+}
+
+// RequiredParser implements the ParserProvider interface. The set parses its
+// files itself, so no parser is required.
+func (s *FileSet) RequiredParser() koanf.Parser {
+	return nil
+}
+
+// Watch starts watching every file of the set, including files that do not
+// exist yet, and calls cb for changes to any of them.
+func (s *FileSet) Watch(cb func(event any, err error)) error {
+	for i, file := range s.files {
+		if err := file.Watch(cb); err != nil {
+			for _, started := range s.files[:i] {
+				_ = started.Unwatch()
+			}
+			return fmt.Errorf("failed to watch %s: %w", file.GetFilePath(), err)
+		}
+	}
+	return nil
+}
+
+// Unwatch stops watching all files of the set.
+func (s *FileSet) Unwatch() error {
+	var errs []error
+	for _, file := range s.files {
+		if err := file.Unwatch(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
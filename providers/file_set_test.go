@@ -0,0 +1,48 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSet_ReadMergesInOrderAndSkipsMissing(t *testing.T) {
+	tempDir := t.TempDir()
+	base := filepath.Join(tempDir, "base.yaml")
+	override := filepath.Join(tempDir, "override.json")
+	missing := filepath.Join(tempDir, "local.yaml")
+
+	require.NoError(t, os.WriteFile(base, []byte("server:\n  host: base\n  port: 80\nname: app\n"), 0644))
+	require.NoError(t, os.WriteFile(override, []byte(`{"server": {"port": 8080}}`), 0644))
+
+	set, err := NewFileSet([]string{base, missing, override})
+	require.NoError(t, err)
+	assert.Equal(t, []string{base, missing, override}, set.Paths())
+	assert.Equal(t, "fileset:"+base+","+missing+","+override, set.Name())
+
+	k := koanf.New(".")
+	require.NoError(t, k.Load(set, set.RequiredParser()))
+	assert.Equal(t, "base", k.String("server.host"))
+	assert.Equal(t, 8080, k.Int("server.port"))
+	assert.Equal(t, "app", k.String("name"))
+
+	_, err = set.ReadBytes()
+	assert.Error(t, err)
+}
+
+func TestFileSet_Errors(t *testing.T) {
+	_, err := NewFileSet(nil)
+	assert.Error(t, err)
+
+	broken := filepath.Join(t.TempDir(), "broken.json")
+	require.NoError(t, os.WriteFile(broken, []byte("{not json"), 0644))
+
+	set, err := NewFileSet([]string{broken})
+	require.NoError(t, err)
+	_, err = set.Read()
+	assert.ErrorContains(t, err, broken)
+}
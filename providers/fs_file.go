@@ -0,0 +1,58 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements FSFile, a provider reading a file from an fs.FS such as
+// an embed.FS.
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// FSFile is a provider that reads a configuration file from a file system
+// abstraction, typically configuration embedded into the binary with go:embed.
+// The file is parsed according to its extension like a regular file.
+type FSFile struct {
+	fsys fs.FS
+	path string
+}
+
+// NewFSFile creates an FSFile for path within fsys. The path uses the slash
+// separated form of io/fs, e.g. "config/defaults.yaml".
+func NewFSFile(fsys fs.FS, path string) (*FSFile, error) {
+	if fsys == nil {
+		return nil, errors.New("file system must not be nil")
+	}
+	if !fs.ValidPath(path) {
+		return nil, fmt.Errorf("invalid file system path %q", path)
+	}
+	return &FSFile{fsys: fsys, path: path}, nil
+}
+
+// Name returns an identifier for the provider, e.g. "fs:config/defaults.yaml".
+func (f *FSFile) Name() string {
+	return "fs:" + f.path
+}
+
+// ReadBytes implements the koanf.Provider interface by reading the file.
+func (f *FSFile) ReadBytes() ([]byte, error) {
+	data, err := fs.ReadFile(f.fsys, f.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name(), err)
+	}
+	return data, nil
+}
+
+// Read implements the koanf.Provider interface but is not supported.
+func (f *FSFile) Read() (map[string]any, error) {
+	return nil, errors.New("Read method not implemented, use ReadBytes instead")
+}
+
+// RequiredParser implements the ParserProvider interface, choosing the parser
+// from the file extension.
+func (f *FSFile) RequiredParser() koanf.Parser {
+	return NewProviderFactory().getParserForFile(f.path)
+}
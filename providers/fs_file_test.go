@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"conf/app.yaml": {Data: []byte("name: app\n")},
+		"conf/app.json": {Data: []byte(`{"name": "app"}`)},
+	}
+
+	p, err := NewFSFile(fsys, "conf/app.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "fs:conf/app.yaml", p.Name())
+	assert.Equal(t, KindFile, ProviderKind(p))
+
+	data, err := p.ReadBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "name: app\n", string(data))
+
+	// The parser follows the extension
+	out, err := p.RequiredParser().Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, "app", out["name"])
+
+	jsonFile, err := NewFSFile(fsys, "conf/app.json")
+	require.NoError(t, err)
+	data, err = jsonFile.ReadBytes()
+	require.NoError(t, err)
+	out, err = jsonFile.RequiredParser().Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, "app", out["name"])
+
+	_, err = p.Read()
+	assert.Error(t, err)
+}
+
+func TestFSFile_Errors(t *testing.T) {
+	fsys := fstest.MapFS{}
+
+	_, err := NewFSFile(nil, "app.yaml")
+	assert.Error(t, err)
+	_, err = NewFSFile(fsys, "../app.yaml")
+	assert.Error(t, err)
+
+	p, err := NewFSFile(fsys, "app.yaml")
+	require.NoError(t, err)
+	_, err = p.ReadBytes()
+	assert.ErrorContains(t, err, "fs:app.yaml")
+}
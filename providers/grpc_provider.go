@@ -0,0 +1,201 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements a provider that reads configuration documents from a
+// central configuration service over gRPC and watches them via server streaming.
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+	"google.golang.org/grpc"
+
+	"github.com/nextpkg/vcfg/providers/configpb"
+)
+
+const (
+	// grpcRequestTimeout bounds a single Get call
+	grpcRequestTimeout = 10 * time.Second
+	// grpcWatchRetryDelay is the pause before re-opening a broken watch stream
+	grpcWatchRetryDelay = time.Second
+)
+
+// GRPCProvider reads a configuration document from a ConfigService (see
+// configpb/config.proto). Read performs a unary Get and parses the document with
+// the parser matching the response's content type; Watch subscribes to the
+// server stream and invokes the callback for every pushed update.
+type GRPCProvider struct {
+	// client is the ConfigService client
+	client configpb.ConfigServiceClient
+	// key identifies the document on the service
+	key string
+
+	// mu protects the watch state
+	mu sync.Mutex
+	// cancel stops the watch loop, nil if not watching
+	cancel context.CancelFunc
+	// done is closed when the watch loop has exited
+	done chan struct{}
+}
+
+// NewGRPCProvider creates a provider for the document stored under key on the
+// ConfigService reachable through conn. The connection is owned by the caller.
+func NewGRPCProvider(conn *grpc.ClientConn, key string) *GRPCProvider {
+	return &GRPCProvider{
+		client: configpb.NewConfigServiceClient(conn),
+		key:    key,
+	}
+}
+
+// Name returns an identifier for the provider, e.g. "grpc:app/config".
+func (g *GRPCProvider) Name() string {
+	return "grpc:" + g.key
+}
+
+// Read implements the koanf.Provider interface by fetching the document and
+// parsing it according to its content type.
+func (g *GRPCProvider) Read() (map[string]any, error) {
+	doc, err := g.get()
+	if err != nil {
+		return nil, err
+	}
+
+	parser, err := parserForContentType(doc.GetContentType())
+	if err != nil {
+		return nil, fmt.Errorf("config key %s: %w", g.key, err)
+	}
+
+	return parser.Unmarshal(doc.GetContent())
+}
+
+// ReadBytes implements the koanf.Provider interface by returning the raw document.
+func (g *GRPCProvider) ReadBytes() ([]byte, error) {
+	doc, err := g.get()
+	if err != nil {
+		return nil, err
+	}
+	return doc.GetContent(), nil
+}
+
+// RequiredParser implements the ParserProvider interface. The provider parses
+// documents itself based on their content type, so no parser is required.
+func (g *GRPCProvider) RequiredParser() koanf.Parser {
+	return nil
+}
+
+// get fetches the current document from the service
+func (g *GRPCProvider) get() (*configpb.ConfigDocument, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), grpcRequestTimeout)
+	defer cancel()
+
+	doc, err := g.client.Get(ctx, &configpb.GetRequest{Key: g.key})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config key %s: %w", g.key, err)
+	}
+	return doc, nil
+}
+
+// Watch subscribes to updates of the document and calls cb with the pushed
+// document for every update. A broken stream is reported through cb and
+// re-opened after a short delay until Unwatch is called.
+func (g *GRPCProvider) Watch(cb func(event any, err error)) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.cancel != nil {
+		return nil // Already watching
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := g.client.Watch(ctx, &configpb.WatchRequest{Key: g.key})
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to watch config key %s: %w", g.key, err)
+	}
+
+	g.cancel = cancel
+	g.done = make(chan struct{})
+	go g.watchLoop(ctx, stream, cb, g.done)
+
+	return nil
+}
+
+// watchLoop receives updates until ctx is cancelled, re-opening the stream on failure
+func (g *GRPCProvider) watchLoop(ctx context.Context, stream grpc.ServerStreamingClient[configpb.ConfigDocument], cb func(event any, err error), done chan struct{}) {
+	defer close(done)
+
+	for {
+		doc, err := stream.Recv()
+		if err == nil {
+			cb(doc, nil)
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		cb(nil, fmt.Errorf("watch stream for config key %s broken: %w", g.key, err))
+
+		// Re-open the stream until it succeeds or the watch is stopped
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(grpcWatchRetryDelay):
+			}
+
+			stream, err = g.client.Watch(ctx, &configpb.WatchRequest{Key: g.key})
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+// Unwatch stops watching the document and waits for the watch loop to exit.
+func (g *GRPCProvider) Unwatch() error {
+	g.mu.Lock()
+	cancel, done := g.cancel, g.done
+	g.cancel, g.done = nil, nil
+	g.mu.Unlock()
+
+	if cancel == nil {
+		return nil // Not watching
+	}
+
+	cancel()
+	<-done
+	return nil
+}
+
+// parserForContentType returns the parser for a MIME type such as
+// "application/yaml" or "application/json; charset=utf-8", or a bare format
+// name understood by ParserForFormat. An empty content type is rejected.
+func parserForContentType(contentType string) (koanf.Parser, error) {
+	if contentType == "" {
+		return nil, errors.New("response has no content type")
+	}
+
+	format := contentType
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		format = mediaType
+	}
+	if i := strings.LastIndex(format, "/"); i >= 0 {
+		format = format[i+1:]
+	}
+	format = strings.TrimPrefix(format, "x-")
+	if i := strings.LastIndex(format, "+"); i >= 0 {
+		format = format[i+1:]
+	}
+
+	return ParserForFormat(format)
+}
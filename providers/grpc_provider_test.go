@@ -0,0 +1,167 @@
+package providers
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/nextpkg/vcfg/providers/configpb"
+)
+
+// fakeConfigService is an in-memory ConfigService. Documents pushed through
+// updates are forwarded to all watchers.
+type fakeConfigService struct {
+	configpb.UnimplementedConfigServiceServer
+
+	mu      sync.Mutex
+	docs    map[string]*configpb.ConfigDocument
+	updates chan *configpb.ConfigDocument
+}
+
+func (s *fakeConfigService) Get(_ context.Context, req *configpb.GetRequest) (*configpb.ConfigDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.docs[req.GetKey()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "key %s not found", req.GetKey())
+	}
+	return doc, nil
+}
+
+func (s *fakeConfigService) Watch(req *configpb.WatchRequest, stream grpc.ServerStreamingServer[configpb.ConfigDocument]) error {
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case doc := <-s.updates:
+			if doc.GetKey() != req.GetKey() {
+				continue
+			}
+			if err := stream.Send(doc); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newGRPCTestConn starts svc on an in-memory listener and returns a client connection
+func newGRPCTestConn(t *testing.T, svc configpb.ConfigServiceServer) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	configpb.RegisterConfigServiceServer(server, svc)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestGRPCProvider_Read(t *testing.T) {
+	svc := &fakeConfigService{docs: map[string]*configpb.ConfigDocument{
+		"app/yaml": {Key: "app/yaml", Content: []byte("name: yaml-app\nport: 8080\n"), ContentType: "application/yaml"},
+		"app/json": {Key: "app/json", Content: []byte(`{"name": "json-app", "port": 9090}`), ContentType: "application/json; charset=utf-8"},
+		"app/bare": {Key: "app/bare", Content: []byte("name: bare\n"), ContentType: "yml"},
+		"app/xml":  {Key: "app/xml", Content: []byte("<name/>"), ContentType: "application/xml"},
+		"app/none": {Key: "app/none", Content: []byte("name: none\n")},
+	}}
+	conn := newGRPCTestConn(t, svc)
+
+	provider := NewGRPCProvider(conn, "app/yaml")
+	assert.Equal(t, "grpc:app/yaml", provider.Name())
+	assert.Nil(t, provider.RequiredParser())
+
+	data, err := provider.Read()
+	require.NoError(t, err)
+	assert.Equal(t, "yaml-app", data["name"])
+	assert.EqualValues(t, 8080, data["port"])
+
+	raw, err := provider.ReadBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "name: yaml-app\nport: 8080\n", string(raw))
+
+	data, err = NewGRPCProvider(conn, "app/json").Read()
+	require.NoError(t, err)
+	assert.Equal(t, "json-app", data["name"])
+	assert.EqualValues(t, 9090, data["port"])
+
+	data, err = NewGRPCProvider(conn, "app/bare").Read()
+	require.NoError(t, err)
+	assert.Equal(t, "bare", data["name"])
+
+	_, err = NewGRPCProvider(conn, "app/xml").Read()
+	assert.ErrorContains(t, err, "app/xml")
+
+	_, err = NewGRPCProvider(conn, "app/none").Read()
+	assert.ErrorContains(t, err, "no content type")
+
+	_, err = NewGRPCProvider(conn, "app/missing").Read()
+	assert.ErrorContains(t, err, "failed to get config key app/missing")
+}
+
+func TestGRPCProvider_Watch(t *testing.T) {
+	svc := &fakeConfigService{updates: make(chan *configpb.ConfigDocument)}
+	conn := newGRPCTestConn(t, svc)
+	provider := NewGRPCProvider(conn, "app")
+
+	received := make(chan *configpb.ConfigDocument, 4)
+	require.NoError(t, provider.Watch(func(event any, err error) {
+		if err == nil {
+			received <- event.(*configpb.ConfigDocument)
+		}
+	}))
+	// A second Watch is a no-op while watching
+	require.NoError(t, provider.Watch(func(any, error) {}))
+
+	for version := int64(1); version <= 2; version++ {
+		svc.updates <- &configpb.ConfigDocument{Key: "app", Content: []byte("name: app\n"), ContentType: "yaml", Version: version}
+
+		select {
+		case doc := <-received:
+			assert.Equal(t, version, doc.GetVersion())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("update %d was not delivered", version)
+		}
+	}
+
+	require.NoError(t, provider.Unwatch())
+	require.NoError(t, provider.Unwatch())
+
+	assert.Empty(t, received)
+}
+
+func TestParserForContentType(t *testing.T) {
+	for _, contentType := range []string{"application/yaml", "application/x-yaml", "text/yaml; charset=utf-8", "yaml"} {
+		_, err := parserForContentType(contentType)
+		assert.NoError(t, err, contentType)
+	}
+	for _, contentType := range []string{"application/json", "application/vnd.app+json", "JSON"} {
+		_, err := parserForContentType(contentType)
+		assert.NoError(t, err, contentType)
+	}
+
+	_, err := parserForContentType("")
+	assert.Error(t, err)
+	_, err = parserForContentType("application/xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements a parser for HCL documents, the syntax of Terraform and
+// other HashiCorp tools.
+package providers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl"
+	"github.com/knadh/koanf/v2"
+)
+
+// hclParser is a koanf.Parser for HCL documents.
+type hclParser struct{}
+
+// HCLParser returns a parser for HCL documents such as .hcl and .tfvars files.
+// Attributes become keys and blocks become nested sections, so
+//
+//	server {
+//	  port = 8080
+//	}
+//
+// sets "server.port", and a labeled block such as `service "web" { ... }` sets
+// keys below "service.web". Repeated blocks of the same name are merged, later
+// ones winning. Expressions that need evaluation, such as Terraform references
+// and function calls, are not supported.
+func HCLParser() koanf.Parser {
+	return hclParser{}
+}
+
+// Unmarshal parses an HCL document into a nested map.
+func (hclParser) Unmarshal(b []byte) (map[string]any, error) {
+	var out map[string]any
+	if err := hcl.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("hcl: %w", err)
+	}
+	if out == nil {
+		return map[string]any{}, nil
+	}
+	return flattenHCLBlocks(out), nil
+}
+
+// Marshal is not supported; HCL is an input-only format.
+func (hclParser) Marshal(map[string]any) ([]byte, error) {
+	return nil, errors.New("hcl: marshaling is not supported")
+}
+
+// flattenHCLBlocks replaces the lists of objects the HCL decoder produces for
+// blocks with plain nested maps
+func flattenHCLBlocks(m map[string]any) map[string]any {
+	for key, value := range m {
+		m[key] = flattenHCLValue(value)
+	}
+	return m
+}
+
+// flattenHCLValue flattens the blocks within a single decoded value
+func flattenHCLValue(value any) any {
+	switch v := value.(type) {
+	case []map[string]any:
+		merged := make(map[string]any)
+		for _, block := range v {
+			for key, value := range flattenHCLBlocks(block) {
+				existing, ok1 := merged[key].(map[string]any)
+				next, ok2 := value.(map[string]any)
+				if ok1 && ok2 {
+					for k, v := range next {
+						existing[k] = v
+					}
+					continue
+				}
+				merged[key] = value
+			}
+		}
+		return merged
+	case map[string]any:
+		return flattenHCLBlocks(v)
+	case []any:
+		for i, item := range v {
+			v[i] = flattenHCLValue(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,67 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHCLParser_Unmarshal(t *testing.T) {
+	data, err := HCLParser().Unmarshal([]byte(`
+# Application settings
+name  = "app"
+debug = true
+ratio = 0.5
+tags  = ["a", "b"]
+
+server {
+  port = 8080
+
+  tls {
+    enabled = true
+  }
+}
+
+server {
+  host = "0.0.0.0"
+}
+
+service "web" {
+  port = 80
+}
+
+service "api" {
+  port = 81
+}
+
+routes = [{ path = "/" }]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":  "app",
+		"debug": true,
+		"ratio": 0.5,
+		"tags":  []any{"a", "b"},
+		"server": map[string]any{
+			"port": 8080,
+			"host": "0.0.0.0",
+			"tls":  map[string]any{"enabled": true},
+		},
+		"service": map[string]any{
+			"web": map[string]any{"port": 80},
+			"api": map[string]any{"port": 81},
+		},
+		"routes": []any{map[string]any{"path": "/"}},
+	}, data)
+
+	data, err = HCLParser().Unmarshal(nil)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	_, err = HCLParser().Unmarshal([]byte(`server {`))
+	assert.ErrorContains(t, err, "hcl:")
+
+	_, err = HCLParser().Marshal(map[string]any{"name": "app"})
+	assert.ErrorContains(t, err, "not supported")
+}
@@ -0,0 +1,267 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements IncludeFile, a file provider that resolves include
+// directives so a large configuration can be split across files.
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+)
+
+// IncludeFile is a file provider whose files may include other files. The value
+// of the include key, a path or a list of paths relative to the including file,
+// names the files to merge; glob patterns such as "conf.d/*.yaml" are expanded
+// in lexical order. Included files may include further files, and a file that
+// includes itself, directly or indirectly, is reported as an error.
+//
+// The included files are merged in the order listed, and the values of the
+// including file are merged over them, so a file can refine what it includes.
+// The include key itself is removed from the result.
+//
+// When watched, a change to the file or to any file it currently includes is
+// reported as a change of the provider.
+type IncludeFile struct {
+	// root is the file the provider was created for
+	root *FileWatcher
+	// key is the include key, e.g. "include"
+	key string
+	// parserFor returns the parser for a file path
+	parserFor func(path string) koanf.Parser
+
+	mu sync.Mutex
+	// included lists the absolute paths of the files included at the last read
+	included []string
+	// watchers watches the included files while watching, keyed by path
+	watchers map[string]*FileWatcher
+	// callback is the watch callback, nil while not watching
+	callback func(event any, err error)
+}
+
+// NewIncludeFile creates an IncludeFile for path that resolves the given
+// include key. Every file is parsed according to its extension.
+func NewIncludeFile(path, key string) (*IncludeFile, error) {
+	return NewProviderFactory().newIncludeFile(path, key)
+}
+
+// newIncludeFile creates an IncludeFile whose files are parsed with the
+// factory's parser selection, including format sniffing
+func (f *ProviderFactory) newIncludeFile(path, key string) (*IncludeFile, error) {
+	if key == "" {
+		return nil, errors.New("include key must not be empty")
+	}
+
+	root, err := NewFileWatcher(path)
+	if err != nil {
+		return nil, err
+	}
+	return &IncludeFile{root: root, key: key, parserFor: f.getParserForFile}, nil
+}
+
+// GetFilePath returns the absolute path of the including file.
+func (p *IncludeFile) GetFilePath() string {
+	return p.root.GetFilePath()
+}
+
+// Included returns the absolute paths of the files included at the last read,
+// directly or indirectly, in merge order.
+func (p *IncludeFile) Included() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.included...)
+}
+
+// Read implements the koanf.Provider interface by loading the file and the
+// files it includes.
+func (p *IncludeFile) Read() (map[string]any, error) {
+	var included []string
+	k, err := p.load(p.root.GetFilePath(), nil, &included)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.included = included
+	err = p.syncWatchers()
+	p.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return k.Raw(), nil
+}
+
+// load reads path and merges the files it includes below its own values. stack
+// holds the chain of including files to detect cycles.
+func (p *IncludeFile) load(path string, stack []string, included *[]string) (*koanf.Koanf, error) {
+	for i, parent := range stack {
+		if parent == path {
+			cycle := append(append([]string(nil), stack[i:]...), path)
+			return nil, fmt.Errorf("include cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	stack = append(stack, path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	file := koanf.New(".")
+	if err := file.Load(rawbytes.Provider(data), p.parserFor(path)); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	includes, err := p.includePaths(path, file.Get(p.key))
+	if err != nil {
+		return nil, err
+	}
+	file.Delete(p.key)
+
+	merged := koanf.New(".")
+	for _, include := range includes {
+		*included = append(*included, include)
+		child, err := p.load(include, stack, included)
+		if err != nil {
+			return nil, err
+		}
+		if err := merged.Merge(child); err != nil {
+			return nil, fmt.Errorf("failed to merge %s: %w", include, err)
+		}
+	}
+	if err := merged.Merge(file); err != nil {
+		return nil, fmt.Errorf("failed to merge %s: %w", path, err)
+	}
+
+	return merged, nil
+}
+
+// includePaths resolves the value of the include key of the file at path to
+// absolute file paths
+func (p *IncludeFile) includePaths(path string, value any) ([]string, error) {
+	var patterns []string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		patterns = []string{v}
+	case []any:
+		for _, item := range v {
+			pattern, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: %s must list file paths, got %v", path, p.key, item)
+			}
+			patterns = append(patterns, pattern)
+		}
+	default:
+		return nil, fmt.Errorf("%s: %s must be a path or a list of paths, got %T", path, p.key, value)
+	}
+
+	var paths []string
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(path), pattern)
+		}
+
+		// Plain paths must exist, patterns may match nothing
+		if !strings.ContainsAny(pattern, "*?[") {
+			paths = append(paths, pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid include pattern %q: %w", path, pattern, err)
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// ReadBytes implements the koanf.Provider interface but is not supported, since
+// the included files may use different formats.
+func (p *IncludeFile) ReadBytes() ([]byte, error) {
+	return nil, errors.New("ReadBytes method not implemented, use Read instead")
+}
+
+// RequiredParser implements the ParserProvider interface. The provider parses
+// its files itself, so no parser is required.
+func (p *IncludeFile) RequiredParser() koanf.Parser {
+	return nil
+}
+
+// Watch starts watching the file and the files it included at the last read,
+// and calls cb for changes to any of them. Files included by later reads are
+// watched from then on.
+func (p *IncludeFile) Watch(cb func(event any, err error)) error {
+	if err := p.root.Watch(cb); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.callback = cb
+	if err := p.syncWatchers(); err != nil {
+		p.callback = nil
+		_ = p.root.Unwatch()
+		return err
+	}
+	return nil
+}
+
+// Unwatch stops watching the file and the files it includes.
+func (p *IncludeFile) Unwatch() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	errs := []error{p.root.Unwatch()}
+	for path, watcher := range p.watchers {
+		errs = append(errs, watcher.Unwatch())
+		delete(p.watchers, path)
+	}
+	p.callback = nil
+	return errors.Join(errs...)
+}
+
+// syncWatchers watches the currently included files and stops watching files
+// that are no longer included. The caller must hold p.mu.
+func (p *IncludeFile) syncWatchers() error {
+	if p.callback == nil {
+		return nil
+	}
+	if p.watchers == nil {
+		p.watchers = make(map[string]*FileWatcher)
+	}
+
+	current := make(map[string]bool, len(p.included))
+	for _, path := range p.included {
+		current[path] = true
+		if _, ok := p.watchers[path]; ok || path == p.root.GetFilePath() {
+			continue
+		}
+		watcher, err := NewFileWatcher(path)
+		if err != nil {
+			return err
+		}
+		if err := watcher.Watch(p.callback); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		p.watchers[path] = watcher
+	}
+
+	var errs []error
+	for path, watcher := range p.watchers {
+		if !current[path] {
+			errs = append(errs, watcher.Unwatch())
+			delete(p.watchers, path)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,147 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestIncludeFile_Read(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "features"), 0755))
+	writeTestFile(t, filepath.Join(dir, "config.yaml"), `include: [database.yaml, "features/*.yaml"]
+name: app
+database:
+  host: override
+`)
+	writeTestFile(t, filepath.Join(dir, "database.yaml"), "include: secrets.json\ndatabase:\n  host: db\n  port: 5432\n")
+	writeTestFile(t, filepath.Join(dir, "secrets.json"), `{"database": {"password": "s3cret"}}`)
+	writeTestFile(t, filepath.Join(dir, "features", "b.yaml"), "features:\n  b: true\n")
+	writeTestFile(t, filepath.Join(dir, "features", "a.yaml"), "features:\n  a: true\n")
+
+	source, err := NewIncludeFile(filepath.Join(dir, "config.yaml"), "include")
+	require.NoError(t, err)
+	assert.Nil(t, source.RequiredParser())
+
+	k := koanf.New(".")
+	require.NoError(t, k.Load(source, nil))
+	assert.Equal(t, map[string]any{
+		"name": "app",
+		"database": map[string]any{
+			"host":     "override",
+			"port":     5432,
+			"password": "s3cret",
+		},
+		"features": map[string]any{"a": true, "b": true},
+	}, k.Raw())
+	assert.Equal(t, []string{
+		filepath.Join(dir, "database.yaml"),
+		filepath.Join(dir, "secrets.json"),
+		filepath.Join(dir, "features", "a.yaml"),
+		filepath.Join(dir, "features", "b.yaml"),
+	}, source.Included())
+
+	_, err = source.ReadBytes()
+	assert.Error(t, err)
+}
+
+func TestIncludeFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewIncludeFile(filepath.Join(dir, "config.yaml"), "")
+	assert.Error(t, err)
+
+	tests := []struct {
+		name   string
+		files  map[string]string
+		errMsg string
+	}{
+		{
+			name: "Cycle",
+			files: map[string]string{
+				"config.yaml": "include: a.yaml\n",
+				"a.yaml":      "include: [b.yaml]\n",
+				"b.yaml":      "include: [a.yaml]\n",
+			},
+			errMsg: "include cycle: " + filepath.Join(dir, "Cycle", "a.yaml") + " -> " +
+				filepath.Join(dir, "Cycle", "b.yaml") + " -> " + filepath.Join(dir, "Cycle", "a.yaml"),
+		},
+		{
+			name:   "SelfInclude",
+			files:  map[string]string{"config.yaml": "include: config.yaml\n"},
+			errMsg: "include cycle",
+		},
+		{
+			name:   "MissingFile",
+			files:  map[string]string{"config.yaml": "include: missing.yaml\n"},
+			errMsg: "failed to read " + filepath.Join(dir, "MissingFile", "missing.yaml"),
+		},
+		{
+			name:   "InvalidValue",
+			files:  map[string]string{"config.yaml": "include: {file: a.yaml}\n"},
+			errMsg: "include must be a path or a list of paths",
+		},
+		{
+			name:   "InvalidItem",
+			files:  map[string]string{"config.yaml": "include: [1]\n"},
+			errMsg: "include must list file paths",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caseDir := filepath.Join(dir, tt.name)
+			require.NoError(t, os.Mkdir(caseDir, 0755))
+			for name, content := range tt.files {
+				writeTestFile(t, filepath.Join(caseDir, name), content)
+			}
+
+			source, err := NewIncludeFile(filepath.Join(caseDir, "config.yaml"), "include")
+			require.NoError(t, err)
+			_, err = source.Read()
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestIncludeFile_Watch(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "config.yaml"), "include: [shared/common.yaml]\n")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "shared"), 0755))
+	writeTestFile(t, filepath.Join(dir, "shared", "common.yaml"), "name: app\n")
+
+	source, err := NewIncludeFile(filepath.Join(dir, "config.yaml"), "include")
+	require.NoError(t, err)
+	_, err = source.Read()
+	require.NoError(t, err)
+
+	var events atomic.Int32
+	require.NoError(t, source.Watch(func(event any, err error) {
+		if err == nil {
+			events.Inc()
+		}
+	}))
+	defer source.Unwatch()
+	time.Sleep(50 * time.Millisecond)
+
+	// Editing the included file in another directory is reported
+	writeTestFile(t, filepath.Join(dir, "shared", "common.yaml"), "name: changed\n")
+	assert.Eventually(t, func() bool { return events.Load() > 0 }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, source.Unwatch())
+	stopped := events.Load()
+	writeTestFile(t, filepath.Join(dir, "shared", "common.yaml"), "name: again\n")
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, stopped, events.Load())
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
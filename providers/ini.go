@@ -0,0 +1,196 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements a parser for INI files, so legacy configuration can be
+// loaded without converting it first.
+package providers
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// iniParser is a koanf.Parser for INI documents.
+type iniParser struct{}
+
+// INIParser returns a parser for INI documents. Keys before the first section
+// header are top-level keys, keys below "[server]" become "server.<key>" and
+// dotted headers such as "[server.tls]" nest further. Keys and values are
+// separated by '=' or ':', values may be enclosed in single or double quotes,
+// and lines starting with ';' or '#' are comments. INI has no types, so all
+// values are strings; they are converted to the field types when decoding,
+// as environment variables are.
+func INIParser() koanf.Parser {
+	return iniParser{}
+}
+
+// Unmarshal parses an INI document into a nested map.
+func (iniParser) Unmarshal(b []byte) (map[string]any, error) {
+	out := make(map[string]any)
+	section := ""
+
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+	for i, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("ini: line %d: unterminated section header", i+1)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == "" {
+				return nil, fmt.Errorf("ini: line %d: empty section name", i+1)
+			}
+			if _, err := nestedSection(out, strings.Split(section, ".")); err != nil {
+				return nil, fmt.Errorf("ini: line %d: %w", i+1, err)
+			}
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			return nil, fmt.Errorf("ini: line %d: expected key = value", i+1)
+		}
+		key := strings.TrimSpace(line[:sep])
+		if key == "" {
+			return nil, fmt.Errorf("ini: line %d: missing key", i+1)
+		}
+
+		path := key
+		if section != "" {
+			path = section + "." + key
+		}
+		if err := setNested(out, path, unquoteINI(strings.TrimSpace(line[sep+1:]))); err != nil {
+			return nil, fmt.Errorf("ini: line %d: %w", i+1, err)
+		}
+	}
+
+	return out, nil
+}
+
+// Marshal serializes o as an INI document: top-level values first, then one
+// section per nested map. Lists are written as comma-separated values.
+func (iniParser) Marshal(o map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeINISection(&buf, "", o); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeINISection writes the values of section followed by its subsections
+func writeINISection(buf *bytes.Buffer, name string, section map[string]any) error {
+	keys := sortedKeys(section)
+
+	if name != "" {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(buf, "[%s]\n", name)
+	}
+	for _, key := range keys {
+		if _, ok := section[key].(map[string]any); ok {
+			continue
+		}
+		value := formatScalar(section[key])
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("ini: value of %s spans several lines", joinPath(name, key))
+		}
+		if value != strings.TrimSpace(value) {
+			value = `"` + value + `"`
+		}
+		fmt.Fprintf(buf, "%s = %s\n", key, value)
+	}
+
+	for _, key := range keys {
+		if sub, ok := section[key].(map[string]any); ok {
+			if err := writeINISection(buf, joinPath(name, key), sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unquoteINI strips a pair of matching quotes around value
+func unquoteINI(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// setNested stores value at the dotted path in m, creating the maps on the way.
+// A path whose parent is already set to a value is rejected, as is a path set to
+// a value that already holds keys.
+func setNested(m map[string]any, path string, value any) error {
+	parts := strings.Split(path, ".")
+	parent, err := nestedSection(m, parts[:len(parts)-1])
+	if err != nil {
+		return err
+	}
+
+	last := parts[len(parts)-1]
+	if _, ok := parent[last].(map[string]any); ok {
+		return fmt.Errorf("key %s is also a section", path)
+	}
+	parent[last] = value
+	return nil
+}
+
+// nestedSection returns the map at the path below m, creating missing maps
+func nestedSection(m map[string]any, parts []string) (map[string]any, error) {
+	for i, part := range parts {
+		switch next := m[part].(type) {
+		case map[string]any:
+			m = next
+		case nil:
+			sub := make(map[string]any)
+			m[part] = sub
+			m = sub
+		default:
+			return nil, fmt.Errorf("section %s is also a key", strings.Join(parts[:i+1], "."))
+		}
+	}
+	return m, nil
+}
+
+// formatScalar returns the text form of a value in a format without types
+func formatScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatScalar(item)
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinPath joins a parent path and a key with a dot
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
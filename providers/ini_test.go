@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestINIParser_Unmarshal(t *testing.T) {
+	data, err := INIParser().Unmarshal([]byte(`; legacy settings
+name = app
+debug: true
+
+[server]
+host = "0.0.0.0"
+port=8080
+# comments are skipped
+banner = ' hello '
+
+[server.tls]
+cert = /etc/app.pem
+
+[database]
+url = postgres://db:5432/app?sslmode=disable
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":  "app",
+		"debug": "true",
+		"server": map[string]any{
+			"host":   "0.0.0.0",
+			"port":   "8080",
+			"banner": " hello ",
+			"tls":    map[string]any{"cert": "/etc/app.pem"},
+		},
+		"database": map[string]any{"url": "postgres://db:5432/app?sslmode=disable"},
+	}, data)
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{"UnterminatedSection", "[server\nport = 1\n", "line 1: unterminated section header"},
+		{"EmptySection", "[ ]\n", "line 1: empty section name"},
+		{"MissingSeparator", "[server]\nport\n", "line 2: expected key = value"},
+		{"MissingKey", "= value\n", "line 1: missing key"},
+		{"KeyIsSection", "[server]\nport = 1\n[server.port]\n", "section server.port is also a key"},
+		{"SectionIsKey", "[server.tls]\n[server]\ntls = on\n", "key server.tls is also a section"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := INIParser().Unmarshal([]byte(tt.content))
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestINIParser_Marshal(t *testing.T) {
+	values := map[string]any{
+		"name": "app",
+		"server": map[string]any{
+			"port":   8080,
+			"banner": " hello ",
+			"tls":    map[string]any{"cert": "/etc/app.pem"},
+		},
+		"tags": []any{"a", "b"},
+	}
+
+	data, err := INIParser().Marshal(values)
+	require.NoError(t, err)
+	assert.Equal(t, `name = app
+tags = a,b
+
+[server]
+banner = " hello "
+port = 8080
+
+[server.tls]
+cert = /etc/app.pem
+`, string(data))
+
+	parsed, err := INIParser().Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, " hello ", parsed["server"].(map[string]any)["banner"])
+
+	_, err = INIParser().Marshal(map[string]any{"motd": "line one\nline two"})
+	assert.ErrorContains(t, err, "value of motd spans several lines")
+}
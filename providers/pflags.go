@@ -0,0 +1,97 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements PFlags, a provider for spf13/pflag flag sets as used by
+// cobra-based command line tools.
+package providers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+// PFlags is a provider that reads the flags of a pflag.FlagSet, such as
+// cmd.Flags() of a cobra command. Only flags set on the command line are
+// reported, so flags left at their defaults don't override the values of
+// other sources; defaults belong in `default` struct tags or a defaults file.
+//
+// Flag names map to configuration keys split by the delimiter, so the flag
+// --server.port sets the key server.port when the delimiter is ".".
+type PFlags struct {
+	flags *pflag.FlagSet
+	delim string
+}
+
+// NewPFlags creates a provider for the flags of fs that have been set.
+func NewPFlags(fs *pflag.FlagSet, delim string) *PFlags {
+	return &PFlags{flags: fs, delim: delim}
+}
+
+// Name returns an identifier for the provider, e.g. "pflags:serve".
+func (p *PFlags) Name() string {
+	return "pflags:" + p.flags.Name()
+}
+
+// Read implements the koanf.Provider interface by returning the values of the
+// flags set on the command line, converted to the flag's type.
+func (p *PFlags) Read() (map[string]any, error) {
+	values := make(map[string]any)
+	p.flags.Visit(func(f *pflag.Flag) {
+		values[f.Name] = flagValue(f)
+	})
+
+	if p.delim == "" {
+		return values, nil
+	}
+	return maps.Unflatten(values, p.delim), nil
+}
+
+// flagValue returns the typed value of f: lists for slice flags, numbers and
+// booleans for the corresponding flag types and strings for all others
+func flagValue(f *pflag.Flag) any {
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		items := sv.GetSlice()
+		list := make([]any, len(items))
+		for i, item := range items {
+			list[i] = item
+		}
+		return list
+	}
+
+	s := f.Value.String()
+	typ := f.Value.Type()
+	switch {
+	case typ == "bool":
+		if v, err := strconv.ParseBool(s); err == nil {
+			return v
+		}
+	case strings.HasPrefix(typ, "int") && typ != "intSlice":
+		if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return v
+		}
+	case strings.HasPrefix(typ, "uint"):
+		if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return v
+		}
+	case strings.HasPrefix(typ, "float"):
+		if v, err := strconv.ParseFloat(s, 64); err == nil {
+			return v
+		}
+	}
+	return s
+}
+
+// ReadBytes implements the koanf.Provider interface but is not supported.
+func (p *PFlags) ReadBytes() ([]byte, error) {
+	return nil, errors.New("ReadBytes method not implemented, use Read instead")
+}
+
+// RequiredParser implements the ParserProvider interface. Flag values are
+// read directly, so no parser is required.
+func (p *PFlags) RequiredParser() koanf.Parser {
+	return nil
+}
@@ -0,0 +1,48 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPFlags_Read(t *testing.T) {
+	fs := pflag.NewFlagSet("serve", pflag.ContinueOnError)
+	fs.String("name", "default", "")
+	fs.Int("server.port", 80, "")
+	fs.Bool("debug", false, "")
+	fs.Float64("ratio", 0.5, "")
+	fs.StringSlice("tags", nil, "")
+	fs.Duration("timeout", 0, "")
+	require.NoError(t, fs.Parse([]string{"--server.port=9090", "--debug", "--tags=a,b", "--timeout=5s"}))
+
+	p := NewPFlags(fs, ".")
+	assert.Equal(t, "pflags:serve", p.Name())
+	assert.Equal(t, KindFlags, ProviderKind(p))
+	assert.Nil(t, p.RequiredParser())
+
+	data, err := p.Read()
+	require.NoError(t, err)
+	// Flags left at their defaults are not reported
+	assert.Equal(t, map[string]any{
+		"server":  map[string]any{"port": int64(9090)},
+		"debug":   true,
+		"tags":    []any{"a", "b"},
+		"timeout": "5s",
+	}, data)
+
+	_, err = p.ReadBytes()
+	assert.Error(t, err)
+}
+
+func TestPFlags_EmptyDelimiter(t *testing.T) {
+	fs := pflag.NewFlagSet("app", pflag.ContinueOnError)
+	fs.Uint("server.port", 0, "")
+	require.NoError(t, fs.Parse([]string{"--server.port=8080"}))
+
+	data, err := NewPFlags(fs, "").Read()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"server.port": uint64(8080)}, data)
+}
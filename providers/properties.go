@@ -0,0 +1,178 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements a parser for Java properties files.
+package providers
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+// propertiesParser is a koanf.Parser for Java properties documents.
+type propertiesParser struct{}
+
+// PropertiesParser returns a parser for Java properties documents. Dotted keys
+// such as "server.port" become nested keys. It follows the rules of Java's
+// Properties.load: keys and values are separated by '=', ':' or whitespace,
+// lines starting with '#' or '!' are comments, a trailing backslash continues
+// the value on the next line, and backslash escapes including \uXXXX are
+// decoded. As with INI, all values are strings.
+func PropertiesParser() koanf.Parser {
+	return propertiesParser{}
+}
+
+// Unmarshal parses a properties document into a nested map.
+func (propertiesParser) Unmarshal(b []byte) (map[string]any, error) {
+	out := make(map[string]any)
+
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+	lines := strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// Join continuation lines, dropping the leading whitespace of each
+		for endsWithEscape(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+
+		rawKey, rawValue := splitProperty(line)
+		key, err := unescapeProperty(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("properties: line %d: %w", lineNo, err)
+		}
+		value, err := unescapeProperty(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("properties: line %d: %w", lineNo, err)
+		}
+		if err := setNested(out, key, value); err != nil {
+			return nil, fmt.Errorf("properties: line %d: %w", lineNo, err)
+		}
+	}
+
+	return out, nil
+}
+
+// Marshal serializes o as a properties document with one dotted key per value,
+// sorted by key. Lists are written as comma-separated values.
+func (propertiesParser) Marshal(o map[string]any) ([]byte, error) {
+	flat, _ := maps.Flatten(o, nil, ".")
+
+	var buf bytes.Buffer
+	for _, key := range sortedKeys(flat) {
+		fmt.Fprintf(&buf, "%s = %s\n", escapeProperty(key, true), escapeProperty(formatScalar(flat[key]), false))
+	}
+	return buf.Bytes(), nil
+}
+
+// endsWithEscape reports whether line ends with an unescaped backslash
+func endsWithEscape(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// splitProperty splits a logical line at the first unescaped separator
+func splitProperty(line string) (key, value string) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+
+	key = line[:end]
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return key, rest
+}
+
+// unescapeProperty decodes the backslash escapes of a key or value
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("malformed \\u escape in %q", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\u escape in %q", s)
+			}
+			sb.WriteRune(rune(r))
+			i += 4
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String(), nil
+}
+
+// escapeProperty escapes s for a properties file. Keys also escape separators;
+// values only their leading whitespace.
+func escapeProperty(s string, key bool) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			if key {
+				sb.WriteByte('\\')
+			}
+			sb.WriteRune(r)
+		case ' ':
+			if key || i == 0 {
+				sb.WriteByte('\\')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
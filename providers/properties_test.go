@@ -0,0 +1,64 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertiesParser_Unmarshal(t *testing.T) {
+	data, err := PropertiesParser().Unmarshal([]byte("# application settings\r\n" +
+		"! also a comment\n" +
+		"app.name = demo\n" +
+		"server.port:8080\n" +
+		"server.host localhost\n" +
+		"   server.banner =   Welcome to \\\n" +
+		"                     the app\n" +
+		"greeting = caf\\u00e9\\tbar\n" +
+		"path\\ with\\ spaces = c:\\\\temp\n" +
+		"key\\=with\\:separators = ok\n" +
+		"empty=\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"app": map[string]any{"name": "demo"},
+		"server": map[string]any{
+			"port":   "8080",
+			"host":   "localhost",
+			"banner": "Welcome to the app",
+		},
+		"greeting":            "café\tbar",
+		"path with spaces":    `c:\temp`,
+		"key=with:separators": "ok",
+		"empty":               "",
+	}, data)
+
+	_, err = PropertiesParser().Unmarshal([]byte("a = \\u12\n"))
+	assert.ErrorContains(t, err, "line 1: malformed \\u escape")
+
+	_, err = PropertiesParser().Unmarshal([]byte("a = 1\na.b = 2\n"))
+	assert.ErrorContains(t, err, "line 2: section a is also a key")
+}
+
+func TestPropertiesParser_Marshal(t *testing.T) {
+	values := map[string]any{
+		"server": map[string]any{"port": 8080, "banner": " hi\nthere"},
+		"tags":   []any{"a", "b"},
+		"a key":  "x=y",
+	}
+
+	data, err := PropertiesParser().Marshal(values)
+	require.NoError(t, err)
+	assert.Equal(t, "a\\ key = x=y\n"+
+		"server.banner = \\ hi\\nthere\n"+
+		"server.port = 8080\n"+
+		"tags = a,b\n", string(data))
+
+	parsed, err := PropertiesParser().Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"server": map[string]any{"port": "8080", "banner": " hi\nthere"},
+		"tags":   "a,b",
+		"a key":  "x=y",
+	}, parsed)
+}
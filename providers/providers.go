@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/knadh/koanf/parsers/json"
-	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
@@ -29,24 +28,82 @@ type ParserProvider interface {
 // containing both the data provider and its associated parser.
 // Parser can be nil for providers that handle parsing internally.
 type ProviderConfig struct {
+	// Name is a human readable identifier used in introspection, logs and errors.
+	// If empty, the factory derives it from the file path or provider type.
+	Name string
 	// Provider is the koanf data provider
 	Provider koanf.Provider
 	// Parser is the associated parser, nil if provider handles parsing internally
 	Parser koanf.Parser
+	// Kind classifies the source for precedence rules, e.g. KindFile.
+	// If empty, the factory derives it with ProviderKind.
+	Kind string
+}
+
+// Source kinds classify providers so their precedence can be declared by kind
+// rather than by registration order.
+const (
+	// KindDefaults is a defaults file merged below the other sources
+	KindDefaults = "defaults"
+	// KindFile is a local file or a set of files
+	KindFile = "file"
+	// KindEnv is read from environment variables
+	KindEnv = "env"
+	// KindFlags is read from command line flags
+	KindFlags = "flags"
+	// KindRemote is fetched from a remote service such as Consul or Vault
+	KindRemote = "remote"
+	// KindCustom is any other provider
+	KindCustom = "custom"
+)
+
+// KindProvider is an optional interface that providers can implement to
+// declare their source kind, e.g. KindRemote. It takes precedence over the
+// detection of ProviderKind.
+type KindProvider interface {
+	// SourceKind returns the kind of the source.
+	SourceKind() string
 }
 
 // ProviderFactory is responsible for creating provider configurations
 // from various input sources with automatic parser detection.
-type ProviderFactory struct{}
+type ProviderFactory struct {
+	// sniffFormat makes sources of unknown format detect JSON or YAML from their content
+	sniffFormat bool
+	// includeKey makes file sources resolve include directives under this key, "" to disable
+	includeKey string
+}
 
 // NewProviderFactory creates a new provider factory
 func NewProviderFactory() *ProviderFactory {
 	return &ProviderFactory{}
 }
 
+// SetFormatSniffing enables or disables content sniffing. When enabled, sources
+// whose format cannot be derived from a file extension or the provider type are
+// parsed with SniffingParser instead of the static JSON or YAML fallback.
+func (f *ProviderFactory) SetFormatSniffing(enabled bool) {
+	f.sniffFormat = enabled
+}
+
+// SetIncludeKey makes file sources created from paths resolve include
+// directives under key, see IncludeFile. An empty key disables includes.
+func (f *ProviderFactory) SetIncludeKey(key string) {
+	f.includeKey = key
+}
+
+// fallbackParser returns the parser used for sources of unknown format
+func (f *ProviderFactory) fallbackParser(defaultParser koanf.Parser) koanf.Parser {
+	if f.sniffFormat {
+		return SniffingParser()
+	}
+	return defaultParser
+}
+
 // CreateProviders creates provider configurations from various input sources.
 // Supported source types:
 //   - string: treated as file path, automatically detects parser from extension
+//   - ProviderConfig: used as is, allowing an explicit name and parser
 //   - koanf.Provider: uses zero-config auto-detection for parser requirement
 //
 // Returns a slice of ProviderConfig with appropriate parsers assigned,
@@ -57,6 +114,20 @@ func (f *ProviderFactory) CreateProviders(sources ...any) ([]ProviderConfig, err
 	for _, source := range sources {
 		switch s := source.(type) {
 		case string:
+			if f.includeKey != "" {
+				// Files resolve include directives and parse themselves
+				includeFile, err := f.newIncludeFile(s, f.includeKey)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create file watcher for %s: %w", s, err)
+				}
+				configs = append(configs, ProviderConfig{
+					Name:     includeFile.GetFilePath(),
+					Provider: includeFile,
+					Kind:     KindFile,
+				})
+				continue
+			}
+
 			// Create enhanced file watcher that monitors parent directory
 			// to handle atomic file operations properly
 			fileWatcher, err := NewFileWatcher(s)
@@ -65,17 +136,33 @@ func (f *ProviderFactory) CreateProviders(sources ...any) ([]ProviderConfig, err
 			}
 			parser := f.getParserForFile(s)
 			configs = append(configs, ProviderConfig{
+				Name:     fileWatcher.GetFilePath(),
 				Provider: fileWatcher,
 				Parser:   parser,
+				Kind:     KindFile,
 			})
+		case ProviderConfig:
+			// Fully specified provider configuration, e.g. a named custom source
+			if s.Provider == nil {
+				return nil, fmt.Errorf("provider config %q has no provider", s.Name)
+			}
+			if s.Name == "" {
+				s.Name = ProviderName(s.Provider)
+			}
+			if s.Kind == "" {
+				s.Kind = ProviderKind(s.Provider)
+			}
+			configs = append(configs, s)
 		case koanf.Provider:
 			// Direct provider instance - use intelligent auto-detection
 			// to determine if parser is needed based on provider type
 			parser := f.detectParserRequirement(s)
 
 			configs = append(configs, ProviderConfig{
+				Name:     ProviderName(s),
 				Provider: s,
 				Parser:   parser,
+				Kind:     ProviderKind(s),
 			})
 		default:
 			return nil, fmt.Errorf("unsupported source type: %T", source)
@@ -85,6 +172,38 @@ func (f *ProviderFactory) CreateProviders(sources ...any) ([]ProviderConfig, err
 	return configs, nil
 }
 
+// ProviderName derives a human readable name for a provider.
+// File based providers are identified by their path, all others by their Go type.
+func ProviderName(provider koanf.Provider) string {
+	if fp, ok := provider.(interface{ GetFilePath() string }); ok {
+		return fp.GetFilePath()
+	}
+	return fmt.Sprintf("%T", provider)
+}
+
+// ProviderKind derives the source kind of a provider, one of the Kind
+// constants. Providers implementing KindProvider declare it themselves, the
+// providers of this package and the koanf env and file providers are
+// recognized by type, and all others are KindCustom.
+func ProviderKind(provider koanf.Provider) string {
+	if kp, ok := provider.(KindProvider); ok {
+		return kp.SourceKind()
+	}
+
+	switch provider.(type) {
+	case *FileWatcher, *IncludeFile, *FileSet, *Dir, *SopsFile, *FSFile, *file.File:
+		return KindFile
+	case *env.Env, *EnvBlob:
+		return KindEnv
+	case *CliFlags, *CliProviderWrapper, *PFlags:
+		return KindFlags
+	case *ConsulProvider, *VaultProvider, *RemoteProvider, *GRPCProvider:
+		return KindRemote
+	default:
+		return KindCustom
+	}
+}
+
 // detectParserRequirement intelligently determines the parser requirement
 // for a given provider using type assertion. This method implements a
 // zero-configuration approach that works with common koanf provider types.
@@ -96,6 +215,8 @@ func (f *ProviderFactory) CreateProviders(sources ...any) ([]ProviderConfig, err
 //     - file.File: returns json.Parser() (needs external parsing)
 //  3. Default to json.Parser() for unknown types (safe fallback)
 //
+// With format sniffing enabled, the JSON defaults are replaced by SniffingParser.
+//
 // This approach is more robust than error message inspection and provides
 // better maintainability and forward compatibility.
 func (f *ProviderFactory) detectParserRequirement(provider koanf.Provider) koanf.Parser {
@@ -114,16 +235,16 @@ func (f *ProviderFactory) detectParserRequirement(provider koanf.Provider) koanf
 	case *file.File:
 		// File provider only reads raw bytes, requires external parser
 		// Default to JSON parser for flexibility
-		return json.Parser()
+		return f.fallbackParser(json.Parser())
 	case *FileWatcher:
 		// FileWatcher wraps file provider, also needs external parser
 		// Default to JSON parser for flexibility
-		return json.Parser()
+		return f.fallbackParser(json.Parser())
 	default:
 		// Priority 3: Safe fallback for unknown provider types
 		// Assume external parser is needed to avoid runtime errors
 		// Custom providers should implement ParserProvider for explicit control
-		return json.Parser()
+		return f.fallbackParser(json.Parser())
 	}
 }
 
@@ -131,21 +252,31 @@ func (f *ProviderFactory) detectParserRequirement(provider koanf.Provider) koanf
 // Supports common configuration file formats with sensible defaults.
 //
 // Supported extensions:
-//   - .yaml, .yml: returns yaml.Parser()
+//   - .yaml, .yml: returns YAMLParser()
 //   - .json: returns json.Parser()
-//   - others: defaults to yaml.Parser() for maximum compatibility
+//   - .ini: returns INIParser()
+//   - .properties: returns PropertiesParser()
+//   - .hcl, .tfvars: returns HCLParser()
+//   - others: defaults to YAMLParser() for maximum compatibility,
+//     or SniffingParser() if format sniffing is enabled
 func (f *ProviderFactory) getParserForFile(filePath string) koanf.Parser {
 	// Extract and normalize file extension
 	ext := strings.ToLower(filepath.Ext(filePath))
 
 	switch ext {
 	case ".yaml", ".yml":
-		return yaml.Parser()
+		return YAMLParser()
 	case ".json":
 		return json.Parser()
+	case ".ini":
+		return INIParser()
+	case ".properties":
+		return PropertiesParser()
+	case ".hcl", ".tfvars":
+		return HCLParser()
 	default:
 		// Default to YAML parser for unknown extensions
 		// YAML is more forgiving and human-readable than JSON
-		return yaml.Parser()
+		return f.fallbackParser(YAMLParser())
 	}
 }
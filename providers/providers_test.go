@@ -33,7 +33,7 @@ func TestProviderFactory_CreateProviders_WithCustomProviders(t *testing.T) {
 
 	// Verify YAML provider config
 	assert.Equal(t, yamlProvider, configs[1].Provider)
-	assert.IsType(t, yaml.Parser(), configs[1].Parser)
+	assert.IsType(t, YAMLParser(), configs[1].Parser)
 }
 
 func TestProviderFactory_CreateProviders_WithFileProvider(t *testing.T) {
@@ -80,7 +80,7 @@ func TestProviderFactory_CreateProviders_WithFilePath(t *testing.T) {
 	assert.IsType(t, json.Parser(), configs[0].Parser)
 
 	// YAML file should use YAML parser
-	assert.IsType(t, yaml.Parser(), configs[1].Parser)
+	assert.IsType(t, YAMLParser(), configs[1].Parser)
 }
 
 func TestProviderFactory_CreateProviders_MixedSources(t *testing.T) {
@@ -99,7 +99,7 @@ func TestProviderFactory_CreateProviders_MixedSources(t *testing.T) {
 	require.Len(t, configs, 3)
 
 	// File path should use YAML parser
-	assert.IsType(t, yaml.Parser(), configs[0].Parser)
+	assert.IsType(t, YAMLParser(), configs[0].Parser)
 
 	// Custom provider should use its required parser (JSON)
 	assert.Equal(t, customProvider, configs[1].Provider)
@@ -174,7 +174,7 @@ func TestCustomYAMLProvider(t *testing.T) {
 
 	// Test RequiredParser
 	parser := provider.RequiredParser()
-	assert.IsType(t, yaml.Parser(), parser)
+	assert.IsType(t, YAMLParser(), parser)
 }
 
 // TestCustomProviders_EmptyData tests custom providers with empty data
@@ -215,14 +215,18 @@ func TestGetParserForFile(t *testing.T) {
 		expected interface{}
 	}{
 		{"JSON file", "config.json", json.Parser()},
-		{"YAML file", "config.yaml", yaml.Parser()},
-		{"YML file", "config.yml", yaml.Parser()},
-		{"Unknown extension", "config.txt", yaml.Parser()}, // defaults to YAML
-		{"No extension", "config", yaml.Parser()},          // defaults to YAML
-		{"Empty string", "", yaml.Parser()},                // defaults to YAML
+		{"YAML file", "config.yaml", YAMLParser()},
+		{"YML file", "config.yml", YAMLParser()},
+		{"Unknown extension", "config.txt", YAMLParser()}, // defaults to YAML
+		{"No extension", "config", YAMLParser()},          // defaults to YAML
+		{"Empty string", "", YAMLParser()},                // defaults to YAML
 		{"Multiple dots", "config.backup.json", json.Parser()},
 		{"Case insensitive", "config.JSON", json.Parser()},
-		{"Case insensitive YAML", "config.YAML", yaml.Parser()},
+		{"Case insensitive YAML", "config.YAML", YAMLParser()},
+		{"INI file", "config.ini", INIParser()},
+		{"Properties file", "application.properties", PropertiesParser()},
+		{"HCL file", "config.hcl", HCLParser()},
+		{"Terraform variables file", "prod.tfvars", HCLParser()},
 	}
 
 	for _, tt := range tests {
@@ -239,13 +243,13 @@ func TestProviderFactory_UnsupportedFileExtension(t *testing.T) {
 	factory := NewProviderFactory()
 
 	// Test with unsupported file extension (should default to YAML)
-	configs, err := factory.CreateProviders("config.xml", "config.ini")
+	configs, err := factory.CreateProviders("config.xml", "config.toml")
 	require.NoError(t, err)
 	require.Len(t, configs, 2)
 
 	// Both should default to YAML parser
-	assert.IsType(t, yaml.Parser(), configs[0].Parser)
-	assert.IsType(t, yaml.Parser(), configs[1].Parser)
+	assert.IsType(t, YAMLParser(), configs[0].Parser)
+	assert.IsType(t, YAMLParser(), configs[1].Parser)
 }
 
 // TestProviderFactory_EmptyProviders tests factory with no providers
@@ -256,3 +260,65 @@ func TestProviderFactory_EmptyProviders(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, configs)
 }
+
+func TestProviderFactory_CreateProviders_WithProviderConfig(t *testing.T) {
+	factory := NewProviderFactory()
+
+	yamlProvider := NewCustomYAMLProvider([]byte(`key: value`))
+	jsonProvider := NewCustomJSONProvider([]byte(`{"key": "value"}`))
+
+	configs, err := factory.CreateProviders(
+		ProviderConfig{Name: "remote-settings", Provider: yamlProvider, Parser: yaml.Parser()},
+		ProviderConfig{Provider: jsonProvider, Parser: json.Parser()},
+	)
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+
+	// Explicit name and parser are kept
+	assert.Equal(t, "remote-settings", configs[0].Name)
+	assert.Equal(t, yamlProvider, configs[0].Provider)
+	assert.IsType(t, yaml.Parser(), configs[0].Parser)
+
+	// Missing name is derived from the provider type
+	assert.Equal(t, "*providers.CustomJSONProvider", configs[1].Name)
+
+	// A config without provider is rejected
+	_, err = factory.CreateProviders(ProviderConfig{Name: "empty"})
+	assert.Error(t, err)
+}
+
+func TestProviderName(t *testing.T) {
+	fw, err := NewFileWatcher("config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, fw.GetFilePath(), ProviderName(fw))
+
+	assert.Equal(t, "*env.Env", ProviderName(env.Provider("APP_", ".", nil)))
+}
+
+type kindedProvider struct{ *mockProvider }
+
+func (kindedProvider) SourceKind() string { return KindRemote }
+
+func TestProviderKind(t *testing.T) {
+	fileWatcher, err := NewFileWatcher("config.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, KindFile, ProviderKind(fileWatcher))
+	assert.Equal(t, KindEnv, ProviderKind(env.Provider("APP_", ".", nil)))
+	assert.Equal(t, KindEnv, ProviderKind(NewEnvBlob("APP_CONFIG")))
+	assert.Equal(t, KindCustom, ProviderKind(NewCustomJSONProvider(nil)))
+	assert.Equal(t, KindRemote, ProviderKind(kindedProvider{&mockProvider{}}))
+
+	factory := NewProviderFactory()
+	configs, err := factory.CreateProviders(
+		"config.yaml",
+		NewCustomJSONProvider(nil),
+		ProviderConfig{Provider: NewCustomJSONProvider(nil)},
+		ProviderConfig{Provider: NewCustomJSONProvider(nil), Kind: KindDefaults},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, KindFile, configs[0].Kind)
+	assert.Equal(t, KindCustom, configs[1].Kind)
+	assert.Equal(t, KindCustom, configs[2].Kind)
+	assert.Equal(t, KindDefaults, configs[3].Kind)
+}
@@ -0,0 +1,344 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements a provider that fetches a configuration document from an
+// HTTP(S) URL and polls it for changes using conditional requests.
+package providers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	// remoteRequestTimeout bounds a single request of the default HTTP client
+	remoteRequestTimeout = 10 * time.Second
+	// remoteDefaultPoll is how often a watched document is polled
+	remoteDefaultPoll = 30 * time.Second
+	// remoteDefaultAttempts is how often a failed fetch is tried in total
+	remoteDefaultAttempts = 3
+	// remoteDefaultBackoff is the delay before the first retry; it doubles per attempt
+	remoteDefaultBackoff = 500 * time.Millisecond
+	// remoteMaxBody limits the size of a fetched document
+	remoteMaxBody = 10 << 20
+)
+
+// RemoteOption configures a RemoteProvider.
+type RemoteOption func(*RemoteProvider)
+
+// WithRemoteFormat sets the format of the document, e.g. "json" or "yaml". By
+// default it is derived from the URL's file extension and, if that is unknown,
+// detected from the content.
+func WithRemoteFormat(format string) RemoteOption {
+	return func(r *RemoteProvider) {
+		r.format = format
+	}
+}
+
+// WithRemoteHeader adds a header sent with every request, e.g. an Authorization
+// header.
+func WithRemoteHeader(key, value string) RemoteOption {
+	return func(r *RemoteProvider) {
+		r.header.Add(key, value)
+	}
+}
+
+// WithRemotePollInterval sets how often a watched document is polled, 30 seconds
+// by default.
+func WithRemotePollInterval(interval time.Duration) RemoteOption {
+	return func(r *RemoteProvider) {
+		if interval > 0 {
+			r.poll = interval
+		}
+	}
+}
+
+// WithRemoteRetry sets how often a failed fetch is attempted in total and the
+// delay before the first retry, which doubles with every further attempt.
+// Network errors and 5xx or 429 responses are retried, other responses are not.
+func WithRemoteRetry(attempts int, backoff time.Duration) RemoteOption {
+	return func(r *RemoteProvider) {
+		if attempts > 0 {
+			r.attempts = attempts
+		}
+		if backoff >= 0 {
+			r.backoff = backoff
+		}
+	}
+}
+
+// WithRemoteHTTPClient sets the HTTP client used for requests, e.g. one with TLS
+// client certificates or a custom CA.
+func WithRemoteHTTPClient(client *http.Client) RemoteOption {
+	return func(r *RemoteProvider) {
+		if client != nil {
+			r.client = client
+		}
+	}
+}
+
+// RemoteProvider fetches a JSON or YAML document from an HTTP(S) URL. Requests
+// carry the ETag and Last-Modified values of the previous response, so an
+// unchanged document costs a 304 response and is served from memory. While
+// watched the URL is polled and a changed document is reported like a file
+// change, so the configuration reloads.
+type RemoteProvider struct {
+	// client performs the HTTP requests
+	client *http.Client
+	// url is the address of the document
+	url *url.URL
+	// header holds additional request headers
+	header http.Header
+	// format is the document format, empty to derive it
+	format string
+	// poll is the polling interval while watched
+	poll time.Duration
+	// attempts is the total number of tries of a failed fetch
+	attempts int
+	// backoff is the delay before the first retry
+	backoff time.Duration
+
+	// fetchMu serializes fetches so validators and cached body stay consistent
+	fetchMu sync.Mutex
+	// body is the most recently fetched document
+	body []byte
+	// etag is the ETag of the most recent response, empty if none
+	etag string
+	// lastModified is the Last-Modified value of the most recent response
+	lastModified string
+
+	// mu protects the watch state
+	mu sync.Mutex
+	// cancel stops the watch loop, nil if not watching
+	cancel context.CancelFunc
+	// done is closed when the watch loop has exited
+	done chan struct{}
+}
+
+// retryableError marks fetch failures that are worth retrying
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// NewRemoteProvider creates a provider for the document at rawURL, which must be
+// an http or https URL.
+func NewRemoteProvider(rawURL string, opts ...RemoteOption) (*RemoteProvider, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote config url %s: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("remote config url %s must use http or https", rawURL)
+	}
+
+	r := &RemoteProvider{
+		client:   &http.Client{Timeout: remoteRequestTimeout},
+		url:      u,
+		header:   http.Header{},
+		poll:     remoteDefaultPoll,
+		attempts: remoteDefaultAttempts,
+		backoff:  remoteDefaultBackoff,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+
+	if r.format != "" {
+		if _, err := ParserForFormat(r.format); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Name returns an identifier for the provider, the URL with any password redacted,
+// e.g. "remote:https://config.example.com/app.yaml".
+func (r *RemoteProvider) Name() string {
+	return "remote:" + r.url.Redacted()
+}
+
+// RequiredParser implements the ParserProvider interface. It returns the parser
+// for the configured format or the URL's extension, and a parser that detects
+// JSON or YAML from the content otherwise.
+func (r *RemoteProvider) RequiredParser() koanf.Parser {
+	format := r.format
+	if format == "" {
+		format = strings.TrimPrefix(path.Ext(r.url.Path), ".")
+	}
+	if parser, err := ParserForFormat(format); err == nil {
+		return parser
+	}
+	return SniffingParser()
+}
+
+// ReadBytes implements the koanf.Provider interface by fetching the document,
+// or returning the cached copy if the server reports it unchanged.
+func (r *RemoteProvider) ReadBytes() ([]byte, error) {
+	body, _, err := r.fetch(context.Background())
+	return body, err
+}
+
+// Read implements the koanf.Provider interface by fetching and parsing the document.
+func (r *RemoteProvider) Read() (map[string]any, error) {
+	body, err := r.ReadBytes()
+	if err != nil {
+		return nil, err
+	}
+	return r.RequiredParser().Unmarshal(body)
+}
+
+// fetch returns the current document and whether it differs from the previously
+// fetched one. Retryable failures are retried with exponential backoff.
+func (r *RemoteProvider) fetch(ctx context.Context) ([]byte, bool, error) {
+	r.fetchMu.Lock()
+	defer r.fetchMu.Unlock()
+
+	delay := r.backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var body []byte
+		var changed bool
+		body, changed, err = r.fetchOnce(ctx)
+		if err == nil {
+			return body, changed, nil
+		}
+
+		var retryable *retryableError
+		if !errors.As(err, &retryable) || attempt >= r.attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, false, fmt.Errorf("failed to fetch remote config %s: %w", r.url.Redacted(), ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	return nil, false, fmt.Errorf("failed to fetch remote config %s: %w", r.url.Redacted(), err)
+}
+
+// fetchOnce sends a single conditional request. The caller must hold fetchMu.
+func (r *RemoteProvider) fetchOnce(ctx context.Context) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	if r.body != nil {
+		if r.etag != "" {
+			req.Header.Set("If-None-Match", r.etag)
+		}
+		if r.lastModified != "" {
+			req.Header.Set("If-Modified-Since", r.lastModified)
+		}
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, false, &retryableError{err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && r.body != nil:
+		return r.body, false, nil
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return nil, false, &retryableError{errors.New(resp.Status)}
+	default:
+		return nil, false, errors.New(resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, remoteMaxBody))
+	if err != nil {
+		return nil, false, &retryableError{err}
+	}
+
+	changed := r.body != nil && !bytes.Equal(body, r.body)
+	r.body = body
+	r.etag = resp.Header.Get("ETag")
+	r.lastModified = resp.Header.Get("Last-Modified")
+	return body, changed, nil
+}
+
+// Watch polls the URL at the poll interval and calls cb with the URL whenever the
+// document changed. Failed polls are reported through cb and retried on the next
+// tick until Unwatch is called.
+func (r *RemoteProvider) Watch(cb func(event any, err error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return nil // Already watching
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go r.watchLoop(ctx, cb, r.done)
+
+	return nil
+}
+
+// watchLoop polls the URL until ctx is cancelled
+func (r *RemoteProvider) watchLoop(ctx context.Context, cb func(event any, err error), done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.poll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, changed, err := r.fetch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			cb(nil, err)
+			continue
+		}
+		if changed {
+			cb(r.url.Redacted(), nil)
+		}
+	}
+}
+
+// Unwatch stops polling and waits for the watch loop to exit.
+func (r *RemoteProvider) Unwatch() error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return nil // Not watching
+	}
+
+	cancel()
+	<-done
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements content sniffing for sources whose format is unknown.
+package providers
+
+import (
+	"bytes"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/v2"
+)
+
+// DetectFormat guesses the format of a configuration document from its content.
+// Documents starting with '{' or '[' after leading whitespace (and an optional
+// UTF-8 byte order mark) are reported as "json", everything else as "yaml".
+func DetectFormat(b []byte) string {
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+	b = bytes.TrimLeft(b, " \t\r\n")
+	if len(b) > 0 && (b[0] == '{' || b[0] == '[') {
+		return "json"
+	}
+	return "yaml"
+}
+
+// sniffingParser is a koanf.Parser that picks the JSON or YAML parser per
+// document based on DetectFormat.
+type sniffingParser struct{}
+
+// SniffingParser returns a parser that detects whether each document is JSON
+// or YAML from its content. It is used as the fallback for sources without a
+// known format when format sniffing is enabled on the ProviderFactory.
+func SniffingParser() koanf.Parser {
+	return sniffingParser{}
+}
+
+// Unmarshal parses b with the parser matching its detected format.
+func (sniffingParser) Unmarshal(b []byte) (map[string]any, error) {
+	if DetectFormat(b) == "json" {
+		return json.Parser().Unmarshal(b)
+	}
+	return YAMLParser().Unmarshal(b)
+}
+
+// Marshal serializes o as YAML, which is also a superset of JSON.
+func (sniffingParser) Marshal(o map[string]any) ([]byte, error) {
+	return YAMLParser().Marshal(o)
+}
@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/providers/env"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"Object", `{"name":"app"}`, "json"},
+		{"Array", `[1, 2]`, "json"},
+		{"LeadingWhitespace", "\n\t  {\"name\":\"app\"}", "json"},
+		{"ByteOrderMark", "\xef\xbb\xbf{\"name\":\"app\"}", "json"},
+		{"Mapping", "name: app\nport: 8080\n", "yaml"},
+		{"DocumentMarker", "---\nname: app\n", "yaml"},
+		{"Comment", "# settings\nname: app\n", "yaml"},
+		{"Empty", "", "yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectFormat([]byte(tt.content)))
+		})
+	}
+}
+
+func TestSniffingParser(t *testing.T) {
+	parser := SniffingParser()
+
+	data, err := parser.Unmarshal([]byte(` {"name": "json-app", "server": {"port": 80}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "json-app", data["name"])
+	assert.Equal(t, map[string]any{"port": float64(80)}, data["server"])
+
+	data, err = parser.Unmarshal([]byte("name: yaml-app\nserver:\n  port: 80\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "yaml-app", data["name"])
+	assert.Equal(t, map[string]any{"port": 80}, data["server"])
+
+	_, err = parser.Unmarshal([]byte(`{"name": `))
+	assert.Error(t, err)
+}
+
+func TestProviderFactory_FormatSniffing(t *testing.T) {
+	factory := NewProviderFactory()
+	factory.SetFormatSniffing(true)
+
+	configs, err := factory.CreateProviders(
+		rawbytes.Provider([]byte("name: app\n")),
+		"config.conf",
+		"config.json",
+		env.Provider("APP_", ".", nil),
+	)
+	require.NoError(t, err)
+	require.Len(t, configs, 4)
+
+	// Unknown formats fall back to sniffing, known ones are unchanged
+	assert.IsType(t, SniffingParser(), configs[0].Parser)
+	assert.IsType(t, SniffingParser(), configs[1].Parser)
+	assert.IsType(t, json.Parser(), configs[2].Parser)
+	assert.Nil(t, configs[3].Parser)
+
+	data, err := configs[0].Provider.ReadBytes()
+	require.NoError(t, err)
+	parsed, err := configs[0].Parser.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, "app", parsed["name"])
+
+	// Sniffing can be disabled again
+	factory.SetFormatSniffing(false)
+	assert.IsType(t, json.Parser(), factory.detectParserRequirement(rawbytes.Provider(nil)))
+	assert.IsType(t, YAMLParser(), factory.getParserForFile("config.conf"))
+}
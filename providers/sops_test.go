@@ -0,0 +1,65 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSops is a stand-in for the sops executable that "decrypts" a file by
+// stripping ENC[...] markers, and fails unless FAKE_SOPS_KEY is set
+const fakeSops = `#!/bin/sh
+[ "$1" = "--decrypt" ] || { echo "unexpected arguments: $*" >&2; exit 2; }
+[ -n "$FAKE_SOPS_KEY" ] || { echo "Failed to get the data key required to decrypt the SOPS file." >&2; exit 128; }
+for last; do :; done
+sed -e 's/ENC\[\([^]]*\)\]/\1/g' "$last"
+`
+
+func writeFakeSops(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake sops script requires a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), "sops")
+	require.NoError(t, os.WriteFile(path, []byte(fakeSops), 0o755))
+	return path
+}
+
+func TestSopsFile(t *testing.T) {
+	binary := writeFakeSops(t)
+	path := filepath.Join(t.TempDir(), "secrets.enc.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("database:\n  password: ENC[s3cret]\n"), 0o600))
+
+	t.Run("decrypts", func(t *testing.T) {
+		sops, err := NewSopsFile(path, WithSopsBinary(binary), WithSopsEnv("FAKE_SOPS_KEY=1"))
+		require.NoError(t, err)
+		assert.Equal(t, "sops:"+sops.GetFilePath(), sops.Name())
+
+		k := koanf.New(".")
+		require.NoError(t, k.Load(sops, sops.RequiredParser()))
+		assert.Equal(t, "s3cret", k.String("database.password"))
+	})
+
+	t.Run("reports sops errors", func(t *testing.T) {
+		sops, err := NewSopsFile(path, WithSopsBinary(binary))
+		require.NoError(t, err)
+
+		_, err = sops.ReadBytes()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to decrypt sops file")
+		assert.Contains(t, err.Error(), "Failed to get the data key")
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		_, err := NewSopsFile(filepath.Join(t.TempDir(), "secrets.env"))
+		assert.Error(t, err)
+
+		_, err = NewSopsFile(filepath.Join(t.TempDir(), "secrets.enc"), WithSopsFormat("json"))
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,401 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements a provider that reads secrets from a HashiCorp Vault KV v2
+// secrets engine, keeps its token alive and re-reads secrets when they change.
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	// vaultRequestTimeout bounds a single request to Vault
+	vaultRequestTimeout = 10 * time.Second
+	// vaultDefaultRefresh is how often a watched secret is re-read without a lease
+	vaultDefaultRefresh = 5 * time.Minute
+	// vaultMinInterval is the shortest delay between two watch iterations
+	vaultMinInterval = time.Second
+)
+
+// VaultOption configures a VaultProvider.
+type VaultOption func(*VaultProvider)
+
+// WithVaultToken sets the token used to authenticate. It defaults to the
+// VAULT_TOKEN environment variable.
+func WithVaultToken(token string) VaultOption {
+	return func(v *VaultProvider) {
+		v.token = token
+	}
+}
+
+// WithVaultMount sets the mount path of the KV v2 secrets engine, "secret" by default.
+func WithVaultMount(mount string) VaultOption {
+	return func(v *VaultProvider) {
+		v.mount = strings.Trim(mount, "/")
+	}
+}
+
+// WithVaultNamespace sets the Vault Enterprise namespace of all requests.
+func WithVaultNamespace(namespace string) VaultOption {
+	return func(v *VaultProvider) {
+		v.namespace = namespace
+	}
+}
+
+// WithVaultKeyPath merges the secret's fields below the given dotted configuration
+// path instead of at the root, e.g. "database" turns the field "password" into
+// database.password.
+func WithVaultKeyPath(path string) VaultOption {
+	return func(v *VaultProvider) {
+		v.keyPath = path
+	}
+}
+
+// WithVaultRefreshInterval sets how often a watched secret is re-read when Vault
+// reports no lease duration for it, 5 minutes by default.
+func WithVaultRefreshInterval(interval time.Duration) VaultOption {
+	return func(v *VaultProvider) {
+		if interval > 0 {
+			v.refresh = interval
+		}
+	}
+}
+
+// WithVaultHTTPClient sets the HTTP client used for requests, e.g. one with TLS
+// client certificates or a custom CA.
+func WithVaultHTTPClient(client *http.Client) VaultOption {
+	return func(v *VaultProvider) {
+		if client != nil {
+			v.client = client
+		}
+	}
+}
+
+// VaultProvider reads one secret from a Vault KV v2 secrets engine and merges its
+// fields into the configuration. While watched it renews its token before the
+// token expires and re-reads the secret when its lease runs out or, without a
+// lease, periodically; a new secret version is reported like a file change so the
+// configuration reloads.
+type VaultProvider struct {
+	// client performs the HTTP requests
+	client *http.Client
+	// addr is the base URL of the Vault server
+	addr *url.URL
+	// mount is the mount path of the KV v2 secrets engine
+	mount string
+	// path is the path of the secret within the mount
+	path string
+	// token authenticates requests
+	token string
+	// namespace is the Vault Enterprise namespace, empty for none
+	namespace string
+	// keyPath is the dotted configuration path the secret's fields are merged under
+	keyPath string
+	// refresh is the re-read interval of watched secrets without a lease
+	refresh time.Duration
+	// minInterval is the shortest delay between two watch iterations
+	minInterval time.Duration
+
+	// mu protects version, lease and the watch state
+	mu sync.Mutex
+	// version is the secret version returned by the most recent Read
+	version int64
+	// lease is the lease duration returned by the most recent Read
+	lease time.Duration
+	// cancel stops the watch loop, nil if not watching
+	cancel context.CancelFunc
+	// done is closed when the watch loop has exited
+	done chan struct{}
+}
+
+// vaultSecret is the response of a KV v2 read
+type vaultSecret struct {
+	LeaseDuration int `json:"lease_duration"`
+	Data          struct {
+		Data     map[string]any `json:"data"`
+		Metadata struct {
+			Version int64 `json:"version"`
+		} `json:"metadata"`
+	} `json:"data"`
+}
+
+// vaultTokenInfo holds the fields of token lookup and renewal responses
+type vaultTokenInfo struct {
+	Data struct {
+		TTL       int  `json:"ttl"`
+		Renewable bool `json:"renewable"`
+	} `json:"data"`
+	Auth struct {
+		LeaseDuration int  `json:"lease_duration"`
+		Renewable     bool `json:"renewable"`
+	} `json:"auth"`
+}
+
+// NewVaultProvider creates a provider for the secret at path of the KV v2 engine
+// on the Vault server at addr, e.g. "https://vault:8200" and "myapp/db".
+func NewVaultProvider(addr, path string, opts ...VaultOption) (*VaultProvider, error) {
+	if addr == "" {
+		return nil, errors.New("vault provider requires an address")
+	}
+	if path == "" {
+		return nil, errors.New("vault provider requires a secret path")
+	}
+
+	base, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault address %s: %w", addr, err)
+	}
+
+	v := &VaultProvider{
+		client:      &http.Client{Timeout: vaultRequestTimeout},
+		addr:        base,
+		mount:       "secret",
+		path:        strings.Trim(path, "/"),
+		token:       os.Getenv("VAULT_TOKEN"),
+		refresh:     vaultDefaultRefresh,
+		minInterval: vaultMinInterval,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(v)
+		}
+	}
+
+	if v.token == "" {
+		return nil, errors.New("vault provider requires a token")
+	}
+	return v, nil
+}
+
+// Name returns an identifier for the provider, e.g. "vault:secret/myapp/db".
+func (v *VaultProvider) Name() string {
+	return "vault:" + v.mount + "/" + v.path
+}
+
+// Read implements the koanf.Provider interface by reading the latest version of
+// the secret and returning its fields, nested below the key path if one is set.
+func (v *VaultProvider) Read() (map[string]any, error) {
+	secret, err := v.readSecret(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	v.version = secret.Data.Metadata.Version
+	v.lease = time.Duration(secret.LeaseDuration) * time.Second
+	v.mu.Unlock()
+
+	data := secret.Data.Data
+	if data == nil {
+		data = map[string]any{}
+	}
+	if v.keyPath == "" {
+		return data, nil
+	}
+
+	// Wrap the fields in one map per path segment, innermost first
+	segments := strings.Split(v.keyPath, ".")
+	for i := len(segments) - 1; i >= 0; i-- {
+		data = map[string]any{segments[i]: data}
+	}
+	return data, nil
+}
+
+// ReadBytes implements the koanf.Provider interface but is not supported,
+// since secrets are returned as structured data.
+func (v *VaultProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("ReadBytes method not implemented, use Read instead")
+}
+
+// RequiredParser implements the ParserProvider interface. Read returns
+// structured data, so no parser is required.
+func (v *VaultProvider) RequiredParser() koanf.Parser {
+	return nil
+}
+
+// readSecret fetches the latest version of the secret
+func (v *VaultProvider) readSecret(ctx context.Context) (*vaultSecret, error) {
+	var secret vaultSecret
+	if err := v.do(ctx, http.MethodGet, v.mount+"/data/"+v.path, &secret); err != nil {
+		return nil, fmt.Errorf("failed to read vault secret %s: %w", v.Name(), err)
+	}
+	return &secret, nil
+}
+
+// do sends an authenticated request to the Vault API path and decodes the JSON
+// response into out.
+func (v *VaultProvider) do(ctx context.Context, method, path string, out any) error {
+	var body io.Reader
+	if method == http.MethodPost {
+		body = bytes.NewReader([]byte("{}"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, v.addr.JoinPath("v1", path).String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Watch keeps the token alive and re-reads the secret when its lease expires or,
+// without a lease, at the refresh interval. cb is called with the new version
+// whenever the secret changed. Failures are reported through cb and retried on
+// the next iteration until Unwatch is called.
+func (v *VaultProvider) Watch(cb func(event any, err error)) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.cancel != nil {
+		return nil // Already watching
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	v.cancel = cancel
+	v.done = make(chan struct{})
+	go v.watchLoop(ctx, cb, v.done)
+
+	return nil
+}
+
+// watchLoop renews the token and re-reads the secret until ctx is cancelled
+func (v *VaultProvider) watchLoop(ctx context.Context, cb func(event any, err error), done chan struct{}) {
+	defer close(done)
+
+	now := time.Now()
+	renewAt := v.nextRenewal(ctx, now, cb)
+	readAt := now.Add(v.readInterval())
+
+	for {
+		next := readAt
+		if !renewAt.IsZero() && renewAt.Before(next) {
+			next = renewAt
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(max(time.Until(next), v.minInterval)):
+		}
+
+		now = time.Now()
+		if !renewAt.IsZero() && !now.Before(renewAt) {
+			renewAt = v.renewToken(ctx, now, cb)
+		}
+		if !now.Before(readAt) {
+			v.checkVersion(ctx, cb)
+			readAt = now.Add(v.readInterval())
+		}
+	}
+}
+
+// readInterval returns how long to wait before re-reading the secret: its lease
+// duration if Vault reported one, the refresh interval otherwise
+func (v *VaultProvider) readInterval() time.Duration {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.lease > 0 {
+		return v.lease
+	}
+	return v.refresh
+}
+
+// checkVersion re-reads the secret and calls cb if its version changed
+func (v *VaultProvider) checkVersion(ctx context.Context, cb func(event any, err error)) {
+	secret, err := v.readSecret(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			cb(nil, err)
+		}
+		return
+	}
+
+	v.mu.Lock()
+	changed := secret.Data.Metadata.Version != v.version
+	v.mu.Unlock()
+
+	if changed {
+		// Read records the new version when the manager reloads
+		cb(secret.Data.Metadata.Version, nil)
+	}
+}
+
+// nextRenewal looks up the token and returns when it should be renewed, or the
+// zero time if it does not expire or cannot be renewed. If the lookup fails the
+// renewal is attempted soon, so the token does not expire unnoticed.
+func (v *VaultProvider) nextRenewal(ctx context.Context, now time.Time, cb func(event any, err error)) time.Time {
+	var info vaultTokenInfo
+	if err := v.do(ctx, http.MethodGet, "auth/token/lookup-self", &info); err != nil {
+		if ctx.Err() == nil {
+			cb(nil, fmt.Errorf("failed to look up vault token: %w", err))
+		}
+		return now.Add(v.minInterval)
+	}
+	return renewalTime(now, info.Data.TTL, info.Data.Renewable)
+}
+
+// renewToken renews the token and returns when it should be renewed next
+func (v *VaultProvider) renewToken(ctx context.Context, now time.Time, cb func(event any, err error)) time.Time {
+	var info vaultTokenInfo
+	if err := v.do(ctx, http.MethodPost, "auth/token/renew-self", &info); err != nil {
+		if ctx.Err() == nil {
+			cb(nil, fmt.Errorf("failed to renew vault token: %w", err))
+		}
+		// Retry soon; the token may still be valid for a while
+		return now.Add(v.minInterval)
+	}
+	return renewalTime(now, info.Auth.LeaseDuration, info.Auth.Renewable)
+}
+
+// renewalTime schedules a renewal after two thirds of a token's ttl in seconds.
+// Tokens without ttl never expire and non-renewable tokens cannot be renewed.
+func renewalTime(now time.Time, ttl int, renewable bool) time.Time {
+	if ttl <= 0 || !renewable {
+		return time.Time{}
+	}
+	return now.Add(time.Duration(ttl) * time.Second * 2 / 3)
+}
+
+// Unwatch stops watching and waits for the watch loop to exit.
+func (v *VaultProvider) Unwatch() error {
+	v.mu.Lock()
+	cancel, done := v.cancel, v.done
+	v.cancel, v.done = nil, nil
+	v.mu.Unlock()
+
+	if cancel == nil {
+		return nil // Not watching
+	}
+
+	cancel()
+	<-done
+	return nil
+}
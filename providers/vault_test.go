@@ -0,0 +1,201 @@
+package providers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault serves a single KV v2 secret and the token endpoints
+type fakeVault struct {
+	mu       sync.Mutex
+	version  int64
+	data     map[string]any
+	tokenTTL int
+	renewals int
+	// lookupErrors is the number of token lookups still to fail
+	lookupErrors int
+}
+
+func (f *fakeVault) setSecret(data map[string]any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data = data
+	f.version++
+}
+
+func (f *fakeVault) renewCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.renewals
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("X-Vault-Token") != "s.token" || r.Header.Get("X-Vault-Namespace") != "team" {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+		return
+	}
+
+	var resp any
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/kv/data/myapp/db":
+		resp = map[string]any{
+			"lease_duration": 0,
+			"data": map[string]any{
+				"data":     f.data,
+				"metadata": map[string]any{"version": f.version},
+			},
+		}
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/auth/token/lookup-self":
+		if f.lookupErrors > 0 {
+			f.lookupErrors--
+			http.Error(w, `{"errors":["internal error"]}`, http.StatusInternalServerError)
+			return
+		}
+		resp = map[string]any{"data": map[string]any{"ttl": f.tokenTTL, "renewable": true}}
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/auth/token/renew-self":
+		f.renewals++
+		resp = map[string]any{"auth": map[string]any{"lease_duration": f.tokenTTL, "renewable": true}}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newTestVaultProvider(t *testing.T, vault *fakeVault, opts ...VaultOption) *VaultProvider {
+	t.Helper()
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+
+	opts = append([]VaultOption{
+		WithVaultToken("s.token"),
+		WithVaultNamespace("team"),
+		WithVaultMount("/kv/"),
+	}, opts...)
+	provider, err := NewVaultProvider(server.URL, "myapp/db", opts...)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestVaultProvider_Read(t *testing.T) {
+	vault := &fakeVault{}
+	vault.setSecret(map[string]any{"password": "hunter2", "port": 5432})
+
+	provider := newTestVaultProvider(t, vault, WithVaultKeyPath("database.primary"))
+	assert.Equal(t, "vault:kv/myapp/db", provider.Name())
+	assert.Nil(t, provider.RequiredParser())
+
+	data, err := provider.Read()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"database": map[string]any{
+			"primary": map[string]any{"password": "hunter2", "port": float64(5432)},
+		},
+	}, data)
+
+	// Without key path the fields are merged at the root
+	data, err = newTestVaultProvider(t, vault).Read()
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", data["password"])
+
+	// Errors include Vault's response
+	denied := newTestVaultProvider(t, vault, WithVaultToken("wrong"))
+	_, err = denied.Read()
+	assert.ErrorContains(t, err, "failed to read vault secret vault:kv/myapp/db: 403 Forbidden")
+
+	t.Setenv("VAULT_TOKEN", "")
+	_, err = NewVaultProvider("http://vault:8200", "myapp/db")
+	assert.ErrorContains(t, err, "requires a token")
+
+	t.Setenv("VAULT_TOKEN", "s.env")
+	fromEnv, err := NewVaultProvider("http://vault:8200", "myapp/db")
+	require.NoError(t, err)
+	assert.Equal(t, "s.env", fromEnv.token)
+}
+
+func TestVaultProvider_Watch(t *testing.T) {
+	vault := &fakeVault{tokenTTL: 1}
+	vault.setSecret(map[string]any{"password": "old"})
+
+	provider := newTestVaultProvider(t, vault, WithVaultRefreshInterval(20*time.Millisecond))
+	provider.minInterval = 10 * time.Millisecond
+
+	_, err := provider.Read()
+	require.NoError(t, err)
+
+	// Until the manager re-reads the secret, the new version is reported on every check
+	received := make(chan any, 1)
+	require.NoError(t, provider.Watch(func(event any, err error) {
+		if err == nil {
+			select {
+			case received <- event:
+			default:
+			}
+		}
+	}))
+	// A second Watch is a no-op while watching
+	require.NoError(t, provider.Watch(func(any, error) {}))
+
+	// The token is renewed before its ttl of one second expires
+	require.Eventually(t, func() bool { return vault.renewCount() >= 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Unchanged secrets are not reported
+	assert.Empty(t, received)
+
+	vault.setSecret(map[string]any{"password": "new"})
+	select {
+	case event := <-received:
+		assert.Equal(t, int64(2), event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("new secret version was not reported")
+	}
+
+	require.NoError(t, provider.Unwatch())
+	require.NoError(t, provider.Unwatch())
+}
+
+func TestVaultProvider_WatchLookupFailure(t *testing.T) {
+	vault := &fakeVault{tokenTTL: 60, lookupErrors: 1}
+	vault.setSecret(map[string]any{"password": "old"})
+
+	provider := newTestVaultProvider(t, vault, WithVaultRefreshInterval(time.Hour))
+	provider.minInterval = 10 * time.Millisecond
+
+	errs := make(chan error, 10)
+	require.NoError(t, provider.Watch(func(event any, err error) {
+		if err != nil {
+			select {
+			case errs <- err:
+			default:
+			}
+		}
+	}))
+	defer provider.Unwatch()
+
+	// The failed lookup is reported and the token is renewed regardless
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "failed to look up vault token")
+	case <-time.After(5 * time.Second):
+		t.Fatal("lookup failure was not reported")
+	}
+	require.Eventually(t, func() bool { return vault.renewCount() >= 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRenewalTime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, now.Add(40*time.Second), renewalTime(now, 60, true))
+	assert.True(t, renewalTime(now, 0, true).IsZero())
+	assert.True(t, renewalTime(now, 60, false).IsZero())
+}
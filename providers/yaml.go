@@ -0,0 +1,57 @@
+// Package providers implements a factory pattern for creating koanf providers
+// with automatic parser detection and configuration management.
+// This file implements a YAML parser that accepts multi-document streams.
+package providers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// yamlParser is a koanf.Parser for YAML streams of one or more documents.
+type yamlParser struct{}
+
+// YAMLParser returns a parser for YAML documents. Unlike the koanf YAML parser
+// it reads every document of a multi-document stream, separated by "---" lines,
+// and merges them in order, later documents overriding earlier ones. Empty
+// documents are skipped.
+func YAMLParser() koanf.Parser {
+	return yamlParser{}
+}
+
+// Unmarshal parses all documents of b and merges them into a single map.
+func (yamlParser) Unmarshal(b []byte) (map[string]any, error) {
+	var out map[string]any
+
+	decoder := yamlv3.NewDecoder(bytes.NewReader(b))
+	for {
+		var doc map[string]any
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			continue
+		}
+
+		if out == nil {
+			out = doc
+			continue
+		}
+		maps.Merge(doc, out)
+	}
+}
+
+// Marshal serializes o as a single YAML document.
+func (yamlParser) Marshal(o map[string]any) ([]byte, error) {
+	return yaml.Parser().Marshal(o)
+}
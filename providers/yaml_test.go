@@ -0,0 +1,43 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLParser_MultiDocument(t *testing.T) {
+	data, err := YAMLParser().Unmarshal([]byte(`---
+server:
+  host: base
+  port: 80
+tags: [a]
+---
+# empty documents are skipped
+---
+server:
+  port: 8080
+tags: [b, c]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"server": map[string]any{"host": "base", "port": 8080},
+		"tags":   []any{"b", "c"},
+	}, data)
+
+	data, err = YAMLParser().Unmarshal([]byte("name: single\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "single"}, data)
+
+	data, err = YAMLParser().Unmarshal(nil)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	_, err = YAMLParser().Unmarshal([]byte("name: ok\n---\n- not a mapping\n"))
+	assert.Error(t, err)
+
+	out, err := YAMLParser().Marshal(map[string]any{"name": "app"})
+	require.NoError(t, err)
+	assert.Equal(t, "name: app\n", string(out))
+}
@@ -0,0 +1,139 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the masking of secret configuration fields, marked with
+// a `vcfg:"secret"` or `sensitive:"true"` struct tag, in output meant for logs.
+package vcfg
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/nextpkg/vcfg/plugins"
+)
+
+// redactedValue replaces the values of secret fields
+const redactedValue = "[REDACTED]"
+
+// isSecretField reports whether field is marked as secret with `vcfg:"secret"`
+// or `sensitive:"true"`
+func isSecretField(field reflect.StructField) bool {
+	if tag, ok := field.Tag.Lookup("vcfg"); ok {
+		for option := range strings.SplitSeq(tag, ",") {
+			if strings.TrimSpace(option) == "secret" {
+				return true
+			}
+		}
+	}
+	if tag, ok := field.Tag.Lookup("sensitive"); ok {
+		sensitive, _ := strconv.ParseBool(tag)
+		return sensitive
+	}
+	return false
+}
+
+// Redact returns a copy of cfg that is safe to log: every field marked as
+// secret, e.g.
+//
+//	Password string `koanf:"password" vcfg:"secret"`
+//
+// is masked. Non-empty strings become "[REDACTED]" and other values their zero
+// value; a secret struct, slice or map is masked as a whole. Diff masks the
+// values of secret fields the same way, so changes can be logged safely too.
+func Redact[T any](cfg *T) *T {
+	if cfg == nil {
+		return nil
+	}
+	clone := plugins.DeepCopy(cfg)
+	redactValue(reflect.ValueOf(clone).Elem())
+	return clone
+}
+
+// redactValue masks the secret fields found anywhere below v, which must be settable
+func redactValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			redactValue(v.Elem())
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			v.Set(settableCopy(v.Elem(), redactValue))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range v.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if isSecretField(field) {
+				maskValue(v.Field(i))
+			} else {
+				redactValue(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			redactValue(v.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			v.SetMapIndex(key, settableCopy(v.MapIndex(key), redactValue))
+		}
+	}
+}
+
+// maskValue masks v and everything below it, which must be settable
+func maskValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.Len() > 0 {
+			v.SetString(redactedValue)
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			maskValue(v.Elem())
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			v.Set(settableCopy(v.Elem(), maskValue))
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				maskValue(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			maskValue(v.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			v.SetMapIndex(key, settableCopy(v.MapIndex(key), maskValue))
+		}
+	default:
+		v.Set(reflect.Zero(v.Type()))
+	}
+}
+
+// settableCopy returns a settable copy of v, which may be a map entry or the
+// value of an interface, after applying fn to it
+func settableCopy(v reflect.Value, fn func(reflect.Value)) reflect.Value {
+	copied := reflect.New(v.Type()).Elem()
+	copied.Set(v)
+	fn(copied)
+	return copied
+}
+
+// maskChange masks the values of a change to a secret field
+func maskChange(change Change) Change {
+	change.Secret = true
+	if change.OldValue != nil {
+		change.OldValue = redactedValue
+	}
+	if change.NewValue != nil {
+		change.NewValue = redactedValue
+	}
+	return change
+}
@@ -0,0 +1,80 @@
+package vcfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type RedactTestCredentials struct {
+	User  string `koanf:"user"`
+	Token string `koanf:"token"`
+}
+
+type RedactTestConfig struct {
+	Database struct {
+		Host     string `koanf:"host"`
+		Password string `koanf:"password" vcfg:"secret"`
+		Port     int    `koanf:"port"`
+	} `koanf:"database"`
+	APIKeys     []string               `koanf:"api_keys" sensitive:"true"`
+	Credentials *RedactTestCredentials `koanf:"credentials" vcfg:"secret"`
+	Services    map[string]struct {
+		URL   string `koanf:"url"`
+		Token string `koanf:"token" vcfg:"secret"`
+		Retry int    `koanf:"retry" vcfg:"secret"`
+	} `koanf:"services"`
+	Public string `koanf:"public" sensitive:"false"`
+	Empty  string `koanf:"empty" vcfg:"secret"`
+}
+
+func newRedactTestConfig() *RedactTestConfig {
+	cfg := &RedactTestConfig{
+		APIKeys:     []string{"k1", "k2"},
+		Credentials: &RedactTestCredentials{User: "admin", Token: "t0k3n"},
+		Public:      "visible",
+	}
+	cfg.Database.Host = "db"
+	cfg.Database.Password = "s3cret"
+	cfg.Database.Port = 5432
+	cfg.Services = map[string]struct {
+		URL   string `koanf:"url"`
+		Token string `koanf:"token" vcfg:"secret"`
+		Retry int    `koanf:"retry" vcfg:"secret"`
+	}{"billing": {URL: "https://billing", Token: "b1ll", Retry: 3}}
+	return cfg
+}
+
+func TestRedact(t *testing.T) {
+	cfg := newRedactTestConfig()
+	redacted := Redact(cfg)
+
+	assert.Equal(t, "db", redacted.Database.Host)
+	assert.Equal(t, redactedValue, redacted.Database.Password)
+	assert.Equal(t, 5432, redacted.Database.Port)
+	assert.Equal(t, []string{redactedValue, redactedValue}, redacted.APIKeys)
+	assert.Equal(t, &RedactTestCredentials{User: redactedValue, Token: redactedValue}, redacted.Credentials)
+	assert.Equal(t, "https://billing", redacted.Services["billing"].URL)
+	assert.Equal(t, redactedValue, redacted.Services["billing"].Token)
+	assert.Zero(t, redacted.Services["billing"].Retry)
+	assert.Equal(t, "visible", redacted.Public)
+	assert.Empty(t, redacted.Empty)
+
+	// The original is untouched
+	assert.Equal(t, newRedactTestConfig(), cfg)
+	assert.Nil(t, Redact[RedactTestConfig](nil))
+}
+
+func TestDiff_Secrets(t *testing.T) {
+	oldCfg := newRedactTestConfig()
+	newCfg := newRedactTestConfig()
+	newCfg.Database.Password = "rotated"
+	newCfg.Database.Port = 6543
+	newCfg.Credentials.Token = "new"
+
+	assert.Equal(t, []Change{
+		{Path: "credentials.token", OldValue: redactedValue, NewValue: redactedValue, Secret: true},
+		{Path: "database.password", OldValue: redactedValue, NewValue: redactedValue, Secret: true},
+		{Path: "database.port", OldValue: 5432, NewValue: 6543},
+	}, Diff(oldCfg, newCfg))
+}
@@ -0,0 +1,260 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the reload pipeline that re-reads all sources, publishes
+// the new configuration, reloads affected plugins and records a reload trace.
+package vcfg
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// ReloadInfo describes the most recent configuration reload: what triggered it,
+// what changed and which plugins were reloaded as a result.
+type ReloadInfo struct {
+	// Sources lists the sources whose change notifications triggered the reload.
+	// Events coalesced by debouncing are reported together.
+	Sources []string
+	// Time is when the reload started
+	Time time.Time
+	// ChangedPaths lists the configuration key paths whose values changed
+	ChangedPaths []string
+	// Changes holds the changed values with their old and new values, in the
+	// order of ChangedPaths. See Diff.
+	Changes []Change
+	// ReloadedPlugins lists the keys of plugin instances that were reloaded
+	ReloadedPlugins []string
+	// SkippedPlugins lists the keys of plugin instances that were evaluated but not
+	// reloaded because their configuration section did not change
+	SkippedPlugins []string
+	// ThrottledPlugins lists the keys of changed plugin instances whose reload was
+	// postponed because they are backing off after repeated failures
+	ThrottledPlugins []string
+	// StartedPlugins lists the keys of plugin instances the reload started, e.g.
+	// because it enabled them
+	StartedPlugins []string
+	// StoppedPlugins lists the keys of plugin instances the reload shut down, e.g.
+	// because it disabled them
+	StoppedPlugins []string
+	// Err holds the error of a failed reload, nil on success
+	Err error
+}
+
+// ReloadRejected describes a reload that was rejected in strict reload mode
+// because the new configuration could not be loaded or failed validation.
+type ReloadRejected struct {
+	// Sources lists the sources whose change notifications triggered the reload
+	Sources []string
+	// Time is when the reload started
+	Time time.Time
+	// Err is the load or validation error that caused the rejection
+	Err error
+}
+
+// rejectionSubscriber is a registered callback for rejected reloads
+type rejectionSubscriber struct {
+	// id identifies the subscription for cancellation
+	id uint64
+	// notify is called with every rejected reload
+	notify func(ReloadRejected)
+}
+
+// LastReloadInfo returns information about the most recent reload attempt.
+// The zero value is returned if no reload has happened yet.
+func (cm *ConfigManager[T]) LastReloadInfo() ReloadInfo {
+	info, ok := cm.lastReload.Load().(ReloadInfo)
+	if !ok {
+		return ReloadInfo{}
+	}
+	return info
+}
+
+// manualSource is the trigger source recorded in ReloadInfo for Reload calls
+const manualSource = "manual"
+
+// Reload re-reads all sources, validates the result and, if it is valid, makes
+// it the current configuration and reloads the plugins whose configuration
+// changed, exactly like a reload triggered by file watching. Use it where
+// watching is not desired, e.g. from a SIGHUP handler or an admin endpoint.
+//
+// An invalid configuration leaves the previous typed configuration active; with
+// WithStrictReload the merged values are kept too and OnReloadRejected
+// callbacks run. LastReloadInfo reports the outcome with the source "manual".
+// Runtime changes made with Set are applied over the sources again.
+func (cm *ConfigManager[T]) Reload(ctx context.Context) error {
+	if cm == nil {
+		return NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+	return cm.reload(ctx, manualSource)
+}
+
+// reloadFromWatch is invoked once a burst of change events has settled.
+// It reloads the configuration and logs the outcome.
+func (cm *ConfigManager[T]) reloadFromWatch(sources []string) {
+	if err := cm.reload(context.Background(), sources...); err != nil {
+		cm.log().Error("Failed to reload configuration", "error", err, "sources", sources)
+		return
+	}
+
+	info := cm.LastReloadInfo()
+	cm.log().Debug("Configuration reloaded successfully",
+		"sources", info.Sources,
+		"changes", info.Changes,
+		"plugins", info.ReloadedPlugins,
+		"skipped_plugins", info.SkippedPlugins,
+		"started_plugins", info.StartedPlugins,
+		"stopped_plugins", info.StoppedPlugins,
+	)
+}
+
+// reload re-reads all sources, stores the new configuration and triggers
+// plugin reloads for plugin configurations that changed. The outcome is
+// recorded as the last ReloadInfo, attributed to the given trigger sources.
+func (cm *ConfigManager[T]) reload(ctx context.Context, sources ...string) (err error) {
+	info := ReloadInfo{
+		Sources: sources,
+		Time:    time.Now(),
+	}
+	defer func() {
+		info.Err = err
+		cm.recordReload(info)
+	}()
+
+	ctx, span := cm.startSpan(ctx, spanReload, attrSources.StringSlice(sources))
+	defer func() { endSpan(span, err) }()
+
+	cm.reloadMu.Lock()
+	defer cm.reloadMu.Unlock()
+
+	// Get old configuration before reload
+	oldConfig := cm.Get()
+
+	// Reload configuration
+	var newConfig *T
+	if cm.strictReload {
+		newConfig, err = cm.loadCandidate(ctx)
+		if err != nil {
+			cm.rejectReload(ReloadRejected{Sources: sources, Time: info.Time, Err: err})
+			return err
+		}
+	} else {
+		newConfig, err = cm.load(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cm.publish(ctx, &info, oldConfig, newConfig)
+}
+
+// loadCandidate loads and validates the configuration from all sources and only
+// replaces the merged values once the result is valid, so a rejected reload
+// leaves the manager exactly as it was.
+func (cm *ConfigManager[T]) loadCandidate(ctx context.Context) (*T, error) {
+	cfg, k, origins, err := cm.loadDetached(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cm.mu.Lock()
+	cm.koanf = k
+	cm.origins = origins
+	cm.mu.Unlock()
+
+	return cfg, nil
+}
+
+// loadDetached loads and validates the configuration from all sources like
+// load, but returns the merged values with the result instead of storing them
+func (cm *ConfigManager[T]) loadDetached(ctx context.Context) (cfg *T, k *koanf.Koanf, origins keyOrigins, err error) {
+	ctx, span := cm.startSpan(ctx, spanLoad)
+	defer func() { endSpan(span, err) }()
+
+	k, origins, err = cm.mergeSources(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	cfg, err = cm.decode(ctx, k)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cfg, k, origins, nil
+}
+
+// rejectReload logs a rejected reload and notifies the rejection subscribers
+func (cm *ConfigManager[T]) rejectReload(event ReloadRejected) {
+	cm.log().Warn("Configuration reload rejected, keeping previous configuration",
+		"error", event.Err, "sources", event.Sources)
+
+	cm.subMu.Lock()
+	subscribers := slices.Clone(cm.rejectionSubscribers)
+	cm.subMu.Unlock()
+
+	for _, s := range subscribers {
+		s.notify(event)
+	}
+}
+
+// OnReloadRejected registers cb to be called whenever a reload is rejected in
+// strict reload mode (see Builder.WithStrictReload). Callbacks run synchronously
+// on the reloading goroutine in registration order. The returned function
+// cancels the subscription.
+func (cm *ConfigManager[T]) OnReloadRejected(cb func(event ReloadRejected)) (unsubscribe func()) {
+	cm.subMu.Lock()
+	defer cm.subMu.Unlock()
+
+	cm.nextSubID++
+	id := cm.nextSubID
+	cm.rejectionSubscribers = append(cm.rejectionSubscribers, rejectionSubscriber{id: id, notify: cb})
+
+	return func() {
+		cm.subMu.Lock()
+		defer cm.subMu.Unlock()
+		cm.rejectionSubscribers = slices.DeleteFunc(cm.rejectionSubscribers, func(s rejectionSubscriber) bool {
+			return s.id == id
+		})
+	}
+}
+
+// publish stores newConfig as the current configuration, notifies subscribers,
+// reloads plugins whose configuration changed and runs the reload hooks. The
+// changed paths and reloaded plugins are recorded in info.
+func (cm *ConfigManager[T]) publish(ctx context.Context, info *ReloadInfo, oldConfig, newConfig *T) error {
+	// Store new configuration and record it in the snapshot history
+	cm.storeConfig(newConfig)
+
+	// Handle plugin configuration changes intelligently
+	if oldConfig != nil {
+		info.Changes = Diff(oldConfig, newConfig)
+		info.ChangedPaths = changePaths(info.Changes)
+		cm.notifySubscribers(oldConfig, newConfig)
+
+		report, err := cm.pluginManager.ReloadWithReport(ctx, oldConfig, newConfig)
+		info.ReloadedPlugins = report.Reloaded
+		info.SkippedPlugins = report.Skipped
+		info.ThrottledPlugins = report.Throttled
+		info.StartedPlugins = report.Started
+		info.StoppedPlugins = report.Stopped
+		if err != nil {
+			return fmt.Errorf("failed to handle smart plugin reload: %w", err)
+		}
+	}
+
+	cm.runReloadHooks(ctx, newConfig)
+
+	return nil
+}
+
+// runReloadHooks calls the registered reload hooks in order. Hook errors are
+// logged and do not revert the configuration or stop later hooks.
+func (cm *ConfigManager[T]) runReloadHooks(ctx context.Context, cfg *T) {
+	for i, hook := range cm.reloadHooks {
+		if err := hook(ctx, cfg); err != nil {
+			cm.log().Error("Reload hook failed", "index", i, "error", err)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package vcfg
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigManager_LastReloadInfo(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\ncache:\n  host: one\n")
+
+	cm, err := NewBuilder[RecorderAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		WithWatch(WithDebounce(20 * time.Millisecond)).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	// No reload happened yet
+	assert.Equal(t, ReloadInfo{}, cm.LastReloadInfo())
+
+	time.Sleep(50 * time.Millisecond)
+	before := time.Now()
+	writeFile(t, configFile, "name: app\ncache:\n  host: two\n")
+
+	require.Eventually(t, func() bool {
+		return cm.Get().Cache.Host == "two" && !cm.LastReloadInfo().Time.IsZero()
+	}, 2*time.Second, 10*time.Millisecond)
+
+	info := cm.LastReloadInfo()
+	require.NoError(t, info.Err)
+	absPath, err := filepath.Abs(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{absPath}, info.Sources)
+	assert.False(t, info.Time.Before(before))
+	assert.Equal(t, []string{"cache.host"}, info.ChangedPaths)
+	assert.Equal(t, []Change{{Path: "cache.host", OldValue: "one", NewValue: "two"}}, info.Changes)
+	assert.Equal(t, []string{"recorder:cache"}, info.ReloadedPlugins)
+}
+
+// TwoRecorderAppConfig holds two recorder plugin instances
+type TwoRecorderAppConfig struct {
+	Cache recorderConfig `koanf:"cache"`
+	Queue recorderConfig `koanf:"queue"`
+}
+
+func TestConfigManager_LastReloadInfo_SkippedPlugins(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "cache:\n  host: one\nqueue:\n  host: q\n")
+
+	cm, err := NewBuilder[TwoRecorderAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	writeFile(t, configFile, "cache:\n  host: two\nqueue:\n  host: q\n")
+	require.NoError(t, cm.reload(context.Background()))
+
+	info := cm.LastReloadInfo()
+	assert.Equal(t, []string{"recorder:cache"}, info.ReloadedPlugins)
+	assert.Equal(t, []string{"recorder:queue"}, info.SkippedPlugins)
+	assert.Empty(t, info.ThrottledPlugins)
+	assert.Equal(t, 0, getRecorder(t, cm, "recorder:queue").reloadCount())
+
+	// A reload without changes evaluates and skips every plugin
+	require.NoError(t, cm.reload(context.Background()))
+	info = cm.LastReloadInfo()
+	assert.Empty(t, info.ReloadedPlugins)
+	assert.ElementsMatch(t, []string{"recorder:cache", "recorder:queue"}, info.SkippedPlugins)
+}
+
+func TestConfigManager_LastReloadInfo_Failure(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\n")
+
+	cm, err := NewBuilder[RecorderAppConfig]().
+		AddFile(configFile).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	writeFile(t, configFile, "name: [broken\n")
+	err = cm.reload(context.Background(), "manual")
+	require.Error(t, err)
+
+	info := cm.LastReloadInfo()
+	assert.Equal(t, []string{"manual"}, info.Sources)
+	assert.Equal(t, err, info.Err)
+	assert.Empty(t, info.ChangedPaths)
+	assert.Equal(t, "app", cm.Get().Name)
+}
+
+func TestReload_RemovedKeyRevertsToDefault(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "cache:\n  host: cachehost\n  port: 1000\n")
+
+	cm, err := NewBuilder[RecorderAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	plugin := getRecorder(t, cm, "recorder:cache")
+	assert.Equal(t, 1000, cm.Get().Cache.Port)
+
+	// Removing the key must revert the field to its struct-tag default
+	writeFile(t, configFile, "cache:\n  host: cachehost\n")
+	require.NoError(t, cm.reload(context.Background()))
+
+	assert.Equal(t, 6379, cm.Get().Cache.Port)
+	assert.False(t, cm.koanf.Exists("cache.port"))
+	require.Equal(t, 1, plugin.reloadCount())
+	assert.Equal(t, 6379, plugin.lastReload().Port)
+	assert.Equal(t, []string{"cache.port"}, cm.LastReloadInfo().ChangedPaths)
+
+	// Re-adding the default value explicitly is not a change
+	writeFile(t, configFile, "cache:\n  host: cachehost\n  port: 6379\n")
+	require.NoError(t, cm.reload(context.Background()))
+	assert.Equal(t, 1, plugin.reloadCount())
+}
+
+func TestBuilder_WithReloadHook(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: one\n")
+
+	var calls []string
+	cm, err := NewBuilder[TestConfig]().
+		AddFile(configFile).
+		WithReloadHook(func(ctx context.Context, cfg *TestConfig) error {
+			calls = append(calls, "first:"+cfg.Name)
+			return errors.New("sidecar unreachable")
+		}).
+		WithReloadHook(func(ctx context.Context, cfg *TestConfig) error {
+			calls = append(calls, "second:"+cfg.Name)
+			return nil
+		}).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	// Hooks do not run for the initial load
+	assert.Empty(t, calls)
+
+	writeFile(t, configFile, "name: two\n")
+	require.NoError(t, cm.reload(context.Background()))
+
+	// Both hooks ran with the new config; the failing hook did not revert it
+	assert.Equal(t, []string{"first:two", "second:two"}, calls)
+	assert.Equal(t, "two", cm.Get().Name)
+	assert.NoError(t, cm.LastReloadInfo().Err)
+
+	// Hooks run even when nothing changed
+	require.NoError(t, cm.reload(context.Background()))
+	assert.Len(t, calls, 4)
+
+	// Hooks do not run for failed reloads
+	writeFile(t, configFile, "name: [broken\n")
+	require.Error(t, cm.reload(context.Background()))
+	assert.Len(t, calls, 4)
+	assert.Equal(t, "two", cm.Get().Name)
+}
+
+func TestBuilder_WithStrictReload(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\ncache:\n  host: one\n")
+
+	cm, err := NewBuilder[UpdateAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		WithStrictReload().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	plugin := getRecorder(t, cm, "recorder:cache")
+	original := cm.Get()
+
+	var rejected []ReloadRejected
+	unsubscribe := cm.OnReloadRejected(func(event ReloadRejected) {
+		rejected = append(rejected, event)
+	})
+	var changes int
+	cm.OnChange(func(_, _ *UpdateAppConfig) { changes++ })
+
+	// An invalid configuration is rejected as a whole
+	writeFile(t, configFile, "name: ''\nextra: true\ncache:\n  host: two\n")
+	err = cm.reload(context.Background(), configFile)
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeValidationFailure})
+
+	assert.Same(t, original, cm.Get())
+	assert.Equal(t, 0, plugin.reloadCount())
+	assert.Equal(t, 0, changes)
+	merged, err := cm.MarshalMerged("yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(merged), "extra")
+
+	require.Len(t, rejected, 1)
+	assert.Equal(t, []string{configFile}, rejected[0].Sources)
+	assert.ErrorIs(t, rejected[0].Err, &ConfigError{Type: ErrorTypeValidationFailure})
+	assert.False(t, rejected[0].Time.IsZero())
+	assert.Error(t, cm.LastReloadInfo().Err)
+
+	// Unparsable files are rejected too
+	writeFile(t, configFile, "name: [unclosed\n")
+	assert.Error(t, cm.reload(context.Background(), configFile))
+	assert.Len(t, rejected, 2)
+
+	// Valid configurations are published as usual
+	writeFile(t, configFile, "name: next\ncache:\n  host: two\n")
+	require.NoError(t, cm.reload(context.Background(), configFile))
+	assert.Equal(t, "next", cm.Get().Name)
+	assert.Equal(t, 1, plugin.reloadCount())
+	assert.Equal(t, 1, changes)
+
+	unsubscribe()
+	writeFile(t, configFile, "name: ''\n")
+	assert.Error(t, cm.reload(context.Background(), configFile))
+	assert.Len(t, rejected, 2)
+}
+
+func TestConfigManager_Reload(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\ncache:\n  host: one\n")
+
+	cm, err := NewBuilder[UpdateAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	plugin := getRecorder(t, cm, "recorder:cache")
+
+	// Without watching, changes are only picked up on demand
+	writeFile(t, configFile, "name: app\ncache:\n  host: two\n")
+	assert.Equal(t, "one", cm.Get().Cache.Host)
+
+	require.NoError(t, cm.Reload(context.Background()))
+	assert.Equal(t, "two", cm.Get().Cache.Host)
+	assert.Equal(t, 1, plugin.reloadCount())
+
+	info := cm.LastReloadInfo()
+	assert.Equal(t, []string{"manual"}, info.Sources)
+	assert.Equal(t, []string{"cache.host"}, info.ChangedPaths)
+	assert.Equal(t, []string{"recorder:cache"}, info.ReloadedPlugins)
+
+	// An invalid configuration keeps the current one
+	writeFile(t, configFile, "name: ''\ncache:\n  host: three\n")
+	err = cm.Reload(context.Background())
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeValidationFailure})
+	assert.Equal(t, "two", cm.Get().Cache.Host)
+	assert.Equal(t, 1, plugin.reloadCount())
+	assert.Equal(t, err, cm.LastReloadInfo().Err)
+
+	var nilManager *ConfigManager[UpdateAppConfig]
+	assert.Error(t, nilManager.Reload(context.Background()))
+}
+
+// RecorderMapAppConfig holds a recorder plugin instance per map entry
+type RecorderMapAppConfig struct {
+	Caches map[string]recorderConfig `koanf:"caches"`
+}
+
+func TestConfigManager_LastReloadInfo_MapPlugins(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "caches:\n  a:\n    host: one\n")
+
+	cm, err := NewBuilder[RecorderMapAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	first := getRecorder(t, cm, "recorder:caches.a")
+
+	writeFile(t, configFile, "caches:\n  b:\n    host: two\n")
+	require.NoError(t, cm.reload(context.Background()))
+
+	info := cm.LastReloadInfo()
+	assert.Equal(t, []string{"recorder:caches.b"}, info.StartedPlugins)
+	assert.Equal(t, []string{"recorder:caches.a"}, info.StoppedPlugins)
+	assert.Equal(t, 1, first.shutdown)
+	assert.Equal(t, "two", getRecorder(t, cm, "recorder:caches.b").startups[0].Host)
+	assert.NotContains(t, cm.pluginManager.Clone(), "recorder:caches.a")
+}
+
+// RecorderSliceAppConfig holds a recorder plugin instance per list element,
+// identified by its host
+type RecorderSliceAppConfig struct {
+	Pool []recorderConfig `koanf:"pool" plugin:"key=host"`
+}
+
+func TestConfigManager_LastReloadInfo_SlicePlugins(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "pool:\n  - host: a\n  - host: b\n")
+
+	cm, err := NewBuilder[RecorderSliceAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	b := getRecorder(t, cm, "recorder:pool[b]")
+
+	// Reordering keeps the instance of b, while a is replaced by c
+	writeFile(t, configFile, "pool:\n  - host: b\n  - host: c\n")
+	require.NoError(t, cm.reload(context.Background()))
+
+	info := cm.LastReloadInfo()
+	assert.Equal(t, []string{"recorder:pool[c]"}, info.StartedPlugins)
+	assert.Equal(t, []string{"recorder:pool[a]"}, info.StoppedPlugins)
+	assert.Empty(t, info.ReloadedPlugins)
+	assert.Same(t, b, getRecorder(t, cm, "recorder:pool[b]"))
+	assert.Equal(t, "Pool[0]", cm.pluginManager.Clone()["recorder:pool[b]"].ConfigPath)
+}
@@ -0,0 +1,378 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the export of a configuration struct as a JSON Schema
+// document, derived from its koanf, default and validate struct tags.
+package vcfg
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDialect is the JSON Schema version of generated documents
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches the duration strings accepted by time.ParseDuration
+const durationPattern = `^-?([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$|^0$`
+
+// bytesPattern matches the human-readable byte sizes accepted for int64 fields
+const bytesPattern = `^[0-9]+(\.[0-9]+)? ?([kKmMgGtTpP]([iI]?[bB])?|[bB])?$`
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// jsonSchema is a JSON Schema node; only the keywords vcfg emits are modeled
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 any                    `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	Default              any                    `json:"default,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+	MinProperties        *int                   `json:"minProperties,omitempty"`
+	MaxProperties        *int                   `json:"maxProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+}
+
+// GenerateJSONSchema returns a JSON Schema (draft 2020-12) document describing
+// the configuration files accepted for T, so editors and CI can check them
+// before deployment. Property names follow the koanf tags like the rest of
+// vcfg, `default` tags become defaults, and the common rules of `validate` tags
+// are translated:
+//
+//   - required adds the property to the parent's required list
+//   - min, max, len, gt, gte, lt and lte become length, item count or range limits
+//   - oneof becomes an enum
+//   - email, url, uri, hostname, ipv4, ipv6 and uuid become formats
+//   - rules after dive apply to the elements of slices and maps
+//
+// Rules without a JSON Schema equivalent are ignored, so a document that passes
+// the schema may still fail validation when it is loaded. Durations and byte
+// sizes are described as the strings vcfg accepts for them.
+func GenerateJSONSchema[T any]() ([]byte, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot generate a JSON Schema for %s: not a struct", t)
+	}
+
+	g := &schemaGenerator{visiting: make(map[reflect.Type]bool)}
+	schema, err := g.typeSchema(t, "")
+	if err != nil {
+		return nil, err
+	}
+	schema.Schema = jsonSchemaDialect
+	schema.Title = t.Name()
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaGenerator builds the schema of a type, tracking the struct types being
+// expanded so recursive types terminate
+type schemaGenerator struct {
+	visiting map[reflect.Type]bool
+}
+
+// typeSchema returns the schema of values of type t; path names the value in errors
+func (g *schemaGenerator) typeSchema(t reflect.Type, path string) (*jsonSchema, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == durationType:
+		return &jsonSchema{Type: []string{"string", "integer"}, Pattern: durationPattern}, nil
+	case t == timeType:
+		return &jsonSchema{Type: "string", Format: "date-time"}, nil
+	case t.Kind() != reflect.String && reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return &jsonSchema{Type: "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}, nil
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &jsonSchema{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer", Minimum: new(float64)}, nil
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &jsonSchema{Type: "string"}, nil
+		}
+		items, err := g.typeSchema(t.Elem(), path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := g.typeSchema(t.Elem(), path+".*")
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return g.structSchema(t, path)
+	default:
+		// Interfaces and other kinds accept any value
+		return &jsonSchema{}, nil
+	}
+}
+
+// structSchema returns the object schema of the struct type t
+func (g *schemaGenerator) structSchema(t reflect.Type, path string) (*jsonSchema, error) {
+	schema := &jsonSchema{Type: "object"}
+	if g.visiting[t] {
+		// A recursive reference; describing it again would never end
+		return schema, nil
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	schema.Properties = make(map[string]*jsonSchema)
+	if err := g.addFields(schema, t, path); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// addFields adds the exported fields of t to the object schema, inlining the
+// fields of squashed and embedded structs
+func (g *schemaGenerator) addFields(schema *jsonSchema, t reflect.Type, path string) error {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("koanf") == "-" {
+			continue
+		}
+
+		name := joinKeyPath("", field)
+		if name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := g.addFields(schema, embedded, path); err != nil {
+					return err
+				}
+				continue
+			}
+			name = strings.ToLower(field.Name)
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		property, err := g.typeSchema(field.Type, fieldPath)
+		if err != nil {
+			return err
+		}
+
+		if value, ok := field.Tag.Lookup("default"); ok {
+			def, err := schemaDefault(field.Type, value)
+			if err != nil {
+				return fmt.Errorf("invalid default for %s: %w", fieldPath, err)
+			}
+			property.Default = def
+		}
+
+		required, err := applyValidateRules(property, field.Type, field.Tag.Get("validate"))
+		if err != nil {
+			return fmt.Errorf("invalid validate tag for %s: %w", fieldPath, err)
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = property
+	}
+	return nil
+}
+
+// schemaDefault converts a `default` tag to the JSON value of a field of type t,
+// following the rules of the defaults package
+func schemaDefault(t reflect.Type, value string) (any, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Slice:
+		items := []string{}
+		for item := range strings.SplitSeq(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	default:
+		return value, nil
+	}
+}
+
+// applyValidateRules translates the rules of a validate tag into keywords of
+// the schema of a field of type t. It reports whether the field is required.
+func applyValidateRules(schema *jsonSchema, t reflect.Type, tag string) (bool, error) {
+	if tag == "" || tag == "-" {
+		return false, nil
+	}
+
+	rules, elemRules, dive := strings.Cut(tag, ",dive")
+	if dive {
+		elemRules = strings.TrimPrefix(elemRules, ",")
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		target := schema.Items
+		if t.Kind() == reflect.Map {
+			target = schema.AdditionalProperties
+		}
+		if target != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+			if _, err := applyValidateRules(target, t.Elem(), elemRules); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	required := false
+	for rule := range strings.SplitSeq(rules, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if strings.Contains(name, "|") {
+			// Alternatives have no direct equivalent
+			continue
+		}
+
+		switch name {
+		case "required":
+			required = true
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			if err := applyLimit(schema, t, name, param); err != nil {
+				return false, err
+			}
+		case "oneof":
+			schema.Enum = oneOfEnum(t, param)
+		case "email", "hostname", "ipv4", "ipv6", "uuid":
+			schema.Format = name
+		case "url", "uri":
+			schema.Format = "uri"
+		case "min_bytes", "max_bytes":
+			// Byte counts may be written as sizes such as "512MB"
+			schema.Type = []string{"integer", "string"}
+			schema.Pattern = bytesPattern
+		}
+	}
+	return required, nil
+}
+
+// applyLimit translates a size or range rule. For strings, slices and maps the
+// limit applies to the length; for numbers to the value.
+func applyLimit(schema *jsonSchema, t reflect.Type, rule, param string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		if t == durationType {
+			// Duration limits such as min=1s have no JSON Schema equivalent
+			return nil
+		}
+		return fmt.Errorf("rule %s: %w", rule, err)
+	}
+
+	var lower, upper **int
+	switch t.Kind() {
+	case reflect.String:
+		lower, upper = &schema.MinLength, &schema.MaxLength
+	case reflect.Slice, reflect.Array:
+		lower, upper = &schema.MinItems, &schema.MaxItems
+	case reflect.Map:
+		lower, upper = &schema.MinProperties, &schema.MaxProperties
+	default:
+		switch rule {
+		case "min", "gte":
+			schema.Minimum = &limit
+		case "max", "lte":
+			schema.Maximum = &limit
+		case "gt":
+			schema.ExclusiveMinimum = &limit
+		case "lt":
+			schema.ExclusiveMaximum = &limit
+		case "len":
+			schema.Minimum, schema.Maximum = &limit, &limit
+		}
+		return nil
+	}
+
+	n := int(limit)
+	switch rule {
+	case "min", "gte":
+		*lower = &n
+	case "max", "lte":
+		*upper = &n
+	case "gt":
+		n++
+		*lower = &n
+	case "lt":
+		n--
+		*upper = &n
+	case "len":
+		*lower, *upper = &n, &n
+	}
+	return nil
+}
+
+// oneOfEnum returns the values of a oneof rule, typed like the field
+func oneOfEnum(t reflect.Type, param string) []any {
+	values := strings.Fields(param)
+	enum := make([]any, 0, len(values))
+	for _, value := range values {
+		if typed, err := schemaDefault(t, value); err == nil && t.Kind() != reflect.Slice {
+			enum = append(enum, typed)
+		} else {
+			enum = append(enum, value)
+		}
+	}
+	return enum
+}
@@ -0,0 +1,98 @@
+package vcfg
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SchemaTestBase struct {
+	Name string `koanf:"name" validate:"required,min=3,max=32"`
+}
+
+type SchemaTestNode struct {
+	Value    string            `koanf:"value"`
+	Children []*SchemaTestNode `koanf:"children"`
+}
+
+type SchemaTestConfig struct {
+	SchemaTestBase `koanf:",squash"`
+
+	Server struct {
+		Host    string        `koanf:"host" default:"localhost" validate:"hostname"`
+		Port    int           `koanf:"port" default:"8080" validate:"required,gte=1,lte=65535"`
+		Timeout time.Duration `koanf:"timeout" default:"30s" validate:"min=1s"`
+	} `koanf:"server" validate:"required"`
+	Mode     string            `koanf:"mode" validate:"oneof=dev prod"`
+	Level    int               `koanf:"level" validate:"oneof=1 2 3"`
+	Tags     []string          `koanf:"tags" default:"a,b" validate:"max=5,dive,min=1"`
+	Labels   map[string]string `koanf:"labels"`
+	MaxBody  int64             `koanf:"max_body" validate:"max_bytes=1GB"`
+	Workers  uint              `koanf:"workers"`
+	Ratio    float64           `koanf:"ratio" validate:"gt=0,lt=1"`
+	Admin    string            `koanf:"admin" validate:"omitempty,email"`
+	Tree     SchemaTestNode    `koanf:"tree"`
+	Internal string            `koanf:"-"`
+	Extra    any               `koanf:"extra"`
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	data, err := GenerateJSONSchema[SchemaTestConfig]()
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	assert.Equal(t, jsonSchemaDialect, schema["$schema"])
+	assert.Equal(t, "SchemaTestConfig", schema["title"])
+	assert.Equal(t, "object", schema["type"])
+	assert.ElementsMatch(t, []any{"name", "server"}, schema["required"])
+
+	props := schema["properties"].(map[string]any)
+	assert.NotContains(t, props, "internal")
+	assert.NotContains(t, props, "schematestbase")
+
+	assert.Equal(t, map[string]any{"type": "string", "minLength": 3.0, "maxLength": 32.0}, props["name"])
+
+	server := props["server"].(map[string]any)
+	assert.Equal(t, []any{"port"}, server["required"])
+	serverProps := server["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "hostname", "default": "localhost"}, serverProps["host"])
+	assert.Equal(t, map[string]any{"type": "integer", "default": 8080.0, "minimum": 1.0, "maximum": 65535.0}, serverProps["port"])
+	timeout := serverProps["timeout"].(map[string]any)
+	assert.Equal(t, []any{"string", "integer"}, timeout["type"])
+	assert.Equal(t, "30s", timeout["default"])
+
+	assert.Equal(t, []any{"dev", "prod"}, props["mode"].(map[string]any)["enum"])
+	assert.Equal(t, []any{1.0, 2.0, 3.0}, props["level"].(map[string]any)["enum"])
+	assert.Equal(t, map[string]any{
+		"type":     "array",
+		"default":  []any{"a", "b"},
+		"maxItems": 5.0,
+		"items":    map[string]any{"type": "string", "minLength": 1.0},
+	}, props["tags"])
+	assert.Equal(t, map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}}, props["labels"])
+	assert.Equal(t, []any{"integer", "string"}, props["max_body"].(map[string]any)["type"])
+	assert.Equal(t, 0.0, props["workers"].(map[string]any)["minimum"])
+	assert.Equal(t, map[string]any{"type": "number", "exclusiveMinimum": 0.0, "exclusiveMaximum": 1.0}, props["ratio"])
+	assert.Equal(t, "email", props["admin"].(map[string]any)["format"])
+	assert.Equal(t, map[string]any{}, props["extra"])
+
+	// Recursive types terminate
+	children := props["tree"].(map[string]any)["properties"].(map[string]any)["children"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "object"}, children["items"])
+}
+
+func TestGenerateJSONSchema_Errors(t *testing.T) {
+	_, err := GenerateJSONSchema[string]()
+	assert.ErrorContains(t, err, "not a struct")
+
+	type badDefault struct {
+		Port int `koanf:"port" default:"http"`
+	}
+	_, err = GenerateJSONSchema[badDefault]()
+	assert.ErrorContains(t, err, "invalid default for port")
+}
@@ -0,0 +1,197 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements runtime changes of single keys with Set and their
+// persistence to a config file with Save.
+package vcfg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+
+	"github.com/nextpkg/vcfg/providers"
+)
+
+// setSource is the trigger source recorded in ReloadInfo for Set calls, and
+// the origin of the values it changed
+const setSource = "set"
+
+// Set changes the value at the dotted key path, e.g. Set("server.port", 9090),
+// and publishes the resulting configuration like Update: it is validated first,
+// and subscribers, affected plugins and reload hooks see the change, with
+// LastReloadInfo reporting the source "set".
+//
+// Unlike Update, changed values survive reloads: they are merged over all
+// sources until the manager is closed, and Origin reports them as "set". A map
+// value is merged into the section at key. Use Save to persist the changes to
+// a config file.
+func (cm *ConfigManager[T]) Set(key string, value any) (err error) {
+	if cm == nil {
+		return NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+	if key == "" {
+		return NewValidationError(setSource, "key must not be empty", nil)
+	}
+
+	info := ReloadInfo{
+		Sources: []string{setSource},
+		Time:    time.Now(),
+	}
+
+	cm.reloadMu.Lock()
+	defer cm.reloadMu.Unlock()
+
+	current := cm.Get()
+	if current == nil {
+		return fmt.Errorf("no configuration loaded to change")
+	}
+
+	change := koanf.New(".")
+	if err := change.Set(key, value); err != nil {
+		return NewParseError(setSource, "invalid value for "+key, err)
+	}
+
+	cm.mu.RLock()
+	k := cm.koanf.Copy()
+	origins := make(keyOrigins, len(cm.origins))
+	for path, source := range cm.origins {
+		origins[path] = source
+	}
+	cm.mu.RUnlock()
+
+	if err := k.Merge(change); err != nil {
+		return NewParseError(setSource, "failed to apply value for "+key, err)
+	}
+	origins.record(change.Keys(), setSource)
+	origins.prune(k)
+
+	cfg, err := cm.decode(context.Background(), k)
+	if err != nil {
+		return err
+	}
+
+	// Only changes that passed validation are kept and recorded as reloads
+	cm.overridesMu.Lock()
+	if cm.overrides == nil {
+		cm.overrides = koanf.New(".")
+	}
+	mergeErr := cm.overrides.Merge(change)
+	cm.overridesMu.Unlock()
+	if mergeErr != nil {
+		return NewParseError(setSource, "failed to record value for "+key, mergeErr)
+	}
+
+	cm.mu.Lock()
+	cm.koanf = k
+	cm.origins = origins
+	cm.mu.Unlock()
+
+	defer func() {
+		info.Err = err
+		cm.recordReload(info)
+	}()
+
+	return cm.publish(context.Background(), &info, current, cfg)
+}
+
+// overrideValues returns a copy of the values changed with Set, nil if none
+func (cm *ConfigManager[T]) overrideValues() *koanf.Koanf {
+	cm.overridesMu.Lock()
+	defer cm.overridesMu.Unlock()
+
+	if cm.overrides == nil {
+		return nil
+	}
+	return cm.overrides.Copy()
+}
+
+// Save persists the values changed with Set to the config file at path in the
+// given format, "yaml", "json", "ini" or "properties"; an empty format is
+// derived from the file extension. The existing values of the file are kept
+// and the changed values are merged over them, so saving to one of the
+// manager's own files makes the changes permanent. Comments and formatting of
+// the file are not preserved.
+//
+// The file is replaced atomically: the new content is written to a temporary
+// file in the same directory, which is then renamed over path, so readers such
+// as a watching manager never observe a partially written file. A missing file
+// is created.
+func (cm *ConfigManager[T]) Save(path, format string) error {
+	if cm == nil {
+		return NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+	if format == "" {
+		format = filepath.Ext(path)
+	}
+
+	parser, err := providers.ParserForFormat(format)
+	if err != nil {
+		return NewParseError("save", "unsupported output format", err)
+	}
+
+	k := koanf.New(".")
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := k.Load(rawbytes.Provider(data), parser); err != nil {
+			return NewParseError(path, "failed to parse existing file", err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if overrides := cm.overrideValues(); overrides != nil {
+		if err := k.Merge(overrides); err != nil {
+			return NewParseError(path, "failed to apply changed values", err)
+		}
+	}
+
+	out, err := k.Marshal(parser)
+	if err != nil {
+		return NewParseError("save", "failed to marshal configuration", err)
+	}
+	return writeFileAtomic(path, out)
+}
+
+// writeFileAtomic replaces the file at path with data through a temporary file
+// and a rename, keeping the permissions of an existing file
+func writeFileAtomic(path string, data []byte) (err error) {
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %s: %w", path, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp.Name(), err)
+	}
+	if err = tmp.Chmod(mode); err != nil {
+		return fmt.Errorf("failed to set permissions of %s: %w", tmp.Name(), err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", tmp.Name(), err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", tmp.Name(), err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
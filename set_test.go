@@ -0,0 +1,98 @@
+package vcfg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SetAppConfig is an application config with validation rules for Set tests
+type SetAppConfig struct {
+	Name   string `koanf:"name" validate:"required"`
+	Server struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port" validate:"min=1,max=65535"`
+	} `koanf:"server"`
+}
+
+func TestConfigManager_Set(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\nserver:\n  host: localhost\n  port: 8080\n")
+
+	cm, err := NewBuilder[SetAppConfig]().AddFile(configFile).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	var notified *SetAppConfig
+	cm.OnChange(func(_, newCfg *SetAppConfig) { notified = newCfg })
+
+	require.NoError(t, cm.Set("server.port", 9090))
+	assert.Equal(t, 9090, cm.Get().Server.Port)
+	assert.Equal(t, "localhost", cm.Get().Server.Host)
+	assert.Same(t, cm.Get(), notified)
+	assert.Equal(t, []string{setSource}, cm.LastReloadInfo().Sources)
+	source, ok := cm.Origin("server.port")
+	assert.True(t, ok)
+	assert.Equal(t, "set", source)
+
+	// A value violating validation is rejected without side effects
+	current := cm.Get()
+	err = cm.Set("server.port", 70000)
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeValidationFailure})
+	assert.Same(t, current, cm.Get())
+	assert.Equal(t, 9090, cm.Int("server.port"))
+
+	assert.Error(t, cm.Set("", 1))
+
+	// Changed values survive reloads from the sources
+	writeFile(t, configFile, "name: renamed\nserver:\n  host: localhost\n  port: 8080\n")
+	require.NoError(t, cm.reload(context.Background()))
+	assert.Equal(t, "renamed", cm.Get().Name)
+	assert.Equal(t, 9090, cm.Get().Server.Port)
+}
+
+func TestConfigManager_Save(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	writeFile(t, configFile, "name: app\nserver:\n  host: localhost\n  port: 8080\n")
+	require.NoError(t, os.Chmod(configFile, 0o600))
+
+	cm, err := NewBuilder[SetAppConfig]().AddFile(configFile).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	require.NoError(t, cm.Set("server.port", 9090))
+
+	// Saving to the source file keeps its other values
+	require.NoError(t, cm.Save(configFile, "yaml"))
+	saved, err := New[SetAppConfig](WithFile(configFile))
+	require.NoError(t, err)
+	defer saved.Close()
+	assert.Equal(t, "app", saved.Get().Name)
+	assert.Equal(t, "localhost", saved.Get().Server.Host)
+	assert.Equal(t, 9090, saved.Get().Server.Port)
+
+	info, err := os.Stat(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// A missing file is created with the changed values only, in the format
+	// of its extension
+	overridesFile := filepath.Join(dir, "overrides.json")
+	require.NoError(t, cm.Save(overridesFile, ""))
+	data, err := os.ReadFile(overridesFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"server": {"port": 9090}}`, string(data))
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	assert.Error(t, cm.Save(filepath.Join(dir, "config.hcl"), ""))
+	assert.Error(t, cm.Save(filepath.Join(dir, "config.txt"), "txt"))
+}
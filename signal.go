@@ -0,0 +1,95 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements reloading the configuration when the process receives a
+// signal, the SIGHUP convention of daemons.
+package vcfg
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ReloadOnSignal reloads the configuration with Reload whenever the process
+// receives one of signals, SIGHUP if none are given:
+//
+//	cm.ReloadOnSignal()  // kill -HUP <pid> reloads the configuration
+//
+// Failed reloads are logged and keep the current configuration. The handler is
+// removed when the manager is closed, before its plugins are shut down, or when
+// the returned function is called, whichever comes first; signals arriving
+// afterwards get their default behavior again. On a closed manager no handler
+// is installed.
+func (cm *ConfigManager[T]) ReloadOnSignal(signals ...os.Signal) (stop func()) {
+	if cm == nil {
+		return func() {}
+	}
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	cm.signalMu.Lock()
+	defer cm.signalMu.Unlock()
+	if cm.closed {
+		return func() {}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	signal.Notify(ch, signals...)
+
+	go func() {
+		defer close(stopped)
+		defer signal.Stop(ch)
+		for {
+			select {
+			case sig := <-ch:
+				cm.reloadFromSignal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+	cm.signalHandlers = append(cm.signalHandlers, stop)
+	return stop
+}
+
+// stopSignalHandlers removes the handlers installed by ReloadOnSignal, waiting
+// for reloads in progress, and keeps new ones from being installed
+func (cm *ConfigManager[T]) stopSignalHandlers() {
+	cm.signalMu.Lock()
+	handlers := cm.signalHandlers
+	cm.signalHandlers = nil
+	cm.closed = true
+	cm.signalMu.Unlock()
+
+	for _, stop := range handlers {
+		stop()
+	}
+}
+
+// reloadFromSignal reloads the configuration after sig was received and logs
+// the outcome
+func (cm *ConfigManager[T]) reloadFromSignal(sig os.Signal) {
+	if err := cm.Reload(context.Background()); err != nil {
+		cm.log().Error("Failed to reload configuration", "error", err, "signal", sig.String())
+		return
+	}
+
+	info := cm.LastReloadInfo()
+	cm.log().Info("Configuration reloaded on signal",
+		"signal", sig.String(),
+		"changes", info.Changes,
+		"plugins", info.ReloadedPlugins,
+	)
+}
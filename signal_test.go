@@ -0,0 +1,99 @@
+//go:build unix
+
+package vcfg
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigManager_ReloadOnSignal(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: one\n")
+
+	cm, err := NewBuilder[TestConfig]().
+		AddFile(configFile).
+		Build(context.Background())
+	require.NoError(t, err)
+
+	// Keep SIGUSR1 from terminating the test once the handler is removed
+	guard := make(chan os.Signal, 2)
+	signal.Notify(guard, syscall.SIGUSR1)
+	defer signal.Stop(guard)
+
+	cm.ReloadOnSignal(syscall.SIGUSR1)
+
+	writeFile(t, configFile, "name: two\n")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	require.Eventually(t, func() bool {
+		return cm.Get().Name == "two"
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"manual"}, cm.LastReloadInfo().Sources)
+
+	// Closing the manager removes the handler
+	require.NoError(t, cm.Close())
+	writeFile(t, configFile, "name: three\n")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	<-guard
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "two", cm.Get().Name)
+}
+
+func TestConfigManager_ReloadOnSignal_Stop(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: one\n")
+
+	cm, err := NewBuilder[TestConfig]().
+		AddFile(configFile).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	guard := make(chan os.Signal, 2)
+	signal.Notify(guard, syscall.SIGUSR2)
+	defer signal.Stop(guard)
+
+	stop := cm.ReloadOnSignal(syscall.SIGUSR2)
+	stop()
+	stop()
+
+	writeFile(t, configFile, "name: two\n")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+	<-guard
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "one", cm.Get().Name)
+}
+
+func TestConfigManager_ReloadOnSignal_AfterClose(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: one\n")
+
+	cm, err := NewBuilder[TestConfig]().
+		AddFile(configFile).
+		Build(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, cm.Close())
+
+	guard := make(chan os.Signal, 2)
+	signal.Notify(guard, syscall.SIGUSR1)
+	defer signal.Stop(guard)
+
+	// No handler is installed on a closed manager
+	stop := cm.ReloadOnSignal(syscall.SIGUSR1)
+	defer stop()
+	assert.Empty(t, cm.signalHandlers)
+
+	writeFile(t, configFile, "name: two\n")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	<-guard
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "one", cm.Get().Name)
+}
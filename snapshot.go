@@ -0,0 +1,170 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the history of published configurations and rolling back
+// to one of them.
+package vcfg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+
+	"github.com/nextpkg/vcfg/plugins"
+)
+
+const (
+	// rollbackSource is the trigger source recorded in ReloadInfo for Rollback calls
+	rollbackSource = "rollback"
+	// defaultSnapshotRetention is the number of snapshots kept unless configured
+	defaultSnapshotRetention = 10
+)
+
+// Snapshot is a published configuration as recorded in the manager's history.
+type Snapshot[T any] struct {
+	// Version numbers the published configurations of a manager, starting at 1
+	Version uint64
+	// Time is when the configuration was published
+	Time time.Time
+	// Config is a copy of the typed configuration
+	Config *T
+	// Values is a copy of the merged source values Config was decoded from,
+	// including the changes made with Update
+	Values map[string]any
+}
+
+// snapshotEntry is a history record; it is copied before being handed out
+type snapshotEntry[T any] struct {
+	version uint64
+	time    time.Time
+	cfg     *T
+	koanf   *koanf.Koanf
+	origins keyOrigins
+}
+
+// export returns a copy of the entry that callers may modify freely
+func (e snapshotEntry[T]) export() *Snapshot[T] {
+	snapshot := &Snapshot[T]{
+		Version: e.version,
+		Time:    e.time,
+		Config:  plugins.DeepCopy(e.cfg),
+	}
+	if e.koanf != nil {
+		snapshot.Values = e.koanf.Raw()
+	}
+	return snapshot
+}
+
+// storeConfig makes cfg the current configuration and records it, together with
+// the current merged values, as the newest snapshot. Older snapshots beyond the
+// retention count are dropped.
+func (cm *ConfigManager[T]) storeConfig(cfg *T) {
+	cm.cfg.Store(cfg)
+
+	cm.mu.RLock()
+	var k *koanf.Koanf
+	if cm.koanf != nil {
+		k = cm.koanf.Copy()
+	}
+	origins := cm.origins
+	cm.mu.RUnlock()
+
+	cm.snapMu.Lock()
+	defer cm.snapMu.Unlock()
+
+	cm.snapshotVersion++
+	cm.snapshots = append(cm.snapshots, snapshotEntry[T]{
+		version: cm.snapshotVersion,
+		time:    time.Now(),
+		cfg:     cfg,
+		koanf:   k,
+		origins: origins,
+	})
+
+	retention := cm.snapshotRetention
+	if retention <= 0 {
+		retention = defaultSnapshotRetention
+	}
+	if excess := len(cm.snapshots) - retention; excess > 0 {
+		cm.snapshots = append(cm.snapshots[:0:0], cm.snapshots[excess:]...)
+	}
+}
+
+// Snapshot returns the current configuration together with the merged values it
+// was decoded from, or nil if no configuration has been loaded yet. The returned
+// snapshot is a copy; changing it does not affect the manager.
+func (cm *ConfigManager[T]) Snapshot() *Snapshot[T] {
+	cm.snapMu.Lock()
+	defer cm.snapMu.Unlock()
+
+	if len(cm.snapshots) == 0 {
+		return nil
+	}
+	return cm.snapshots[len(cm.snapshots)-1].export()
+}
+
+// Snapshots returns the retained history of published configurations, oldest
+// first; the last entry is the current configuration. Every initial load,
+// reload, Update, Commit and Rollback adds an entry.
+func (cm *ConfigManager[T]) Snapshots() []*Snapshot[T] {
+	cm.snapMu.Lock()
+	defer cm.snapMu.Unlock()
+
+	snapshots := make([]*Snapshot[T], len(cm.snapshots))
+	for i, entry := range cm.snapshots {
+		snapshots[i] = entry.export()
+	}
+	return snapshots
+}
+
+// Rollback reverts to the configuration published n steps before the current
+// one, e.g. Rollback(1) undoes the last reload. The restored configuration is
+// published like a reload: subscribers are notified, affected plugins are
+// reloaded, reload hooks run and LastReloadInfo reports the source "rollback".
+//
+// The snapshots newer than the restored one are discarded, so repeated calls of
+// Rollback(1) walk further back. Like Update, a rollback lives in memory only:
+// the next reload from the sources replaces it with the values read from them.
+func (cm *ConfigManager[T]) Rollback(n int) (err error) {
+	if cm == nil {
+		return NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+	if n < 1 {
+		return fmt.Errorf("rollback requires at least one step, got %d", n)
+	}
+
+	cm.reloadMu.Lock()
+	defer cm.reloadMu.Unlock()
+
+	cm.snapMu.Lock()
+	index := len(cm.snapshots) - 1 - n
+	if index < 0 {
+		available := max(len(cm.snapshots)-1, 0)
+		cm.snapMu.Unlock()
+		return fmt.Errorf("cannot roll back %d steps, only %d previous configurations retained", n, available)
+	}
+	target := cm.snapshots[index]
+	// The restored configuration is recorded again as the newest snapshot
+	cm.snapshots = cm.snapshots[:index]
+	cm.snapMu.Unlock()
+
+	info := ReloadInfo{
+		Sources: []string{rollbackSource},
+		Time:    time.Now(),
+	}
+	defer func() {
+		info.Err = err
+		cm.recordReload(info)
+	}()
+
+	oldConfig := cm.Get()
+
+	if target.koanf != nil {
+		cm.mu.Lock()
+		cm.koanf = target.koanf.Copy()
+		cm.origins = target.origins
+		cm.mu.Unlock()
+	}
+
+	return cm.publish(context.Background(), &info, oldConfig, plugins.DeepCopy(target.cfg))
+}
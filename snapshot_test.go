@@ -0,0 +1,85 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigManager_SnapshotRollback(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: first\ncache:\n  host: one\n")
+
+	cm, err := NewBuilder[UpdateAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		WithSnapshotRetention(3).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	plugin := getRecorder(t, cm, "recorder:cache")
+
+	snapshot := cm.Snapshot()
+	require.NotNil(t, snapshot)
+	assert.Equal(t, uint64(1), snapshot.Version)
+	assert.Equal(t, "first", snapshot.Config.Name)
+	assert.Equal(t, map[string]any{"host": "one"}, snapshot.Values["cache"])
+
+	// Snapshots are copies
+	snapshot.Config.Name = "tampered"
+	assert.Equal(t, "first", cm.Get().Name)
+
+	assert.ErrorContains(t, cm.Rollback(1), "only 0 previous configurations retained")
+	assert.ErrorContains(t, cm.Rollback(0), "at least one step")
+
+	// Every reload is recorded, beyond the retention count the oldest are dropped
+	for _, name := range []string{"second", "third", "fourth"} {
+		writeFile(t, configFile, "name: "+name+"\ncache:\n  host: "+name+"\n")
+		require.NoError(t, cm.reload(context.Background(), configFile))
+	}
+
+	snapshots := cm.Snapshots()
+	require.Len(t, snapshots, 3)
+	assert.Equal(t, uint64(2), snapshots[0].Version)
+	assert.Equal(t, "second", snapshots[0].Config.Name)
+	assert.Equal(t, "fourth", snapshots[2].Config.Name)
+	assert.Equal(t, cm.Snapshot(), snapshots[2])
+
+	// A rollback publishes the older configuration like a reload
+	reloads := plugin.reloadCount()
+	require.NoError(t, cm.Rollback(1))
+	assert.Equal(t, "third", cm.Get().Name)
+	assert.Equal(t, reloads+1, plugin.reloadCount())
+	assert.Equal(t, "third", plugin.lastReload().Host)
+
+	info := cm.LastReloadInfo()
+	assert.Equal(t, []string{"rollback"}, info.Sources)
+	assert.NoError(t, info.Err)
+	assert.Contains(t, info.ChangedPaths, "name")
+
+	merged, err := cm.MarshalMerged("yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(merged), "name: third")
+
+	// Newer snapshots are discarded, so the next rollback goes further back
+	snapshots = cm.Snapshots()
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, uint64(5), snapshots[1].Version)
+
+	require.NoError(t, cm.Rollback(1))
+	assert.Equal(t, "second", cm.Get().Name)
+	assert.Len(t, cm.Snapshots(), 1)
+	assert.Error(t, cm.Rollback(1))
+}
+
+func TestBuilder_WithSnapshotRetentionInvalid(t *testing.T) {
+	_, err := NewBuilder[UpdateAppConfig]().
+		AddFile("config.yaml").
+		WithSnapshotRetention(0).
+		Build(context.Background())
+	assert.ErrorContains(t, err, "snapshot retention must be at least 1")
+}
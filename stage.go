@@ -0,0 +1,99 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the two-phase Prepare/Commit API that loads a candidate
+// configuration without publishing it.
+package vcfg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+
+	"github.com/nextpkg/vcfg/plugins"
+)
+
+// commitSource is the trigger source recorded in ReloadInfo for Commit calls
+const commitSource = "commit"
+
+// stagedConfig is a candidate configuration loaded by Prepare
+type stagedConfig[T any] struct {
+	// cfg is the loaded and validated configuration
+	cfg *T
+	// koanf holds the merged values cfg was decoded from
+	koanf *koanf.Koanf
+	// origins records the source of each merged value
+	origins keyOrigins
+}
+
+// Prepare loads and validates the configuration from all sources like a reload,
+// but stages the result instead of publishing it: Get, plugins and subscribers
+// keep seeing the current configuration until Commit is called. The returned
+// value is a copy of the staged configuration for inspection; changing it does
+// not affect what Commit publishes.
+//
+// Each call replaces the previously staged configuration. A failed Prepare
+// discards it, so a later Commit fails instead of publishing an older candidate.
+// Like Commit, Prepare reads the sources and traces the load with a background
+// context, as it is not tied to a request whose cancellation should abort it.
+func (cm *ConfigManager[T]) Prepare() (*T, error) {
+	if cm == nil {
+		return nil, NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+
+	cm.stageMu.Lock()
+	defer cm.stageMu.Unlock()
+	cm.staged = nil
+
+	cfg, k, origins, err := cm.loadDetached(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	cm.staged = &stagedConfig[T]{cfg: cfg, koanf: k, origins: origins}
+	return plugins.DeepCopy(cfg), nil
+}
+
+// Commit publishes the configuration staged by the last successful Prepare.
+// It is stored, subscribers are notified, affected plugins are reloaded and
+// reload hooks run, exactly like a reload from the sources; LastReloadInfo
+// reports the source "commit". The staged configuration is consumed, so each
+// Prepare can be committed at most once.
+//
+// Commit publishes the staged values even if the sources changed or a reload
+// happened after Prepare.
+func (cm *ConfigManager[T]) Commit() (err error) {
+	if cm == nil {
+		return NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+
+	cm.stageMu.Lock()
+	staged := cm.staged
+	cm.staged = nil
+	cm.stageMu.Unlock()
+
+	if staged == nil {
+		return fmt.Errorf("no prepared configuration to commit")
+	}
+
+	info := ReloadInfo{
+		Sources: []string{commitSource},
+		Time:    time.Now(),
+	}
+	defer func() {
+		info.Err = err
+		cm.recordReload(info)
+	}()
+
+	cm.reloadMu.Lock()
+	defer cm.reloadMu.Unlock()
+
+	oldConfig := cm.Get()
+
+	cm.mu.Lock()
+	cm.koanf = staged.koanf
+	cm.origins = staged.origins
+	cm.mu.Unlock()
+
+	return cm.publish(context.Background(), &info, oldConfig, staged.cfg)
+}
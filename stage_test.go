@@ -0,0 +1,67 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigManager_PrepareCommit(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\ncache:\n  host: one\n")
+
+	cm, err := NewBuilder[UpdateAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	plugin := getRecorder(t, cm, "recorder:cache")
+	original := cm.Get()
+
+	assert.ErrorContains(t, cm.Commit(), "no prepared configuration")
+
+	// An invalid candidate is rejected and nothing is published
+	writeFile(t, configFile, "name: ''\ncache:\n  host: two\n")
+	_, err = cm.Prepare()
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeValidationFailure})
+	assert.ErrorContains(t, cm.Commit(), "no prepared configuration")
+	assert.Same(t, original, cm.Get())
+
+	// A valid candidate is only published on Commit
+	writeFile(t, configFile, "name: next\nextra: true\ncache:\n  host: two\n")
+	candidate, err := cm.Prepare()
+	require.NoError(t, err)
+	assert.Equal(t, "next", candidate.Name)
+	assert.Equal(t, "two", candidate.Cache.Host)
+
+	assert.Same(t, original, cm.Get())
+	assert.Equal(t, 0, plugin.reloadCount())
+	merged, err := cm.MarshalMerged("yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(merged), "extra")
+
+	// Changes to the returned copy are not committed
+	candidate.Name = "tampered"
+
+	require.NoError(t, cm.Commit())
+	assert.Equal(t, "next", cm.Get().Name)
+	assert.Equal(t, 1, plugin.reloadCount())
+	assert.Equal(t, "two", plugin.lastReload().Host)
+
+	merged, err = cm.MarshalMerged("yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(merged), "extra: true")
+
+	info := cm.LastReloadInfo()
+	assert.Equal(t, []string{"commit"}, info.Sources)
+	assert.Equal(t, []string{"cache.host", "name"}, info.ChangedPaths)
+	assert.NoError(t, info.Err)
+
+	// The staged configuration is consumed by Commit
+	assert.ErrorContains(t, cm.Commit(), "no prepared configuration")
+}
@@ -0,0 +1,238 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the detection of keys in the merged configuration that
+// match no field of the configuration type, e.g. misspelled keys.
+package vcfg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// StrictKeysMode selects how WithStrictKeys treats unknown keys.
+type StrictKeysMode int
+
+const (
+	// StrictKeysFail fails loads and reloads of configurations with unknown keys
+	StrictKeysFail StrictKeysMode = iota
+	// StrictKeysWarn logs unknown keys and delivers them as an
+	// EventValidationWarning without failing the load
+	StrictKeysWarn
+)
+
+// maxSuggestionDistance is the largest edit distance between an unknown key and
+// a field key for the field to be suggested
+const maxSuggestionDistance = 2
+
+// unknownKey is a key that matches no field of the configuration type
+type unknownKey struct {
+	// key is the shortest unknown prefix of the merged key, e.g. databse for
+	// databse.host
+	key string
+	// suggestion is the most similar known key at the same level, "" if none
+	suggestion string
+}
+
+// String returns the key followed by the suggestion, if any.
+func (u unknownKey) String() string {
+	if u.suggestion == "" {
+		return u.key
+	}
+	return fmt.Sprintf("%s (did you mean %s?)", u.key, u.suggestion)
+}
+
+// unknownKeys returns the keys of k that match no field of t, in key order.
+// Each unknown section is reported once by its own key rather than per leaf.
+// Keys below maps, interfaces, lists and types decoded from text are not
+// checked, nor is SchemaVersionKey, which is read by the migrations.
+func unknownKeys(t reflect.Type, k *koanf.Koanf) []unknownKey {
+	var unknown []unknownKey
+	seen := make(map[string]bool)
+	for _, key := range k.Keys() {
+		if key == SchemaVersionKey {
+			continue
+		}
+
+		segments := strings.Split(key, ".")
+		parent, i := unknownSegment(t, segments)
+		if i < 0 {
+			continue
+		}
+
+		prefix := strings.Join(segments[:i+1], ".")
+		if seen[prefix] {
+			continue
+		}
+		seen[prefix] = true
+
+		u := unknownKey{key: prefix}
+		if suggestion := closestFieldKey(parent, segments[i]); suggestion != "" {
+			u.suggestion = strings.Join(append(segments[:i:i], suggestion), ".")
+		}
+		unknown = append(unknown, u)
+	}
+	return unknown
+}
+
+// unknownSegment returns the index of the first segment of a key that matches
+// no field, along with the struct type that lacks it; -1 if the key is known.
+// Keys are matched case-insensitively, as they are when decoding.
+func unknownSegment(t reflect.Type, segments []string) (reflect.Type, int) {
+	for i, segment := range segments {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		switch {
+		case t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType):
+			return nil, -1
+		case t.Kind() == reflect.Map:
+			t = t.Elem()
+		case t.Kind() == reflect.Struct:
+			fieldType, found, remain := lookupFieldKey(t, segment)
+			if remain {
+				return nil, -1
+			}
+			if !found {
+				return t, i
+			}
+			t = fieldType
+		default:
+			// Interfaces take any value; type mismatches are reported by decoding
+			return nil, -1
+		}
+	}
+	return nil, -1
+}
+
+// lookupFieldKey returns the type of the field of struct t whose key is name,
+// looking through squashed and embedded structs. remain reports a field with
+// the ",remain" option, which collects all otherwise unknown keys.
+func lookupFieldKey(t reflect.Type, name string) (fieldType reflect.Type, found, remain bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("koanf")
+		if tag == "-" {
+			continue
+		}
+		if strings.Contains(tag, ",remain") {
+			remain = true
+			continue
+		}
+
+		key := joinKeyPath("", field)
+		if key == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if ft, ok, rem := lookupFieldKey(embedded, name); ok || rem {
+					return ft, ok, rem
+				}
+			}
+			// Without the squash option an embedded struct also decodes from
+			// a key named after its type
+			key = strings.ToLower(field.Name)
+		}
+		if strings.EqualFold(key, name) {
+			return field.Type, true, false
+		}
+	}
+	return nil, false, remain
+}
+
+// closestFieldKey returns the key of the field of struct t that is most similar
+// to name, "" if no key is close enough to be a likely typo
+func closestFieldKey(t reflect.Type, name string) string {
+	if t == nil {
+		return ""
+	}
+
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, key := range fieldKeys(t) {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(key))
+		if distance < bestDistance && distance < len(name) {
+			best, bestDistance = key, distance
+		}
+	}
+	return best
+}
+
+// fieldKeys returns the keys of the fields of struct t, including the fields
+// of squashed and embedded structs
+func fieldKeys(t reflect.Type) []string {
+	var keys []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if (!field.IsExported() && !field.Anonymous) || field.Tag.Get("koanf") == "-" {
+			continue
+		}
+		key := joinKeyPath("", field)
+		if key != "" {
+			keys = append(keys, key)
+			continue
+		}
+		embedded := field.Type
+		for embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+		if embedded.Kind() == reflect.Struct {
+			keys = append(keys, fieldKeys(embedded)...)
+		}
+	}
+	return keys
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// checkUnknownKeys applies the strict keys mode to the unknown keys of k: in
+// StrictKeysFail mode they fail the load, otherwise they are returned as
+// warnings to report with reportWarnings once the load succeeded
+func (cm *ConfigManager[T]) checkUnknownKeys(k *koanf.Koanf) ([]string, error) {
+	if !cm.strictKeys {
+		return nil, nil
+	}
+	unknown := unknownKeys(reflect.TypeOf((*T)(nil)).Elem(), k)
+	if len(unknown) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, len(unknown))
+	for i, u := range unknown {
+		names[i] = u.String()
+	}
+	if cm.strictKeysMode == StrictKeysFail {
+		return nil, NewValidationError("koanf", "unknown keys: "+strings.Join(names, ", "), nil)
+	}
+
+	warnings := make([]string, len(names))
+	for i, name := range names {
+		warnings[i] = "unknown key " + name
+	}
+	return warnings, nil
+}
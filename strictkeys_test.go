@@ -0,0 +1,89 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// StrictCommon is squashed into StrictConfig
+type StrictCommon struct {
+	Name string `koanf:"name"`
+}
+
+// StrictConfig covers the field kinds the unknown key check descends into
+type StrictConfig struct {
+	StrictCommon `koanf:",squash"`
+	Database     struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"database"`
+	Labels  map[string]string `koanf:"labels"`
+	Plugins map[string]any    `koanf:"plugins"`
+	Extra   struct {
+		Known string         `koanf:"known"`
+		Rest  map[string]any `koanf:",remain"`
+	} `koanf:"extra"`
+}
+
+func TestBuilder_WithStrictKeys(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, `name: app
+database:
+  host: db
+  prot: 5432
+databse:
+  host: typo
+labels:
+  anything: goes
+plugins:
+  cache:
+    size: 10
+extra:
+  other: kept
+`)
+
+	_, err := NewBuilder[StrictConfig]().
+		AddFile(configFile).
+		WithStrictKeys().
+		Build(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(),
+		"unknown keys: database.prot (did you mean database.port?), databse (did you mean database?)")
+
+	// Without strict keys, unknown keys are ignored
+	_, err = NewBuilder[StrictConfig]().AddFile(configFile).Build(context.Background())
+	require.NoError(t, err)
+}
+
+func TestBuilder_WithStrictKeys_Warn(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\nnmae: typo\nunrelated: value\n")
+
+	cm, err := NewBuilder[StrictConfig]().
+		AddFile(configFile).
+		WithStrictKeys(StrictKeysWarn).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	event := nextEvent(t, cm.Events())
+	assert.Equal(t, EventValidationWarning, event.Type)
+	assert.Equal(t, []string{"unknown key nmae (did you mean name?)", "unknown key unrelated"}, event.Warnings)
+	assert.Equal(t, EventLoaded, nextEvent(t, cm.Events()).Type)
+
+	// Reloads report unknown keys again
+	writeFile(t, configFile, "name: app\n")
+	require.NoError(t, cm.Reload(context.Background()))
+	assert.Equal(t, EventReloaded, nextEvent(t, cm.Events()).Type)
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("port", "port"))
+	assert.Equal(t, 2, editDistance("prot", "port"))
+	assert.Equal(t, 1, editDistance("databse", "database"))
+	assert.Equal(t, 4, editDistance("", "name"))
+}
@@ -0,0 +1,93 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements typed change subscriptions that notify callers when a
+// selected projection of the configuration changes between reloads.
+package vcfg
+
+import (
+	"reflect"
+	"slices"
+)
+
+// subscriber is a registered change callback on the full configuration
+type subscriber[T any] struct {
+	// id identifies the subscription for cancellation
+	id uint64
+	// notify is called with the previous and the new configuration after a reload
+	notify func(oldCfg, newCfg *T)
+}
+
+// Subscribe registers cb to be called after a reload whenever the value returned
+// by selector differs between the previous and the new configuration, compared
+// with reflect.DeepEqual. Reloads that leave the selected value unchanged do not
+// invoke cb. It is a function rather than a method because Go methods cannot
+// declare their own type parameters:
+//
+//	cancel := vcfg.Subscribe(cm, func(c *AppConfig) DatabaseConfig { return c.Database },
+//		func(oldDB, newDB DatabaseConfig) { pool.Resize(newDB.MaxConns) })
+//	defer cancel()
+//
+// Callbacks run synchronously on the reloading goroutine in registration order,
+// so they should return quickly. The returned function cancels the subscription.
+func Subscribe[T, S any](cm *ConfigManager[T], selector func(*T) S, cb func(oldValue, newValue S)) (cancel func()) {
+	cm.subMu.Lock()
+	defer cm.subMu.Unlock()
+
+	cm.nextSubID++
+	id := cm.nextSubID
+	cm.subscribers = append(cm.subscribers, subscriber[T]{
+		id: id,
+		notify: func(oldCfg, newCfg *T) {
+			oldValue, newValue := selector(oldCfg), selector(newCfg)
+			if !reflect.DeepEqual(oldValue, newValue) {
+				cb(oldValue, newValue)
+			}
+		},
+	})
+
+	return func() {
+		cm.subMu.Lock()
+		defer cm.subMu.Unlock()
+		cm.subscribers = slices.DeleteFunc(cm.subscribers, func(s subscriber[T]) bool {
+			return s.id == id
+		})
+	}
+}
+
+// OnChange registers cb to be called after every successful reload, Update,
+// Commit or Rollback that changes the configuration, with the previous and the
+// new configuration. The new configuration has already passed validation and is
+// what Get returns. Reloads that leave the configuration unchanged, compared with
+// reflect.DeepEqual, do not invoke cb. Use Subscribe to react to a part of the
+// configuration only.
+//
+// Callbacks run synchronously on the reloading goroutine in registration order,
+// so they should return quickly and must not modify the configurations passed to
+// them. The returned function cancels the subscription.
+func (cm *ConfigManager[T]) OnChange(cb func(oldCfg, newCfg *T)) (unsubscribe func()) {
+	return Subscribe(cm, func(cfg *T) *T { return cfg }, cb)
+}
+
+// OnChangeDiff is like OnChange but also passes the changed values as computed
+// by Diff, so callbacks can react to or log individual changes:
+//
+//	cm.OnChangeDiff(func(_, _ *AppConfig, changes []vcfg.Change) {
+//		for _, c := range changes {
+//			log.Printf("%s changed from %v to %v", c.Path, c.OldValue, c.NewValue)
+//		}
+//	})
+func (cm *ConfigManager[T]) OnChangeDiff(cb func(oldCfg, newCfg *T, changes []Change)) (unsubscribe func()) {
+	return cm.OnChange(func(oldCfg, newCfg *T) {
+		cb(oldCfg, newCfg, Diff(oldCfg, newCfg))
+	})
+}
+
+// notifySubscribers calls every subscription with the configuration before and after a reload
+func (cm *ConfigManager[T]) notifySubscribers(oldCfg, newCfg *T) {
+	cm.subMu.Lock()
+	subscribers := slices.Clone(cm.subscribers)
+	cm.subMu.Unlock()
+
+	for _, s := range subscribers {
+		s.notify(oldCfg, newCfg)
+	}
+}
@@ -0,0 +1,138 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SubscribeDatabaseConfig struct {
+	Host  string   `koanf:"host"`
+	Port  int      `koanf:"port"`
+	Hosts []string `koanf:"hosts"`
+}
+
+type SubscribeAppConfig struct {
+	Name     string                  `koanf:"name"`
+	Database SubscribeDatabaseConfig `koanf:"database"`
+}
+
+func TestSubscribe(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: one\ndatabase:\n  host: db1\n  port: 5432\n")
+
+	cm, err := NewBuilder[SubscribeAppConfig]().AddFile(configFile).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	type change struct{ old, new SubscribeDatabaseConfig }
+	var dbChanges []change
+	cancel := Subscribe(cm, func(c *SubscribeAppConfig) SubscribeDatabaseConfig { return c.Database },
+		func(oldDB, newDB SubscribeDatabaseConfig) {
+			dbChanges = append(dbChanges, change{oldDB, newDB})
+		})
+
+	var names []string
+	Subscribe(cm, func(c *SubscribeAppConfig) string { return c.Name },
+		func(_, newName string) { names = append(names, newName) })
+
+	reload := func(content string) {
+		t.Helper()
+		writeFile(t, configFile, content)
+		require.NoError(t, cm.reload(context.Background()))
+	}
+
+	// Changes outside the selected projection do not fire
+	reload("name: two\ndatabase:\n  host: db1\n  port: 5432\n")
+	assert.Empty(t, dbChanges)
+	assert.Equal(t, []string{"two"}, names)
+
+	// A change inside the projection fires with old and new values
+	reload("name: two\ndatabase:\n  host: db2\n  port: 5432\n")
+	require.Len(t, dbChanges, 1)
+	assert.Equal(t, "db1", dbChanges[0].old.Host)
+	assert.Equal(t, "db2", dbChanges[0].new.Host)
+	assert.Equal(t, []string{"two"}, names)
+
+	// Reloads without changes fire nothing; slices are compared deeply
+	reload("name: two\ndatabase:\n  host: db2\n  port: 5432\n  hosts: [a, b]\n")
+	require.Len(t, dbChanges, 2)
+	reload("name: two\ndatabase:\n  host: db2\n  port: 5432\n  hosts: [a, b]\n")
+	assert.Len(t, dbChanges, 2)
+
+	// Cancelled subscriptions are not notified
+	cancel()
+	reload("name: three\ndatabase:\n  host: db3\n")
+	assert.Len(t, dbChanges, 2)
+	assert.Equal(t, []string{"two", "three"}, names)
+}
+
+func TestConfigManager_OnChange(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: one\ndatabase:\n  port: 5432\n")
+
+	cm, err := NewBuilder[SubscribeAppConfig]().AddFile(configFile).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	type change struct{ old, new *SubscribeAppConfig }
+	var changes []change
+	unsubscribe := cm.OnChange(func(oldCfg, newCfg *SubscribeAppConfig) {
+		changes = append(changes, change{oldCfg, newCfg})
+	})
+
+	reload := func(content string) error {
+		t.Helper()
+		writeFile(t, configFile, content)
+		return cm.reload(context.Background())
+	}
+
+	// Any change fires with the previous and the published configuration
+	require.NoError(t, reload("name: one\ndatabase:\n  port: 6543\n"))
+	require.Len(t, changes, 1)
+	assert.Equal(t, 5432, changes[0].old.Database.Port)
+	assert.Equal(t, 6543, changes[0].new.Database.Port)
+	assert.Same(t, cm.Get(), changes[0].new)
+
+	// Unchanged reloads and failed reloads do not fire
+	require.NoError(t, reload("name: one\ndatabase:\n  port: 6543\n"))
+	assert.Error(t, reload("name: [unclosed\n"))
+	assert.Len(t, changes, 1)
+
+	// In-memory updates fire as well
+	require.NoError(t, cm.Update(func(cfg *SubscribeAppConfig) error {
+		cfg.Name = "updated"
+		return nil
+	}))
+	require.Len(t, changes, 2)
+	assert.Equal(t, "updated", changes[1].new.Name)
+
+	unsubscribe()
+	require.NoError(t, reload("name: two\n"))
+	assert.Len(t, changes, 2)
+}
+
+func TestConfigManager_OnChangeDiff(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: one\ndatabase:\n  port: 5432\n")
+
+	cm, err := NewBuilder[SubscribeAppConfig]().AddFile(configFile).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	var got []Change
+	unsubscribe := cm.OnChangeDiff(func(_, _ *SubscribeAppConfig, changes []Change) {
+		got = append(got, changes...)
+	})
+	defer unsubscribe()
+
+	writeFile(t, configFile, "name: two\ndatabase:\n  port: 6543\n")
+	require.NoError(t, cm.reload(context.Background()))
+	assert.Equal(t, []Change{
+		{Path: "database.port", OldValue: 5432, NewValue: 6543},
+		{Path: "name", OldValue: "one", NewValue: "two"},
+	}, got)
+}
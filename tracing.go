@@ -0,0 +1,43 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements the OpenTelemetry spans recorded for the load and reload
+// pipeline when a tracer is set with WithTracer.
+package vcfg
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Span names and attributes recorded by the manager
+const (
+	spanReload     = "vcfg.reload"
+	spanLoad       = "vcfg.load"
+	spanLoadSource = "vcfg.load_source"
+	spanValidate   = "vcfg.validate"
+
+	attrSource  = attribute.Key("vcfg.source")
+	attrSources = attribute.Key("vcfg.sources")
+)
+
+// startSpan starts a span as a child of the span in ctx. Without a tracer the
+// span is a no-op.
+func (cm *ConfigManager[T]) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	var tracer trace.Tracer = noop.Tracer{}
+	if cm.tracer != nil {
+		tracer = cm.tracer
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, marking it failed if err is not nil
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,74 @@
+package vcfg
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spanNames returns the names of the ended spans in end order
+func spanNames(recorder *tracetest.SpanRecorder) []string {
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+	}
+	return names
+}
+
+func TestBuilder_WithTracer(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\ncache:\n  host: one\n")
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	cm, err := NewBuilder[UpdateAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		WithTracer(provider.Tracer("vcfg")).
+		Build(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"vcfg.load_source", "vcfg.validate", "vcfg.load", "vcfg.plugin.startup"}, spanNames(recorder))
+	ended := recorder.Ended()
+	assert.Contains(t, ended[0].Attributes(), attribute.String("vcfg.source", configFile))
+	assert.Equal(t, ended[2].SpanContext().SpanID(), ended[0].Parent().SpanID())
+	assert.Equal(t, ended[2].SpanContext().SpanID(), ended[1].Parent().SpanID())
+	assert.Contains(t, ended[3].Attributes(), attribute.String("vcfg.plugin.key", "recorder:cache"))
+
+	// A reload nests the load and the plugin reload
+	recorder.Reset()
+	writeFile(t, configFile, "name: app\ncache:\n  host: two\n")
+	require.NoError(t, cm.Reload(context.Background()))
+	assert.Equal(t, []string{"vcfg.load_source", "vcfg.validate", "vcfg.load", "vcfg.plugin.reload", "vcfg.reload"}, spanNames(recorder))
+	ended = recorder.Ended()
+	reload := ended[4]
+	assert.Contains(t, reload.Attributes(), attribute.StringSlice("vcfg.sources", []string{"manual"}))
+	assert.Equal(t, reload.SpanContext().SpanID(), ended[2].Parent().SpanID())
+	assert.Equal(t, reload.SpanContext().SpanID(), ended[3].Parent().SpanID())
+
+	// Failures are marked on the failing span and its parents
+	recorder.Reset()
+	writeFile(t, configFile, "name: ''\n")
+	require.Error(t, cm.Reload(context.Background()))
+	assert.Equal(t, []string{"vcfg.load_source", "vcfg.validate", "vcfg.load", "vcfg.reload"}, spanNames(recorder))
+	ended = recorder.Ended()
+	assert.Equal(t, codes.Unset, ended[0].Status().Code)
+	for _, span := range ended[1:] {
+		assert.Equal(t, codes.Error, span.Status().Code, span.Name())
+	}
+
+	recorder.Reset()
+	require.NoError(t, cm.Close())
+	assert.Equal(t, []string{"vcfg.plugin.shutdown"}, spanNames(recorder))
+
+	_, err = NewBuilder[UpdateAppConfig]().WithTracer(nil).Build(context.Background())
+	assert.Error(t, err)
+}
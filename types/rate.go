@@ -0,0 +1,114 @@
+// Package types provides value types and parsing helpers for human-readable
+// configuration values.
+// This file implements Rate, a frequency such as "50/s".
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rateUnits maps the accepted period names to their duration
+var rateUnits = map[string]time.Duration{
+	"ms":     time.Millisecond,
+	"s":      time.Second,
+	"sec":    time.Second,
+	"second": time.Second,
+	"m":      time.Minute,
+	"min":    time.Minute,
+	"minute": time.Minute,
+	"h":      time.Hour,
+	"hour":   time.Hour,
+	"d":      24 * time.Hour,
+	"day":    24 * time.Hour,
+}
+
+// Rate is a frequency in events per second that decodes from strings such as
+// "50/s", "3000/min" or "10/5s", and from plain numbers of events per second:
+//
+//	type LimitConfig struct {
+//		Requests types.Rate `koanf:"requests" default:"100/s" validate:"gt=0,max=10000"`
+//	}
+//
+// Its underlying type is float64, so numeric validation rules apply to it in
+// events per second.
+type Rate float64
+
+// ParseRate parses a rate of the form "<count>/<period>", where the period is a
+// unit (ms, s, m, h, d, or the long forms sec, min, hour, day) or a duration
+// such as "5s". A plain number is read as events per second.
+func ParseRate(s string) (Rate, error) {
+	value := strings.TrimSpace(s)
+	if value == "" {
+		return 0, fmt.Errorf("empty rate")
+	}
+
+	count, period, hasPeriod := strings.Cut(value, "/")
+	n, err := strconv.ParseFloat(strings.TrimSpace(count), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q: %w", s, err)
+	}
+	if n < 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+		return 0, fmt.Errorf("invalid rate %q: count must be a non-negative number", s)
+	}
+	if !hasPeriod {
+		return Rate(n), nil
+	}
+
+	per, err := parseRatePeriod(strings.TrimSpace(period))
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q: %w", s, err)
+	}
+	return Rate(n / per.Seconds()), nil
+}
+
+// parseRatePeriod parses the period of a rate, a unit name or a duration
+func parseRatePeriod(period string) (time.Duration, error) {
+	if d, ok := rateUnits[strings.ToLower(period)]; ok {
+		return d, nil
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil {
+		return 0, fmt.Errorf("unknown period %q", period)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("period %q must be positive", period)
+	}
+	return d, nil
+}
+
+// PerSecond returns the number of events per second.
+func (r Rate) PerSecond() float64 {
+	return float64(r)
+}
+
+// Every returns the interval between two events, 0 for a zero rate.
+func (r Rate) Every() time.Duration {
+	if r <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / float64(r))
+}
+
+// String formats the rate in events per second, e.g. "50/s".
+func (r Rate) String() string {
+	return strconv.FormatFloat(float64(r), 'f', -1, 64) + "/s"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *Rate) UnmarshalText(text []byte) error {
+	rate, err := ParseRate(string(text))
+	if err != nil {
+		return err
+	}
+	*r = rate
+	return nil
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Rate
+	}{
+		{"50/s", 50},
+		{"50", 50},
+		{"3000/min", 50},
+		{"3000/m", 50},
+		{"7200/h", 2},
+		{"10/5s", 2},
+		{"1/100ms", 10},
+		{" 0.5 / s ", 0.5},
+		{"0/s", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseRate(tt.input)
+			require.NoError(t, err)
+			assert.InDelta(t, float64(tt.expected), float64(result), 1e-9)
+		})
+	}
+}
+
+func TestParseRate_Invalid(t *testing.T) {
+	for _, input := range []string{"", "/s", "fast", "-1/s", "10/fortnight", "10/0s", "10/-1s"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseRate(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRate(t *testing.T) {
+	var r Rate
+	require.NoError(t, r.UnmarshalText([]byte("100/s")))
+	assert.Equal(t, 100.0, r.PerSecond())
+	assert.Equal(t, 10*time.Millisecond, r.Every())
+	assert.Equal(t, "100/s", r.String())
+	assert.Equal(t, time.Duration(0), Rate(0).Every())
+
+	text, err := Rate(0.5).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "0.5/s", string(text))
+}
@@ -0,0 +1,148 @@
+// Package types provides value types and parsing helpers for human-readable
+// configuration values such as byte sizes, rates and URLs. They are shared by
+// the decoding, defaults and validation layers of vcfg.
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// byteUnits maps the accepted unit suffixes (lowercase) to their multiplier.
+// Units are binary: KB and KiB both denote 1024 bytes.
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kb":  1 << 10,
+	"kib": 1 << 10,
+	"m":   1 << 20,
+	"mb":  1 << 20,
+	"mib": 1 << 20,
+	"g":   1 << 30,
+	"gb":  1 << 30,
+	"gib": 1 << 30,
+	"t":   1 << 40,
+	"tb":  1 << 40,
+	"tib": 1 << 40,
+	"p":   1 << 50,
+	"pb":  1 << 50,
+	"pib": 1 << 50,
+}
+
+// ParseBytes parses a human-readable byte size such as "512", "100MB", "1.5 GiB"
+// or "64k" into a number of bytes. Units are case-insensitive and binary
+// (1KB = 1024 bytes). A value without a unit is interpreted as bytes.
+//
+// Returns an error if the value is empty, negative, has an unknown unit,
+// or overflows int64.
+func ParseBytes(s string) (int64, error) {
+	value := strings.TrimSpace(s)
+	if value == "" {
+		return 0, fmt.Errorf("empty byte size")
+	}
+
+	// Split numeric part from unit suffix
+	i := 0
+	for i < len(value) && (value[i] >= '0' && value[i] <= '9' || value[i] == '.') {
+		i++
+	}
+
+	number := value[:i]
+	unit := strings.ToLower(strings.TrimSpace(value[i:]))
+
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", s, value[i:])
+	}
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q: %w", s, err)
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63, which no longer fits int64
+	bytes := n * multiplier
+	if math.IsNaN(bytes) || bytes < 0 {
+		return 0, fmt.Errorf("invalid byte size %q: not a non-negative number", s)
+	}
+	if bytes >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size %q: value overflows int64", s)
+	}
+
+	return int64(bytes), nil
+}
+
+// HasByteUnit reports whether s carries an explicit unit suffix (e.g. "10MB"),
+// as opposed to a plain number of bytes.
+func HasByteUnit(s string) bool {
+	value := strings.TrimSpace(s)
+	if value == "" {
+		return false
+	}
+	last := value[len(value)-1]
+	return (last < '0' || last > '9') && last != '.'
+}
+
+// sizeUnits are the units used by Size.String, largest first
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"PB", 1 << 50},
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+}
+
+// Size is a number of bytes that decodes from human-readable sizes such as
+// "100MB" as well as from plain numbers of bytes, in every provider:
+//
+//	type ServerConfig struct {
+//		MaxBodySize types.Size `koanf:"max_body_size" default:"10MB" validate:"max_bytes=1GB"`
+//	}
+//
+// Its underlying type is int64, so numeric validation rules apply to it.
+type Size int64
+
+// ParseSize parses a human-readable byte size like ParseBytes.
+func ParseSize(s string) (Size, error) {
+	n, err := ParseBytes(s)
+	return Size(n), err
+}
+
+// Bytes returns the size as a number of bytes.
+func (s Size) Bytes() int64 {
+	return int64(s)
+}
+
+// String formats the size with the largest unit that represents it exactly,
+// e.g. "100MB" or "1536B".
+func (s Size) String() string {
+	if s != 0 {
+		for _, unit := range sizeUnits {
+			if int64(s)%unit.bytes == 0 {
+				return strconv.FormatInt(int64(s)/unit.bytes, 10) + unit.suffix
+			}
+		}
+	}
+	return strconv.FormatInt(int64(s), 10) + "B"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Size) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Size) UnmarshalText(text []byte) error {
+	size, err := ParseSize(string(text))
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"0", 0},
+		{"512", 512},
+		{"512B", 512},
+		{"1KB", 1024},
+		{"1kb", 1024},
+		{"1KiB", 1024},
+		{"64k", 64 * 1024},
+		{"500MB", 500 * 1024 * 1024},
+		{"1.5 GB", 1536 * 1024 * 1024},
+		{"2TB", 2 << 40},
+		{" 1 GiB ", 1 << 30},
+		{"8191PB", 8191 << 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseBytes(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestParseBytes_Invalid(t *testing.T) {
+	for _, input := range []string{"", "MB", "10XB", "-1MB", "1.2.3MB", "99999999PB", "9223372036854775807", "8192PB"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseBytes(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHasByteUnit(t *testing.T) {
+	assert.True(t, HasByteUnit("10MB"))
+	assert.True(t, HasByteUnit("1k"))
+	assert.False(t, HasByteUnit("1024"))
+	assert.False(t, HasByteUnit("1.5"))
+	assert.False(t, HasByteUnit(""))
+}
+
+func TestSize(t *testing.T) {
+	var s Size
+	require.NoError(t, s.UnmarshalText([]byte("100MB")))
+	assert.Equal(t, Size(100<<20), s)
+	assert.Equal(t, int64(100<<20), s.Bytes())
+	assert.Equal(t, "100MB", s.String())
+
+	text, err := Size(1536).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "1536B", string(text))
+	assert.Equal(t, "0B", Size(0).String())
+	assert.Equal(t, "2GB", Size(2<<30).String())
+
+	assert.Error(t, s.UnmarshalText([]byte("10XB")))
+	assert.Equal(t, Size(100<<20), s, "a failed parse keeps the value")
+}
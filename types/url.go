@@ -0,0 +1,60 @@
+// Package types provides value types and parsing helpers for human-readable
+// configuration values.
+// This file implements URL, a parsed URL decoded from a string.
+package types
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// URL is a URL that decodes from its string form in every provider, so the
+// configuration holds it parsed rather than as a raw string:
+//
+//	type UpstreamConfig struct {
+//		Endpoint types.URL `koanf:"endpoint" default:"http://localhost:8080" validate:"required"`
+//	}
+//
+// The fields and methods of url.URL are available directly, e.g.
+// cfg.Endpoint.Host. An empty string decodes to the zero URL, which the
+// required rule rejects.
+type URL struct {
+	url.URL
+}
+
+// ParseURL parses s into a URL. Only absolute URLs with a scheme and host are
+// accepted, since relative references are rarely meant in configurations.
+func ParseURL(s string) (URL, error) {
+	if s == "" {
+		return URL{}, nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return URL{}, fmt.Errorf("invalid URL %q: %w", s, err)
+	}
+	if u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+		return URL{}, fmt.Errorf("invalid URL %q: scheme and host are required", s)
+	}
+	return URL{URL: *u}, nil
+}
+
+// String returns the URL in its string form, "" for the zero URL.
+func (u URL) String() string {
+	return u.URL.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u URL) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *URL) UnmarshalText(text []byte) error {
+	parsed, err := ParseURL(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
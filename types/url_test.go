@@ -0,0 +1,39 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURL(t *testing.T) {
+	u, err := ParseURL("https://user@api.example.com:8443/v1?x=1")
+	require.NoError(t, err)
+	assert.Equal(t, "https", u.Scheme)
+	assert.Equal(t, "api.example.com:8443", u.Host)
+	assert.Equal(t, "8443", u.Port())
+	assert.Equal(t, "/v1", u.Path)
+	assert.Equal(t, "https://user@api.example.com:8443/v1?x=1", u.String())
+
+	empty, err := ParseURL("")
+	require.NoError(t, err)
+	assert.Equal(t, URL{}, empty)
+
+	for _, input := range []string{"api.example.com", "/relative/path", "http://[::1", "://missing"} {
+		_, err := ParseURL(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestURL_Text(t *testing.T) {
+	var u URL
+	require.NoError(t, u.UnmarshalText([]byte("redis://cache:6379/0")))
+	assert.Equal(t, "cache:6379", u.Host)
+
+	text, err := u.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "redis://cache:6379/0", string(text))
+
+	assert.Error(t, u.UnmarshalText([]byte("not a url")))
+}
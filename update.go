@@ -0,0 +1,142 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements transactional in-memory updates of the configuration.
+package vcfg
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+
+	"github.com/nextpkg/vcfg/plugins"
+	"github.com/nextpkg/vcfg/validator"
+)
+
+// updateSource is the trigger source recorded in ReloadInfo for Update calls
+const updateSource = "update"
+
+// Update applies several changes to the configuration atomically. fn receives a
+// deep copy of the current configuration to modify; if fn returns an error or the
+// modified copy fails validation, nothing changes. Otherwise the copy becomes the
+// current configuration in one step and affected plugins are reloaded in a single
+// pass, exactly like a reload from the sources (subscribers and reload hooks run
+// too, and LastReloadInfo reports the source "update").
+//
+// The values that changed are written to the merged values behind the key
+// accessors, Sub, UnmarshalInto and MarshalMerged as well, so they agree with
+// Get, and Origin reports them as "update". Unchanged values are kept as the
+// sources set them, with encrypted values and ${...} references unresolved.
+//
+// Updates live in memory only: the next reload from the sources, e.g. triggered
+// by a file change, replaces them with the values read from the sources.
+func (cm *ConfigManager[T]) Update(fn func(cfg *T) error) (err error) {
+	if cm == nil {
+		return NewParseError("manager", "configuration manager not properly initialized", nil)
+	}
+
+	info := ReloadInfo{
+		Sources: []string{updateSource},
+		Time:    time.Now(),
+	}
+
+	cm.reloadMu.Lock()
+	defer cm.reloadMu.Unlock()
+
+	current := cm.Get()
+	if current == nil {
+		return fmt.Errorf("no configuration loaded to update")
+	}
+
+	draft := plugins.DeepCopy(current)
+	if err := fn(draft); err != nil {
+		return fmt.Errorf("update rejected: %w", err)
+	}
+
+	if err := validator.ValidateWithTag(draft, cm.validateTag); err != nil {
+		return NewValidationError("update", "updated configuration validation failed", err)
+	}
+
+	// Only attempted updates that passed validation are recorded as reloads
+	defer func() {
+		info.Err = err
+		cm.recordReload(info)
+	}()
+
+	cm.mu.Lock()
+	if cm.koanf != nil {
+		cm.koanf, cm.origins, err = updatedValues(cm.koanf, cm.origins, current, draft)
+	}
+	cm.mu.Unlock()
+	if err != nil {
+		return NewParseError(updateSource, "failed to apply updated values", err)
+	}
+
+	return cm.publish(context.Background(), &info, current, draft)
+}
+
+// updatedValues returns copies of the merged values k and their origins in
+// which the leaves that differ between current and draft hold the values of
+// draft, written like Dump writes them, and are attributed to Update. The other
+// values are kept as merged, so decrypted secrets and expanded references are
+// never written back.
+func updatedValues[T any](k *koanf.Koanf, origins keyOrigins, current, draft *T) (*koanf.Koanf, keyOrigins, error) {
+	updated := k.Copy()
+	updatedOrigins := make(keyOrigins, len(origins))
+	maps.Copy(updatedOrigins, origins)
+
+	values, _ := dumpValue(reflect.ValueOf(draft)).(map[string]any)
+	for _, change := range Diff(current, draft) {
+		// Diff masks secrets, so the new value is taken from draft itself
+		segments := strings.Split(change.Path, ".")
+		value := lookupFold(values, segments)
+
+		// Sources may spell the keys in another case, as decoding ignores case
+		path := strings.Join(foldPath(updated.Raw(), segments), ".")
+		updated.Delete(path)
+		if value == nil {
+			continue
+		}
+
+		leaf := koanf.New(".")
+		if err := leaf.Set(path, value); err != nil {
+			return nil, nil, err
+		}
+		if err := updated.Merge(leaf); err != nil {
+			return nil, nil, err
+		}
+		updatedOrigins.record(leaf.Keys(), updateSource)
+	}
+	updatedOrigins.prune(updated)
+	return updated, updatedOrigins, nil
+}
+
+// foldPath returns the key path segments as spelled in m, matching each
+// segment exactly or else case-insensitively; segments not in m are kept
+func foldPath(m map[string]any, segments []string) []string {
+	path := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		key, found := segment, false
+		if _, ok := m[segment]; ok {
+			found = true
+		} else {
+			for existing := range m {
+				if strings.EqualFold(existing, segment) {
+					key, found = existing, true
+					break
+				}
+			}
+		}
+		path = append(path, key)
+
+		sub, ok := m[key].(map[string]any)
+		if !found || !ok {
+			return append(path, segments[i+1:]...)
+		}
+		m = sub
+	}
+	return path
+}
@@ -0,0 +1,147 @@
+package vcfg
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// UpdateAppConfig is an application config with validation rules and a recorder plugin
+type UpdateAppConfig struct {
+	Name  string         `koanf:"name" validate:"required"`
+	Tags  []string       `koanf:"tags"`
+	Cache recorderConfig `koanf:"cache"`
+}
+
+func TestConfigManager_Update(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "name: app\ntags: [a]\ncache:\n  host: one\n  port: 1000\n")
+
+	cm, err := NewBuilder[UpdateAppConfig]().
+		AddFile(configFile).
+		WithPlugin().
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	plugin := getRecorder(t, cm, "recorder:cache")
+	original := cm.Get()
+
+	// An update violating validation is rejected without side effects
+	err = cm.Update(func(cfg *UpdateAppConfig) error {
+		cfg.Name = ""
+		cfg.Tags[0] = "mutated"
+		cfg.Cache.Host = "two"
+		return nil
+	})
+	assert.ErrorIs(t, err, &ConfigError{Type: ErrorTypeValidationFailure})
+	assert.Same(t, original, cm.Get())
+	assert.Equal(t, []string{"a"}, original.Tags)
+	assert.Equal(t, 0, plugin.reloadCount())
+
+	// An error returned by the update function aborts it
+	errAbort := errors.New("abort")
+	err = cm.Update(func(cfg *UpdateAppConfig) error {
+		cfg.Cache.Host = "two"
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+	assert.Same(t, original, cm.Get())
+
+	// Several fields change together with a single plugin reload
+	require.NoError(t, cm.Update(func(cfg *UpdateAppConfig) error {
+		cfg.Cache.Host = "two"
+		cfg.Cache.Port = 2000
+		return nil
+	}))
+	assert.Equal(t, "two", cm.Get().Cache.Host)
+	assert.Equal(t, 2000, cm.Get().Cache.Port)
+	assert.Equal(t, "one", original.Cache.Host)
+
+	require.Equal(t, 1, plugin.reloadCount())
+	assert.Equal(t, "two", plugin.lastReload().Host)
+	assert.Equal(t, 2000, plugin.lastReload().Port)
+
+	info := cm.LastReloadInfo()
+	require.NoError(t, info.Err)
+	assert.Equal(t, []string{"update"}, info.Sources)
+	assert.Equal(t, []string{"cache.host", "cache.port"}, info.ChangedPaths)
+	assert.Equal(t, []string{"recorder:cache"}, info.ReloadedPlugins)
+}
+
+func TestConfigManager_UpdateAccessors(t *testing.T) {
+	type Server struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	}
+	type Config struct {
+		Srv Server `koanf:"srv"`
+	}
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "srv:\n  host: one\n  port: 1\nextra: kept\n")
+
+	cm, err := NewBuilder[Config]().AddFile(configFile).Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	require.NoError(t, cm.Update(func(cfg *Config) error {
+		cfg.Srv.Port = 2
+		return nil
+	}))
+
+	// The key accessors, Sub and UnmarshalInto see the update like Get does
+	assert.Equal(t, 2, cm.Get().Srv.Port)
+	assert.Equal(t, 2, cm.Int("srv.port"))
+	assert.Equal(t, "one", cm.String("srv.host"))
+	assert.Equal(t, "kept", cm.String("extra"))
+
+	srv, err := Sub[Server](cm, "srv")
+	require.NoError(t, err)
+	assert.Equal(t, Server{Host: "one", Port: 2}, *srv)
+
+	var out Config
+	require.NoError(t, cm.UnmarshalInto(&out))
+	assert.Equal(t, 2, out.Srv.Port)
+
+	snapshots := cm.Snapshots()
+	require.NotEmpty(t, snapshots)
+	assert.Equal(t, 2, snapshots[len(snapshots)-1].Values["srv"].(map[string]any)["port"])
+}
+
+func TestConfigManager_UpdateKeepsUnresolvedValues(t *testing.T) {
+	t.Setenv("VCFG_UPDATE_HOST", "db.internal")
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configFile, "database:\n  host: ${VCFG_UPDATE_HOST}\n  port: 1\n  password: enc:abc\n")
+
+	cm, err := NewBuilder[ExpandTestConfig]().
+		AddFile(configFile).
+		WithEnvExpansion().
+		WithDecryptor(func(ciphertext string) (string, error) { return "DECRYPTED-" + ciphertext, nil }).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+
+	require.NoError(t, cm.Update(func(cfg *ExpandTestConfig) error {
+		cfg.Database.Port = 2
+		return nil
+	}))
+	assert.Equal(t, "DECRYPTED-abc", cm.Get().Database.Password)
+	assert.Equal(t, "db.internal", cm.Get().Database.Host)
+
+	// Only the changed value is written back; the ciphertext and the reference stay
+	merged, err := cm.MarshalMerged("json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"database":{"host":"${VCFG_UPDATE_HOST}","port":2,"password":"enc:abc"}}`, string(merged))
+	assert.Equal(t, "enc:abc", cm.Snapshots()[len(cm.Snapshots())-1].Values["database"].(map[string]any)["password"])
+
+	source, _ := cm.Origin("database.port")
+	assert.Equal(t, "update", source)
+	source, _ = cm.Origin("database.password")
+	assert.Equal(t, configFile, source)
+}
@@ -0,0 +1,144 @@
+// Package validator provides configuration validation functionality using the
+// go-playground/validator library.
+// This file implements the aggregated validation errors that report every
+// failing field with its configuration key path.
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a field that failed a validation rule.
+type FieldError struct {
+	// Path is the configuration key path of the field as derived from its koanf
+	// tags, e.g. server.port; list and map elements carry their index, e.g.
+	// servers[0].port
+	Path string
+	// Rule is the failed rule, e.g. required or min
+	Rule string
+	// Param is the parameter of the rule, e.g. 1 for min=1; empty if none
+	Param string
+}
+
+// Error implements the error interface. The field value is left out, since it
+// may be a secret.
+func (e *FieldError) Error() string {
+	rule := e.Rule
+	if e.Param != "" {
+		rule += "=" + e.Param
+	}
+	return fmt.Sprintf("%s: failed rule %q", e.Path, rule)
+}
+
+// FieldErrors lists every field that failed validation, in field order, so all
+// problems can be fixed in one pass. Use errors.As to get it from the error
+// returned by Validate.
+type FieldErrors []*FieldError
+
+// Error implements the error interface, listing every field error.
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fieldErr := range e {
+		msgs[i] = fieldErr.Error()
+	}
+	if len(e) == 1 {
+		return "1 field failed validation: " + msgs[0]
+	}
+	return fmt.Sprintf("%d fields failed validation: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the field errors, so errors.As finds each *FieldError.
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fieldErr := range e {
+		errs[i] = fieldErr
+	}
+	return errs
+}
+
+// fieldErrors converts the go-playground errors of validating v to FieldErrors.
+// Other errors are returned unchanged.
+func fieldErrors(v any, err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	root := reflect.TypeOf(v)
+	result := make(FieldErrors, len(validationErrs))
+	for i, fieldErr := range validationErrs {
+		result[i] = &FieldError{
+			Path:  keyPath(root, fieldErr.StructNamespace()),
+			Rule:  fieldErr.Tag(),
+			Param: fieldErr.Param(),
+		}
+	}
+	return result
+}
+
+// keyPath converts the struct namespace of a field, e.g. AppConfig.Server.Port,
+// to its configuration key path within the struct type root, e.g. server.port.
+// Fields are named by their koanf tag like when decoding, falling back to the
+// lower-cased field name; squashed and embedded structs add no segment.
+func keyPath(root reflect.Type, namespace string) string {
+	// The first segment is the name of the root type
+	segments := strings.Split(namespace, ".")[1:]
+
+	var path []string
+	t := root
+	for _, segment := range segments {
+		name, index := segment, ""
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			name, index = segment[:i], segment[i:]
+		}
+
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		var field reflect.StructField
+		found := false
+		if t != nil && t.Kind() == reflect.Struct {
+			field, found = t.FieldByName(name)
+		}
+		if !found {
+			// Names reported by struct level rules need not match a field
+			path = append(path, strings.ToLower(name)+index)
+			t = nil
+			continue
+		}
+
+		if key, ok := fieldKey(field); ok {
+			path = append(path, key+index)
+		}
+		t = field.Type
+		for range strings.Count(index, "[") {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			t = t.Elem()
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+// fieldKey returns the key segment of field, false for squashed and embedded
+// fields that add no segment
+func fieldKey(field reflect.StructField) (string, bool) {
+	if tag, ok := field.Tag.Lookup("koanf"); ok {
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			return parts[0], true
+		}
+		if len(parts) > 1 && parts[1] == "squash" {
+			return "", false
+		}
+	} else if field.Anonymous {
+		return "", false
+	}
+	return strings.ToLower(field.Name), true
+}
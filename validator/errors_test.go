@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+)
+
+type ServerSettings struct {
+	Port int    `koanf:"port" validate:"min=1,max=65535"`
+	Host string `koanf:"host" validate:"required"`
+}
+
+type EmbeddedSettings struct {
+	Region string `koanf:"region" validate:"required"`
+}
+
+type AggregateConfig struct {
+	EmbeddedSettings `koanf:",squash"`
+	Server           ServerSettings `koanf:"server"`
+	Database         struct {
+		URL string `validate:"required"`
+	} `koanf:"database"`
+	Replicas []ServerSettings `koanf:"replicas" validate:"dive"`
+	Limits   map[string]int   `koanf:"limits" validate:"dive,min=1"`
+}
+
+// TestValidate_FieldErrors tests that every failing field is reported with its key path
+func TestValidate_FieldErrors(t *testing.T) {
+	cfg := &AggregateConfig{
+		Server:   ServerSettings{Port: 0, Host: "localhost"},
+		Replicas: []ServerSettings{{Port: 80, Host: "a"}, {Port: 70000, Host: "b"}},
+		Limits:   map[string]int{"conns": 0},
+	}
+
+	err := Validate(cfg)
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("Expected FieldErrors, got: %v", err)
+	}
+
+	want := []FieldError{
+		{Path: "region", Rule: "required"},
+		{Path: "server.port", Rule: "min", Param: "1"},
+		{Path: "database.url", Rule: "required"},
+		{Path: "replicas[1].port", Rule: "max", Param: "65535"},
+		{Path: "limits[conns]", Rule: "min", Param: "1"},
+	}
+	if len(fieldErrs) != len(want) {
+		t.Fatalf("Expected %d field errors, got %d: %v", len(want), len(fieldErrs), err)
+	}
+	for i, fieldErr := range fieldErrs {
+		if *fieldErr != want[i] {
+			t.Errorf("Field error %d = %+v, want %+v", i, *fieldErr, want[i])
+		}
+	}
+
+	if !contains(err.Error(), `5 fields failed validation: region: failed rule "required"; server.port: failed rule "min=1"`) {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+
+	// Each field error can be found with errors.As
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Path != "region" {
+		t.Errorf("Expected the first field error, got: %v", fieldErr)
+	}
+}
+
+// TestValidate_SingleFieldError tests the message of a single failing field
+func TestValidate_SingleFieldError(t *testing.T) {
+	err := Validate(&ServerSettings{Port: 8080})
+	if err == nil || err.Error() != `struct validation failed: 1 field failed validation: host: failed rule "required"` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -1,17 +1,208 @@
 // Package validator provides configuration validation functionality using the
-// go-playground/validator library. It supports both struct tag validation
+// go-playground/validator library. It supports struct tag validation, including
+// the cross-field and conditional rules of go-playground such as required_if and
+// gtfield, rules registered with RegisterValidation and RegisterStructValidation,
 // and custom validation through the Validator interface.
 package validator
 
 import (
+	"cmp"
 	"fmt"
+	"reflect"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/nextpkg/vcfg/types"
+)
+
+// defaultTagName is the struct tag read by the validator unless changed with SetTagName
+const defaultTagName = "validate"
+
+var (
+	// vld is the global validator instance configured with required struct validation enabled.
+	// This ensures that struct fields marked as required are properly validated.
+	vld = newValidator(defaultTagName)
+	// vldTag is the struct tag read by vld
+	vldTag = defaultTagName
+	// vldMu protects vld and vldTag, which SetTagName replaces
+	vldMu sync.RWMutex
+	// rulesMu protects rules and tagged, so tagged instances are always built
+	// with all registered rules
+	rulesMu sync.RWMutex
+	// tagged caches validator instances for tag names passed to ValidateWithTag
+	tagged = make(map[string]*validator.Validate)
+	// rules are the registrations of RegisterValidation and RegisterStructValidation,
+	// applied to every validator instance
+	rules []func(*validator.Validate) error
 )
 
-// vld is the global validator instance configured with required struct validation enabled.
-// This ensures that struct fields marked as required are properly validated.
-var vld = validator.New(validator.WithRequiredStructEnabled())
+// newValidator creates a validator instance reading the given struct tag and
+// registers vcfg's custom rules:
+//   - min_bytes: the integer field must hold at least the given size (e.g. min_bytes=1KB)
+//   - max_bytes: the integer field must hold at most the given size (e.g. max_bytes=1GB)
+func newValidator(tagName string) *validator.Validate {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	return buildValidator(tagName)
+}
+
+// buildValidator is newValidator for callers that hold rulesMu
+func buildValidator(tagName string) *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.SetTagName(tagName)
+
+	// Registration only fails for empty tags or nil functions, so errors are impossible here
+	_ = v.RegisterValidation("min_bytes", bytesRule(func(order int) bool { return order >= 0 }))
+	_ = v.RegisterValidation("max_bytes", bytesRule(func(order int) bool { return order <= 0 }))
+
+	for _, rule := range rules {
+		// Rules were checked on registration, so they apply without errors
+		_ = rule(v)
+	}
+
+	return v
+}
+
+// bytesRule builds a validation function that compares an integer field holding
+// a byte count with the human-readable size given as the tag parameter. accept
+// reports whether the field passes given the order of the field value relative
+// to the size, as returned by cmp.Compare.
+func bytesRule(accept func(order int) bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		limit, err := types.ParseBytes(fl.Param())
+		if err != nil {
+			panic(fmt.Sprintf("invalid %s parameter %q: %v", fl.GetTag(), fl.Param(), err))
+		}
+
+		field := fl.Field()
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return accept(cmp.Compare(field.Int(), limit))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			// Sizes are never negative, and unsigned values may exceed int64
+			return accept(cmp.Compare(field.Uint(), uint64(limit)))
+		default:
+			return false
+		}
+	}
+}
+
+// SetTagName changes the struct tag read by Validate, e.g. to "check" for projects
+// that already use `validate` for another library. An empty name restores the
+// default "validate". It is safe to call concurrently with Validate; validations
+// already in progress finish with the previous tag name.
+func SetTagName(name string) {
+	if name == "" {
+		name = defaultTagName
+	}
+
+	vldMu.Lock()
+	defer vldMu.Unlock()
+	vld = newValidator(name)
+	vldTag = name
+}
+
+// Func validates a field for a rule added with RegisterValidation, an alias so
+// rules can be written without importing go-playground/validator.
+type Func = validator.Func
+
+// FieldLevel gives a Func access to the field being validated.
+type FieldLevel = validator.FieldLevel
+
+// StructLevelFunc validates a struct for RegisterStructValidation.
+type StructLevelFunc = validator.StructLevelFunc
+
+// StructLevel gives a StructLevelFunc access to the struct and reports errors.
+type StructLevel = validator.StructLevel
+
+// RegisterValidation adds a custom validation rule for tag, usable in the
+// struct tags of every configuration like the built-in rules:
+//
+//	validator.RegisterValidation("region", func(fl validator.FieldLevel) bool {
+//		return slices.Contains(knownRegions, fl.Field().String())
+//	})
+//
+// See validator.Validate.RegisterValidation of go-playground for the meaning of
+// callValidationEvenIfNull. Registering a tag again replaces its rule. It is
+// safe to call concurrently with Validate; validations already in progress
+// finish without the new rule.
+func RegisterValidation(tag string, fn Func, callValidationEvenIfNull ...bool) error {
+	return addRule(func(v *validator.Validate) error {
+		return v.RegisterValidation(tag, fn, callValidationEvenIfNull...)
+	})
+}
+
+// RegisterStructValidation adds fn as a validation of the given struct types,
+// for rules spanning several fields that struct tags cannot express. Report
+// failures with sl.ReportError:
+//
+//	validator.RegisterStructValidation(func(sl validator.StructLevel) {
+//		cfg := sl.Current().Interface().(TLSConfig)
+//		if cfg.Enabled && cfg.CertFile == "" {
+//			sl.ReportError(cfg.CertFile, "CertFile", "cert_file", "required_with_tls", "")
+//		}
+//	}, TLSConfig{})
+//
+// It is safe to call concurrently with Validate.
+func RegisterStructValidation(fn StructLevelFunc, structTypes ...any) error {
+	if fn == nil {
+		return fmt.Errorf("struct validation function cannot be nil")
+	}
+	return addRule(func(v *validator.Validate) error {
+		v.RegisterStructValidation(fn, structTypes...)
+		return nil
+	})
+}
+
+// addRule checks rule on a scratch instance, records it for future instances
+// and replaces the existing instances with ones that include it. Instances are
+// replaced rather than modified because go-playground registrations must not
+// run concurrently with validations.
+func addRule(rule func(*validator.Validate) error) error {
+	if err := rule(validator.New()); err != nil {
+		return err
+	}
+
+	rulesMu.Lock()
+	rules = append(rules, rule)
+	for tagName := range tagged {
+		tagged[tagName] = buildValidator(tagName)
+	}
+	rulesMu.Unlock()
+
+	vldMu.Lock()
+	vld = newValidator(vldTag)
+	vldMu.Unlock()
+	return nil
+}
+
+// sharedValidator returns the validator instance used by Validate
+func sharedValidator() *validator.Validate {
+	vldMu.RLock()
+	defer vldMu.RUnlock()
+	return vld
+}
+
+// taggedValidator returns a validator reading tagName, creating it on first use
+func taggedValidator(tagName string) *validator.Validate {
+	rulesMu.RLock()
+	v, ok := tagged[tagName]
+	rulesMu.RUnlock()
+	if ok {
+		return v
+	}
+
+	// Built under the write lock, so a rule registered meanwhile is included
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	if v, ok := tagged[tagName]; ok {
+		return v
+	}
+	v = buildValidator(tagName)
+	tagged[tagName] = v
+	return v
+}
 
 // Validator defines an interface for custom validation logic.
 // Types implementing this interface can provide their own validation
@@ -30,16 +221,32 @@ type Validator interface {
 // 2. Performs struct tag validation (required, format, etc.)
 // 3. Calls custom Validate() method if the type implements Validator interface
 //
-// Returns an error if any validation step fails.
+// Returns an error if any validation step fails. Failed struct tag rules are
+// reported together as FieldErrors, one per failing field with its key path.
 func Validate(v any) error {
+	return validate(sharedValidator(), v)
+}
+
+// ValidateWithTag is like Validate but reads struct tag rules from tagName instead
+// of the shared tag name, without affecting other callers. An empty tagName
+// behaves like Validate.
+func ValidateWithTag(v any, tagName string) error {
+	if tagName == "" {
+		return Validate(v)
+	}
+	return validate(taggedValidator(tagName), v)
+}
+
+// validate runs struct tag validation with instance followed by custom validation
+func validate(instance *validator.Validate, v any) error {
 	if v == nil {
 		return fmt.Errorf("validation target cannot be nil")
 	}
 
 	// basic validate
-	err := vld.Struct(v)
+	err := instance.Struct(v)
 	if err != nil {
-		return fmt.Errorf("struct validation failed: %w", err)
+		return fmt.Errorf("struct validation failed: %w", fieldErrors(v, err))
 	}
 
 	if val, ok := v.(Validator); ok {
@@ -4,6 +4,11 @@ package validator
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -352,3 +357,283 @@ func TestValidate_EdgeCases(t *testing.T) {
 		t.Errorf("Expected no error for struct with only optional fields, got: %v", err)
 	}
 }
+
+// TestValidate_ByteRules tests the min_bytes and max_bytes validation rules
+func TestValidate_ByteRules(t *testing.T) {
+	type SizeConfig struct {
+		MaxFileSize int64  `validate:"omitempty,min_bytes=1KB,max_bytes=1GB"`
+		BufferSize  uint32 `validate:"min_bytes=4k"`
+		MaxMemory   uint64 `validate:"omitempty,max_bytes=1GB"`
+	}
+
+	tests := []struct {
+		name      string
+		value     SizeConfig
+		wantError bool
+	}{
+		{"ValidSizes", SizeConfig{MaxFileSize: 500 << 20, BufferSize: 4096}, false},
+		{"ZeroSkippedByOmitempty", SizeConfig{BufferSize: 8192}, false},
+		{"BelowMinBytes", SizeConfig{MaxFileSize: 100, BufferSize: 4096}, true},
+		{"AboveMaxBytes", SizeConfig{MaxFileSize: 2 << 30, BufferSize: 4096}, true},
+		{"UnsignedBelowMinBytes", SizeConfig{BufferSize: 1024}, true},
+		{"UnsignedAboveInt64", SizeConfig{BufferSize: 4096, MaxMemory: math.MaxUint64}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.value)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Validate() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+// TestValidate_SliceElements tests that dive applies rules to every slice element
+func TestValidate_SliceElements(t *testing.T) {
+	type Endpoint struct {
+		URL string `validate:"required,url"`
+	}
+	type SliceConfig struct {
+		Checks    []string          `validate:"dive,required"`
+		Ports     []int             `validate:"omitempty,dive,min=1,max=65535"`
+		Endpoints []Endpoint        `validate:"dive"`
+		Labels    map[string]string `validate:"dive,keys,required,endkeys,required"`
+	}
+
+	tests := []struct {
+		name      string
+		value     SliceConfig
+		wantError string
+	}{
+		{"Valid", SliceConfig{
+			Checks:    []string{"database", "redis"},
+			Ports:     []int{80, 443},
+			Endpoints: []Endpoint{{URL: "https://example.com"}},
+			Labels:    map[string]string{"team": "core"},
+		}, ""},
+		{"EmptySlices", SliceConfig{}, ""},
+		{"EmptyStringElement", SliceConfig{Checks: []string{"database", ""}}, "checks[1]"},
+		{"IntElementBelowMin", SliceConfig{Ports: []int{80, 0}}, "ports[1]"},
+		{"IntElementAboveMax", SliceConfig{Ports: []int{70000}}, "ports[0]"},
+		{"InvalidStructElement", SliceConfig{Endpoints: []Endpoint{{URL: "https://example.com"}, {URL: "not a url"}}}, "endpoints[1].url"},
+		{"EmptyMapValue", SliceConfig{Labels: map[string]string{"team": ""}}, "labels[team]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.value)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected error for %s, got nil", tt.wantError)
+			}
+			if !contains(err.Error(), tt.wantError) {
+				t.Errorf("Expected error to mention %s, got: %v", tt.wantError, err)
+			}
+		})
+	}
+}
+
+// TestSetTagName tests reading validation rules from a custom tag name
+func TestSetTagName(t *testing.T) {
+	type TaggedStruct struct {
+		Name string `check:"required" validate:"min=100"`
+	}
+
+	SetTagName("check")
+	defer SetTagName("")
+
+	if err := Validate(&TaggedStruct{Name: "app"}); err != nil {
+		t.Errorf("Expected rules of the validate tag to be ignored, got: %v", err)
+	}
+	if err := Validate(&TaggedStruct{}); err == nil {
+		t.Error("Expected error for missing required field under the check tag")
+	}
+
+	// Restoring the default reads the validate tag again
+	SetTagName("")
+	if err := Validate(&TaggedStruct{Name: "app"}); err == nil {
+		t.Error("Expected error from the validate tag after restoring the default")
+	}
+}
+
+// TestValidateWithTag tests per-call tag names alongside concurrent Validate calls
+func TestValidateWithTag(t *testing.T) {
+	type TaggedStruct struct {
+		Port int `cfg:"min=1,max=65535"`
+		Size int `cfg:"omitempty,min_bytes=1KB"`
+	}
+
+	tests := []struct {
+		name      string
+		value     TaggedStruct
+		wantError bool
+	}{
+		{"Valid", TaggedStruct{Port: 8080, Size: 4096}, false},
+		{"PortTooLow", TaggedStruct{Port: 0}, true},
+		{"CustomRuleApplies", TaggedStruct{Port: 80, Size: 10}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWithTag(&tt.value, "cfg")
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateWithTag() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+
+	// The default tag is unaffected and SetTagName is safe to call concurrently
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 50 {
+			SetTagName("validate")
+		}
+	}()
+	for range 50 {
+		if err := Validate(&TestStruct{Name: "a", Email: "a@example.com"}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	<-done
+}
+
+// TestValidate_ConditionalRules tests the cross-field and conditional rules of go-playground
+func TestValidate_ConditionalRules(t *testing.T) {
+	type TLSConfig struct {
+		Enabled  bool
+		CertFile string `validate:"required_if=Enabled true"`
+		MinPort  int
+		MaxPort  int `validate:"gtefield=MinPort"`
+	}
+
+	tests := []struct {
+		name      string
+		value     TLSConfig
+		wantError bool
+	}{
+		{"DisabledWithoutCert", TLSConfig{}, false},
+		{"EnabledWithCert", TLSConfig{Enabled: true, CertFile: "tls.crt"}, false},
+		{"EnabledWithoutCert", TLSConfig{Enabled: true}, true},
+		{"PortRangeInverted", TLSConfig{MinPort: 9000, MaxPort: 8000}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.value)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Validate() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+// TestRegisterValidation tests custom rules on the shared and tag-specific validators
+func TestRegisterValidation(t *testing.T) {
+	type RegionConfig struct {
+		Region string `validate:"region" cfgregion:"region"`
+	}
+
+	// A validator created before the registration picks the rule up too
+	if err := ValidateWithTag(&TestStruct{}, "cfgregion"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	err := RegisterValidation("region", func(fl FieldLevel) bool {
+		return fl.Field().String() == "eu" || fl.Field().String() == "us"
+	})
+	if err != nil {
+		t.Fatalf("RegisterValidation() error = %v", err)
+	}
+
+	if err := Validate(&RegionConfig{Region: "eu"}); err != nil {
+		t.Errorf("Expected known region to pass, got: %v", err)
+	}
+	if err := Validate(&RegionConfig{Region: "mars"}); err == nil {
+		t.Error("Expected unknown region to fail")
+	}
+	if err := ValidateWithTag(&RegionConfig{Region: "mars"}, "cfgregion"); err == nil {
+		t.Error("Expected unknown region to fail with a custom tag name")
+	}
+
+	// The rule survives a change of the tag name
+	SetTagName("cfgregion")
+	defer SetTagName("")
+	if err := Validate(&RegionConfig{Region: "mars"}); err == nil {
+		t.Error("Expected unknown region to fail after SetTagName")
+	}
+
+	if err := RegisterValidation("", func(fl FieldLevel) bool { return true }); err == nil {
+		t.Error("Expected error for empty tag")
+	}
+}
+
+// TestRegisterValidation_ConcurrentTaggedValidator tests that a rule registered
+// while a validator for a new tag name is created reaches that validator
+func TestRegisterValidation_ConcurrentTaggedValidator(t *testing.T) {
+	for i := range 20 {
+		tagName := fmt.Sprintf("cfgrace%d", i)
+		rule := fmt.Sprintf("race_rule_%d", i)
+		ruled := reflect.New(reflect.StructOf([]reflect.StructField{{
+			Name: "Value",
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(fmt.Sprintf(`%s:"%s"`, tagName, rule)),
+		}})).Interface()
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = ValidateWithTag(&TestStruct{}, tagName)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = RegisterValidation(rule, func(fl FieldLevel) bool { return false })
+		}()
+		wg.Wait()
+
+		// A validator cached without the rule would panic on the undefined rule
+		if err := ValidateWithTag(ruled, tagName); err == nil {
+			t.Errorf("Expected %s to fail with tag %s", rule, tagName)
+		}
+	}
+}
+
+// TestRegisterStructValidation tests struct level rules spanning several fields
+func TestRegisterStructValidation(t *testing.T) {
+	type PoolConfig struct {
+		MinConns int
+		MaxConns int
+	}
+
+	err := RegisterStructValidation(func(sl StructLevel) {
+		pool := sl.Current().Interface().(PoolConfig)
+		if pool.MaxConns < pool.MinConns {
+			sl.ReportError(pool.MaxConns, "MaxConns", "max_conns", "pool_range", "")
+		}
+	}, PoolConfig{})
+	if err != nil {
+		t.Fatalf("RegisterStructValidation() error = %v", err)
+	}
+
+	type AppConfig struct {
+		Pool PoolConfig
+	}
+	if err := Validate(&AppConfig{Pool: PoolConfig{MinConns: 1, MaxConns: 10}}); err != nil {
+		t.Errorf("Expected valid pool to pass, got: %v", err)
+	}
+	err = Validate(&AppConfig{Pool: PoolConfig{MinConns: 10, MaxConns: 1}})
+	if err == nil || !strings.Contains(err.Error(), "pool_range") {
+		t.Errorf("Expected pool_range error, got: %v", err)
+	}
+
+	if err := RegisterStructValidation(nil, PoolConfig{}); err == nil {
+		t.Error("Expected error for nil function")
+	}
+}
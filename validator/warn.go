@@ -0,0 +1,70 @@
+// Package validator provides configuration validation functionality using the
+// go-playground/validator library.
+// This file implements warning-level validation: checks that flag deprecated or
+// suspicious settings without rejecting the configuration.
+package validator
+
+import (
+	"errors"
+)
+
+// warnTagSuffix is appended to the tag name of the validation rules to get the
+// tag name of the warning rules, e.g. validate_warn
+const warnTagSuffix = "_warn"
+
+// Warner defines an interface for custom warning checks. Unlike the errors of
+// Validator, the warnings never reject a configuration.
+type Warner interface {
+	// Warnings returns a description of each questionable setting, nil if none
+	Warnings() []string
+}
+
+// Warnings returns the warnings for v: a failed rule of the `validate_warn`
+// struct tag yields a warning instead of an error, followed by the warnings of
+// the Warner interface if v implements it:
+//
+//	type ServerConfig struct {
+//		// Ports below 1024 need privileges, but are allowed
+//		Port int `koanf:"port" validate:"min=1,max=65535" validate_warn:"min=1024"`
+//	}
+//
+// Tag warnings read like FieldError, e.g. `server.port: failed rule "min=1024"`.
+// The warning tag follows SetTagName, e.g. check_warn for "check". Returns nil
+// if there are no warnings.
+func Warnings(v any) []string {
+	vldMu.RLock()
+	tagName := vldTag
+	vldMu.RUnlock()
+
+	return WarningsWithTag(v, tagName)
+}
+
+// WarningsWithTag is like Warnings but reads warning rules from tagName with the
+// suffix _warn, matching ValidateWithTag. An empty tagName behaves like Warnings.
+func WarningsWithTag(v any, tagName string) []string {
+	if v == nil {
+		return nil
+	}
+	if tagName == "" {
+		return Warnings(v)
+	}
+
+	var warnings []string
+	err := taggedValidator(tagName + warnTagSuffix).Struct(v)
+	if err != nil {
+		var fieldErrs FieldErrors
+		if errors.As(fieldErrors(v, err), &fieldErrs) {
+			for _, fieldErr := range fieldErrs {
+				warnings = append(warnings, fieldErr.Error())
+			}
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	if w, ok := v.(Warner); ok {
+		warnings = append(warnings, w.Warnings()...)
+	}
+
+	return warnings
+}
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+type WarnConfig struct {
+	Server struct {
+		Port int `koanf:"port" validate:"min=1" validate_warn:"min=1024"`
+	} `koanf:"server"`
+	LegacyMode bool `koanf:"legacy_mode"`
+}
+
+func (c *WarnConfig) Warnings() []string {
+	if c.LegacyMode {
+		return []string{"legacy_mode is deprecated"}
+	}
+	return nil
+}
+
+// TestWarnings tests that warning rules and Warner report without failing validation
+func TestWarnings(t *testing.T) {
+	cfg := &WarnConfig{LegacyMode: true}
+	cfg.Server.Port = 80
+
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Expected warning rules not to fail validation, got: %v", err)
+	}
+
+	want := []string{`server.port: failed rule "min=1024"`, "legacy_mode is deprecated"}
+	if got := Warnings(cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("Warnings() = %q, want %q", got, want)
+	}
+
+	cfg.Server.Port = 8080
+	cfg.LegacyMode = false
+	if got := Warnings(cfg); got != nil {
+		t.Errorf("Expected no warnings, got: %q", got)
+	}
+}
+
+// TestWarningsWithTag tests that the warning tag follows the validation tag name
+func TestWarningsWithTag(t *testing.T) {
+	type TaggedConfig struct {
+		Workers int `check_warn:"max=64" validate_warn:"max=1"`
+	}
+
+	cfg := &TaggedConfig{Workers: 8}
+	if got := WarningsWithTag(cfg, "check"); got != nil {
+		t.Errorf("Expected no warnings from check_warn, got: %q", got)
+	}
+
+	cfg.Workers = 128
+	got := WarningsWithTag(cfg, "check")
+	if len(got) != 1 || !contains(got[0], `workers: failed rule "max=64"`) {
+		t.Errorf("Expected a check_warn warning, got: %q", got)
+	}
+
+	if got := Warnings(nil); got != nil {
+		t.Errorf("Expected no warnings for nil, got: %q", got)
+	}
+}
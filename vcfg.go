@@ -3,8 +3,16 @@
 // It offers both simple and advanced configuration loading patterns for Go applications.
 package vcfg
 
-// MustLoad is a convenience function that initializes a new ConfigManager with the provided sources
-// and loads the initial configuration. It accepts both file paths (strings) and koanf.Provider instances.
+import (
+	"context"
+
+	"github.com/nextpkg/vcfg/slogs"
+)
+
+// Load initializes a new ConfigManager with the provided sources and loads the
+// initial configuration. It accepts both file paths (strings) and koanf.Provider instances.
+// Failures are logged and returned instead of panicking, which makes Load suitable
+// for use in libraries.
 //
 // Type parameter:
 //   - T: The configuration struct type to unmarshal into
@@ -12,24 +20,47 @@ package vcfg
 // Parameters:
 //   - sources: Variable number of configuration sources (file paths or koanf.Provider instances)
 //
-// Returns a fully initialized ConfigManager with the configuration loaded.
-// Panics if initialization or loading fails - use Builder for error handling.
-func MustLoad[T any](sources ...any) *ConfigManager[T] {
-	cm := newManager[T](sources...)
+// Returns a fully initialized ConfigManager with the configuration loaded,
+// or an error if initialization or loading fails.
+func Load[T any](sources ...any) (*ConfigManager[T], error) {
+	cm, err := createManager[T](sources...)
+	if err != nil {
+		slogs.Error("Failed to create configuration manager", "error", err)
+		return nil, err
+	}
 
 	// Load initial configuration
-	cfg, err := cm.load()
+	cfg, err := cm.load(context.Background())
 	if err != nil {
-		panic(err)
+		slogs.Error("Failed to load configuration", "error", err)
+		return nil, err
 	}
 
-	cm.cfg.Store(cfg)
+	cm.storeConfig(cfg)
+	cm.emitLoaded()
+	return cm, nil
+}
+
+// MustLoad is like Load but panics if initialization or loading fails.
+//
+// Type parameter:
+//   - T: The configuration struct type to unmarshal into
+//
+// Parameters:
+//   - sources: Variable number of configuration sources (file paths or koanf.Provider instances)
+//
+// Returns a fully initialized ConfigManager with the configuration loaded.
+func MustLoad[T any](sources ...any) *ConfigManager[T] {
+	cm, err := Load[T](sources...)
+	if err != nil {
+		panic(err)
+	}
 	return cm
 }
 
-// MustBuild is a convenience function that creates a simple configuration manager
-// using only file sources. It's a shorthand for common use cases where only
-// file-based configuration is needed.
+// Build creates a simple configuration manager using only file sources.
+// It's a shorthand for common use cases where only file-based configuration is needed.
+// Failures are logged and returned instead of panicking.
 //
 // Type parameter:
 //   - T: The configuration struct type to unmarshal into
@@ -37,12 +68,34 @@ func MustLoad[T any](sources ...any) *ConfigManager[T] {
 // Parameters:
 //   - filePaths: Variable number of file paths to load configuration from
 //
-// Returns a ConfigManager configured with the specified files.
-// Panics if building fails - use Builder for error handling.
-func MustBuild[T any](filePaths ...string) *ConfigManager[T] {
+// Returns a ConfigManager configured with the specified files, or an error if building fails.
+func Build[T any](filePaths ...string) (*ConfigManager[T], error) {
 	builder := NewBuilder[T]()
 	for _, path := range filePaths {
 		builder.AddFile(path)
 	}
-	return builder.MustBuild()
+
+	cm, err := builder.Build(context.Background())
+	if err != nil {
+		slogs.Error("Failed to build configuration manager", "error", err)
+		return nil, err
+	}
+	return cm, nil
+}
+
+// MustBuild is like Build but panics if building fails.
+//
+// Type parameter:
+//   - T: The configuration struct type to unmarshal into
+//
+// Parameters:
+//   - filePaths: Variable number of file paths to load configuration from
+//
+// Returns a ConfigManager configured with the specified files.
+func MustBuild[T any](filePaths ...string) *ConfigManager[T] {
+	cm, err := Build[T](filePaths...)
+	if err != nil {
+		panic(err)
+	}
+	return cm
 }
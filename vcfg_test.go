@@ -169,3 +169,57 @@ func TestMustBuild_EmptyFile(t *testing.T) {
 		cm.Close()
 	})
 }
+
+func TestLoad(t *testing.T) {
+	t.Run("successful load", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.json")
+		err := os.WriteFile(configFile, []byte(`{"name":"load-test","port":8080}`), 0644)
+		require.NoError(t, err)
+
+		cm, err := Load[VcfgTestConfig](configFile)
+		require.NoError(t, err)
+		defer cm.Close()
+
+		assert.Equal(t, "load-test", cm.Get().Name)
+		assert.Equal(t, 8080, cm.Get().Port)
+	})
+
+	t.Run("missing file returns error", func(t *testing.T) {
+		cm, err := Load[VcfgTestConfig]("/nonexistent/config.json")
+		assert.Error(t, err)
+		assert.Nil(t, cm)
+	})
+
+	t.Run("unsupported source returns error", func(t *testing.T) {
+		cm, err := Load[VcfgTestConfig](42)
+		assert.Error(t, err)
+		assert.Nil(t, cm)
+	})
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("successful build", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(configFile, []byte("name: build-test\nport: 9090\n"), 0644)
+		require.NoError(t, err)
+
+		cm, err := Build[VcfgTestConfig](configFile)
+		require.NoError(t, err)
+		defer cm.Close()
+
+		assert.Equal(t, "build-test", cm.Get().Name)
+		assert.Equal(t, 9090, cm.Get().Port)
+	})
+
+	t.Run("missing file returns error", func(t *testing.T) {
+		cm, err := Build[VcfgTestConfig]("/nonexistent/config.yaml")
+		assert.Error(t, err)
+		assert.Nil(t, cm)
+	})
+
+	t.Run("no sources returns error", func(t *testing.T) {
+		cm, err := Build[VcfgTestConfig]()
+		assert.Error(t, err)
+		assert.Nil(t, cm)
+	})
+}
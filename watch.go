@@ -0,0 +1,118 @@
+// Package vcfg provides configuration management capabilities.
+// This file implements watch options and the event debouncer that coalesces
+// bursts of change notifications into a single configuration reload.
+package vcfg
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	// WatchOption configures how the ConfigManager reacts to change notifications
+	// emitted by watching providers.
+	WatchOption func(*watchOptions)
+
+	// watchOptions holds the resolved watch settings for a ConfigManager.
+	watchOptions struct {
+		// debounce is the quiet period that must elapse after the last change
+		// event before a reload is triggered. Zero disables debouncing.
+		debounce time.Duration
+	}
+
+	// debouncer delays the execution of fn until no trigger has been received
+	// for the configured delay. Events from all providers share one debouncer,
+	// so a burst touching several sources results in a single call that receives
+	// the names of all sources that triggered it.
+	debouncer struct {
+		// mu protects the timer and pending sources
+		mu sync.Mutex
+		// delay is the quiet period before fn is invoked
+		delay time.Duration
+		// timer is the pending invocation, nil if none was scheduled yet
+		timer *time.Timer
+		// pending holds the distinct sources triggered since the last invocation
+		pending []string
+		// fn is the function invoked once the quiet period has elapsed
+		fn func(sources []string)
+	}
+)
+
+// WithDebounce overrides the platform default debounce interval for watch events.
+// Change notifications arriving within d of each other are coalesced into a single
+// reload. A value of zero disables debouncing and reloads on every event.
+// Negative values are treated as zero.
+func WithDebounce(d time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		if d < 0 {
+			d = 0
+		}
+		o.debounce = d
+	}
+}
+
+// newWatchOptions returns the watch settings with platform defaults applied
+// and the provided options layered on top.
+func newWatchOptions(opts ...WatchOption) watchOptions {
+	o := watchOptions{
+		debounce: defaultWatchDebounce,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// newDebouncer creates a debouncer that invokes fn after delay has elapsed
+// since the most recent trigger.
+func newDebouncer(delay time.Duration, fn func(sources []string)) *debouncer {
+	return &debouncer{
+		delay: delay,
+		fn:    fn,
+	}
+}
+
+// trigger records a change event from source. If debouncing is disabled fn runs
+// synchronously, otherwise the pending invocation is (re)scheduled after the quiet period.
+func (d *debouncer) trigger(source string) {
+	if d.delay <= 0 {
+		d.fn([]string{source})
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !slices.Contains(d.pending, source) {
+		d.pending = append(d.pending, source)
+	}
+
+	if d.timer == nil {
+		d.timer = time.AfterFunc(d.delay, d.fire)
+		return
+	}
+	d.timer.Reset(d.delay)
+}
+
+// fire hands the accumulated sources to fn once the quiet period has elapsed.
+func (d *debouncer) fire() {
+	d.mu.Lock()
+	sources := d.pending
+	d.pending = nil
+	d.mu.Unlock()
+
+	d.fn(sources)
+}
+
+// stop cancels any pending invocation.
+func (d *debouncer) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.pending = nil
+}
@@ -0,0 +1,9 @@
+//go:build darwin
+
+package vcfg
+
+import "time"
+
+// defaultWatchDebounce on macOS accounts for kqueue/FSEvents delivering several
+// coalesced events (write, chmod, rename) for a single editor save.
+const defaultWatchDebounce = 200 * time.Millisecond
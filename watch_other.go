@@ -0,0 +1,9 @@
+//go:build !darwin && !windows
+
+package vcfg
+
+import "time"
+
+// defaultWatchDebounce on Linux and other platforms is short, since inotify reports
+// events promptly but editors still commonly emit a write and a chmod per save.
+const defaultWatchDebounce = 100 * time.Millisecond
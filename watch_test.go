@@ -0,0 +1,180 @@
+package vcfg
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+// countingWatchProvider is a JSON provider that counts reads and lets tests
+// fire watch events manually.
+type countingWatchProvider struct {
+	mu    sync.Mutex
+	data  []byte
+	reads atomic.Int32
+	cb    func(event any, err error)
+}
+
+func (p *countingWatchProvider) ReadBytes() ([]byte, error) {
+	p.reads.Inc()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.data, nil
+}
+
+func (p *countingWatchProvider) Read() (map[string]any, error) {
+	return nil, nil
+}
+
+func (p *countingWatchProvider) Watch(cb func(event any, err error)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cb = cb
+	return nil
+}
+
+func (p *countingWatchProvider) set(data string) {
+	p.mu.Lock()
+	p.data = []byte(data)
+	p.mu.Unlock()
+}
+
+func (p *countingWatchProvider) fire() {
+	p.mu.Lock()
+	cb := p.cb
+	p.mu.Unlock()
+	cb(nil, nil)
+}
+
+func TestNewWatchOptions(t *testing.T) {
+	o := newWatchOptions()
+	assert.Equal(t, defaultWatchDebounce, o.debounce)
+	assert.Greater(t, o.debounce, time.Duration(0))
+
+	o = newWatchOptions(WithDebounce(time.Second))
+	assert.Equal(t, time.Second, o.debounce)
+
+	o = newWatchOptions(WithDebounce(-time.Second))
+	assert.Equal(t, time.Duration(0), o.debounce)
+}
+
+func TestDebouncer_CoalescesBurst(t *testing.T) {
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var received [][]string
+	d := newDebouncer(50*time.Millisecond, func(sources []string) {
+		mu.Lock()
+		received = append(received, sources)
+		mu.Unlock()
+		calls.Inc()
+	})
+
+	for i := range 10 {
+		d.trigger([]string{"a.yaml", "b.yaml"}[i%2])
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// A later event schedules a new call
+	d.trigger("b.yaml")
+	assert.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"a.yaml", "b.yaml"}, received[0])
+	assert.Equal(t, []string{"b.yaml"}, received[1])
+}
+
+func TestDebouncer_Disabled(t *testing.T) {
+	var calls atomic.Int32
+	d := newDebouncer(0, func([]string) { calls.Inc() })
+
+	d.trigger("source")
+	d.trigger("source")
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestDebouncer_Stop(t *testing.T) {
+	var calls atomic.Int32
+	d := newDebouncer(50*time.Millisecond, func([]string) { calls.Inc() })
+
+	d.trigger("source")
+	d.stop()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(0), calls.Load())
+}
+
+func TestConfigManager_EnableWatch_Debounce(t *testing.T) {
+	provider := &countingWatchProvider{data: []byte(`{"name":"initial"}`)}
+	cm := newManager[TestConfig](provider)
+
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+	cm.cfg.Store(cfg)
+	initialReads := provider.reads.Load()
+
+	cm.EnableWatch(WithDebounce(50 * time.Millisecond))
+	defer cm.DisableWatch()
+
+	// Simulate a platform firing several events for one save
+	provider.set(`{"name":"updated"}`)
+	for range 5 {
+		provider.fire()
+	}
+
+	assert.Eventually(t, func() bool { return cm.Get().Name == "updated" }, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, initialReads+1, provider.reads.Load())
+}
+
+func TestConfigManager_EnableWatch_NoDebounce(t *testing.T) {
+	provider := &countingWatchProvider{data: []byte(`{"name":"initial"}`)}
+	cm := newManager[TestConfig](provider)
+
+	cfg, err := cm.load(context.Background())
+	require.NoError(t, err)
+	cm.cfg.Store(cfg)
+	initialReads := provider.reads.Load()
+
+	cm.EnableWatch(WithDebounce(0))
+	defer cm.DisableWatch()
+
+	provider.set(`{"name":"updated"}`)
+	provider.fire()
+	provider.fire()
+
+	assert.Equal(t, "updated", cm.Get().Name)
+	assert.Equal(t, initialReads+2, provider.reads.Load())
+}
+
+func TestBuilder_WithWatchDebounce(t *testing.T) {
+	provider := &countingWatchProvider{data: []byte(`{"name":"initial"}`)}
+
+	cm, err := NewBuilder[TestConfig]().
+		AddProvider(provider).
+		WithWatchDebounce(100 * time.Millisecond).
+		Build(context.Background())
+	require.NoError(t, err)
+	defer cm.Close()
+	initialReads := provider.reads.Load()
+
+	// Events spread over more than the interval still coalesce while each one
+	// arrives before the previous one settled
+	provider.set(`{"name":"updated"}`)
+	for range 5 {
+		provider.fire()
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	assert.Eventually(t, func() bool { return cm.Get().Name == "updated" }, time.Second, 10*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, initialReads+1, provider.reads.Load())
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package vcfg
+
+import "time"
+
+// defaultWatchDebounce on Windows accounts for ReadDirectoryChangesW reporting
+// a rename followed by one or more writes when editors save atomically.
+const defaultWatchDebounce = 200 * time.Millisecond